@@ -1,6 +1,9 @@
 package state
 
 import (
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/cidverse/repoanalyzer/analyzerapi"
@@ -28,6 +31,11 @@ type AuditEvents struct {
 	Payload   map[string]string `json:"payload"`
 }
 
+// Equal returns true if both events have the same timestamp, type and payload
+func (e AuditEvents) Equal(other AuditEvents) bool {
+	return e.Timestamp.Equal(other.Timestamp) && e.Type == other.Type && maps.Equal(e.Payload, other.Payload)
+}
+
 // ActionStateContext holds state information about executed actions / results (ie. generated artifacts)
 type ActionStateContext struct {
 	// Version of the serialized action state
@@ -42,3 +50,18 @@ type ActionStateContext struct {
 	// Steps holds a list of all steps that were part of the pipeline
 	AuditLog []AuditEvents `json:"audit_events"`
 }
+
+// FindArtifacts returns all artifacts of the given type and format, sorted by id
+func (s ActionStateContext) FindArtifacts(artifactType string, format string) []ActionArtifact {
+	var result []ActionArtifact
+	for _, a := range s.Artifacts {
+		if a.Type == artifactType && a.Format == format {
+			result = append(result, a)
+		}
+	}
+	slices.SortFunc(result, func(a, b ActionArtifact) int {
+		return strings.Compare(a.ArtifactID, b.ArtifactID)
+	})
+
+	return result
+}
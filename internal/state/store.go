@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/cidverse/cidverseutils/filesystem"
 	"github.com/rs/zerolog/log"
@@ -97,5 +98,12 @@ func MergeStates(state1 ActionStateContext, state2 ActionStateContext) ActionSta
 		state1.Artifacts[artifactName] = artifact
 	}
 
+	// merge audit log, events are part of the state of all following steps and may be present in multiple files
+	for _, event := range state2.AuditLog {
+		if !slices.ContainsFunc(state1.AuditLog, event.Equal) {
+			state1.AuditLog = append(state1.AuditLog, event)
+		}
+	}
+
 	return state1
 }
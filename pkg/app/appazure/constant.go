@@ -0,0 +1,41 @@
+package appazure
+
+import (
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+)
+
+var mergeRequestFooter = "This PR has been generated by the CID Workflow App."
+
+var azureNetworkAllowList = []actionsdk.ActionAccessNetwork{
+	// Azure DevOps
+	{Host: "dev.azure.com:443"},
+	{Host: "vstoken.dev.azure.com:443"},
+	{Host: "vsblob.dev.azure.com:443"},
+}
+
+var azureWorkflowDependencyList = []dependency.Dependency{
+	// tools
+	{
+		Id:             "cidverse/cid",
+		Type:           "github",
+		Version:        constants.Version,
+		Hash:           constants.BinaryHash,
+		GPGFingerprint: "76A4948E69C62589C7B0AB84E414434DF5371FB6",
+	},
+	// see https://learn.microsoft.com/en-us/azure/devops/pipelines/agents/hosted
+	{
+		Id:      "ubuntu",
+		Type:    "azure-pipelines-image",
+		Version: "24.04",
+	},
+}
+
+var azureWorkflowDependencies = func() map[string]dependency.Dependency {
+	m := make(map[string]dependency.Dependency, len(azureWorkflowDependencyList))
+	for _, dep := range azureWorkflowDependencyList {
+		m[dep.AsPackageUrlNoVersion()] = dep
+	}
+	return m
+}()
@@ -0,0 +1,232 @@
+package appazure
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/app/appcommon"
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/go-vcsapp/pkg/vcsapp"
+)
+
+//go:embed templates/*
+var embedFS embed.FS
+
+type TemplateData struct {
+	Version         string                `json:"version"`
+	VMImage         string                `json:"vm_image"`
+	CID             dependency.Dependency `json:"cid"`
+	ManualWorkflows []string              `json:"manual_workflows,omitempty"` // ManualWorkflows are the workflows that can be selected when running the pipeline manually
+	PushBranches    []string              `json:"push_branches,omitempty"`
+	PushTags        []string              `json:"push_tags,omitempty"`
+	PullRequest     []string              `json:"pull_request,omitempty"`
+	Schedules       []Schedule            `json:"schedules,omitempty"`
+	Workflows       []Workflow            `json:"workflows"`
+}
+
+type Schedule struct {
+	Cron        string   `json:"cron"`
+	DisplayName string   `json:"display_name"`
+	Branches    []string `json:"branches"`
+}
+
+type Workflow struct {
+	Name        string  `json:"name"`
+	WorkflowKey string  `json:"workflow_key"`
+	Condition   string  `json:"condition"` // Condition selects the stages of the workflow based on the build reason
+	Stages      []Stage `json:"stages"`
+}
+
+type Stage struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	DependsOn string `json:"depends_on,omitempty"`
+	Jobs      []Job  `json:"jobs"`
+}
+
+type Job struct {
+	Id          string             `json:"id"`
+	DependsOn   []string           `json:"depends_on,omitempty"` // DependsOn are the jobs of the same stage, jobs of previous stages are covered by the stage dependency
+	Step        plangenerate.Step  `json:"step"`
+	Artifact    string             `json:"artifact"`
+	Downloads   []ArtifactDownload `json:"downloads,omitempty"`
+	Secrets     []string           `json:"secrets,omitempty"` // Secrets need to be mapped explicitly, regular variables are available as environment variables
+	JobTimeout  int                `json:"job_timeout"`
+	StateWfName string             `json:"state_wf_name"`
+}
+
+type ArtifactDownload struct {
+	Artifact string `json:"artifact"`
+	Path     string `json:"path"`
+}
+
+type RenderWorkflowResult struct {
+	WorkflowContent string
+}
+
+// renderWorkflow renders the workflow template and returns the rendered template
+func renderWorkflow(data []appconfig.WorkflowData, templateFile string, outputFile string) (RenderWorkflowResult, error) {
+	content, err := embedFS.ReadFile(path.Join("templates", templateFile))
+	if err != nil {
+		return RenderWorkflowResult{}, fmt.Errorf("failed to read workflow template %s: %w", templateFile, err)
+	}
+
+	template, err := vcsapp.Render(string(content), newTemplateData(data))
+	if err != nil {
+		return RenderWorkflowResult{}, fmt.Errorf("failed to render template %s: %w", templateFile, err)
+	}
+
+	// write workflow file
+	if outputFile != "" {
+		err = os.MkdirAll(filepath.Dir(outputFile), os.ModePerm)
+		if err != nil {
+			return RenderWorkflowResult{}, fmt.Errorf("failed to create workflow file parent directory: %w", err)
+		}
+		err = os.WriteFile(outputFile, template, 0644)
+		if err != nil {
+			return RenderWorkflowResult{}, fmt.Errorf("failed to create workflow file: %w", err)
+		}
+	}
+
+	return RenderWorkflowResult{WorkflowContent: string(template)}, nil
+}
+
+// newTemplateData maps the workflows to azure pipeline stages, all workflows share a single pipeline and are selected by stage conditions
+func newTemplateData(data []appconfig.WorkflowData) *TemplateData {
+	td := &TemplateData{
+		Version: constants.Version,
+		VMImage: "ubuntu-latest",
+	}
+
+	for _, wf := range data {
+		td.Version = wf.Version
+		if image := wf.GetDependency("pkg:azure-pipelines-image/ubuntu"); image.Id != "" {
+			td.VMImage = image.Id + "-" + image.Version
+		}
+		td.CID = wf.GetDependency("pkg:github/cidverse/cid")
+
+		// triggers
+		wfConfig := wf.WorkflowConfig
+		var conditions []string
+		if wfConfig.TriggerManual {
+			td.ManualWorkflows = append(td.ManualWorkflows, wf.WorkflowKey)
+			conditions = append(conditions, fmt.Sprintf("and(eq(variables['Build.Reason'], 'Manual'), eq('${{ parameters.workflow }}', '%s'))", wf.WorkflowKey))
+		}
+		if wfConfig.TriggerPush {
+			var refs []string
+			for _, branch := range wfConfig.TriggerPushBranches {
+				td.PushBranches = appendUnique(td.PushBranches, branch)
+				refs = append(refs, fmt.Sprintf("eq(variables['Build.SourceBranch'], 'refs/heads/%s')", branch))
+			}
+			for _, tag := range wfConfig.TriggerPushTags {
+				prefix := appcommon.TagPatternPrefix(tag)
+				td.PushTags = appendUnique(td.PushTags, prefix+"*")
+				refs = append(refs, fmt.Sprintf("startsWith(variables['Build.SourceBranch'], 'refs/tags/%s')", prefix))
+			}
+			if len(refs) > 0 {
+				conditions = append(conditions, fmt.Sprintf("and(in(variables['Build.Reason'], 'IndividualCI', 'BatchedCI'), %s)", anyOf(refs)))
+			}
+		}
+		if wfConfig.TriggerPullRequest {
+			for _, branch := range wfConfig.TriggerPullRequestBranches {
+				td.PullRequest = appendUnique(td.PullRequest, branch)
+			}
+			conditions = append(conditions, "eq(variables['Build.Reason'], 'PullRequest')")
+		}
+		if wfConfig.TriggerSchedule && wfConfig.TriggerScheduleCron != "" {
+			td.Schedules = append(td.Schedules, Schedule{Cron: wfConfig.TriggerScheduleCron, DisplayName: wf.WorkflowKey, Branches: wfConfig.TriggerScheduleBranches})
+			conditions = append(conditions, fmt.Sprintf("and(eq(variables['Build.Reason'], 'Schedule'), eq(variables['Build.CronSchedule.DisplayName'], '%s'))", wf.WorkflowKey))
+		}
+		if len(conditions) == 0 {
+			continue
+		}
+
+		// stages and jobs
+		workflow := Workflow{
+			Name:        wf.Name,
+			WorkflowKey: wf.WorkflowKey,
+			Condition:   anyOf(conditions),
+		}
+		previousStage := ""
+		for _, stageName := range wf.Plan.Stages {
+			stage := Stage{
+				Id:        identifier(wf.WorkflowKey + "_" + stageName),
+				Name:      fmt.Sprintf("%s - %s", wf.Name, stageName),
+				DependsOn: previousStage,
+			}
+
+			for _, step := range wf.Plan.Steps {
+				if step.Stage != stageName {
+					continue
+				}
+
+				job := Job{
+					Id:          identifier(step.Slug),
+					Step:        step,
+					Artifact:    wf.WorkflowKey + "-" + step.Slug,
+					JobTimeout:  wf.JobTimeout,
+					StateWfName: wf.WorkflowKey,
+				}
+				for _, dep := range step.RunAfter {
+					if slices.ContainsFunc(wf.Plan.Steps, func(s plangenerate.Step) bool { return s.Slug == dep && s.Stage == stageName }) {
+						job.DependsOn = append(job.DependsOn, identifier(dep))
+					}
+				}
+				for _, prev := range step.UsesOutputOf {
+					job.Downloads = append(job.Downloads, ArtifactDownload{Artifact: wf.WorkflowKey + "-" + prev, Path: ".dist/" + prev})
+				}
+				for _, e := range step.Access.Environment {
+					if e.Secret && !e.Pattern {
+						job.Secrets = append(job.Secrets, e.Name)
+					}
+				}
+
+				stage.Jobs = append(stage.Jobs, job)
+			}
+
+			if len(stage.Jobs) > 0 {
+				workflow.Stages = append(workflow.Stages, stage)
+				previousStage = stage.Id
+			}
+		}
+
+		td.Workflows = append(td.Workflows, workflow)
+	}
+
+	return td
+}
+
+// identifier converts a name into a valid stage or job identifier, only letters, numbers and underscores are allowed
+func identifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// anyOf combines the conditions with or, the or function requires at least two arguments
+func anyOf(conditions []string) string {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+
+	return "or(" + strings.Join(conditions, ", ") + ")"
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
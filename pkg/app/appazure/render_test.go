@@ -0,0 +1,23 @@
+package appazure
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/app/appconfig/appconfigtest"
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWorkflow(t *testing.T) {
+	data := appconfigtest.WorkflowData(dependency.Dependency{Id: "ubuntu", Type: "azure-pipelines-image", Version: "24.04"})
+	result, err := renderWorkflow(data, "wf-main.gohtml", "")
+	require.NoError(t, err)
+
+	appconfigtest.AssertGolden(t, "azure-pipelines.yml.golden", result.WorkflowContent)
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "pull_request_build", identifier("pull-request_build"))
+	assert.Equal(t, "go_build", identifier("go.build"))
+}
@@ -0,0 +1,63 @@
+package appbitbucket
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cidverse/cid/pkg/app/appcommon"
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/app/apptask"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/config"
+	"github.com/cidverse/go-vcsapp/pkg/task/taskcommon"
+)
+
+// BitbucketWorkflowTask generates a project-specific Bitbucket Pipelines file and creates a pull request
+//
+// Links of interest:
+// https://support.atlassian.com/bitbucket-cloud/docs/bitbucket-pipelines-configuration-reference/ for the pipeline syntax
+func BitbucketWorkflowTask(taskContext taskcommon.TaskContext, dryRun bool) (apptask.WorkflowTaskResult, error) {
+	return apptask.WorkflowTask(taskContext, apptask.PlatformWorkflowTaskOptions{
+		LoadConfig: func(taskContext taskcommon.TaskContext, cfg *config.CIDConfig) (appconfig.Config, error) {
+			return appconfig.Config{
+				Version:          constants.Version,
+				VersionHash:      constants.BinaryHash,
+				JobTimeout:       20,
+				RunnerTags:       []string{},
+				EgressPolicy:     "audit",
+				ContainerRuntime: "docker",
+				Workflows:        appconfig.DefaultWorkflowConfig(taskContext.Repository.DefaultBranch, taskContext.Repository.Branches, cfg.Conventions.Branching),
+			}, nil
+		},
+		RenderWorkflow: func(workflowState *appconfig.WorkflowState, data apptask.PlatformWorkflowData, template string, targetDir string) (map[string]string, error) {
+			var workflowTemplateData []appconfig.WorkflowData
+
+			for pair := data.Conf.Workflows.Oldest(); pair != nil; pair = pair.Next() {
+				wfKey := pair.Key
+				wfConfig := pair.Value
+
+				filteredEnvs, wfErr := appcommon.FilterVCSEnvironments(data.Environments, wfConfig.EnvironmentPattern)
+				if wfErr != nil {
+					return nil, fmt.Errorf("failed to filter workflow environments [%s]: %w", wfKey, wfErr)
+				}
+
+				wtd, wfErr := appconfig.GenerateWorkflowData(data.CidContext, taskContext, data.Conf, wfKey, wfConfig, data.ProjectVariables, filteredEnvs, bitbucketWorkflowDependencies, bitbucketNetworkAllowList)
+				if wfErr != nil {
+					return nil, fmt.Errorf("failed to generate workflow template [%s]: %w", wfKey, wfErr)
+				}
+
+				workflowTemplateData = append(workflowTemplateData, wtd)
+				workflowState.Workflows.Set(wfKey, &wtd)
+			}
+
+			wfResult, wfErr := renderWorkflow(workflowTemplateData, template, filepath.Join(targetDir, "bitbucket-pipelines.yml"))
+			if wfErr != nil {
+				return nil, fmt.Errorf("failed to render [bitbucket-pipelines.yml]: %w", wfErr)
+			}
+
+			return map[string]string{"bitbucket-pipelines.yml": wfResult.WorkflowContent}, nil
+		},
+		WorkflowStatePath:  filepath.Join(taskContext.Directory, ".cid", "state-bitbucket.json"),
+		MergeRequestFooter: mergeRequestFooter,
+	}, dryRun)
+}
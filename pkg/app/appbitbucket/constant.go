@@ -0,0 +1,41 @@
+package appbitbucket
+
+import (
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+)
+
+var mergeRequestFooter = "This PR has been generated by the CID Workflow App."
+
+var bitbucketNetworkAllowList = []actionsdk.ActionAccessNetwork{
+	// Bitbucket Platform
+	{Host: "bitbucket.org:443"},
+	{Host: "api.bitbucket.org:443"},
+}
+
+var bitbucketWorkflowDependencyList = []dependency.Dependency{
+	// tools
+	{
+		Id:             "cidverse/cid",
+		Type:           "github",
+		Version:        constants.Version,
+		Hash:           constants.BinaryHash,
+		GPGFingerprint: "76A4948E69C62589C7B0AB84E414434DF5371FB6",
+	},
+	// oci-containers
+	{
+		Id:         "atlassian/default-image",
+		Type:       "docker",
+		Version:    "4",
+		Repository: "docker.io",
+	},
+}
+
+var bitbucketWorkflowDependencies = func() map[string]dependency.Dependency {
+	m := make(map[string]dependency.Dependency, len(bitbucketWorkflowDependencyList))
+	for _, dep := range bitbucketWorkflowDependencyList {
+		m[dep.AsPackageUrlNoVersion()] = dep
+	}
+	return m
+}()
@@ -0,0 +1,184 @@
+package appbitbucket
+
+import (
+	"embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/app/appcommon"
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/go-vcsapp/pkg/vcsapp"
+)
+
+//go:embed templates/*
+var embedFS embed.FS
+
+type TemplateData struct {
+	Version      string                `json:"version"`
+	Image        string                `json:"image"`
+	CID          dependency.Dependency `json:"cid"`
+	Docker       bool                  `json:"docker"`
+	Steps        []Step                `json:"steps"` // Steps are defined once and referenced by the pipelines
+	Branches     []Pipeline            `json:"branches,omitempty"`
+	Tags         []Pipeline            `json:"tags,omitempty"`
+	PullRequests []Pipeline            `json:"pull_requests,omitempty"`
+	Custom       []Pipeline            `json:"custom,omitempty"` // Custom pipelines can be run manually or by a schedule configured in the repository settings
+}
+
+type Pipeline struct {
+	Pattern  string `json:"pattern"`
+	Workflow string `json:"workflow"`
+	Items    []Item `json:"items"`
+}
+
+// Item is either a stage of steps or a single deployment or gate step, bitbucket does not allow deployment or manual steps within stages
+type Item struct {
+	Stage string   `json:"stage,omitempty"`
+	Steps []string `json:"steps"` // Steps are the anchors of the step definitions
+}
+
+type Step struct {
+	Anchor             string            `json:"anchor"`
+	Step               plangenerate.Step `json:"step"`
+	Download           bool              `json:"download"` // Download the artifacts of previous steps, bitbucket can not select the artifacts of specific steps
+	JobTimeout         int               `json:"job_timeout"`
+	StateWfName        string            `json:"state_wf_name"`
+	TargetBranchFilter string            `json:"target_branch_filter,omitempty"` // TargetBranchFilter is a shell case pattern, pull requests into other branches skip the step
+}
+
+type RenderWorkflowResult struct {
+	WorkflowContent string
+}
+
+// renderWorkflow renders the workflow template and returns the rendered template
+func renderWorkflow(data []appconfig.WorkflowData, templateFile string, outputFile string) (RenderWorkflowResult, error) {
+	content, err := embedFS.ReadFile(path.Join("templates", templateFile))
+	if err != nil {
+		return RenderWorkflowResult{}, fmt.Errorf("failed to read workflow template %s: %w", templateFile, err)
+	}
+
+	template, err := vcsapp.Render(string(content), newTemplateData(data))
+	if err != nil {
+		return RenderWorkflowResult{}, fmt.Errorf("failed to render template %s: %w", templateFile, err)
+	}
+
+	// write workflow file
+	if outputFile != "" {
+		err = os.MkdirAll(filepath.Dir(outputFile), os.ModePerm)
+		if err != nil {
+			return RenderWorkflowResult{}, fmt.Errorf("failed to create workflow file parent directory: %w", err)
+		}
+		err = os.WriteFile(outputFile, template, 0644)
+		if err != nil {
+			return RenderWorkflowResult{}, fmt.Errorf("failed to create workflow file: %w", err)
+		}
+	}
+
+	return RenderWorkflowResult{WorkflowContent: string(template)}, nil
+}
+
+// newTemplateData maps the workflows to bitbucket pipelines, a branch or tag pattern can only be used by the first workflow that declares it
+func newTemplateData(data []appconfig.WorkflowData) *TemplateData {
+	td := &TemplateData{
+		Version: constants.Version,
+	}
+
+	for _, wf := range data {
+		td.Version = wf.Version
+		td.Image = wf.GetDependencyReference("pkg:docker/atlassian/default-image?repository_url=docker.io")
+		td.CID = wf.GetDependency("pkg:github/cidverse/cid")
+		td.Docker = wf.ContainerRuntime == "docker"
+
+		// steps
+		var items []Item
+		for _, stageName := range wf.Plan.Stages {
+			stage := Item{Stage: stageName}
+			var deployments []Item
+			for _, step := range wf.Plan.Steps {
+				if step.Stage != stageName {
+					continue
+				}
+
+				s := Step{
+					Anchor:             wf.WorkflowKey + "-" + step.Slug,
+					Step:               step,
+					Download:           len(step.UsesOutputOf) > 0,
+					JobTimeout:         wf.JobTimeout,
+					StateWfName:        wf.WorkflowKey,
+					TargetBranchFilter: targetBranchFilter(wf.WorkflowConfig),
+				}
+				td.Steps = append(td.Steps, s)
+
+				if step.Environment != "" || step.IsGate() {
+					deployments = append(deployments, Item{Steps: []string{s.Anchor}})
+				} else {
+					stage.Steps = append(stage.Steps, s.Anchor)
+				}
+			}
+
+			if len(stage.Steps) > 0 {
+				items = append(items, stage)
+			}
+			items = append(items, deployments...)
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		// triggers
+		wfConfig := wf.WorkflowConfig
+		if wfConfig.TriggerPush {
+			for _, branch := range wfConfig.TriggerPushBranches {
+				td.Branches = appendPipeline(td.Branches, Pipeline{Pattern: branch, Workflow: wf.Name, Items: items})
+			}
+			for _, tag := range wfConfig.TriggerPushTags {
+				td.Tags = appendPipeline(td.Tags, Pipeline{Pattern: appcommon.TagPatternPrefix(tag) + "*", Workflow: wf.Name, Items: items})
+			}
+		}
+		if wfConfig.TriggerPullRequest {
+			// pull request pipelines are selected by the source branch, the target branches are checked by the steps
+			td.PullRequests = appendPipeline(td.PullRequests, Pipeline{Pattern: "**", Workflow: wf.Name, Items: items})
+		}
+		if wfConfig.TriggerSchedule {
+			slog.With("workflow", wf.WorkflowKey).With("cron", wfConfig.TriggerScheduleCron).Warn("bitbucket pipelines do not support schedules in the pipeline file, configure a schedule for the custom pipeline in the repository settings (Pipelines > Schedules)")
+		}
+		if wfConfig.TriggerManual || wfConfig.TriggerSchedule {
+			td.Custom = appendPipeline(td.Custom, Pipeline{Pattern: wf.WorkflowKey, Workflow: wf.Name, Items: items})
+		}
+	}
+
+	return td
+}
+
+func appendPipeline(pipelines []Pipeline, pipeline Pipeline) []Pipeline {
+	if slices.ContainsFunc(pipelines, func(p Pipeline) bool { return p.Pattern == pipeline.Pattern }) {
+		return pipelines
+	}
+
+	return append(pipelines, pipeline)
+}
+
+// targetBranchFilter returns the shell case pattern matching the pull request target branches, empty if the workflow is not triggered by pull requests or accepts all target branches
+func targetBranchFilter(wfConfig appconfig.WorkflowConfig) string {
+	if !wfConfig.TriggerPullRequest || len(wfConfig.TriggerPullRequestBranches) == 0 {
+		return ""
+	}
+
+	var patterns []string
+	for _, branch := range wfConfig.TriggerPullRequestBranches {
+		if branch == "**" || branch == "*" {
+			return ""
+		}
+		patterns = append(patterns, strings.ReplaceAll(branch, "**", "*"))
+	}
+
+	return strings.Join(patterns, "|")
+}
@@ -0,0 +1,28 @@
+package appbitbucket
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/app/appconfig/appconfigtest"
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWorkflow(t *testing.T) {
+	data := appconfigtest.WorkflowData(dependency.Dependency{Id: "atlassian/default-image", Type: "docker", Version: "4", Repository: "docker.io"})
+	for i := range data {
+		data[i].ContainerRuntime = "docker"
+	}
+	result, err := renderWorkflow(data, "wf-main.gohtml", "")
+	require.NoError(t, err)
+
+	appconfigtest.AssertGolden(t, "bitbucket-pipelines.yml.golden", result.WorkflowContent)
+}
+
+func TestTargetBranchFilter(t *testing.T) {
+	assert.Equal(t, "main|release/*", targetBranchFilter(appconfig.WorkflowConfig{TriggerPullRequest: true, TriggerPullRequestBranches: []string{"main", "release/**"}}))
+	assert.Equal(t, "", targetBranchFilter(appconfig.WorkflowConfig{TriggerPullRequest: true, TriggerPullRequestBranches: []string{"main", "**"}}))
+	assert.Equal(t, "", targetBranchFilter(appconfig.WorkflowConfig{TriggerPush: true, TriggerPullRequestBranches: []string{"main"}}))
+}
@@ -3,6 +3,7 @@ package appcmd
 import (
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/cidverse/cid/pkg/app/appserver"
 	"github.com/cidverse/cid/pkg/lib/storage"
@@ -18,6 +19,10 @@ func ServerCmd() *cobra.Command {
 		Short:   "API that dynamically generates workflows for the requested repositories",
 		GroupID: "vcsapp",
 		Run: func(cmd *cobra.Command, args []string) {
+			cacheTTL, _ := cmd.Flags().GetDuration("cache-ttl")
+			refreshInterval, _ := cmd.Flags().GetDuration("refresh-interval")
+			metrics, _ := cmd.Flags().GetBool("metrics")
+
 			// platform
 			platform, err := vcsapp.GetPlatformFromEnvironment()
 			if err != nil {
@@ -44,9 +49,15 @@ func ServerCmd() *cobra.Command {
 
 			// listen
 			cfg := &appserver.Config{
-				Platform:   platform,
-				Addr:       appserver.DefaultServerAddr,
-				StorageApi: storageClient,
+				Platform:        platform,
+				Addr:            appserver.DefaultServerAddr,
+				StorageApi:      storageClient,
+				Token:           os.Getenv("CID_APP_SERVER_TOKEN"),
+				HMACSecret:      os.Getenv("CID_APP_SERVER_HMAC_SECRET"),
+				WebhookSecret:   os.Getenv("CID_APP_WEBHOOK_SECRET"),
+				CacheTTL:        cacheTTL,
+				RefreshInterval: refreshInterval,
+				Metrics:         metrics,
 			}
 			cfg.SetRepositories(repos)
 			srv := appserver.NewServer(cfg)
@@ -57,5 +68,9 @@ func ServerCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().Duration("cache-ttl", 24*time.Hour, "Max age of cached pipelines, 0 keeps them until they are invalidated by a webhook")
+	cmd.Flags().Duration("refresh-interval", 15*time.Minute, "Interval to refresh the repository list, 0 disables the refresh")
+	cmd.Flags().Bool("metrics", false, "Expose prometheus metrics on /metrics")
+
 	return cmd
 }
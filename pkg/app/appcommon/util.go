@@ -3,6 +3,7 @@ package appcommon
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/cidverse/cid/pkg/core/actionsdk"
 )
@@ -41,3 +42,12 @@ func FilterVCSEnvironments(environments map[string]VCSEnvironment, pattern strin
 
 	return filtered, nil
 }
+
+// TagPatternPrefix returns the literal prefix of a tag pattern, for platforms that only support simple wildcards, e.g. v for v[0-9]+.[0-9]+.[0-9]+
+func TagPatternPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `[]()*+?.\^$|{}`); i >= 0 {
+		return pattern[:i]
+	}
+
+	return pattern
+}
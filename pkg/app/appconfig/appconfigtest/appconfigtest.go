@@ -0,0 +1,91 @@
+package appconfigtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// CIDDependency is the cid release referenced by the rendered workflows
+var CIDDependency = dependency.Dependency{Id: "cidverse/cid", Type: "github", Version: "0.1.0", Hash: "0000000000000000000000000000000000000000000000000000000000000000", GPGFingerprint: "76A4948E69C62589C7B0AB84E414434DF5371FB6"}
+
+// WorkflowData returns the main, release, pull-request and nightly workflows of a go project with an approved helm deployment, using the platform specific dependencies
+func WorkflowData(dependencies ...dependency.Dependency) []appconfig.WorkflowData {
+	plan := plangenerate.Plan{
+		Name:   "main",
+		Stages: []string{"build", "test", "deploy"},
+		Steps: []plangenerate.Step{
+			{Name: "go-build", Slug: "go-build", Stage: "build"},
+			{Name: "go-test", Slug: "go-test", Stage: "test", RunAfter: []string{"go-build"}},
+			{Name: "go-lint", Slug: "go-lint", Stage: "test", RunAfter: []string{"go-build", "go-test"}, UsesOutputOf: []string{"go-test"}},
+			{Name: "approve helm-deploy", Slug: "approve-helm-deploy", Type: plangenerate.StepTypeGate, Stage: "deploy", Environment: "production", RunAfter: []string{"go-lint"}, Gates: "helm-deploy"},
+			{
+				Name:         "helm-deploy",
+				Slug:         "helm-deploy",
+				Stage:        "deploy",
+				Environment:  "production",
+				Approval:     true,
+				RunAfter:     []string{"approve-helm-deploy", "go-lint"},
+				UsesOutputOf: []string{"approve-helm-deploy", "go-build"},
+				Access: actionsdk.ActionAccess{
+					Environment: []actionsdk.ActionAccessEnv{
+						{Name: "KUBECONFIG_BASE64", Secret: true},
+						{Name: "HELM_NAMESPACE"},
+						{Name: "HELM_.*", Pattern: true, Secret: true},
+					},
+				},
+			},
+		},
+	}
+
+	workflowDependencies := map[string]dependency.Dependency{"pkg:github/cidverse/cid": CIDDependency}
+	for _, dep := range dependencies {
+		workflowDependencies[dep.AsPackageUrlNoVersion()] = dep
+	}
+
+	newWorkflow := func(name string, key string, wfConfig appconfig.WorkflowConfig) appconfig.WorkflowData {
+		return appconfig.WorkflowData{
+			Version:                      "0.1.0",
+			Name:                         name,
+			NameSlug:                     key,
+			JobTimeout:                   20,
+			WorkflowKey:                  key,
+			WorkflowConfig:               wfConfig,
+			Plan:                         plan,
+			WorkflowDependency:           workflowDependencies,
+			ReferencedWorkflowDependency: make(map[string]dependency.Dependency),
+		}
+	}
+
+	return []appconfig.WorkflowData{
+		newWorkflow("Main", "main", appconfig.WorkflowConfig{Type: "main", TriggerManual: true, TriggerPush: true, TriggerPushBranches: []string{"main", "develop"}}),
+		newWorkflow("Release", "release", appconfig.WorkflowConfig{Type: "release", TriggerPush: true, TriggerPushTags: []string{"v[0-9]+.[0-9]+.[0-9]+"}}),
+		newWorkflow("Pull Request", "pull-request", appconfig.WorkflowConfig{Type: "pull-request", TriggerPullRequest: true, TriggerPullRequestBranches: []string{"main"}}),
+		newWorkflow("Nightly", "nightly", appconfig.WorkflowConfig{Type: "nightly", TriggerManual: true, TriggerSchedule: true, TriggerScheduleCron: "0 3 * * 1", TriggerScheduleBranches: []string{"main"}}),
+	}
+}
+
+// AssertGolden compares the content with the golden file in testdata, the golden file is updated when the tests run with -update
+func AssertGolden(t *testing.T, name string, content string) {
+	t.Helper()
+
+	golden := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0755))
+		require.NoError(t, os.WriteFile(golden, []byte(content), 0644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), content)
+}
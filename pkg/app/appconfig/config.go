@@ -7,8 +7,11 @@ import (
 	"path/filepath"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/cidverse/cid/pkg/app/appcommon"
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
+	"github.com/cidverse/cid/pkg/core/config"
 	"github.com/cidverse/cid/pkg/core/plangenerate"
 	"github.com/cidverse/go-vcsapp/pkg/platform/api"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
@@ -52,10 +55,23 @@ func PreProcessWorkflowConfig(wfConfig WorkflowConfig, repo api.Repository) Work
 	return wfConfig
 }
 
-func DefaultWorkflowConfig(defaultBranch string, branches []string) *orderedmap.OrderedMap[string, WorkflowConfig] {
+// DefaultWorkflowConfig returns the default workflows of the branching convention.
+// GitFlow builds the default branch and develop, GitHubFlow and TrunkBased only build the default branch and deploy it to the main-* environments.
+// TrunkBased also releases from release line branches (e.g. release/1.4), which receive patch releases of that line.
+func DefaultWorkflowConfig(defaultBranch string, branches []string, branching config.BranchingConventionType) *orderedmap.OrderedMap[string, WorkflowConfig] {
 	triggerBranches := []string{defaultBranch}
-	if slices.Contains(branches, "develop") && !slices.Contains(triggerBranches, "develop") {
-		triggerBranches = append(triggerBranches, "develop")
+	var mainEnvironmentPattern string
+	var releaseBranches []string
+	switch branching {
+	case config.BranchingGitHubFlow:
+		mainEnvironmentPattern = "main-.*"
+	case config.BranchingTrunkBased:
+		mainEnvironmentPattern = "main-.*"
+		releaseBranches = ReleaseLineBranches(branches)
+	default:
+		if slices.Contains(branches, "develop") && !slices.Contains(triggerBranches, "develop") {
+			triggerBranches = append(triggerBranches, "develop")
+		}
 	}
 
 	workflowMap := orderedmap.New[string, WorkflowConfig]()
@@ -64,18 +80,20 @@ func DefaultWorkflowConfig(defaultBranch string, branches []string) *orderedmap.
 		TriggerManual:       true,
 		TriggerPush:         true,
 		TriggerPushBranches: triggerBranches,
+		EnvironmentPattern:  mainEnvironmentPattern,
 	})
 	workflowMap.Set("Release", WorkflowConfig{
-		Type:               "release",
-		TriggerManual:      true,
-		TriggerPush:        true,
-		TriggerPushTags:    []string{"v[0-9]+.[0-9]+.[0-9]+"}, // try to use patterns that are compatible with regex (gitlab) and glob (github)
-		EnvironmentPattern: "release-.*",
+		Type:                "release",
+		TriggerManual:       true,
+		TriggerPush:         true,
+		TriggerPushBranches: releaseBranches,
+		TriggerPushTags:     []string{"v[0-9]+.[0-9]+.[0-9]+"}, // try to use patterns that are compatible with regex (gitlab) and glob (github)
+		EnvironmentPattern:  "release-.*",
 	})
 	workflowMap.Set("Pull Request", WorkflowConfig{
 		Type:                       "pull-request",
 		TriggerPullRequest:         true,
-		TriggerPullRequestBranches: triggerBranches,
+		TriggerPullRequestBranches: append(slices.Clone(triggerBranches), releaseBranches...),
 		EnvironmentPattern:         "pr-.*",
 	})
 	workflowMap.Set("Nightly", WorkflowConfig{
@@ -90,6 +108,19 @@ func DefaultWorkflowConfig(defaultBranch string, branches []string) *orderedmap.
 	return workflowMap
 }
 
+// ReleaseLineBranches returns the release line branches of the repository, e.g. release/1.4 - the branches are listed by name, as not all platforms support branch patterns
+func ReleaseLineBranches(branches []string) []string {
+	var result []string
+	for _, branch := range branches {
+		if _, ok := commitanalyser.ParseReleaseLine(branch); ok && strings.HasPrefix(branch, "release/") {
+			result = append(result, branch)
+		}
+	}
+	slices.Sort(result)
+
+	return result
+}
+
 // PersistPlan saves the workflow plan to a file in the specified project directory.
 func PersistPlan(plan plangenerate.Plan, file string) error {
 	err := os.MkdirAll(filepath.Dir(file), 0755)
@@ -63,6 +63,8 @@ func GenerateWorkflowData(cidContext *context.CIDContext, taskContext taskcommon
 		Environments: environments,
 		PinVersions:  false,
 		WorkflowType: wfConfig.Type,
+		Workflow:     cidContext.Config.Workflow,
+		AllChanges:   true, // generated workflows run for all changes
 	})
 	if err != nil {
 		return WorkflowData{}, err
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/cidverse/cid/pkg/app/appazure"
+	"github.com/cidverse/cid/pkg/app/appbitbucket"
+	"github.com/cidverse/cid/pkg/app/appgitea"
 	"github.com/cidverse/cid/pkg/app/appgithub"
 	"github.com/cidverse/cid/pkg/app/appgitlab"
 	"github.com/cidverse/cid/pkg/app/apptask"
@@ -35,6 +38,12 @@ func ProcessRepository(platform api.Platform, repo api.Repository, dryRun bool)
 		return apptask.WorkflowTaskResult{}, appgithub.GitHubWorkflowTask(taskContext)
 	} else if platform.Slug() == "gitlab" {
 		return appgitlab.GitLabWorkflowTask(taskContext, dryRun)
+	} else if platform.Slug() == "gitea" || platform.Slug() == "forgejo" {
+		return appgitea.GiteaWorkflowTask(taskContext, dryRun)
+	} else if platform.Slug() == "azuredevops" {
+		return appazure.AzureWorkflowTask(taskContext, dryRun)
+	} else if platform.Slug() == "bitbucket" {
+		return appbitbucket.BitbucketWorkflowTask(taskContext, dryRun)
 	} else {
 		return apptask.WorkflowTaskResult{}, fmt.Errorf("platform %s not supported", platform.Slug())
 	}
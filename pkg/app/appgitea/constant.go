@@ -0,0 +1,58 @@
+package appgitea
+
+import (
+	"github.com/cidverse/cid/pkg/common/dependency"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+)
+
+var mergeRequestFooter = "This PR has been generated by the CID Workflow App."
+
+var giteaNetworkAllowList = []actionsdk.ActionAccessNetwork{
+	// Forgejo Actions
+	{Host: "code.forgejo.org:443"},
+	{Host: "data.forgejo.org:443"},
+	// GitHub Platform
+	{Host: "github.com:443"},
+	{Host: "api.github.com:443"},
+	{Host: "objects.githubusercontent.com:443"},
+	{Host: "release-assets.githubusercontent.com:443"},
+}
+
+var giteaWorkflowDependencyList = []dependency.Dependency{
+	// tools
+	{
+		Id:             "cidverse/cid",
+		Type:           "github",
+		Version:        constants.Version,
+		Hash:           constants.BinaryHash,
+		GPGFingerprint: "76A4948E69C62589C7B0AB84E414434DF5371FB6",
+	},
+	// actions, referenced by full url to work with both the Gitea and Forgejo default actions url
+	{
+		Id:         "actions/checkout",
+		Type:       "forgejo-action",
+		Version:    "v4",
+		Repository: "https://code.forgejo.org",
+	},
+	{
+		Id:         "actions/download-artifact",
+		Type:       "forgejo-action",
+		Version:    "v3",
+		Repository: "https://code.forgejo.org",
+	},
+	{
+		Id:         "actions/upload-artifact",
+		Type:       "forgejo-action",
+		Version:    "v3",
+		Repository: "https://code.forgejo.org",
+	},
+}
+
+var giteaWorkflowDependencies = func() map[string]dependency.Dependency {
+	m := make(map[string]dependency.Dependency, len(giteaWorkflowDependencyList))
+	for _, dep := range giteaWorkflowDependencyList {
+		m[dep.AsPackageUrlNoVersion()] = dep
+	}
+	return m
+}()
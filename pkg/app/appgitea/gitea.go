@@ -0,0 +1,72 @@
+package appgitea
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cidverse/cid/pkg/app/appcommon"
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/app/apptask"
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/core/config"
+	"github.com/cidverse/go-vcsapp/pkg/task/taskcommon"
+	"github.com/gosimple/slug"
+)
+
+// GiteaWorkflowTask generates project-specific Gitea / Forgejo Actions workflow files and creates a pull request
+//
+// Links of interest:
+// https://forgejo.org/docs/latest/user/actions/ for the differences to GitHub Actions
+// https://docs.gitea.com/usage/actions/comparison for the differences to GitHub Actions
+func GiteaWorkflowTask(taskContext taskcommon.TaskContext, dryRun bool) (apptask.WorkflowTaskResult, error) {
+	// forgejo prefers .forgejo/workflows, gitea only reads .gitea/workflows
+	workflowDir := filepath.Join(".gitea", "workflows")
+	if taskContext.Platform.Slug() == "forgejo" {
+		workflowDir = filepath.Join(".forgejo", "workflows")
+	}
+
+	return apptask.WorkflowTask(taskContext, apptask.PlatformWorkflowTaskOptions{
+		LoadConfig: func(taskContext taskcommon.TaskContext, cfg *config.CIDConfig) (appconfig.Config, error) {
+			return appconfig.Config{
+				Version:          constants.Version,
+				VersionHash:      constants.BinaryHash,
+				JobTimeout:       20,
+				RunnerTags:       []string{"ubuntu-latest"},
+				EgressPolicy:     "audit",
+				ContainerRuntime: "podman",
+				Workflows:        appconfig.DefaultWorkflowConfig(taskContext.Repository.DefaultBranch, taskContext.Repository.Branches, cfg.Conventions.Branching),
+			}, nil
+		},
+		RenderWorkflow: func(workflowState *appconfig.WorkflowState, data apptask.PlatformWorkflowData, template string, targetDir string) (map[string]string, error) {
+			files := make(map[string]string)
+
+			for pair := data.Conf.Workflows.Newest(); pair != nil; pair = pair.Prev() {
+				wfKey := pair.Key
+				wfConfig := pair.Value
+
+				filteredEnvs, wfErr := appcommon.FilterVCSEnvironments(data.Environments, wfConfig.EnvironmentPattern)
+				if wfErr != nil {
+					return nil, fmt.Errorf("failed to filter workflow environments [%s]: %w", wfKey, wfErr)
+				}
+
+				wtd, wfErr := appconfig.GenerateWorkflowData(data.CidContext, taskContext, data.Conf, wfKey, wfConfig, data.ProjectVariables, filteredEnvs, giteaWorkflowDependencies, giteaNetworkAllowList)
+				if wfErr != nil {
+					return nil, fmt.Errorf("failed to generate workflow template [%s]: %w", wfKey, wfErr)
+				}
+
+				workflowFile := filepath.Join(workflowDir, fmt.Sprintf("cid-%s.yml", slug.Make(wfKey)))
+				wfResult, wfErr := renderWorkflow(&wtd, data.Conf.RunnerTags, template, filepath.Join(targetDir, workflowFile))
+				if wfErr != nil {
+					return nil, fmt.Errorf("failed to render workflow [%s]: %w", wfKey, wfErr)
+				}
+
+				files[filepath.ToSlash(workflowFile)] = wfResult.WorkflowContent
+				workflowState.Workflows.Set(wfKey, &wtd)
+			}
+
+			return files, nil
+		},
+		WorkflowStatePath:  filepath.Join(taskContext.Directory, ".cid", "state-gitea.json"),
+		MergeRequestFooter: mergeRequestFooter,
+	}, dryRun)
+}
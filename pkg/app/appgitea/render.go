@@ -0,0 +1,59 @@
+package appgitea
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/cidverse/cid/pkg/app/appconfig"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/go-vcsapp/pkg/vcsapp"
+)
+
+//go:embed templates/*
+var embedFS embed.FS
+
+type TemplateData struct {
+	*appconfig.WorkflowData
+	RunnerTags []string `json:"runner_tags,omitempty"`
+}
+
+// HasApprovalGates returns true if the plan contains approval gates, gitea can only approve them by dispatching the workflow
+func (t TemplateData) HasApprovalGates() bool {
+	return slices.ContainsFunc(t.Plan.Steps, func(step plangenerate.Step) bool {
+		return step.IsGate()
+	})
+}
+
+type RenderWorkflowResult struct {
+	WorkflowContent string
+}
+
+// renderWorkflow renders the workflow template and writes it to the output file
+func renderWorkflow(data *appconfig.WorkflowData, runnerTags []string, templateFile string, outputFile string) (RenderWorkflowResult, error) {
+	content, err := embedFS.ReadFile(path.Join("templates", templateFile))
+	if err != nil {
+		return RenderWorkflowResult{}, fmt.Errorf("failed to read workflow template %s: %w", templateFile, err)
+	}
+	template, err := vcsapp.Render(string(content), &TemplateData{WorkflowData: data, RunnerTags: runnerTags})
+	if err != nil {
+		return RenderWorkflowResult{}, fmt.Errorf("failed to render template %s: %w", templateFile, err)
+	}
+
+	// write workflow file
+	if outputFile != "" {
+		err = os.MkdirAll(filepath.Dir(outputFile), os.ModePerm)
+		if err != nil {
+			return RenderWorkflowResult{}, fmt.Errorf("failed to create workflow file parent directory: %w", err)
+		}
+		err = os.WriteFile(outputFile, template, 0644)
+		if err != nil {
+			return RenderWorkflowResult{}, fmt.Errorf("failed to create workflow file: %w", err)
+		}
+	}
+
+	return RenderWorkflowResult{WorkflowContent: string(template)}, nil
+}
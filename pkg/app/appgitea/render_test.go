@@ -0,0 +1,17 @@
+package appgitea
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/app/appconfig/appconfigtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWorkflow(t *testing.T) {
+	for _, data := range appconfigtest.WorkflowData(giteaWorkflowDependencyList[1:]...) {
+		result, err := renderWorkflow(&data, []string{"ubuntu-latest"}, "wf-main.gohtml", "")
+		require.NoError(t, err)
+
+		appconfigtest.AssertGolden(t, "cid-"+data.WorkflowKey+".yml.golden", result.WorkflowContent)
+	}
+}
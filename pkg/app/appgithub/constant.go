@@ -6,6 +6,9 @@ import (
 	"github.com/cidverse/cid/pkg/core/actionsdk"
 )
 
+// approvalEnvironment protects the approval gates of steps without environment, it must have required reviewers
+const approvalEnvironment = "approval"
+
 var mergeRequestFooter = "This PR has been generated by the [CID GitHub App](https://github.com/apps/cid-workflow)."
 
 var githubNetworkAllowList = []actionsdk.ActionAccessNetwork{
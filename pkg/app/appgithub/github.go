@@ -12,6 +12,7 @@ import (
 	"github.com/cidverse/cid/pkg/app/apptemplate"
 	"github.com/cidverse/cid/pkg/constants"
 	"github.com/cidverse/cid/pkg/context"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
 	"github.com/cidverse/go-vcsapp/pkg/task/simpletask"
 	"github.com/cidverse/go-vcsapp/pkg/task/taskcommon"
 	"github.com/gosimple/slug"
@@ -34,7 +35,6 @@ func GitHubWorkflowTask(taskContext taskcommon.TaskContext) error {
 		RunnerTags:       []string{},
 		EgressPolicy:     "block",
 		ContainerRuntime: "podman",
-		Workflows:        appconfig.DefaultWorkflowConfig(taskContext.Repository.DefaultBranch, taskContext.Repository.Branches),
 	}
 	content, err := taskContext.Platform.FileContent(taskContext.Repository, taskContext.Repository.DefaultBranch, appcommon.ConfigFileName)
 	if err == nil {
@@ -63,6 +63,9 @@ func GitHubWorkflowTask(taskContext taskcommon.TaskContext) error {
 	if err != nil {
 		return err
 	}
+	if conf.Workflows == nil {
+		conf.Workflows = appconfig.DefaultWorkflowConfig(taskContext.Repository.DefaultBranch, taskContext.Repository.Branches, cid.Config.Conventions.Branching)
+	}
 
 	// vars
 	vars, err := taskContext.Platform.Variables(taskContext.Repository)
@@ -105,6 +108,7 @@ func GitHubWorkflowTask(taskContext taskcommon.TaskContext) error {
 			if wfErr != nil {
 				return fmt.Errorf("failed to generate workflow template [%s]: %w", wfKey, wfErr)
 			}
+			warnApprovalEnvironments(wfKey, workflowTemplateData.Plan, environments)
 
 			_, wfErr = renderWorkflow(&workflowTemplateData, "wf-main.gohtml", filepath.Join(taskContext.Directory, fmt.Sprintf(".github/workflows/cid-%s.yml", slug.Make(wfKey))))
 			if wfErr != nil {
@@ -153,3 +157,22 @@ func GitHubWorkflowTask(taskContext taskcommon.TaskContext) error {
 
 	return nil
 }
+
+// warnApprovalEnvironments warns if the environment of an approval gate does not exist, GitHub creates it without required reviewers on the first run and the gate does not wait for an approval
+//
+// Gates of steps without environment use the approval environment, the required reviewers have to be configured in the repository settings.
+func warnApprovalEnvironments(wfKey string, plan plangenerate.Plan, environments map[string]appcommon.VCSEnvironment) {
+	for _, step := range plan.Steps {
+		if !step.IsGate() {
+			continue
+		}
+
+		name := step.Environment
+		if name == "" {
+			name = approvalEnvironment
+		}
+		if _, ok := environments[name]; !ok {
+			slog.With("workflow", wfKey).With("step", step.Name).With("environment", name).Warn("approval gate environment does not exist, create it with required reviewers in the repository settings")
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/cidverse/cid/pkg/app/apptask"
 	"github.com/cidverse/cid/pkg/constants"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/config"
 	"github.com/cidverse/go-vcsapp/pkg/task/taskcommon"
 )
 
@@ -18,7 +19,7 @@ import (
 // https://gitlab.com/gitlab-org/gitlab/-/tree/master/doc/ci/runners/hosted_runners?ref_type=heads for all available runner tags
 func GitLabWorkflowTask(taskContext taskcommon.TaskContext, dryRun bool) (apptask.WorkflowTaskResult, error) {
 	return apptask.WorkflowTask(taskContext, apptask.PlatformWorkflowTaskOptions{
-		LoadConfig: func(taskContext taskcommon.TaskContext) (appconfig.Config, error) {
+		LoadConfig: func(taskContext taskcommon.TaskContext, cfg *config.CIDConfig) (appconfig.Config, error) {
 			return appconfig.Config{
 				Version:          constants.Version,
 				VersionHash:      constants.BinaryHash,
@@ -26,10 +27,10 @@ func GitLabWorkflowTask(taskContext taskcommon.TaskContext, dryRun bool) (apptas
 				RunnerTags:       []string{"saas-linux-small-amd64"},
 				EgressPolicy:     "block",
 				ContainerRuntime: "podman",
-				Workflows:        appconfig.DefaultWorkflowConfig(taskContext.Repository.DefaultBranch, taskContext.Repository.Branches),
+				Workflows:        appconfig.DefaultWorkflowConfig(taskContext.Repository.DefaultBranch, taskContext.Repository.Branches, cfg.Conventions.Branching),
 			}, nil
 		},
-		RenderWorkflow: func(workflowState *appconfig.WorkflowState, data apptask.PlatformWorkflowData, template string, targetPath string) (string, error) {
+		RenderWorkflow: func(workflowState *appconfig.WorkflowState, data apptask.PlatformWorkflowData, template string, targetDir string) (map[string]string, error) {
 			var workflowTemplateData []appconfig.WorkflowData
 
 			for pair := data.Conf.Workflows.Newest(); pair != nil; pair = pair.Prev() {
@@ -38,18 +39,20 @@ func GitLabWorkflowTask(taskContext taskcommon.TaskContext, dryRun bool) (apptas
 
 				filteredEnvs, wfErr := appcommon.FilterVCSEnvironments(data.Environments, wfConfig.EnvironmentPattern)
 				if wfErr != nil {
-					return "", fmt.Errorf("failed to filter workflow environments [%s]: %w", wfKey, wfErr)
+					return nil, fmt.Errorf("failed to filter workflow environments [%s]: %w", wfKey, wfErr)
 				}
 
 				wtd, wfErr := appconfig.GenerateWorkflowData(data.CidContext, taskContext, data.Conf, wfKey, wfConfig, data.ProjectVariables, filteredEnvs, gitlabWorkflowDependencies, gitlabNetworkAllowList)
 				if wfErr != nil {
-					return "", fmt.Errorf("failed to generate workflow template [%s]: %w", wfKey, wfErr)
+					return nil, fmt.Errorf("failed to generate workflow template [%s]: %w", wfKey, wfErr)
 				}
 
-				for i := range wtd.Plan.Steps { // TD-001: add gitlab-sarif-converter to steps that produce SARIF reports, due to automatic report conversion for GitLab
+				for i := range wtd.Plan.Steps { // TD-001: steps that produce SARIF reports also produce GitLab Code Quality and SAST reports, due to automatic report conversion for GitLab
 					if wtd.Plan.Steps[i].Outputs.ContainsArtifactWithTypeAndFormat("report", "sarif") {
-						wtd.Plan.Steps[i].Access.Executables = append(wtd.Plan.Steps[i].Access.Executables, actionsdk.ActionAccessExecutable{Name: "gitlab-sarif-converter"})
-						wtd.Plan.Steps[i].Outputs.Artifacts = append(wtd.Plan.Steps[i].Outputs.Artifacts, actionsdk.ActionArtifactType{Type: "report", Format: "gl-codequality"})
+						wtd.Plan.Steps[i].Outputs.Artifacts = append(wtd.Plan.Steps[i].Outputs.Artifacts,
+							actionsdk.ActionArtifactType{Type: "report", Format: "gl-codequality"},
+							actionsdk.ActionArtifactType{Type: "report", Format: "gl-sast"},
+						)
 					}
 				}
 
@@ -58,12 +61,12 @@ func GitLabWorkflowTask(taskContext taskcommon.TaskContext, dryRun bool) (apptas
 			}
 
 			// render workflow
-			wfResult, wfErr := renderWorkflow(workflowTemplateData, data.Conf.RunnerTags, "wf-main.gohtml", filepath.Join(taskContext.Directory, ".gitlab-ci.yml"))
+			wfResult, wfErr := renderWorkflow(workflowTemplateData, data.Conf.RunnerTags, "wf-main.gohtml", filepath.Join(targetDir, ".gitlab-ci.yml"))
 			if wfErr != nil {
-				return "", fmt.Errorf("failed to render [gitlab-ci.yml]: %w", wfErr)
+				return nil, fmt.Errorf("failed to render [gitlab-ci.yml]: %w", wfErr)
 			}
 
-			return wfResult.WorkflowContent, nil
+			return map[string]string{".gitlab-ci.yml": wfResult.WorkflowContent}, nil
 		},
 		WorkflowStatePath:  filepath.Join(taskContext.Directory, ".cid", "state-gitlab.json"),
 		MergeRequestFooter: mergeRequestFooter,
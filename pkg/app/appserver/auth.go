@@ -0,0 +1,78 @@
+package appserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	headerSignature = "X-CID-Signature"
+	headerTimestamp = "X-CID-Timestamp"
+	maxSignatureAge = 5 * time.Minute
+)
+
+// authMiddleware requires either the bearer token or a valid request signature, requests are not authenticated if neither is configured
+func authMiddleware(token string, hmacSecret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if token == "" && hmacSecret == "" {
+				return next(c)
+			}
+
+			req := c.Request()
+			if token != "" {
+				if bearer, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+					return next(c)
+				}
+			}
+			if hmacSecret != "" && req.Header.Get(headerSignature) != "" {
+				if verifyRequestSignature(hmacSecret, req.Method, req.URL.RequestURI(), req.Header.Get(headerTimestamp), req.Header.Get(headerSignature), time.Now()) {
+					return next(c)
+				}
+			}
+
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid or missing credentials")
+		}
+	}
+}
+
+// SignRequest returns the signature of a request, the signed message is <timestamp>\n<method>\n<request-uri>
+func SignRequest(secret string, method string, requestURI string, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + requestURI))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyRequestSignature checks the signature and rejects timestamps that are older than maxSignatureAge to prevent replays
+func verifyRequestSignature(secret string, method string, requestURI string, timestamp string, signature string, now time.Time) bool {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > maxSignatureAge || age < -maxSignatureAge {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(SignRequest(secret, method, requestURI, timestamp)))
+}
+
+// verifyGitHubSignature checks the X-Hub-Signature-256 header of a GitHub webhook delivery
+func verifyGitHubSignature(secret string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal([]byte(signature), []byte("sha256="+hex.EncodeToString(mac.Sum(nil))))
+}
+
+// verifyGitLabToken checks the X-Gitlab-Token header of a GitLab webhook delivery
+func verifyGitLabToken(secret string, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
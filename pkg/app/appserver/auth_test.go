@@ -0,0 +1,57 @@
+package appserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	e := echo.New()
+	e.GET("/v1/pipeline", func(c *echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, authMiddleware("my-token", "my-secret"))
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	oldTimestamp := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "missing credentials", status: http.StatusUnauthorized},
+		{name: "valid token", headers: map[string]string{"Authorization": "Bearer my-token"}, status: http.StatusOK},
+		{name: "invalid token", headers: map[string]string{"Authorization": "Bearer other-token"}, status: http.StatusUnauthorized},
+		{name: "valid signature", headers: map[string]string{headerTimestamp: timestamp, headerSignature: SignRequest("my-secret", "GET", "/v1/pipeline?project_id=1", timestamp)}, status: http.StatusOK},
+		{name: "signature of other request", headers: map[string]string{headerTimestamp: timestamp, headerSignature: SignRequest("my-secret", "GET", "/v1/pipeline?project_id=2", timestamp)}, status: http.StatusUnauthorized},
+		{name: "expired signature", headers: map[string]string{headerTimestamp: oldTimestamp, headerSignature: SignRequest("my-secret", "GET", "/v1/pipeline?project_id=1", oldTimestamp)}, status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/pipeline?project_id=1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddlewareDisabled(t *testing.T) {
+	e := echo.New()
+	e.GET("/v1/pipeline", func(c *echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, authMiddleware("", ""))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/pipeline", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,117 @@
+package appserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/pkg/app/appcore"
+	"github.com/cidverse/cid/pkg/lib/storage"
+	"github.com/cidverse/cid/pkg/lib/telemetry"
+	"github.com/cidverse/go-vcsapp/pkg/platform/api"
+)
+
+// objectName returns the storage object name of the cached pipeline of a project
+func (s *Server) objectName(projectId int64) string {
+	return fmt.Sprintf("%s/%d.json", strings.ToLower(s.cfg.Platform.Name()), projectId)
+}
+
+// loadCachedPipeline returns the cached pipeline, nil if the pipeline is not cached or expired
+func (s *Server) loadCachedPipeline(ctx context.Context, projectId int64) *PipelineArtifact {
+	if s.cfg.StorageApi == nil {
+		slog.Debug("Storage API not configured, skipping retrieval from cache")
+		return nil
+	}
+
+	object, err := s.cfg.StorageApi.GetObject(ctx, storage.DefaultBucket, s.objectName(projectId))
+	if err != nil {
+		return nil
+	}
+	objectBytes, err := io.ReadAll(object)
+	if err != nil {
+		slog.Debug("GetObject Read Error: " + err.Error())
+		return nil
+	}
+
+	var artifact PipelineArtifact
+	err = json.Unmarshal(objectBytes, &artifact)
+	if err != nil {
+		slog.Error("GetObject Unmarshal Error: " + err.Error())
+		return nil
+	}
+	if s.cfg.CacheTTL > 0 && time.Since(artifact.GeneratedAt) > s.cfg.CacheTTL {
+		slog.With("project_id", projectId).Debug("Cached pipeline expired")
+		return nil
+	}
+	if artifact.ETag == "" {
+		artifact.ETag = pipelineETag(artifact.WorkflowContent)
+	}
+
+	return &artifact
+}
+
+// storePipeline stores the pipeline in the cache, if storage is configured
+func (s *Server) storePipeline(ctx context.Context, artifact *PipelineArtifact) {
+	if s.cfg.StorageApi == nil {
+		return
+	}
+
+	data, err := json.Marshal(artifact)
+	if err != nil {
+		slog.Error("JSON Marshal Error: " + err.Error())
+		return
+	}
+
+	err = s.cfg.StorageApi.PutObject(ctx, storage.DefaultBucket, s.objectName(artifact.ProjectID), bytes.NewReader(data), "application/json")
+	if err != nil {
+		slog.Error("PutObject Error: " + err.Error())
+	}
+}
+
+// invalidatePipeline removes the cached pipeline of a project
+func (s *Server) invalidatePipeline(ctx context.Context, projectId int64) {
+	if s.cfg.StorageApi == nil {
+		return
+	}
+
+	err := s.cfg.StorageApi.RemoveObject(ctx, storage.DefaultBucket, s.objectName(projectId))
+	if err != nil {
+		slog.With("project_id", projectId).With("err", err).Warn("Failed to invalidate cached pipeline")
+	}
+}
+
+// generatePipeline renders the pipeline of a repository and stores it in the cache
+func (s *Server) generatePipeline(ctx context.Context, repo api.Repository) (*PipelineArtifact, error) {
+	slog.With("namespace", repo.Namespace).With("repo", repo.Name).With("platform", s.cfg.Platform.Name()).Info("running workflow update task")
+	pipelineResult, err := appcore.ProcessRepository(s.cfg.Platform, repo, true)
+	telemetry.PipelineGenerations.WithLabelValues(strings.ToLower(s.cfg.Platform.Name()), telemetry.Result(err)).Inc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to process repository %s/%s: %w", repo.Namespace, repo.Name, err)
+	}
+
+	artifact := &PipelineArtifact{
+		ProjectID:       repo.Id,
+		RepoPath:        repo.Path,
+		GeneratedAt:     time.Now(),
+		ETag:            pipelineETag(pipelineResult.WorkflowContent),
+		WorkflowState:   pipelineResult.WorkflowState,
+		WorkflowContent: pipelineResult.WorkflowContent,
+	}
+	s.storePipeline(ctx, artifact)
+
+	return artifact, nil
+}
+
+// pipelineETag returns the etag of the generated files, json encoding sorts the map keys
+func pipelineETag(content map[string]string) string {
+	data, _ := json.Marshal(content)
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
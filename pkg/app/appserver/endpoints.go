@@ -1,19 +1,14 @@
 package appserver
 
 import (
-	"bytes"
-	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/cidverse/cid/pkg/app/appconfig"
-	"github.com/cidverse/cid/pkg/app/appcore"
+	"github.com/cidverse/cid/pkg/lib/telemetry"
 	"github.com/labstack/echo/v5"
 )
 
@@ -30,11 +25,12 @@ type PipelineArtifact struct {
 	ProjectID       int64                    `json:"project_id"`
 	RepoPath        string                   `json:"repo_path"`
 	GeneratedAt     time.Time                `json:"generated_at"`
+	ETag            string                   `json:"etag,omitempty"`
 	WorkflowState   *appconfig.WorkflowState `json:"workflow_state"`
 	WorkflowContent map[string]string        `json:"workflow_content"`
 }
 
-// pipelineGenerator
+// pipelineGenerator returns the cached pipeline or generates it, clients can use the etag to skip unchanged pipelines
 func (s *Server) pipelineGenerator(c *echo.Context) error {
 	projectIdStr := c.QueryParam("project_id")
 	projectId, err := strconv.ParseInt(projectIdStr, 10, 64)
@@ -43,51 +39,39 @@ func (s *Server) pipelineGenerator(c *echo.Context) error {
 	}
 	file := c.QueryParam("file")
 
-	// storage
-	bucketName := "cidverse-cid"
-	objectName := fmt.Sprintf("%s/%d.json", strings.ToLower(s.cfg.Platform.Name()), projectId)
-	var responseData *PipelineArtifact
-
 	// check storage for cached artifact
-	if s.cfg.StorageApi != nil {
-		object, err := s.cfg.StorageApi.GetObject(context.Background(), bucketName, objectName)
-		if err == nil {
-			objectBytes, err := io.ReadAll(object)
-			if err != nil {
-				slog.Error("GetObject Read Error: " + err.Error())
-			}
-			err = json.Unmarshal(objectBytes, &responseData)
-			if err != nil {
-				slog.Error("GetObject Unmarshal Error: " + err.Error())
-			}
-		}
+	ctx := c.Request().Context()
+	responseData := s.loadCachedPipeline(ctx, projectId)
+	if responseData != nil {
+		telemetry.PipelineCache.WithLabelValues("hit").Inc()
 	} else {
-		slog.Debug("Storage API not configured, skipping retrieval from cache")
-	}
+		telemetry.PipelineCache.WithLabelValues("miss").Inc()
 
-	if responseData == nil {
-		// Lookup repo
-		platform := s.cfg.Platform
-		repo, ok := s.cfg.RepositoriesByID[projectId]
+		// lookup repo
+		repo, ok := s.cfg.Repository(projectId)
 		if !ok {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("repository with ID %d not found", projectId))
 		}
 
 		// render pipeline
-		slog.With("namespace", repo.Namespace).With("repo", repo.Name).With("platform", platform.Name()).Info("running workflow update task")
-		pipelineResult, err := appcore.ProcessRepository(platform, repo, true)
+		responseData, err = s.generatePipeline(ctx, repo)
 		if err != nil {
 			slog.With("repository", fmt.Sprintf("%s/%s", repo.Namespace, repo.Name)).With("err", err).Warn("Failed to process repository")
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to generate pipeline")
 		}
+	}
 
-		// prepare response
-		responseData = &PipelineArtifact{
-			ProjectID:       projectId,
-			RepoPath:        repo.Path,
-			GeneratedAt:     time.Now(),
-			WorkflowState:   pipelineResult.WorkflowState,
-			WorkflowContent: pipelineResult.WorkflowContent,
-		}
+	// cache headers
+	etag := `"` + responseData.ETag + `"`
+	c.Response().Header().Set("ETag", etag)
+	if s.cfg.CacheTTL > 0 {
+		maxAge := max(int((s.cfg.CacheTTL - time.Since(responseData.GeneratedAt)).Seconds()), 0)
+		c.Response().Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", maxAge))
+	} else {
+		c.Response().Header().Set("Cache-Control", "no-cache")
+	}
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
 	}
 
 	// return file content
@@ -99,18 +83,5 @@ func (s *Server) pipelineGenerator(c *echo.Context) error {
 		}
 	}
 
-	// store result, if storage api is provided
-	if s.cfg.StorageApi != nil {
-		data, err := json.Marshal(responseData)
-		if err != nil {
-			slog.Error("JSON Marshal Error: " + err.Error())
-		}
-
-		err = s.cfg.StorageApi.PutObject(context.Background(), bucketName, objectName, bytes.NewReader(data), "application/json")
-		if err != nil {
-			slog.Error("PutObject Error: " + err.Error())
-		}
-	}
-
 	return c.JSON(http.StatusOK, responseData)
 }
@@ -8,9 +8,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+	"github.com/cidverse/cid/pkg/lib/telemetry"
 	"github.com/cidverse/go-vcsapp/pkg/platform/api"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
@@ -18,15 +21,32 @@ import (
 
 const DefaultServerAddr = "0.0.0.0:9056"
 
+const (
+	webhookWorkers   = 2   // webhookWorkers limits the concurrent pipeline generations triggered by webhooks
+	webhookQueueSize = 100 // webhookQueueSize limits the pending webhook events, further deliveries are rejected until the queue drains
+)
+
 type Config struct {
 	Platform         api.Platform
 	Repositories     []api.Repository
 	RepositoriesByID map[int64]api.Repository
 	Addr             string
 	StorageApi       storageapi.API
+
+	Token           string        // Token is required as bearer token to access the api, authentication is disabled if neither Token nor HMACSecret are set
+	HMACSecret      string        // HMACSecret can be used to sign requests instead of passing the token, see verifyRequestSignature
+	WebhookSecret   string        // WebhookSecret is used to verify GitHub and GitLab webhook deliveries
+	CacheTTL        time.Duration // CacheTTL is the max age of cached pipelines, 0 keeps them until they are invalidated by a webhook
+	RefreshInterval time.Duration // RefreshInterval defines how often the repository list is refreshed, 0 disables the refresh
+	Metrics         bool          // Metrics exposes the prometheus metrics on /metrics
+
+	mu sync.RWMutex
 }
 
 func (c *Config) SetRepositories(repos []api.Repository) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.Repositories = repos
 	c.RepositoriesByID = make(map[int64]api.Repository, len(repos))
 	for _, repo := range repos {
@@ -34,15 +54,41 @@ func (c *Config) SetRepositories(repos []api.Repository) {
 	}
 }
 
+// Repository returns the repository with the given id
+func (c *Config) Repository(id int64) (api.Repository, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	repo, ok := c.RepositoriesByID[id]
+	return repo, ok
+}
+
+// RefreshRepositories reloads the repository list from the platform
+func (c *Config) RefreshRepositories() error {
+	repos, err := c.Platform.Repositories(api.RepositoryListOpts{
+		IncludeBranches:   false,
+		IncludeCommitHash: false,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	c.SetRepositories(repos)
+	slog.With("repos", len(repos)).Debug("Refreshed repositories")
+	return nil
+}
+
 type Server struct {
-	cfg *Config
-	e   *echo.Echo
+	cfg          *Config
+	e            *echo.Echo
+	webhookQueue chan webhookEvent
 }
 
 func NewServer(cfg *Config) *Server {
 	s := &Server{
-		cfg: cfg,
-		e:   echo.New(),
+		cfg:          cfg,
+		e:            echo.New(),
+		webhookQueue: make(chan webhookEvent, webhookQueueSize),
 	}
 
 	// middleware
@@ -51,12 +97,27 @@ func NewServer(cfg *Config) *Server {
 
 	// endpoints
 	s.e.GET("/health", s.healthCheck)
-	s.e.GET("/v1/pipeline", s.pipelineGenerator)
+	s.e.GET("/v1/pipeline", s.pipelineGenerator, authMiddleware(cfg.Token, cfg.HMACSecret))
+	s.e.POST("/v1/webhook", s.webhook)
+	if cfg.Metrics {
+		s.e.GET("/metrics", echo.WrapHandler(telemetry.Handler()))
+	}
+
+	if cfg.Token == "" && cfg.HMACSecret == "" {
+		slog.Warn("No token or hmac secret configured, the pipeline api is not protected")
+	}
 
 	return s
 }
 
 func (s *Server) start(ctx context.Context) error {
+	if s.cfg.RefreshInterval > 0 {
+		go s.refreshRepositories(ctx)
+	}
+	for i := 0; i < webhookWorkers; i++ {
+		go s.processWebhookEvents(ctx)
+	}
+
 	serverErrChan := make(chan error, 1)
 	go func() {
 		slog.Info("Starting server", "addr", s.cfg.Addr)
@@ -84,6 +145,23 @@ func (s *Server) start(ctx context.Context) error {
 	}
 }
 
+// refreshRepositories periodically reloads the repository list, until the context is cancelled
+func (s *Server) refreshRepositories(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.cfg.RefreshRepositories(); err != nil {
+				slog.With("err", err).Warn("Failed to refresh repositories")
+			}
+		}
+	}
+}
+
 // ListenAndServe starts the server and listens for signals to gracefully shut down.
 func (s *Server) ListenAndServe() error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -0,0 +1,163 @@
+package appserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v5"
+)
+
+var (
+	ErrWebhookSignature   = errors.New("invalid webhook signature")
+	ErrWebhookUnsupported = errors.New("unsupported webhook event")
+)
+
+// gitlabRepositoryEvents are the system hook events that change the repository list
+var gitlabRepositoryEvents = []string{"project_create", "project_destroy", "project_rename", "project_transfer"}
+
+// webhookEvent is the platform independent representation of a webhook delivery
+type webhookEvent struct {
+	ProjectID         int64
+	Regenerate        bool // Regenerate is set for pushes to the default branch, which can change the generated pipeline
+	RepositoryChanged bool // RepositoryChanged is set if repositories were created, deleted or renamed
+}
+
+type githubWebhookPayload struct {
+	Ref        string `json:"ref"`
+	Action     string `json:"action"`
+	Repository struct {
+		ID            int64  `json:"id"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+}
+
+type gitlabWebhookPayload struct {
+	EventName string `json:"event_name"`
+	Ref       string `json:"ref"`
+	ProjectID int64  `json:"project_id"`
+	Project   struct {
+		ID            int64  `json:"id"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+}
+
+// parseWebhook verifies and parses GitHub and GitLab push and repository events
+func parseWebhook(header http.Header, body []byte, secret string) (webhookEvent, error) {
+	if githubEvent := header.Get("X-GitHub-Event"); githubEvent != "" {
+		if !verifyGitHubSignature(secret, body, header.Get("X-Hub-Signature-256")) {
+			return webhookEvent{}, ErrWebhookSignature
+		}
+
+		var payload githubWebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return webhookEvent{}, fmt.Errorf("failed to parse webhook payload: %w", err)
+		}
+
+		switch githubEvent {
+		case "push":
+			return webhookEvent{ProjectID: payload.Repository.ID, Regenerate: payload.Ref == "refs/heads/"+payload.Repository.DefaultBranch}, nil
+		case "repository":
+			return webhookEvent{ProjectID: payload.Repository.ID, RepositoryChanged: true}, nil
+		case "ping":
+			return webhookEvent{}, nil
+		}
+		return webhookEvent{}, fmt.Errorf("%w: %s", ErrWebhookUnsupported, githubEvent)
+	}
+
+	if gitlabEvent := header.Get("X-Gitlab-Event"); gitlabEvent != "" {
+		if !verifyGitLabToken(secret, header.Get("X-Gitlab-Token")) {
+			return webhookEvent{}, ErrWebhookSignature
+		}
+
+		var payload gitlabWebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return webhookEvent{}, fmt.Errorf("failed to parse webhook payload: %w", err)
+		}
+		projectID := payload.ProjectID
+		if projectID == 0 {
+			projectID = payload.Project.ID
+		}
+
+		switch {
+		case gitlabEvent == "Push Hook" || (gitlabEvent == "System Hook" && payload.EventName == "push"):
+			return webhookEvent{ProjectID: projectID, Regenerate: payload.Ref == "refs/heads/"+payload.Project.DefaultBranch}, nil
+		case gitlabEvent == "System Hook" && slices.Contains(gitlabRepositoryEvents, payload.EventName):
+			return webhookEvent{ProjectID: projectID, RepositoryChanged: true}, nil
+		}
+		return webhookEvent{}, fmt.Errorf("%w: %s %s", ErrWebhookUnsupported, gitlabEvent, payload.EventName)
+	}
+
+	return webhookEvent{}, ErrWebhookUnsupported
+}
+
+// webhook invalidates and regenerates cached pipelines on pushes to the default branch and refreshes the repository list on repository events
+func (s *Server) webhook(c *echo.Context) error {
+	if s.cfg.WebhookSecret == "" {
+		return echo.NewHTTPError(http.StatusForbidden, "webhooks are not enabled")
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body")
+	}
+
+	event, err := parseWebhook(c.Request().Header, body, s.cfg.WebhookSecret)
+	if errors.Is(err, ErrWebhookSignature) {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	} else if errors.Is(err, ErrWebhookUnsupported) {
+		slog.With("err", err).Debug("Ignoring webhook event")
+		return c.NoContent(http.StatusNoContent)
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if event.RepositoryChanged || event.Regenerate {
+		select {
+		case s.webhookQueue <- event:
+		default:
+			slog.With("project_id", event.ProjectID).Warn("Webhook queue is full, rejecting webhook event")
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "webhook queue is full")
+		}
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// processWebhookEvents processes the queued webhook events in the background, as pipeline generation takes longer than webhook timeouts allow
+func (s *Server) processWebhookEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.webhookQueue:
+			s.processWebhookEvent(ctx, event)
+		}
+	}
+}
+
+// processWebhookEvent invalidates the cached pipeline and regenerates it, or refreshes the repository list
+func (s *Server) processWebhookEvent(ctx context.Context, event webhookEvent) {
+	if event.RepositoryChanged {
+		if err := s.cfg.RefreshRepositories(); err != nil {
+			slog.With("err", err).Warn("Failed to refresh repositories")
+		}
+		s.invalidatePipeline(ctx, event.ProjectID)
+		return
+	}
+
+	s.invalidatePipeline(ctx, event.ProjectID)
+	repo, ok := s.cfg.Repository(event.ProjectID)
+	if !ok {
+		slog.With("project_id", event.ProjectID).Debug("Webhook for unknown repository, skipping pipeline generation")
+		return
+	}
+	if _, err := s.generatePipeline(ctx, repo); err != nil {
+		slog.With("project_id", event.ProjectID).With("err", err).Warn("Failed to regenerate pipeline")
+	}
+}
@@ -0,0 +1,122 @@
+package appserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/lib/storage"
+	"github.com/cidverse/cid/pkg/lib/storage/storagetest"
+	"github.com/cidverse/go-vcsapp/pkg/platform/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func githubHeader(event string, secret string, body []byte) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	header := http.Header{}
+	header.Set("X-GitHub-Event", event)
+	header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func gitlabHeader(event string, token string) http.Header {
+	header := http.Header{}
+	header.Set("X-Gitlab-Event", event)
+	header.Set("X-Gitlab-Token", token)
+	return header
+}
+
+func TestParseWebhookGitHubPush(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main","repository":{"id":42,"default_branch":"main"}}`)
+
+	event, err := parseWebhook(githubHeader("push", "secret", body), body, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, webhookEvent{ProjectID: 42, Regenerate: true}, event)
+
+	// pushes to other branches do not change the pipeline
+	body = []byte(`{"ref":"refs/heads/feature","repository":{"id":42,"default_branch":"main"}}`)
+	event, err = parseWebhook(githubHeader("push", "secret", body), body, "secret")
+	require.NoError(t, err)
+	assert.False(t, event.Regenerate)
+}
+
+func TestParseWebhookGitHubRepository(t *testing.T) {
+	body := []byte(`{"action":"renamed","repository":{"id":42}}`)
+
+	event, err := parseWebhook(githubHeader("repository", "secret", body), body, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, webhookEvent{ProjectID: 42, RepositoryChanged: true}, event)
+}
+
+func TestParseWebhookGitHubInvalidSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main","repository":{"id":42,"default_branch":"main"}}`)
+
+	_, err := parseWebhook(githubHeader("push", "other-secret", body), body, "secret")
+	assert.ErrorIs(t, err, ErrWebhookSignature)
+}
+
+func TestParseWebhookGitLab(t *testing.T) {
+	body := []byte(`{"object_kind":"push","ref":"refs/heads/main","project_id":7,"project":{"id":7,"default_branch":"main"}}`)
+	event, err := parseWebhook(gitlabHeader("Push Hook", "secret"), body, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, webhookEvent{ProjectID: 7, Regenerate: true}, event)
+
+	body = []byte(`{"event_name":"project_destroy","project_id":7}`)
+	event, err = parseWebhook(gitlabHeader("System Hook", "secret"), body, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, webhookEvent{ProjectID: 7, RepositoryChanged: true}, event)
+
+	_, err = parseWebhook(gitlabHeader("Push Hook", "wrong"), body, "secret")
+	assert.ErrorIs(t, err, ErrWebhookSignature)
+
+	_, err = parseWebhook(gitlabHeader("Issue Hook", "secret"), []byte(`{}`), "secret")
+	assert.ErrorIs(t, err, ErrWebhookUnsupported)
+}
+
+// fakePlatform implements the platform methods used by the server, all other methods panic
+type fakePlatform struct {
+	api.Platform
+}
+
+func (p fakePlatform) Name() string {
+	return "GitHub"
+}
+
+func TestWebhookQueue(t *testing.T) {
+	s := NewServer(&Config{Platform: fakePlatform{}, WebhookSecret: "secret"})
+	s.webhookQueue = make(chan webhookEvent, 1)
+	body := []byte(`{"ref":"refs/heads/main","repository":{"id":42,"default_branch":"main"}}`)
+
+	deliver := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/webhook", bytes.NewReader(body))
+		req.Header = githubHeader("push", "secret", body)
+		rec := httptest.NewRecorder()
+		s.e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusAccepted, deliver())
+	assert.Equal(t, webhookEvent{ProjectID: 42, Regenerate: true}, <-s.webhookQueue)
+
+	// deliveries are rejected while the queue is full
+	assert.Equal(t, http.StatusAccepted, deliver())
+	assert.Equal(t, http.StatusServiceUnavailable, deliver())
+}
+
+func TestProcessWebhookEventInvalidatesCache(t *testing.T) {
+	store := storagetest.NewStorage(t)
+	s := NewServer(&Config{Platform: fakePlatform{}, StorageApi: store})
+	require.NoError(t, store.PutObject(context.Background(), storage.DefaultBucket, "github/42.json", bytes.NewReader([]byte(`{}`)), "application/json"))
+
+	// unknown repositories are not regenerated
+	s.processWebhookEvent(context.Background(), webhookEvent{ProjectID: 42, Regenerate: true})
+	assert.Nil(t, store.Object(storage.DefaultBucket, "github/42.json"))
+}
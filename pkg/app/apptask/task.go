@@ -3,6 +3,7 @@ package apptask
 import (
 	"fmt"
 	"log/slog"
+	"maps"
 	"path/filepath"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/cidverse/cid/pkg/app/appmergerequest"
 	"github.com/cidverse/cid/pkg/app/apptemplate"
 	"github.com/cidverse/cid/pkg/context"
+	"github.com/cidverse/cid/pkg/core/config"
 	"github.com/cidverse/go-vcsapp/pkg/platform/api"
 	"github.com/cidverse/go-vcsapp/pkg/task/simpletask"
 	"github.com/cidverse/go-vcsapp/pkg/task/taskcommon"
@@ -24,29 +26,29 @@ type PlatformWorkflowData struct {
 }
 
 type PlatformWorkflowTaskOptions struct {
-	LoadConfig func(taskContext taskcommon.TaskContext) (appconfig.Config, error) // LoadConfig defines a function that loads the configuration for the workflow task.
+	LoadConfig func(taskContext taskcommon.TaskContext, cfg *config.CIDConfig) (appconfig.Config, error) // LoadConfig defines a function that loads the configuration for the workflow task, cfg is the parsed project configuration.
 
-	// RenderWorkflow defines a function that renders the workflow file based on the provided parameters.
+	// RenderWorkflow defines a function that renders the workflow files into the target directory, returning the content of each file by its path relative to the repository root.
 	RenderWorkflow func(
 		workflowState *appconfig.WorkflowState,
 		data PlatformWorkflowData,
 		template string,
-		targetPath string,
-	) (string, error)
+		targetDir string,
+	) (map[string]string, error)
 
 	WorkflowStatePath  string // Path where workflow state JSON will be stored.
 	MergeRequestFooter string // MergeRequestFooter defines the footer of the merge request description, e.g. "This MR was auto-generated by CID."
 }
 
 func WorkflowTaskData(taskContext taskcommon.TaskContext, opts PlatformWorkflowTaskOptions) (PlatformWorkflowData, error) {
-	// load config
-	conf, err := opts.LoadConfig(taskContext)
+	// app context
+	cid, err := context.NewAppContextFromDir(taskContext.Directory, taskContext.Directory)
 	if err != nil {
 		return PlatformWorkflowData{}, err
 	}
 
-	// app context
-	cid, err := context.NewAppContextFromDir(taskContext.Directory, taskContext.Directory)
+	// load config
+	conf, err := opts.LoadConfig(taskContext, cid.Config)
 	if err != nil {
 		return PlatformWorkflowData{}, err
 	}
@@ -94,24 +96,19 @@ func WorkflowTask(taskContext taskcommon.TaskContext, opts PlatformWorkflowTaskO
 	renderedFiles := make(map[string]string)
 	stateFile := opts.WorkflowStatePath
 
-	// load config
-	conf, err := opts.LoadConfig(taskContext)
-	if err != nil {
-		return WorkflowTaskResult{}, err
-	}
-
 	// clone repository
 	slog.With("dir", taskContext.Directory).With("clone-uri", taskContext.Repository.CloneSSH).Debug("cloning repository")
-	err = helper.Clone()
+	err := helper.Clone()
 	if err != nil {
 		return WorkflowTaskResult{}, fmt.Errorf("failed to clone repository: %w", err)
 	}
 
-	// workflow data
+	// workflow data, the config is loaded from the cloned repository
 	data, err := WorkflowTaskData(taskContext, opts)
 	if err != nil {
 		return WorkflowTaskResult{}, fmt.Errorf("failed to prepare workflow task data: %w", err)
 	}
+	conf := data.Conf
 
 	// create and checkout new branch
 	branch := fmt.Sprintf("%s-%s", appcommon.BranchName, conf.Version)
@@ -122,12 +119,12 @@ func WorkflowTask(taskContext taskcommon.TaskContext, opts PlatformWorkflowTaskO
 
 	// render workflows
 	if conf.Workflows != nil {
-		content, err := opts.RenderWorkflow(workflowState, data, "wf-main.gohtml", filepath.Join(taskContext.Directory, ".gitlab-ci.yml"))
+		files, err := opts.RenderWorkflow(workflowState, data, "wf-main.gohtml", taskContext.Directory)
 		if err != nil {
 			return WorkflowTaskResult{}, fmt.Errorf("failed to render workflow: %w", err)
 		}
 
-		renderedFiles[".gitlab-ci.yml"] = content
+		maps.Copy(renderedFiles, files)
 	}
 
 	// support files
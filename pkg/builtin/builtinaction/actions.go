@@ -1,12 +1,17 @@
 package builtinaction
 
 import (
+	"reflect"
+
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/ansible/ansibledeploy"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/ansible/ansiblelint"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/cargo/cargobuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/cargo/cargotest"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changeloggenerate"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changelogupdate"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/codecov/codecovupload"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/coverage/coveragecheck"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/coverage/coveragemerge"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/dotnet/dotnetbuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/dotnet/dotnettest"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/github/githubreleasepublish"
@@ -34,8 +39,11 @@ import (
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/poetry/poetrybuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/poetry/poetrytest"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/renovate/renovatelint"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/sarif/sarifbaselinediff"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/sarif/sarifpolicycheck"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/semgrep/semgrepscan"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/sonarqube/sonarqubescan"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testreport"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/trivy/trivyfsscan"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/uv/uvbuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/uv/uvtest"
@@ -89,12 +97,21 @@ func GetActions(sdk actionsdk.SDKClient) map[string]actionsdk.Action {
 		trivyfsscan.Action{Sdk: sdk},
 		// zizmor
 		zizmorscan.Action{Sdk: sdk},
+		// sarif
+		sarifbaselinediff.Action{Sdk: sdk},
+		sarifpolicycheck.Action{Sdk: sdk},
 		// renovate
 		renovatelint.Action{Sdk: sdk},
 		// changelog
 		changeloggenerate.Action{Sdk: sdk},
+		changelogupdate.Action{Sdk: sdk},
 		// codecov
 		codecovupload.Action{Sdk: sdk},
+		// coverage
+		coveragemerge.Action{Sdk: sdk},
+		coveragecheck.Action{Sdk: sdk},
+		// test results
+		testreport.Action{Sdk: sdk},
 		// helm
 		helmbuild.Action{Sdk: sdk},
 		helmlint.Action{Sdk: sdk},
@@ -117,3 +134,17 @@ func GetActions(sdk actionsdk.SDKClient) map[string]actionsdk.Action {
 
 	return actionMap
 }
+
+// GetConfigTypes returns the config struct of each action, based on the return type of the GetConfig method of the action
+func GetConfigTypes() map[string]reflect.Type {
+	result := make(map[string]reflect.Type)
+	for name, action := range GetActions(nil) {
+		method := reflect.ValueOf(action).MethodByName("GetConfig")
+		if !method.IsValid() || method.Type().NumOut() == 0 {
+			continue
+		}
+		result[name] = method.Type().Out(0)
+	}
+
+	return result
+}
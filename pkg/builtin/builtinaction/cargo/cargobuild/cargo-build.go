@@ -52,7 +52,8 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 func (a Action) GetConfig(d *actionsdk.ModuleExecutionContextV1Response) (Config, error) {
 	cfg := Config{}
 	if cfg.CargoVersion == "" {
-		cfg.CargoVersion = cargocommon.GetVersion(d.Env["NCI_COMMIT_REF_TYPE"], d.Env["NCI_COMMIT_REF_RELEASE"], d.Env["NCI_COMMIT_HASH_SHORT"])
+		refType, release := common.ReleaseRef(d.Env)
+		cfg.CargoVersion = cargocommon.GetVersion(refType, release, d.Env["NCI_COMMIT_HASH_SHORT"])
 	}
 
 	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 
 	"path"
 )
@@ -20,6 +22,7 @@ type Action struct {
 }
 
 type Config struct {
+	Quarantine []string `json:"quarantine"  env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
@@ -35,7 +38,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name: "cargo",
@@ -76,7 +79,7 @@ func (a Action) Execute() (err error) {
 	}
 
 	// parse config
-	_, err = a.GetConfig(d)
+	cfg, err := a.GetConfig(d)
 	if err != nil {
 		return err
 	}
@@ -95,13 +98,13 @@ func (a Action) Execute() (err error) {
 	})
 	if err != nil {
 		return err
-	} else if cmdResult.Code != 0 {
-		return fmt.Errorf("cargo test failed, exit code %d", cmdResult.Code)
 	}
+	testsFailed := cmdResult.Code != 0
 
 	// junit report
+	junitReport := path.Join(d.Module.ModuleDir, "target", "nextest", "ci", "junit.xml")
 	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
-		File:   path.Join(d.Module.ModuleDir, "target", "nextest", "ci", "junit.xml"),
+		File:   junitReport,
 		Type:   "report",
 		Format: "junit",
 	})
@@ -109,5 +112,14 @@ func (a Action) Execute() (err error) {
 		return err
 	}
 
-	return nil
+	// test results
+	report, err := testresultcommon.ReadReports(a.Sdk, testresult.FormatJUnit, junitReport)
+	if err != nil {
+		return err
+	}
+
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
@@ -23,6 +23,8 @@ func TestCargoTest(t *testing.T) {
 		Type:   "report",
 		Format: "junit",
 	}).Return("", "", nil)
+	sdk.On("FileExistsV1", "/my-project/target/nextest/ci/junit.xml").Return(true)
+	sdk.On("FileReadV1", "/my-project/target/nextest/ci/junit.xml").Return(`<testsuites><testsuite name="my-project"><testcase name="adds"/></testsuite></testsuites>`, nil)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
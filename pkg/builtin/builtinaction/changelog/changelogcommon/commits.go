@@ -0,0 +1,62 @@
+package changelogcommon
+
+import (
+	"fmt"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cidverseutils/version"
+	"github.com/cidverse/go-vcs/vcsapi"
+)
+
+// FetchReleaseCommits returns the commits between the current release and the previous release of the same type (stable or pre-release)
+func FetchReleaseCommits(sdk actionsdk.SDKClient, env map[string]string) ([]*actionsdk.VCSCommit, error) {
+	// find last release to generate the changelog diff
+	currentRelease := env["NCI_COMMIT_REF_NAME"]
+	releases, err := sdk.VCSReleasesV1(actionsdk.VCSReleasesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	previousRelease := LatestReleaseOfSameType(releases, currentRelease)
+	previousReleaseVCSRef := "tag/" + previousRelease.Ref.Value
+	if previousRelease.Ref.Value == "" {
+		previousReleaseVCSRef = ""
+	}
+	c, err := sdk.VCSCommitsV1(actionsdk.VCSCommitsRequest{
+		FromHash: fmt.Sprintf("hash/%s", env["NCI_COMMIT_HASH"]),
+		ToHash:   previousReleaseVCSRef,
+		Limit:    1000,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = sdk.LogV1(actionsdk.LogV1Request{
+		Level:   "debug",
+		Message: "fetch commits",
+		Context: map[string]interface{}{
+			"release_current":  currentRelease,
+			"release_previous": previousRelease.Version,
+			"from":             env["NCI_COMMIT_HASH"],
+			"to":               previousReleaseVCSRef,
+			"count":            len(c),
+		},
+	})
+
+	return c, nil
+}
+
+// LatestReleaseOfSameType returns the newest release older than the current release, pre-releases are only compared with pre-releases
+func LatestReleaseOfSameType(releases []actionsdk.VCSRelease, currentRelease string) actionsdk.VCSRelease {
+	currentReleaseStable := version.IsStable(currentRelease)
+
+	for _, release := range releases {
+		compare, _ := version.Compare(currentRelease, release.Version)
+		if compare > 0 && version.IsStable(release.Version) == currentReleaseStable {
+			return release
+		}
+	}
+
+	return actionsdk.VCSRelease{
+		Version: "0.0.0",
+		Ref:     vcsapi.VCSRef{},
+	}
+}
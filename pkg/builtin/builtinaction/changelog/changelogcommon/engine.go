@@ -2,40 +2,29 @@ package changelogcommon
 
 import (
 	"bufio"
-	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
-	"github.com/oriser/regroup"
 )
 
+// DefaultCommitPattern returns the commit patterns of the project commit convention (CID_CONVENTION_COMMIT, CID_CONVENTION_COMMIT_PATTERN)
+func DefaultCommitPattern(env map[string]string) []string {
+	return commitanalyser.CommitPatterns(env["CID_CONVENTION_COMMIT"], env["CID_CONVENTION_COMMIT_PATTERN"])
+}
+
 func PreprocessCommits(commitPattern []string, commits []*actionsdk.VCSCommit) []*actionsdk.VCSCommit {
 	var response []*actionsdk.VCSCommit
-
-	var commitExpr []*regexp.Regexp
-	var commitGroupExpr []*regroup.ReGroup
-	for _, commitPattern := range commitPattern {
-		commitExpr = append(commitExpr, regexp.MustCompile(commitPattern))
-		commitGroupExpr = append(commitGroupExpr, regroup.MustCompile(commitPattern))
-	}
+	parser := commitanalyser.NewCommitParser(commitPattern)
 
 	// process commits
 	for _, commit := range commits { //nolint:gocritic
 		// parse context info
 		commit.Context = make(map[string]string)
-		for id := range commitPattern {
-			// check if commit matches the pattern
-			if !commitExpr[id].MatchString(commit.Message) {
-				continue
-			}
-
-			match, matchErr := commitGroupExpr[id].Groups(commit.Message)
-			if matchErr != nil {
-				continue
-			}
-
+		if match, ok := parser.Parse(commit.Message); ok {
 			commit.Context["type"] = match["type"]
 			commit.Context["scope"] = match["scope"]
 			commit.Context["breaking"] = strconv.FormatBool(len(match["breaking"]) > 0)
@@ -44,8 +33,9 @@ func PreprocessCommits(commitPattern []string, commits []*actionsdk.VCSCommit) [
 			commit.Context["author_email"] = commit.Author.Email
 			commit.Context["committer_name"] = commit.Committer.Name
 			commit.Context["committer_email"] = commit.Committer.Email
-
-			break
+			if match["issue"] != "" {
+				commit.Context["issue"] = match["issue"]
+			}
 		}
 
 		response = append(response, commit)
@@ -68,6 +58,9 @@ func ProcessCommits(config Config, commits []*actionsdk.VCSCommit) TemplateData
 	commitGroups := make(map[string][]*actionsdk.VCSCommit)
 	noteGroups := make(map[string][]string)
 	contributors := make(map[string]ContributorData)
+	var issues []IssueData
+	var mergeRequests []MergeRequestData
+	var dependencies []DependencyUpdateData
 
 	// process commits
 	for _, commit := range commits { //nolint:gocritic
@@ -78,6 +71,34 @@ func ProcessCommits(config Config, commits []*actionsdk.VCSCommit) TemplateData
 		// issue linking
 		commit.Message = AddLinks(commit.Message)
 		commit.Description = AddLinks(commit.Description)
+		commitIssues := ExtractIssues(commit.Message+"\n"+commit.Description, config.IssuePrefix)
+		if issue := commit.Context["issue"]; issue != "" && !slices.Contains(commitIssues, issue) {
+			commitIssues = append([]string{issue}, commitIssues...)
+		}
+		for _, id := range commitIssues {
+			if !slices.ContainsFunc(issues, func(i IssueData) bool { return i.ID == id }) {
+				issues = append(issues, IssueData{ID: id, URL: IssueURL(config.IssueURL, id, config.IssuePrefix)})
+			}
+		}
+		if len(commitIssues) > 0 {
+			commit.Context["issues"] = strings.Join(commitIssues, ",")
+		}
+
+		// merge request
+		if id := commit.Context["merge_request_id"]; id != "" && !slices.ContainsFunc(mergeRequests, func(mr MergeRequestData) bool { return mr.ID == id }) {
+			mergeRequests = append(mergeRequests, MergeRequestData{
+				ID:     id,
+				Title:  commit.Context["merge_request_title"],
+				Author: commit.Context["merge_request_author"],
+				URL:    commit.Context["merge_request_url"],
+			})
+		}
+
+		// dependency updates
+		if name, ver, ok := ParseDependencyUpdate(commit); ok {
+			dependencies = addDependencyUpdate(dependencies, name, ver, commit.Hash)
+			continue
+		}
 
 		// contributor
 		if contributor, ok := contributors[commit.Author.Email]; ok {
@@ -113,10 +134,17 @@ func ProcessCommits(config Config, commits []*actionsdk.VCSCommit) TemplateData
 		commitGroups[commit.Context["type"]] = append(commitGroups[commit.Context["type"]], commit)
 	}
 
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		return dependencies[i].Name < dependencies[j].Name
+	})
+
 	return TemplateData{
-		Commits:      commits,
-		CommitGroups: commitGroups,
-		NoteGroups:   noteGroups,
-		Contributors: contributors,
+		Commits:       commits,
+		CommitGroups:  commitGroups,
+		NoteGroups:    noteGroups,
+		Contributors:  contributors,
+		Issues:        issues,
+		MergeRequests: mergeRequests,
+		Dependencies:  dependencies,
 	}
 }
@@ -116,3 +116,120 @@ func TestRenderDiscordTemplate(t *testing.T) {
 -  this feature is pretty useful
 `, output)
 }
+
+func releaseNotesTestData() TemplateData {
+	cfg := config
+	cfg.IssuePrefix = "#"
+	cfg.IssueURL = "https://github.com/cidverse/cid/issues/{number}"
+
+	data := []*actionsdk.VCSCommit{
+		{
+			Hash:    "a1",
+			Message: "feat(api): add endpoint",
+			Author:  actionsdk.VCSAuthor{Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		{
+			Hash:        "a2",
+			Message:     "fix: resolve crash",
+			Description: "Fixes #12",
+			Author:      actionsdk.VCSAuthor{Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		{
+			Hash:    "a3",
+			Message: "chore(deps): update module github.com/spf13/cobra to v1.9.0",
+			Author:  actionsdk.VCSAuthor{Name: "renovate[bot]", Email: "bot@renovateapp.com"},
+		},
+		{
+			Hash:    "a4",
+			Message: "chore(deps): update module github.com/spf13/cobra to v1.10.0",
+			Author:  actionsdk.VCSAuthor{Name: "renovate[bot]", Email: "bot@renovateapp.com"},
+		},
+	}
+	data = PreprocessCommits(cfg.CommitPattern, data)
+	SetMergeRequest(data[0], MergeRequestData{ID: "#5", Title: "Add endpoint", Author: "jane", URL: "https://github.com/cidverse/cid/pull/5"})
+
+	templateData := ProcessCommits(cfg, data)
+	templateData.ProjectName = "CID"
+	templateData.Version = "1.1.0"
+	templateData.ReleaseDate = time.Unix(int64(1609502400), int64(0))
+	return templateData
+}
+
+func TestProcessCommitsReleaseNotes(t *testing.T) {
+	templateData := releaseNotesTestData()
+
+	assert.Equal(t, []IssueData{{ID: "#12", URL: "https://github.com/cidverse/cid/issues/12"}}, templateData.Issues)
+	assert.Equal(t, []MergeRequestData{{ID: "#5", Title: "Add endpoint", Author: "jane", URL: "https://github.com/cidverse/cid/pull/5"}}, templateData.MergeRequests)
+	assert.Equal(t, []DependencyUpdateData{{Name: "github.com/spf13/cobra", Version: "v1.10.0", Commits: []string{"a4", "a3"}}}, templateData.Dependencies)
+	assert.Len(t, templateData.Contributors, 1)
+	assert.NotContains(t, templateData.CommitGroups, "chore")
+}
+
+func TestRenderReleaseNotesTemplates(t *testing.T) {
+	templateData := releaseNotesTestData()
+
+	template, err := GetFileContentFromEmbedFS(TemplateFS, "templates/github.changelog")
+	assert.NoError(t, err)
+	output, err := RenderTemplate(&templateData, template)
+	assert.NoError(t, err)
+	assert.Equal(t, `## Bug Fixes
+- resolve crash
+
+## Features
+- **api:** add endpoint
+
+## Dependency Updates
+- github.com/spf13/cobra to v1.10.0
+`, output)
+
+	template, err = GetFileContentFromEmbedFS(TemplateFS, "templates/release-notes.md")
+	assert.NoError(t, err)
+	output, err = RenderTemplate(&templateData, template)
+	assert.NoError(t, err)
+	assert.Equal(t, `# CID 1.1.0 (2021-01-01)
+
+## Bug Fixes
+- resolve crash
+
+## Features
+- **api:** add endpoint ([#5](https://github.com/cidverse/cid/pull/5) by @jane)
+
+## Dependency Updates
+- github.com/spf13/cobra to v1.10.0
+
+## Resolved Issues
+- [#12](https://github.com/cidverse/cid/issues/12)
+
+`, output)
+
+	template, err = GetFileContentFromEmbedFS(TemplateFS, "templates/release-notes.html")
+	assert.NoError(t, err)
+	output, err = RenderHTMLTemplate(&templateData, template)
+	assert.NoError(t, err)
+	assert.Contains(t, output, `<li><strong>api:</strong> add endpoint (<a href="https://github.com/cidverse/cid/pull/5">#5</a> by @jane)</li>`)
+	assert.Contains(t, output, `<li><a href="https://github.com/cidverse/cid/issues/12">#12</a></li>`)
+
+	output, err = RenderJSON(&templateData)
+	assert.NoError(t, err)
+	assert.Contains(t, output, `"title": "Features"`)
+	assert.Contains(t, output, `"merge_request": "#5"`)
+}
+
+func TestProcessCommitsJiraConvention(t *testing.T) {
+	cfg := Config{
+		CommitPattern: DefaultCommitPattern(map[string]string{"CID_CONVENTION_COMMIT": "Jira"}),
+		IssueURL:      "https://example.atlassian.net/browse/{id}",
+	}
+	data := PreprocessCommits(cfg.CommitPattern, []*actionsdk.VCSCommit{
+		{Hash: "a1", Message: "ABC-12 feat(api): add login", Author: actionsdk.VCSAuthor{Name: "Jane", Email: "jane@example.com"}},
+		{Hash: "a2", Message: "[ABC-13] rotate certificates", Author: actionsdk.VCSAuthor{Name: "Jane", Email: "jane@example.com"}},
+	})
+	templateData := ProcessCommits(cfg, data)
+
+	assert.Equal(t, "add login", templateData.CommitGroups["feat"][0].Context["subject"])
+	assert.Equal(t, "rotate certificates", templateData.CommitGroups["chore"][0].Context["subject"])
+	assert.Equal(t, []IssueData{
+		{ID: "ABC-12", URL: "https://example.atlassian.net/browse/ABC-12"},
+		{ID: "ABC-13", URL: "https://example.atlassian.net/browse/ABC-13"},
+	}, templateData.Issues)
+}
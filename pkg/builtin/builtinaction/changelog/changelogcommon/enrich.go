@@ -0,0 +1,105 @@
+package changelogcommon
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cidverseutils/version"
+)
+
+var dependencyScopes = []string{"deps", "deps-dev"}
+
+var renovateSubjectRegex = regexp.MustCompile(`(?i)^update (?:dependency |module |plugin )?(?P<name>\S+)(?: (?:action|docker tag|digest|image|orb))? to (?P<version>\S+)`)
+
+// ExtractIssues returns the issue ids referenced in the input, e.g. #123 for the prefix # or JIRA-123 for the prefix JIRA-
+func ExtractIssues(input string, issuePrefix string) []string {
+	if issuePrefix == "" {
+		return nil
+	}
+
+	expr := regexp.MustCompile(`(?:^|[^\w])(` + regexp.QuoteMeta(issuePrefix) + `(\d+))\b`)
+	var issues []string
+	for _, match := range expr.FindAllStringSubmatch(input, -1) {
+		if !slices.Contains(issues, match[1]) {
+			issues = append(issues, match[1])
+		}
+	}
+
+	return issues
+}
+
+// IssueURL returns the link to an issue, {id} and {number} in the url template are replaced with the issue id and number
+func IssueURL(urlTemplate string, issueID string, issuePrefix string) string {
+	if urlTemplate == "" {
+		return ""
+	}
+
+	url := strings.ReplaceAll(urlTemplate, "{id}", issueID)
+	return strings.ReplaceAll(url, "{number}", strings.TrimPrefix(issueID, issuePrefix))
+}
+
+// DefaultIssueURL returns the issue url template of the repository host
+func DefaultIssueURL(hostType string, projectURL string) string {
+	if projectURL == "" {
+		return ""
+	}
+
+	switch hostType {
+	case "github", "gitea":
+		return projectURL + "/issues/{number}"
+	case "gitlab":
+		return projectURL + "/-/issues/{number}"
+	default:
+		return ""
+	}
+}
+
+// ParseDependencyUpdate detects dependency updates made by Renovate, based on the commit author or the deps scope
+func ParseDependencyUpdate(commit *actionsdk.VCSCommit) (name string, ver string, ok bool) {
+	isRenovate := strings.Contains(strings.ToLower(commit.Author.Name), "renovate")
+	if !isRenovate && !slices.Contains(dependencyScopes, commit.Context["scope"]) {
+		return "", "", false
+	}
+
+	subject := commit.Context["subject"]
+	if subject == "" {
+		subject = commit.Message
+	}
+	match := renovateSubjectRegex.FindStringSubmatch(subject)
+	if match == nil {
+		return subject, "", true
+	}
+
+	return match[1], match[2], true
+}
+
+// SetMergeRequest adds the merge request information to the commit context
+func SetMergeRequest(commit *actionsdk.VCSCommit, mr MergeRequestData) {
+	if commit.Context == nil {
+		commit.Context = make(map[string]string)
+	}
+
+	commit.Context["merge_request_id"] = mr.ID
+	commit.Context["merge_request_title"] = mr.Title
+	commit.Context["merge_request_author"] = mr.Author
+	commit.Context["merge_request_url"] = mr.URL
+}
+
+// addDependencyUpdate groups the update by dependency name, keeping the highest version
+func addDependencyUpdate(updates []DependencyUpdateData, name string, ver string, hash string) []DependencyUpdateData {
+	for i := range updates {
+		if updates[i].Name != name {
+			continue
+		}
+
+		updates[i].Commits = append(updates[i].Commits, hash)
+		if cmp, err := version.Compare(ver, updates[i].Version); updates[i].Version == "" || (err == nil && cmp > 0) {
+			updates[i].Version = ver
+		}
+		return updates
+	}
+
+	return append(updates, DependencyUpdateData{Name: name, Version: ver, Commits: []string{hash}})
+}
@@ -0,0 +1,191 @@
+package changelogcommon
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// KeepAChangelogSections are the change types of Keep a Changelog, in the order of the specification - https://keepachangelog.com/en/1.1.0/
+var KeepAChangelogSections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// DefaultKeepAChangelogTypeMaps maps conventional commit types to Keep a Changelog sections, commits of other types are not included
+var DefaultKeepAChangelogTypeMaps = map[string]string{
+	"feat":      "Added",
+	"perf":      "Changed",
+	"refactor":  "Changed",
+	"deprecate": "Deprecated",
+	"revert":    "Removed",
+	"fix":       "Fixed",
+	"security":  "Security",
+}
+
+const keepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+const unreleasedHeading = "## [Unreleased]"
+
+// KeepAChangelogEntries groups the processed commits into Keep a Changelog sections, commits with the security scope are always listed as Security
+func KeepAChangelogEntries(data *TemplateData, typeMaps map[string]string) map[string][]string {
+	entries := make(map[string][]string)
+	for _, commit := range data.Commits {
+		if len(commit.Context) == 0 || commit.Context["subject"] == "" {
+			continue
+		}
+		if _, _, isDependency := ParseDependencyUpdate(commit); isDependency {
+			continue
+		}
+
+		section := typeMaps[commit.Context["type"]]
+		if commit.Context["scope"] == "security" {
+			section = "Security"
+		}
+		if section == "" {
+			continue
+		}
+
+		entry := commit.Context["subject"]
+		if commit.Context["scope"] != "" && commit.Context["scope"] != "security" {
+			entry = fmt.Sprintf("**%s:** %s", commit.Context["scope"], entry)
+		}
+		if commit.Context["breaking"] == "true" {
+			entry = "**BREAKING:** " + entry
+		}
+		entries[section] = append(entries[section], entry)
+	}
+
+	return entries
+}
+
+// UpdateKeepAChangelog inserts the release section below the Unreleased section of the changelog.
+// Manual entries in the Unreleased section are moved into the release, the changelog is returned unchanged if the release already exists.
+func UpdateKeepAChangelog(content string, version string, releaseDate time.Time, entries map[string][]string) string {
+	version = strings.TrimPrefix(version, "v")
+	if strings.TrimSpace(content) == "" {
+		content = keepAChangelogHeader
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if slices.ContainsFunc(lines, func(line string) bool { return isReleaseHeading(line, version) }) {
+		return content
+	}
+
+	// locate the unreleased section and the first release
+	unreleasedStart, unreleasedEnd := -1, -1
+	insertAt := len(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSpace(line), unreleasedHeading) {
+			unreleasedStart = i
+			continue
+		}
+		if unreleasedStart >= 0 && unreleasedEnd < 0 {
+			unreleasedEnd = i
+		}
+		if insertAt == len(lines) {
+			insertAt = i
+		}
+	}
+	if unreleasedStart >= 0 && unreleasedEnd < 0 {
+		unreleasedEnd = len(lines)
+	}
+
+	// merge manual entries of the unreleased section
+	merged := make(map[string][]string)
+	if unreleasedStart >= 0 {
+		for section, manual := range parseKeepAChangelogEntries(lines[unreleasedStart+1 : unreleasedEnd]) {
+			merged[section] = append(merged[section], manual...)
+		}
+	}
+	for section, generated := range entries {
+		for _, entry := range generated {
+			if !slices.Contains(merged[section], "- "+entry) {
+				merged[section] = append(merged[section], "- "+entry)
+			}
+		}
+	}
+
+	// render
+	var result []string
+	if unreleasedStart >= 0 {
+		result = append(result, lines[:unreleasedStart]...)
+	} else {
+		result = append(result, lines[:insertAt]...)
+	}
+	result = trimTrailingEmptyLines(result)
+	result = append(result, "", unreleasedHeading, "")
+	result = append(result, renderKeepAChangelogRelease(version, releaseDate, merged)...)
+	if insertAt < len(lines) {
+		result = append(result, "")
+		result = append(result, lines[insertAt:]...)
+	}
+
+	return strings.Join(result, "\n") + "\n"
+}
+
+// parseKeepAChangelogEntries returns the entries per section, multi-line entries are kept as one entry
+func parseKeepAChangelogEntries(lines []string) map[string][]string {
+	entries := make(map[string][]string)
+	section := ""
+	for _, line := range lines {
+		if strings.HasPrefix(line, "### ") {
+			section = strings.TrimSpace(strings.TrimPrefix(line, "### "))
+			continue
+		}
+		if section == "" || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || len(entries[section]) == 0 {
+			entries[section] = append(entries[section], line)
+		} else {
+			entries[section][len(entries[section])-1] += "\n" + line
+		}
+	}
+
+	return entries
+}
+
+func renderKeepAChangelogRelease(version string, releaseDate time.Time, entries map[string][]string) []string {
+	result := []string{fmt.Sprintf("## [%s] - %s", version, releaseDate.Format("2006-01-02"))}
+
+	// known sections first, custom sections of manual edits afterward
+	sections := slices.Clone(KeepAChangelogSections)
+	for _, section := range slices.Sorted(maps.Keys(entries)) {
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+
+	for _, section := range sections {
+		if len(entries[section]) == 0 {
+			continue
+		}
+
+		result = append(result, "", "### "+section, "")
+		result = append(result, entries[section]...)
+	}
+
+	return result
+}
+
+func isReleaseHeading(line string, version string) bool {
+	return strings.HasPrefix(line, "## ["+version+"]") || strings.HasPrefix(line, "## "+version+" ") || strings.TrimSpace(line) == "## "+version
+}
+
+func trimTrailingEmptyLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,105 @@
+package changelogcommon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepAChangelogEntries(t *testing.T) {
+	data := PreprocessCommits(config.CommitPattern, []*actionsdk.VCSCommit{
+		{Hash: "a1", Message: "feat(api): add endpoint"},
+		{Hash: "a2", Message: "fix: resolve crash"},
+		{Hash: "a3", Message: "fix(security): escape user input"},
+		{Hash: "a4", Message: "chore: update ci"},
+		{Hash: "a5", Message: "fix(deps): update module github.com/spf13/cobra to v1.10.0"},
+	})
+	templateData := ProcessCommits(Config{}, data)
+
+	entries := KeepAChangelogEntries(&templateData, DefaultKeepAChangelogTypeMaps)
+	assert.Equal(t, map[string][]string{
+		"Added":    {"**api:** add endpoint"},
+		"Fixed":    {"resolve crash"},
+		"Security": {"escape user input"},
+	}, entries)
+}
+
+func TestUpdateKeepAChangelogNewFile(t *testing.T) {
+	output := UpdateKeepAChangelog("", "v1.0.0", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), map[string][]string{
+		"Fixed": {"resolve crash"},
+		"Added": {"add endpoint"},
+	})
+
+	assert.Equal(t, `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+
+## [1.0.0] - 2021-01-01
+
+### Added
+
+- add endpoint
+
+### Fixed
+
+- resolve crash
+`, output)
+}
+
+func TestUpdateKeepAChangelogKeepsManualEdits(t *testing.T) {
+	existing := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- manual entry
+  with a second line
+
+### Migration
+
+- run the migration script
+
+## [1.0.0] - 2021-01-01
+
+### Added
+
+- add endpoint
+`
+
+	output := UpdateKeepAChangelog(existing, "1.1.0", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), map[string][]string{
+		"Added": {"add cli"},
+	})
+	assert.Equal(t, `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2021-02-01
+
+### Added
+
+- manual entry
+  with a second line
+- add cli
+
+### Migration
+
+- run the migration script
+
+## [1.0.0] - 2021-01-01
+
+### Added
+
+- add endpoint
+`, output)
+
+	// existing release is not modified
+	assert.Equal(t, output, UpdateKeepAChangelog(output, "1.1.0", time.Now(), map[string][]string{"Fixed": {"other"}}))
+}
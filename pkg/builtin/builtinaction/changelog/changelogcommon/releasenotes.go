@@ -0,0 +1,97 @@
+package changelogcommon
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReleaseNotes is the machine-readable changelog, consumed by other actions like chat notifications
+type ReleaseNotes struct {
+	ProjectName   string                 `json:"project_name"`
+	ProjectURL    string                 `json:"project_url"`
+	Version       string                 `json:"version"`
+	ReleaseDate   time.Time              `json:"release_date"`
+	Sections      []ReleaseNotesSection  `json:"sections"`
+	Notes         map[string][]string    `json:"notes,omitempty"`
+	Dependencies  []DependencyUpdateData `json:"dependencies,omitempty"`
+	Issues        []IssueData            `json:"issues,omitempty"`
+	MergeRequests []MergeRequestData     `json:"merge_requests,omitempty"`
+	Contributors  []ContributorData      `json:"contributors"`
+}
+
+// ReleaseNotesSection is a group of changes, e.g. Features or Bug Fixes
+type ReleaseNotesSection struct {
+	Title   string              `json:"title"`
+	Changes []ReleaseNotesEntry `json:"changes"`
+}
+
+// ReleaseNotesEntry is a single change
+type ReleaseNotesEntry struct {
+	Hash         string   `json:"hash"`
+	Scope        string   `json:"scope,omitempty"`
+	Subject      string   `json:"subject"`
+	Breaking     bool     `json:"breaking"`
+	Author       string   `json:"author"`
+	Issues       []string `json:"issues,omitempty"`
+	MergeRequest string   `json:"merge_request,omitempty"`
+}
+
+// NewReleaseNotes converts the processed commits into the machine-readable changelog
+func NewReleaseNotes(data *TemplateData) ReleaseNotes {
+	notes := ReleaseNotes{
+		ProjectName:   data.ProjectName,
+		ProjectURL:    data.ProjectURL,
+		Version:       data.Version,
+		ReleaseDate:   data.ReleaseDate,
+		Sections:      []ReleaseNotesSection{},
+		Notes:         data.NoteGroups,
+		Dependencies:  data.Dependencies,
+		Issues:        data.Issues,
+		MergeRequests: data.MergeRequests,
+		Contributors:  slices.Collect(maps.Values(data.Contributors)),
+	}
+
+	for _, title := range slices.Sorted(maps.Keys(data.CommitGroups)) {
+		section := ReleaseNotesSection{Title: title}
+		for _, c := range data.CommitGroups[title] {
+			breaking, _ := strconv.ParseBool(c.Context["breaking"])
+			entry := ReleaseNotesEntry{
+				Hash:         c.Hash,
+				Scope:        c.Context["scope"],
+				Subject:      c.Context["subject"],
+				Breaking:     breaking,
+				Author:       c.Author.Name,
+				MergeRequest: c.Context["merge_request_id"],
+			}
+			if c.Context["issues"] != "" {
+				entry.Issues = strings.Split(c.Context["issues"], ",")
+			}
+			section.Changes = append(section.Changes, entry)
+		}
+		notes.Sections = append(notes.Sections, section)
+	}
+
+	sort.Slice(notes.Contributors, func(i, j int) bool {
+		if notes.Contributors[i].Commits != notes.Contributors[j].Commits {
+			return notes.Contributors[i].Commits > notes.Contributors[j].Commits
+		}
+		return notes.Contributors[i].Name < notes.Contributors[j].Name
+	})
+
+	return notes
+}
+
+// RenderJSON renders the machine-readable changelog
+func RenderJSON(data *TemplateData) (string, error) {
+	content, err := json.MarshalIndent(NewReleaseNotes(data), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
@@ -2,6 +2,8 @@ package changelogcommon
 
 import (
 	"bytes"
+	htmltemplate "html/template"
+	"strings"
 	"text/template"
 )
 
@@ -21,3 +23,24 @@ func RenderTemplate(data *TemplateData, templateRaw string) (string, error) {
 
 	return buffer.String(), nil
 }
+
+// RenderHTMLTemplate renders the template with contextual escaping of all commit data
+func RenderHTMLTemplate(data *TemplateData, templateRaw string) (string, error) {
+	tmpl, err := htmltemplate.New("inmemory").Parse(templateRaw)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := bytes.NewBufferString("")
+	err = tmpl.Execute(buffer, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// IsHTMLTemplate checks if the template renders html
+func IsHTMLTemplate(templateFile string) bool {
+	return strings.HasSuffix(templateFile, ".html")
+}
@@ -10,32 +10,60 @@ import (
 //go:embed templates/*
 var TemplateFS embed.FS
 
+// JSONChangelogFile is the machine-readable changelog, see ReleaseNotes
+const JSONChangelogFile = "changelog.json"
+
 type TemplateData struct {
-	ProjectName  string
-	ProjectURL   string
-	Version      string
-	ReleaseDate  time.Time
-	Commits      []*actionsdk.VCSCommit
-	CommitGroups map[string][]*actionsdk.VCSCommit
-	NoteGroups   map[string][]string
-	Contributors map[string]ContributorData
+	ProjectName   string
+	ProjectURL    string
+	Version       string
+	ReleaseDate   time.Time
+	Commits       []*actionsdk.VCSCommit
+	CommitGroups  map[string][]*actionsdk.VCSCommit
+	NoteGroups    map[string][]string
+	Contributors  map[string]ContributorData
+	Issues        []IssueData            // Issues referenced by the commits, using the IssuePrefix
+	MergeRequests []MergeRequestData     // MergeRequests the commits were merged with
+	Dependencies  []DependencyUpdateData // Dependencies updated by Renovate, excluded from the CommitGroups
 }
 
 type ContributorData struct {
-	Name    string
-	Email   string
-	Commits int
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+}
+
+// IssueData is an issue referenced in a commit message, e.g. #123 or JIRA-123
+type IssueData struct {
+	ID  string `json:"id"`
+	URL string `json:"url,omitempty"`
+}
+
+// MergeRequestData is the pull request / merge request a commit was merged with
+type MergeRequestData struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	URL    string `json:"url,omitempty"`
+}
+
+// DependencyUpdateData is a dependency update, grouped by dependency name
+type DependencyUpdateData struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version,omitempty"`
+	Commits []string `json:"commits"`
 }
 
 type Config struct {
-	Templates     []string          `yaml:"templates"`
-	CommitPattern []string          `yaml:"commit_pattern"`
-	TitleMaps     map[string]string `yaml:"title_maps"`
-	NoteKeywords  []NoteKeyword     `yaml:"note_keywords"`
-	IssuePrefix   string            `yaml:"issue_prefix"`
+	Templates     []string          `json:"templates" yaml:"templates"`
+	CommitPattern []string          `json:"commit_pattern" yaml:"commit_pattern"`
+	TitleMaps     map[string]string `json:"title_maps" yaml:"title_maps"`
+	NoteKeywords  []NoteKeyword     `json:"note_keywords" yaml:"note_keywords"`
+	IssuePrefix   string            `json:"issue_prefix" yaml:"issue_prefix"`
+	IssueURL      string            `json:"issue_url" yaml:"issue_url"` // IssueURL is the link to an issue, {id} and {number} are replaced with the issue id and number
 }
 
 type NoteKeyword struct {
-	Keyword string
-	Title   string
+	Keyword string `json:"keyword" yaml:"keyword"`
+	Title   string `json:"title" yaml:"title"`
 }
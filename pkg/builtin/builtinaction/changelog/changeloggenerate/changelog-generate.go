@@ -6,11 +6,9 @@ import (
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changelogcommon"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
-	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/cidverse/cid/pkg/lib/mergerequest"
 
 	"time"
-
-	"github.com/cidverse/cidverseutils/version"
 )
 
 const URI = "builtin://actions/changelog-generate"
@@ -20,23 +18,47 @@ type Action struct {
 }
 
 type Config struct {
-	Templates     []string                      `yaml:"templates"`
-	CommitPattern []string                      `yaml:"commit_pattern"`
-	TitleMaps     map[string]string             `yaml:"title_maps"`
-	NoteKeywords  []changelogcommon.NoteKeyword `yaml:"note_keywords"`
-	IssuePrefix   string                        `yaml:"issue_prefix"`
+	Templates     []string                      `json:"templates" yaml:"templates"`
+	CommitPattern []string                      `json:"commit_pattern" yaml:"commit_pattern"`
+	TitleMaps     map[string]string             `json:"title_maps" yaml:"title_maps"`
+	NoteKeywords  []changelogcommon.NoteKeyword `json:"note_keywords" yaml:"note_keywords"`
+	IssuePrefix   string                        `json:"issue_prefix" yaml:"issue_prefix"` // IssuePrefix enables the extraction of referenced issues, e.g. # or JIRA-
+	IssueURL      string                        `json:"issue_url" yaml:"issue_url"`
+	GitHubToken   string                        `json:"github_token" yaml:"github_token" env:"GITHUB_TOKEN"`
+	GitLabToken   string                        `json:"gitlab_token" yaml:"gitlab_token" env:"GITLAB_TOKEN"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
 	return actionsdk.ActionMetadata{
 		Name:        "changelog-generate",
 		Description: `Generates a changelog based on the commit history. The default regex expression supports parsing semantic commit messages.`,
-		Category:    "build",
-		Scope:       actionsdk.ActionScopeProject,
-		Rules:       []actionsdk.ActionRule{},
+		Documentation: `The commits are enriched with referenced issues (requires issue_prefix, e.g. #), the merged pull requests / merge requests (requires GITHUB_TOKEN or GITLAB_TOKEN) and Renovate dependency updates are grouped into a separate section.
+Besides the rendered templates, a machine-readable changelog.json is stored as changelog artifact for other actions, like chat notifications.`,
+		Category: "build",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules:    []actionsdk.ActionRule{},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: []actionsdk.ActionAccessEnv{
+				{
+					Name:        "GITHUB_TOKEN",
+					Description: "The GitHub token is used to query the pull requests of the commits.",
+					Secret:      true,
+				},
+				{
+					Name:        "GITLAB_TOKEN",
+					Description: "The GitLab token is used to query the merge requests of the commits.",
+					Secret:      true,
+				},
+			},
 			Executables: []actionsdk.ActionAccessExecutable{},
+			Network: []actionsdk.ActionAccessNetwork{
+				{
+					Host: "api.github.com:443",
+				},
+				{
+					Host: "gitlab.com:443",
+				},
+			},
 		},
 		Output: actionsdk.ActionOutput{
 			Artifacts: []actionsdk.ActionArtifactType{
@@ -54,10 +76,10 @@ func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Confi
 			"github.changelog",
 			"gitlab.changelog",
 			"discord.changelog",
+			"release-notes.md",
+			"release-notes.html",
 		},
-		CommitPattern: []string{
-			"^(?P<type>[A-Za-z]+)((?:\\((?P<scope>[^()\\r\\n]*)\\)|\\()?(?P<breaking>!)?)(:\\s?(?P<subject>.*))?$",
-		},
+		CommitPattern: changelogcommon.DefaultCommitPattern(d.Env),
 		TitleMaps: map[string]string{
 			"build":    "Build System",
 			"ci":       "CI",
@@ -80,6 +102,7 @@ func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Confi
 				Title:   "Breaking Changes",
 			},
 		},
+		IssueURL: changelogcommon.DefaultIssueURL(d.Env["NCI_REPOSITORY_HOST_TYPE"], d.Env["NCI_REPOSITORY_PROJECT_URL"]),
 	}
 
 	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
@@ -102,46 +125,28 @@ func (a Action) Execute() (err error) {
 		return err
 	}
 
-	// find last release to generate the changelog diff
-	currentRelease := d.Env["NCI_COMMIT_REF_NAME"]
-	releases, err := a.Sdk.VCSReleasesV1(actionsdk.VCSReleasesRequest{})
+	// commits since the previous release
+	c, err := changelogcommon.FetchReleaseCommits(a.Sdk, d.Env)
 	if err != nil {
 		return err
 	}
-	previousRelease := latestReleaseOfSameType(releases, currentRelease)
-	previousReleaseVCSRef := "tag/" + previousRelease.Ref.Value
-	if previousRelease.Ref.Value == "" {
-		previousReleaseVCSRef = ""
-	}
-	c, err := a.Sdk.VCSCommitsV1(actionsdk.VCSCommitsRequest{
-		FromHash: fmt.Sprintf("hash/%s", d.Env["NCI_COMMIT_HASH"]),
-		ToHash:   previousReleaseVCSRef,
-		Limit:    1000,
-	})
-	if err != nil {
-		return err
-	}
-	_ = a.Sdk.LogV1(actionsdk.LogV1Request{
-		Level:   "debug",
-		Message: "fetch commits",
-		Context: map[string]interface{}{
-			"release_current":  currentRelease,
-			"release_previous": previousRelease.Version,
-			"from":             d.Env["NCI_COMMIT_HASH"],
-			"to":               previousReleaseVCSRef,
-			"count":            len(c),
-		},
-	})
 
 	// preprocess
 	commits := changelogcommon.PreprocessCommits(cfg.CommitPattern, c)
 
+	// merge requests
+	a.addMergeRequests(d.Env, cfg, commits)
+
 	// analyze / grouping
-	templateData := changelogcommon.ProcessCommits(changelogcommon.Config{TitleMaps: cfg.TitleMaps, NoteKeywords: cfg.NoteKeywords}, commits)
+	templateData := changelogcommon.ProcessCommits(changelogcommon.Config{TitleMaps: cfg.TitleMaps, NoteKeywords: cfg.NoteKeywords, IssuePrefix: cfg.IssuePrefix, IssueURL: cfg.IssueURL}, commits)
 	templateData.ProjectName = d.Env["NCI_PROJECT_NAME"]
 	templateData.ProjectURL = d.Env["NCI_REPOSITORY_PROJECT_URL"]
 	templateData.ReleaseDate = time.Now()
 	templateData.Version = d.Env["NCI_COMMIT_REF_NAME"]
+	if d.Env["NCI_COMMIT_REF_TYPE"] != "tag" && d.Env["NCI_NEXT_VERSION"] != "" {
+		// preview of the upcoming release, based on the configured release rules and channels
+		templateData.Version = "v" + d.Env["NCI_NEXT_VERSION"]
+	}
 
 	// render all templates
 	for _, templateFile := range cfg.Templates {
@@ -151,7 +156,11 @@ func (a Action) Execute() (err error) {
 		}
 
 		// render
-		output, outputErr := changelogcommon.RenderTemplate(&templateData, content)
+		render := changelogcommon.RenderTemplate
+		if changelogcommon.IsHTMLTemplate(templateFile) {
+			render = changelogcommon.RenderHTMLTemplate
+		}
+		output, outputErr := render(&templateData, content)
 		if outputErr != nil {
 			return fmt.Errorf("failed to render template %s", templateFile)
 		}
@@ -169,21 +178,49 @@ func (a Action) Execute() (err error) {
 		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "rendered changelog template successfully", Context: map[string]interface{}{"template": templateFile}})
 	}
 
+	// machine-readable changelog
+	output, err := changelogcommon.RenderJSON(&templateData)
+	if err != nil {
+		return fmt.Errorf("failed to render json changelog: %w", err)
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:    changelogcommon.JSONChangelogFile,
+		Content: output,
+		Type:    "changelog",
+		Format:  "json",
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func latestReleaseOfSameType(releases []actionsdk.VCSRelease, currentRelease string) actionsdk.VCSRelease {
-	currentReleaseStable := version.IsStable(currentRelease)
+// addMergeRequests adds the pull requests / merge requests to the commit context, if a token is available
+func (a Action) addMergeRequests(env map[string]string, cfg Config, commits []*actionsdk.VCSCommit) {
+	repo := mergerequest.RepositoryFromEnv(env, cfg.GitHubToken, cfg.GitLabToken)
+	if !repo.Supported() {
+		return
+	}
 
-	for _, release := range releases {
-		compare, _ := version.Compare(currentRelease, release.Version)
-		if compare > 0 && version.IsStable(release.Version) == currentReleaseStable {
-			return release
-		}
+	lookup, err := mergerequest.NewLookup(repo)
+	if err != nil {
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to create merge request lookup, skipping merge request lookup", Context: map[string]interface{}{"error": err.Error()}})
+		return
 	}
 
-	return actionsdk.VCSRelease{
-		Version: "0.0.0",
-		Ref:     vcsapi.VCSRef{},
+	for _, commit := range commits {
+		if len(commit.Context) == 0 {
+			continue
+		}
+
+		mr, err := lookup.FindByCommit(commit.Hash)
+		if err != nil {
+			_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to query merge requests, skipping merge request lookup", Context: map[string]interface{}{"error": err.Error()}})
+			return
+		}
+		if mr != nil {
+			changelogcommon.SetMergeRequest(commit, changelogcommon.MergeRequestData{ID: mr.ID, Title: mr.Title, Author: mr.Author, URL: mr.URL})
+		}
 	}
 }
@@ -1,6 +1,7 @@
 package changeloggenerate
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/cidverse/go-vcs/vcsapi"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestChangelogGenerateWithPreviousRelease(t *testing.T) {
@@ -53,6 +55,9 @@ func TestChangelogGenerateWithPreviousRelease(t *testing.T) {
 		Content: "## Features\n- add cool new feature\n\n",
 		Type:    "changelog",
 	}).Return("", "", nil)
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "changelog.json" && req.Type == "changelog" && req.Format == "json" && strings.Contains(req.Content, `"subject": "add cool new feature"`)
+	})).Return("", "", nil)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
@@ -87,6 +92,9 @@ func TestChangelogGenerateFirstRelease(t *testing.T) {
 		Content: "## Features\n- add cool new feature\n\n",
 		Type:    "changelog",
 	}).Return("", "", nil)
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "changelog.json" && req.Type == "changelog" && req.Format == "json" && strings.Contains(req.Content, `"subject": "add cool new feature"`)
+	})).Return("", "", nil)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
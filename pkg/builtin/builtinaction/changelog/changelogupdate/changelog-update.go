@@ -0,0 +1,185 @@
+package changelogupdate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changelogcommon"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/mergerequest"
+)
+
+const URI = "builtin://actions/changelog-update"
+
+type Action struct {
+	Sdk actionsdk.SDKClient
+}
+
+type Config struct {
+	File          string            `json:"file"           env:"CHANGELOG_FILE"`
+	CommitPattern []string          `json:"commit_pattern"`
+	TypeMaps      map[string]string `json:"type_maps"`
+	MergeRequest  bool              `json:"merge_request"  env:"CHANGELOG_MERGE_REQUEST"`
+	Branch        string            `json:"branch"         env:"CHANGELOG_BRANCH"`
+	GitHubToken   string            `json:"github_token"   env:"GITHUB_TOKEN"`
+	GitLabToken   string            `json:"gitlab_token"   env:"GITLAB_TOKEN"`
+}
+
+func (a Action) Metadata() actionsdk.ActionMetadata {
+	return actionsdk.ActionMetadata{
+		Name:        "changelog-update",
+		Description: `Updates the CHANGELOG.md in the Keep a Changelog format on release.`,
+		Documentation: `Inserts a section for the released version, commit types are mapped to the Added, Changed, Deprecated, Removed, Fixed and Security sections.
+Manual entries in the Unreleased section are moved into the released version, all other manual edits are kept.
+The updated changelog can be proposed with a merge request by setting CHANGELOG_MERGE_REQUEST=true, which requires a GITHUB_TOKEN or GITLAB_TOKEN with write access.
+The merge request updates the changelog of the default branch, entries added after the release tag are kept.
+The main workflow only runs this action on release if CHANGELOG_UPDATE=true is set.`,
+		Category: "build",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules: []actionsdk.ActionRule{
+			{
+				Type:       "cel",
+				Expression: `NCI_COMMIT_REF_TYPE == "tag"`,
+			},
+		},
+		Access: actionsdk.ActionAccess{
+			Environment: []actionsdk.ActionAccessEnv{
+				{
+					Name:        "CHANGELOG_.*",
+					Description: "Configuration of the changelog file and merge request.",
+					Pattern:     true,
+				},
+				{
+					Name:        "GITHUB_TOKEN",
+					Description: "The GitHub token is used to open a pull request with the updated changelog.",
+					Secret:      true,
+				},
+				{
+					Name:        "GITLAB_TOKEN",
+					Description: "The GitLab token is used to open a merge request with the updated changelog.",
+					Secret:      true,
+				},
+			},
+			Network: []actionsdk.ActionAccessNetwork{
+				{
+					Host: actionsdk.NetworkHostRepositoryAPI,
+				},
+			},
+		},
+		Output: actionsdk.ActionOutput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "changelog",
+					Format: "markdown",
+				},
+			},
+		},
+	}
+}
+
+func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Config, error) {
+	cfg := Config{
+		File:          "CHANGELOG.md",
+		CommitPattern: changelogcommon.DefaultCommitPattern(d.Env),
+		TypeMaps:      changelogcommon.DefaultKeepAChangelogTypeMaps,
+		Branch:        "cid/changelog",
+	}
+
+	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func (a Action) Execute() (err error) {
+	// query action data
+	d, err := a.Sdk.ProjectExecutionContextV1()
+	if err != nil {
+		return err
+	}
+
+	// parse config
+	cfg, err := a.GetConfig(d)
+	if err != nil {
+		return err
+	}
+
+	// commits since the previous release
+	c, err := changelogcommon.FetchReleaseCommits(a.Sdk, d.Env)
+	if err != nil {
+		return err
+	}
+	commits := changelogcommon.PreprocessCommits(cfg.CommitPattern, c)
+	templateData := changelogcommon.ProcessCommits(changelogcommon.Config{}, commits)
+	entries := changelogcommon.KeepAChangelogEntries(&templateData, cfg.TypeMaps)
+
+	// update changelog
+	file := actionsdk.JoinPath(d.ProjectDir, cfg.File)
+	content := ""
+	if a.Sdk.FileExistsV1(file) {
+		content, err = a.Sdk.FileReadV1(file)
+		if err != nil {
+			return fmt.Errorf("failed to read changelog %s: %w", cfg.File, err)
+		}
+	}
+
+	releaseVersion := d.Env["NCI_COMMIT_REF_RELEASE"]
+	if releaseVersion == "" {
+		releaseVersion = strings.TrimPrefix(d.Env["NCI_COMMIT_REF_NAME"], "v")
+	}
+	releaseDate := time.Now()
+	updated := changelogcommon.UpdateKeepAChangelog(content, releaseVersion, releaseDate, entries)
+	if updated == content {
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "changelog already contains the release", Context: map[string]interface{}{"file": cfg.File, "version": releaseVersion}})
+		return nil
+	}
+
+	err = a.Sdk.FileWriteV1(file, []byte(updated))
+	if err != nil {
+		return fmt.Errorf("failed to write changelog %s: %w", cfg.File, err)
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:    "CHANGELOG.md",
+		Content: updated,
+		Type:    "changelog",
+		Format:  "markdown",
+	})
+	if err != nil {
+		return err
+	}
+	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "updated changelog", Context: map[string]interface{}{"file": cfg.File, "version": releaseVersion}})
+
+	// merge request
+	if !cfg.MergeRequest {
+		return nil
+	}
+
+	// the changelog of the default branch may contain changes made after the release tag, the release is inserted into the current content
+	repo := mergerequest.RepositoryFromEnv(d.Env, cfg.GitHubToken, cfg.GitLabToken)
+	commitMessage := fmt.Sprintf("docs: update changelog for %s", releaseVersion)
+	req := mergerequest.SubmitRequest{
+		BaseBranch:    d.Env["NCI_PROJECT_DEFAULT_BRANCH"],
+		Branch:        cfg.Branch,
+		CommitMessage: commitMessage,
+		Title:         commitMessage,
+		Description:   fmt.Sprintf("Adds the release notes of version %s to %s.", releaseVersion, cfg.File),
+		Files: map[string]mergerequest.FileUpdate{
+			cfg.File: func(content string) string {
+				return changelogcommon.UpdateKeepAChangelog(content, releaseVersion, releaseDate, entries)
+			},
+		},
+	}
+
+	url, err := mergerequest.Submit(repo, req)
+	if err != nil {
+		return fmt.Errorf("failed to open merge request with the updated changelog: %w", err)
+	} else if url == "" {
+		return nil
+	}
+	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "opened merge request with the updated changelog", Context: map[string]interface{}{"url": url}})
+
+	return nil
+}
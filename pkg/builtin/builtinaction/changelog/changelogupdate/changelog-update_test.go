@@ -0,0 +1,44 @@
+package changelogupdate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changelogcommon"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangelogUpdate(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("ProjectExecutionContextV1").Return(changelogcommon.TestProjectData(), nil)
+	sdk.On("VCSReleasesV1", actionsdk.VCSReleasesRequest{}).Return([]actionsdk.VCSRelease{
+		{Version: "1.1.0", Ref: vcsapi.VCSRef{Type: "tag", Value: "v1.1.0"}},
+	}, nil)
+	sdk.On("VCSCommitsV1", actionsdk.VCSCommitsRequest{
+		FromHash: "hash/abcdef123456",
+		ToHash:   "tag/v1.1.0",
+		Limit:    1000,
+	}).Return([]*actionsdk.VCSCommit{
+		{Hash: "a1", Message: "feat: add cool new feature"},
+		{Hash: "a2", Message: "fix(api): resolve issue"},
+		{Hash: "a3", Message: "ci: update pipeline"},
+	}, nil)
+	sdk.On("FileExistsV1", "/my-project/CHANGELOG.md").Return(true)
+	sdk.On("FileReadV1", "/my-project/CHANGELOG.md").Return("# Changelog\n\n## [Unreleased]\n\n### Added\n\n- manual entry\n\n## [1.1.0] - 2021-01-01\n\n### Fixed\n\n- old fix\n", nil)
+	sdk.On("FileWriteV1", "/my-project/CHANGELOG.md", mock.MatchedBy(func(content []byte) bool {
+		return strings.Contains(string(content), "## [Unreleased]\n\n## [1.2.0] - ") &&
+			strings.Contains(string(content), "### Added\n\n- manual entry\n- add cool new feature\n\n### Fixed\n\n- **api:** resolve issue\n\n## [1.1.0] - 2021-01-01") &&
+			!strings.Contains(string(content), "update pipeline")
+	})).Return(nil)
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "CHANGELOG.md" && req.Type == "changelog" && req.Format == "markdown"
+	})).Return("", "", nil)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,38 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
+)
+
+// ReleaseRef returns the ref type and release name used to version the artifacts of the current module.
+// Modules with independent versions (module tags, e.g. my-module/v1.2.3) are only released by their own module tag, otherwise a snapshot of the next module version is built.
+func ReleaseRef(env map[string]string) (refType string, release string) {
+	if env["NCI_MODULE_VERSION"] == "" {
+		return env["NCI_COMMIT_REF_TYPE"], env["NCI_COMMIT_REF_RELEASE"]
+	}
+
+	if env["NCI_MODULE_RELEASE"] == "true" {
+		return "tag", env["NCI_MODULE_VERSION"]
+	}
+	return "branch", env["NCI_MODULE_NEXT_VERSION"]
+}
+
+// TagRelease returns the module slug and the version of a release tag, the module is empty for repository releases (v1.2.3)
+func TagRelease(tag string) (module string, release string) {
+	if slug, ver, ok := commitanalyser.ParseModuleTag(tag); ok {
+		return slug, ver
+	}
+
+	return "", strings.TrimPrefix(tag, "v")
+}
+
+// ReleaseArtifactQuery restricts the artifact query to the artifacts of the released module, if the tag is a module tag
+func ReleaseArtifactQuery(query string, tag string) string {
+	if module, _ := TagRelease(tag); module != "" {
+		return "(" + query + `) && module == "` + module + `"`
+	}
+
+	return query
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseRef(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		expectedType    string
+		expectedRelease string
+	}{
+		{
+			name:            "repository version",
+			env:             map[string]string{"NCI_COMMIT_REF_TYPE": "tag", "NCI_COMMIT_REF_RELEASE": "1.2.0"},
+			expectedType:    "tag",
+			expectedRelease: "1.2.0",
+		},
+		{
+			name:            "module release",
+			env:             map[string]string{"NCI_COMMIT_REF_TYPE": "tag", "NCI_COMMIT_REF_RELEASE": "api/v2.0.0", "NCI_MODULE_VERSION": "2.0.0", "NCI_MODULE_NEXT_VERSION": "2.0.0", "NCI_MODULE_RELEASE": "true"},
+			expectedType:    "tag",
+			expectedRelease: "2.0.0",
+		},
+		{
+			name:            "tag of another module",
+			env:             map[string]string{"NCI_COMMIT_REF_TYPE": "tag", "NCI_COMMIT_REF_RELEASE": "api/v2.0.0", "NCI_MODULE_VERSION": "1.4.0", "NCI_MODULE_NEXT_VERSION": "1.5.0", "NCI_MODULE_RELEASE": "false"},
+			expectedType:    "branch",
+			expectedRelease: "1.5.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refType, release := ReleaseRef(tt.env)
+			assert.Equal(t, tt.expectedType, refType)
+			assert.Equal(t, tt.expectedRelease, release)
+		})
+	}
+}
+
+func TestTagRelease(t *testing.T) {
+	module, release := TagRelease("v1.2.0")
+	assert.Equal(t, "", module)
+	assert.Equal(t, "1.2.0", release)
+
+	module, release = TagRelease("api/v2.0.0-rc.1")
+	assert.Equal(t, "api", module)
+	assert.Equal(t, "2.0.0-rc.1", release)
+}
+
+func TestReleaseArtifactQuery(t *testing.T) {
+	assert.Equal(t, `artifact_type == "binary"`, ReleaseArtifactQuery(`artifact_type == "binary"`, "v1.2.0"))
+	assert.Equal(t, `(artifact_type == "binary") && module == "api"`, ReleaseArtifactQuery(`artifact_type == "binary"`, "api/v1.2.0"))
+}
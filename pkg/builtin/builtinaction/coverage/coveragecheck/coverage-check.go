@@ -0,0 +1,232 @@
+package coveragecheck
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/coveragepolicy"
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+	"github.com/cidverse/cid/pkg/lib/gitdiff"
+	"github.com/cidverse/cid/pkg/lib/mergerequest"
+)
+
+const URI = "builtin://actions/coverage-check"
+
+type Action struct {
+	Sdk actionsdk.SDKClient
+}
+
+type Config struct {
+	ProjectMinimum float64            `json:"project_minimum"  env:"COVERAGE_MIN_PROJECT"  validate:"gte=0,lte=100"`
+	ModuleMinimum  float64            `json:"module_minimum"   env:"COVERAGE_MIN_MODULE"   validate:"gte=0,lte=100"`
+	ModuleMinimums map[string]float64 `json:"module_minimums"`
+	DiffMinimum    float64            `json:"diff_minimum"     env:"COVERAGE_MIN_DIFF"     validate:"gte=0,lte=100"`
+	Mode           string             `json:"mode"             env:"COVERAGE_MODE"         validate:"oneof=fail warn"`
+	Comment        bool               `json:"comment"          env:"COVERAGE_COMMENT"`
+	GitHubToken    string             `json:"github_token"     env:"GITHUB_TOKEN"`
+	GitLabToken    string             `json:"gitlab_token"     env:"GITLAB_TOKEN"`
+}
+
+func (a Action) Metadata() actionsdk.ActionMetadata {
+	return actionsdk.ActionMetadata{
+		Name:        "coverage-check",
+		Description: "Checks the project, module and diff coverage against configurable minimums.",
+		Documentation: `Uses the merged coverage of all modules to enforce a minimum coverage for the project and for each module.
+For merge requests, the diff coverage (the percentage of changed lines that are covered) is calculated against the merge base of the target branch.
+
+The result is published as Markdown summary and as a comment on the pull request / merge request. Depending on the mode, a failed check fails the step or only logs a warning.
+Per module minimums can be configured in the action config:
+
+` + "```yaml" + `
+module_minimums:
+  github-com-cidverse-my-project: 75
+` + "```",
+		Category: "test",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules: []actionsdk.ActionRule{
+			{
+				Type:       "cel",
+				Expression: `size(PROJECT_BUILD_SYSTEMS) > 0`,
+			},
+		},
+		Access: actionsdk.ActionAccess{
+			Environment: []actionsdk.ActionAccessEnv{
+				{
+					Name:        "COVERAGE_MIN_PROJECT",
+					Description: "Minimum line coverage of the project in percent.",
+				},
+				{
+					Name:        "COVERAGE_MIN_MODULE",
+					Description: "Minimum line coverage of each module in percent.",
+				},
+				{
+					Name:        "COVERAGE_MIN_DIFF",
+					Description: "Minimum coverage of the lines changed by a merge request in percent.",
+				},
+				{
+					Name:        "COVERAGE_MODE",
+					Description: "fail (default) fails the step if a check fails, warn only logs a warning.",
+				},
+				{
+					Name:        "COVERAGE_COMMENT",
+					Description: "Comment the summary on the pull request / merge request. Defaults to true.",
+				},
+				{
+					Name:        "GITHUB_TOKEN",
+					Description: "The GitHub token used to comment on pull requests.",
+					Secret:      true,
+				},
+				{
+					Name:        "GITLAB_TOKEN",
+					Description: "The GitLab token used to comment on merge requests.",
+					Secret:      true,
+				},
+			},
+			Network: []actionsdk.ActionAccessNetwork{
+				{
+					Host: actionsdk.NetworkHostRepositoryAPI,
+				},
+			},
+		},
+		Input: actionsdk.ActionInput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "coverage",
+				},
+			},
+		},
+		Output: actionsdk.ActionOutput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "markdown",
+				},
+			},
+		},
+	}
+}
+
+func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Config, error) {
+	cfg := Config{
+		Mode:    "fail",
+		Comment: true,
+	}
+
+	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func (a Action) Execute() (err error) {
+	// query action data
+	d, err := a.Sdk.ProjectExecutionContextV1()
+	if err != nil {
+		return err
+	}
+
+	// parse config
+	cfg, err := a.GetConfig(d)
+	if err != nil {
+		return err
+	}
+
+	// merged coverage
+	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: `artifact_type == "report" && format == "coverage"`})
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "no coverage report available, skipping coverage check"})
+		return nil
+	}
+	content, err := a.Sdk.ArtifactDownloadByteArrayV1(actionsdk.ArtifactDownloadByteArrayRequest{ID: artifacts[0].ArtifactID})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve coverage report %s: %w", artifacts[0].ArtifactID, err)
+	}
+	var project coverage.ProjectReport
+	if err = json.Unmarshal(content.Bytes, &project); err != nil {
+		return fmt.Errorf("failed to parse coverage report %s: %w", artifacts[0].ArtifactID, err)
+	}
+	if project.Report == nil {
+		project.Report = coverage.NewReport()
+	}
+
+	// diff coverage
+	var diff *coveragepolicy.DiffCoverage
+	if targetBranch := d.Env["NCI_MERGE_REQUEST_TARGET_BRANCH_NAME"]; targetBranch != "" {
+		diffs, err := a.Sdk.VCSDiffV1(actionsdk.VCSDiffRequest{
+			FromHash:  "branch/" + targetBranch,
+			ToHash:    "hash/" + d.Env["NCI_COMMIT_HASH"],
+			MergeBase: true,
+		})
+		if err != nil {
+			_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to calculate diff, skipping diff coverage", Context: map[string]interface{}{"target_branch": targetBranch, "error": err.Error()}})
+		} else {
+			result := coveragepolicy.CalculateDiffCoverage(project.Report, changedLines(diffs))
+			diff = &result
+		}
+	}
+
+	// evaluate
+	result := coveragepolicy.Evaluate(coveragepolicy.Policy{
+		ProjectMinimum: cfg.ProjectMinimum,
+		ModuleMinimum:  cfg.ModuleMinimum,
+		ModuleMinimums: cfg.ModuleMinimums,
+		DiffMinimum:    cfg.DiffMinimum,
+	}, project, diff)
+
+	// summary
+	summary, err := coveragepolicy.RenderMarkdownSummary(project, diff, result)
+	if err != nil {
+		return err
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:    actionsdk.JoinPath(d.Config.TempDir, "coverage-summary.md"),
+		Content: summary,
+		Type:    "report",
+		Format:  "markdown",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload coverage summary: %w", err)
+	}
+
+	// comment on the pull request / merge request
+	if cfg.Comment && d.Env["NCI_MERGE_REQUEST_ID"] != "" {
+		repo := mergerequest.RepositoryFromEnv(d.Env, cfg.GitHubToken, cfg.GitLabToken)
+		if err = mergerequest.UpsertComment(repo, d.Env["NCI_MERGE_REQUEST_ID"], coveragepolicy.SummaryMarker, summary); err != nil {
+			_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to comment coverage summary on merge request", Context: map[string]interface{}{"error": err.Error()}})
+		}
+	}
+
+	// result
+	failed := result.Failed()
+	for _, c := range failed {
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "coverage below minimum", Context: map[string]interface{}{"check": c.Name, "coverage": fmt.Sprintf("%.2f%%", c.Actual), "minimum": fmt.Sprintf("%.2f%%", c.Minimum)}})
+	}
+	if len(failed) > 0 && cfg.Mode == "fail" {
+		return fmt.Errorf("coverage check failed, %d of %d check(s) below the minimum", len(failed), len(result.Checks))
+	}
+
+	return nil
+}
+
+// changedLines returns the added or modified line numbers of the new file version, per file path
+func changedLines(diffs []actionsdk.VCSDiff) map[string][]int {
+	result := make(map[string][]int)
+	for _, d := range diffs {
+		if d.FileTo.Name == "" {
+			continue // deleted file
+		}
+
+		for _, h := range d.Hunks {
+			result[d.FileTo.Name] = append(result[d.FileTo.Name], gitdiff.Hunk(h).ToLineNumbers()...)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,206 @@
+package coveragecheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/coverage/coveragemerge"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/gitdiff"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleCoverage = `{
+  "summary": {"lines_valid": 4, "lines_covered": 2},
+  "modules": {"backend": {"lines_valid": 4, "lines_covered": 2}},
+  "report": {"files": {"backend/api.go": {"path": "backend/api.go", "lines": {
+    "1": {"number": 1, "hits": 1},
+    "2": {"number": 2, "hits": 1},
+    "3": {"number": 3, "hits": 0},
+    "4": {"number": 4, "hits": 0}
+  }}}}
+}`
+
+func setup(t *testing.T, env map[string]string) *actionsdk.MockSDKClient {
+	return setupWithCoverage(t, env, []byte(sampleCoverage))
+}
+
+func setupWithCoverage(t *testing.T, env map[string]string, projectCoverage []byte) *actionsdk.MockSDKClient {
+	sdk := common.TestSetup(t)
+	data := common.TestProjectData()
+	for k, v := range env {
+		data.Env[k] = v
+	}
+	sdk.On("ProjectExecutionContextV1").Return(data, nil)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report" && format == "coverage"`}).Return([]*actionsdk.Artifact{
+		{ArtifactID: "root|report|coverage.json", Module: "root", Type: "report", Format: "coverage", FormatVersion: "json"},
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "root|report|coverage.json"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: projectCoverage,
+	}, nil)
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "/my-project/.tmp/coverage-summary.md" && req.Format == "markdown"
+	})).Return("", "", nil)
+
+	return sdk
+}
+
+func TestCoverageCheckProjectMinimum(t *testing.T) {
+	sdk := setup(t, map[string]string{"COVERAGE_MIN_PROJECT": "80"})
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.ErrorContains(t, err, "1 of 1 check(s) below the minimum")
+}
+
+func TestCoverageCheckWarnMode(t *testing.T) {
+	sdk := setup(t, map[string]string{"COVERAGE_MIN_PROJECT": "80", "COVERAGE_MODE": "warn"})
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+}
+
+func TestCoverageCheckDiff(t *testing.T) {
+	sdk := setup(t, map[string]string{
+		"COVERAGE_MIN_DIFF":                    "50",
+		"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main",
+	})
+	sdk.On("VCSDiffV1", actionsdk.VCSDiffRequest{FromHash: "branch/main", ToHash: "hash/abcdef123456", MergeBase: true}).Return([]actionsdk.VCSDiff{
+		{
+			FileFrom: actionsdk.VCSFile{Name: "backend/api.go"},
+			FileTo:   actionsdk.VCSFile{Name: "backend/api.go"},
+			Hunks:    []actionsdk.VCSDiffHunk{{FromLine: 2, FromLines: 1, ToLine: 2, ToLines: 2}},
+		},
+	}, nil)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+}
+
+func TestChangedLines(t *testing.T) {
+	lines := changedLines([]actionsdk.VCSDiff{
+		{
+			FileFrom: actionsdk.VCSFile{Name: "main.go"},
+			FileTo:   actionsdk.VCSFile{Name: "main.go"},
+			Hunks: []actionsdk.VCSDiffHunk{
+				{FromLine: 2, FromLines: 0, ToLine: 3, ToLines: 1},
+				{FromLine: 4, FromLines: 1, ToLine: 5, ToLines: 0},
+				{FromLine: 6, FromLines: 1, ToLine: 6, ToLines: 2},
+			},
+		},
+		{
+			FileTo: actionsdk.VCSFile{Name: "new.go"},
+			Hunks:  []actionsdk.VCSDiffHunk{{FromLine: 0, FromLines: 0, ToLine: 1, ToLines: 2}},
+		},
+		{
+			FileFrom: actionsdk.VCSFile{Name: "deleted.go"},
+			Hunks:    []actionsdk.VCSDiffHunk{{FromLine: 1, FromLines: 1, ToLine: 0, ToLines: 0}},
+		},
+	})
+
+	assert.Equal(t, map[string][]int{"main.go": {3, 6, 7}, "new.go": {1, 2}}, lines)
+}
+
+const sampleJacoco = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<report name="backend">
+  <package name="com/example">
+    <sourcefile name="Main.java">
+      <line nr="3" mi="0" ci="3" mb="0" cb="0"/>
+      <line nr="4" mi="2" ci="0" mb="0" cb="0"/>
+      <line nr="5" mi="0" ci="2" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>`
+
+// mergeJacoco runs the coverage merge of a JaCoCo report for a maven module and returns the merged project coverage
+func mergeJacoco(t *testing.T, projectDir string) []byte {
+	t.Helper()
+
+	sdk := common.TestSetup(t)
+	data := common.TestProjectData()
+	data.ProjectDir = projectDir
+	data.Modules = []*actionsdk.ProjectModule{
+		{ModuleDir: filepath.Join(projectDir, "backend"), Name: "backend", Slug: "backend", BuildSystem: "maven", Files: []string{
+			filepath.Join(projectDir, "backend", "pom.xml"),
+			filepath.Join(projectDir, "backend", "src", "main", "java", "com", "example", "Main.java"),
+		}},
+	}
+	sdk.On("ProjectExecutionContextV1").Return(data, nil)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report"`}).Return([]*actionsdk.Artifact{
+		{ArtifactID: "backend|report|jacoco.xml", Module: "backend", Type: "report", Format: "jacoco"},
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "backend|report|jacoco.xml"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte(sampleJacoco),
+	}, nil)
+	var merged []byte
+	sdk.On("ArtifactUploadV1", mock.Anything).Run(func(args mock.Arguments) {
+		if req := args.Get(0).(actionsdk.ArtifactUploadRequest); req.Format == "coverage" {
+			merged = req.ContentBytes
+		}
+	}).Return("", "", nil)
+
+	require.NoError(t, coveragemerge.Action{Sdk: sdk}.Execute())
+	require.NotEmpty(t, merged)
+
+	return merged
+}
+
+// diffRepository commits a java source file and a change of it, the diff between both commits is returned like the vcs diff of the sdk
+func diffRepository(t *testing.T, dir string) []actionsdk.VCSDiff {
+	t.Helper()
+
+	file := "backend/src/main/java/com/example/Main.java"
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	commit := func(content string) string {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+		_, err := wt.Add(file)
+		require.NoError(t, err)
+		hash, err := wt.Commit("update", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()}})
+		require.NoError(t, err)
+		return hash.String()
+	}
+	base := commit("package com.example;\n\nclass Main {\n}\n")
+	head := commit("package com.example;\n\nclass Main {\n  void a() {}\n  void b() {}\n}\n")
+
+	hunks, err := gitdiff.Hunks(dir, base, head)
+	require.NoError(t, err)
+	var result []actionsdk.VCSDiff
+	for path, fileHunks := range hunks {
+		d := actionsdk.VCSDiff{FileFrom: actionsdk.VCSFile{Name: path}, FileTo: actionsdk.VCSFile{Name: path}}
+		for _, h := range fileHunks {
+			d.Hunks = append(d.Hunks, actionsdk.VCSDiffHunk{FromLine: h.FromLine, FromLines: h.FromLines, ToLine: h.ToLine, ToLines: h.ToLines})
+		}
+		result = append(result, d)
+	}
+
+	return result
+}
+
+func TestCoverageCheckDiffJacoco(t *testing.T) {
+	projectDir := t.TempDir()
+	diffs := diffRepository(t, projectDir)
+	merged := mergeJacoco(t, projectDir)
+
+	sdk := setupWithCoverage(t, map[string]string{
+		"COVERAGE_MIN_DIFF":                    "75",
+		"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main",
+	}, merged)
+	sdk.On("VCSDiffV1", actionsdk.VCSDiffRequest{FromHash: "branch/main", ToHash: "hash/abcdef123456", MergeBase: true}).Return(diffs, nil)
+
+	// the changed lines 4 and 5 are reported relative to the source root, only one of them is covered
+	err := Action{Sdk: sdk}.Execute()
+	assert.ErrorContains(t, err, "1 of 1 check(s) below the minimum")
+}
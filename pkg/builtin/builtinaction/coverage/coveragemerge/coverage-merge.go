@@ -0,0 +1,255 @@
+package coveragemerge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+)
+
+const URI = "builtin://actions/coverage-merge"
+
+// formatPriority defines which report is used if a module provides multiple coverage formats, to avoid counting the same lines twice
+var formatPriority = []coverage.Format{
+	coverage.FormatGoCoverage,
+	coverage.FormatLCOV,
+	coverage.FormatCobertura,
+	coverage.FormatJacoco,
+	coverage.FormatOpenCover,
+	coverage.FormatClover,
+}
+
+type Action struct {
+	Sdk actionsdk.SDKClient
+}
+
+type Config struct {
+}
+
+func (a Action) Metadata() actionsdk.ActionMetadata {
+	var input []actionsdk.ActionArtifactType
+	for _, f := range formatPriority {
+		input = append(input, actionsdk.ActionArtifactType{Type: "report", Format: string(f)})
+	}
+
+	return actionsdk.ActionMetadata{
+		Name:        "coverage-merge",
+		Description: "Merges the coverage reports of all modules into a single project coverage.",
+		Documentation: `Collects the coverage reports of all modules (go coverage profile, LCOV, Cobertura, JaCoCo, OpenCover, Clover), converts them into a common model and merges them into one project total.
+If a module provides multiple formats, only one of them is used in the order listed above.
+File paths that are relative to a source root (e.g. com/example/Main.java in JaCoCo reports) are resolved to the matching module file, so they can be compared with the changed files of a merge request.
+The merged report is published as JSON and as Cobertura XML for tools and platforms that display coverage.`,
+		Category: "test",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules: []actionsdk.ActionRule{
+			{
+				Type:       "cel",
+				Expression: `size(PROJECT_BUILD_SYSTEMS) > 0`,
+			},
+		},
+		Access: actionsdk.ActionAccess{
+			Environment: []actionsdk.ActionAccessEnv{},
+		},
+		Input: actionsdk.ActionInput{
+			Artifacts: input,
+		},
+		Output: actionsdk.ActionOutput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "coverage",
+				},
+				{
+					Type:   "report",
+					Format: string(coverage.FormatCobertura),
+				},
+			},
+		},
+	}
+}
+
+func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Config, error) {
+	cfg := Config{}
+
+	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func (a Action) Execute() (err error) {
+	// query action data
+	d, err := a.Sdk.ProjectExecutionContextV1()
+	if err != nil {
+		return err
+	}
+
+	// parse config
+	_, err = a.GetConfig(d)
+	if err != nil {
+		return err
+	}
+
+	// collect coverage reports, grouped by module
+	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: `artifact_type == "report"`})
+	if err != nil {
+		return err
+	}
+	reportsByModule := make(map[string][]*actionsdk.Artifact)
+	for _, artifact := range artifacts {
+		if !slices.Contains(formatPriority, coverage.Format(artifact.Format)) {
+			continue
+		}
+		if artifact.Format == string(coverage.FormatGoCoverage) && artifact.FormatVersion != "out" {
+			continue
+		}
+		reportsByModule[artifact.Module] = append(reportsByModule[artifact.Module], artifact)
+	}
+
+	// parse and merge
+	result := coverage.ProjectReport{Modules: make(map[string]coverage.Summary)}
+	var reports []*coverage.Report
+	for moduleSlug, moduleArtifacts := range reportsByModule {
+		module := findModule(d.Modules, moduleSlug)
+		format := preferredFormat(moduleArtifacts)
+
+		var moduleReports []*coverage.Report
+		for _, artifact := range moduleArtifacts {
+			if coverage.Format(artifact.Format) != format {
+				continue
+			}
+
+			content, err := a.Sdk.ArtifactDownloadByteArrayV1(actionsdk.ArtifactDownloadByteArrayRequest{ID: artifact.ArtifactID})
+			if err != nil {
+				return fmt.Errorf("failed to retrieve coverage report %s: %w", artifact.ArtifactID, err)
+			}
+
+			opts := coverage.ParseOptions{}
+			if module != nil && module.BuildSystem == "gomod" {
+				opts.GoModulePath = module.Name
+			}
+			report, err := coverage.Parse(format, bytes.NewReader(content.Bytes), opts)
+			if err != nil {
+				return fmt.Errorf("failed to parse coverage report %s: %w", artifact.ArtifactID, err)
+			}
+			moduleReports = append(moduleReports, report)
+		}
+
+		moduleReport := coverage.Merge(moduleReports...).MapPaths(func(p string) string {
+			return projectRelativePath(d.ProjectDir, module, p)
+		})
+		result.Modules[moduleSlug] = moduleReport.Summary()
+		reports = append(reports, moduleReport)
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "module coverage", Context: map[string]interface{}{"module": moduleSlug, "format": format, "line_coverage": fmt.Sprintf("%.2f%%", result.Modules[moduleSlug].LinePercent())}})
+	}
+	result.Report = coverage.Merge(reports...)
+	result.Summary = result.Report.Summary()
+	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "project coverage", Context: map[string]interface{}{"modules": len(result.Modules), "lines_valid": result.Summary.LinesValid, "lines_covered": result.Summary.LinesCovered, "line_coverage": fmt.Sprintf("%.2f%%", result.Summary.LinePercent())}})
+
+	// store result
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal coverage report: %w", err)
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:          actionsdk.JoinPath(d.Config.TempDir, "coverage.json"),
+		ContentBytes:  resultJSON,
+		Type:          "report",
+		Format:        "coverage",
+		FormatVersion: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload coverage report: %w", err)
+	}
+
+	var cobertura bytes.Buffer
+	if err = coverage.WriteCobertura(&cobertura, result.Report); err != nil {
+		return fmt.Errorf("failed to convert coverage report to cobertura: %w", err)
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:          actionsdk.JoinPath(d.Config.TempDir, "cobertura.xml"),
+		ContentBytes:  cobertura.Bytes(),
+		Type:          "report",
+		Format:        string(coverage.FormatCobertura),
+		FormatVersion: "xml",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload cobertura report: %w", err)
+	}
+
+	return nil
+}
+
+// preferredFormat returns the format with the highest priority that is present in the artifacts
+func preferredFormat(artifacts []*actionsdk.Artifact) coverage.Format {
+	for _, f := range formatPriority {
+		if slices.ContainsFunc(artifacts, func(a *actionsdk.Artifact) bool { return a.Format == string(f) }) {
+			return f
+		}
+	}
+
+	return ""
+}
+
+func findModule(modules []*actionsdk.ProjectModule, slug string) *actionsdk.ProjectModule {
+	for _, m := range modules {
+		if m.Slug == slug {
+			return m
+		}
+		if sub := findModule(m.Submodules, slug); sub != nil {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// projectRelativePath converts absolute paths, module relative paths and source root relative paths into paths relative to the project root
+func projectRelativePath(projectDir string, module *actionsdk.ProjectModule, p string) string {
+	if filepath.IsAbs(p) || strings.HasPrefix(p, "/") {
+		if rel, err := filepath.Rel(projectDir, p); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return p
+	}
+
+	if module != nil && module.ModuleDir != "" {
+		p = moduleRelativePath(module, p)
+		if moduleRel, err := filepath.Rel(projectDir, module.ModuleDir); err == nil && moduleRel != "." && !strings.HasPrefix(moduleRel, "..") {
+			return filepath.ToSlash(filepath.Join(moduleRel, p))
+		}
+	}
+
+	return p
+}
+
+// moduleRelativePath resolves paths relative to a source root (e.g. com/example/Main.java) to the module file with the same path suffix (e.g. src/main/java/com/example/Main.java)
+func moduleRelativePath(module *actionsdk.ProjectModule, p string) string {
+	moduleDir := filepath.ToSlash(module.ModuleDir)
+	match := ""
+	for _, file := range module.Files {
+		rel, ok := strings.CutPrefix(filepath.ToSlash(file), moduleDir+"/")
+		if !ok {
+			continue
+		}
+
+		if rel == p {
+			return p
+		}
+		if match == "" && strings.HasSuffix(rel, "/"+p) {
+			match = rel
+		}
+	}
+
+	if match != "" {
+		return match
+	}
+	return p
+}
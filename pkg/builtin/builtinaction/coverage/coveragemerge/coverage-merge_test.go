@@ -0,0 +1,70 @@
+package coveragemerge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCoverageMerge(t *testing.T) {
+	sdk := common.TestSetup(t)
+	data := common.TestProjectData()
+	data.Modules = []*actionsdk.ProjectModule{
+		{ModuleDir: "/my-project/backend", Name: "github.com/cidverse/backend", Slug: "backend", BuildSystem: "gomod"},
+		{ModuleDir: "/my-project/frontend", Name: "frontend", Slug: "frontend", BuildSystem: "npm"},
+	}
+	sdk.On("ProjectExecutionContextV1").Return(data, nil)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report"`}).Return([]*actionsdk.Artifact{
+		{ArtifactID: "backend|report|cover.out", Module: "backend", Type: "report", Format: "go-coverage", FormatVersion: "out"},
+		{ArtifactID: "backend|report|cover.html", Module: "backend", Type: "report", Format: "go-coverage", FormatVersion: "html"},
+		{ArtifactID: "backend|report|cobertura.xml", Module: "backend", Type: "report", Format: "cobertura"},
+		{ArtifactID: "frontend|report|lcov.info", Module: "frontend", Type: "report", Format: "lcov"},
+		{ArtifactID: "frontend|report|junit.xml", Module: "frontend", Type: "report", Format: "junit"},
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "backend|report|cover.out"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte("mode: set\ngithub.com/cidverse/backend/main.go:3.13,5.2 1 1\ngithub.com/cidverse/backend/main.go:7.13,8.2 1 0\n"),
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "frontend|report|lcov.info"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte("SF:src/index.js\nDA:1,1\nDA:2,0\nend_of_record\n"),
+	}, nil)
+
+	var result coverage.ProjectReport
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "/my-project/.tmp/coverage.json" && req.Format == "coverage" && json.Unmarshal(req.ContentBytes, &result) == nil
+	})).Return("", "", nil)
+	var cobertura string
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		if req.File != "/my-project/.tmp/cobertura.xml" || req.Format != "cobertura" {
+			return false
+		}
+		cobertura = string(req.ContentBytes)
+		return true
+	})).Return("", "", nil)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.Summary.LinesValid)
+	assert.Equal(t, 4, result.Summary.LinesCovered)
+	assert.Equal(t, 3, result.Modules["backend"].LinesCovered)
+	assert.Contains(t, result.Report.Files, "backend/main.go")
+	assert.Contains(t, result.Report.Files, "frontend/src/index.js")
+	assert.Contains(t, cobertura, `filename="frontend/src/index.js"`)
+}
+
+func TestProjectRelativePath(t *testing.T) {
+	module := &actionsdk.ProjectModule{ModuleDir: "/my-project/backend", Files: []string{
+		"/my-project/backend/pom.xml",
+		"/my-project/backend/src/main/java/com/example/Main.java",
+	}}
+
+	assert.Equal(t, "backend/src/main/java/com/example/Main.java", projectRelativePath("/my-project", module, "com/example/Main.java"))
+	assert.Equal(t, "backend/src/main/java/com/example/Main.java", projectRelativePath("/my-project", module, "src/main/java/com/example/Main.java"))
+	assert.Equal(t, "backend/src/main/java/com/example/Main.java", projectRelativePath("/my-project", module, "/my-project/backend/src/main/java/com/example/Main.java"))
+	assert.Equal(t, "backend/com/example/Other.java", projectRelativePath("/my-project", module, "com/example/Other.java"))
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 
 	"strings"
 )
@@ -16,6 +18,7 @@ type Action struct {
 }
 
 type Config struct {
+	Quarantine []string `json:"quarantine"  env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
@@ -31,7 +34,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name: "dotnet",
@@ -76,7 +79,7 @@ func (a Action) Execute() (err error) {
 	}
 
 	// parse config
-	_, err = a.GetConfig(d)
+	cfg, err := a.GetConfig(d)
 	if err != nil {
 		return err
 	}
@@ -103,9 +106,8 @@ func (a Action) Execute() (err error) {
 	})
 	if err != nil {
 		return err
-	} else if cmdResult.Code != 0 {
-		return fmt.Errorf("dotnet test failed, exit code %d", cmdResult.Code)
 	}
+	testsFailed := cmdResult.Code != 0
 
 	// store report
 	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
@@ -146,5 +148,14 @@ func (a Action) Execute() (err error) {
 		}
 	}
 
-	return nil
+	// test results
+	report, err := testresultcommon.ReadReports(a.Sdk, testresult.FormatTRX, trxReport)
+	if err != nil {
+		return err
+	}
+
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
@@ -43,6 +43,7 @@ func TestDotNetTest(t *testing.T) {
 		Type:   "report",
 		Format: "cobertura",
 	}).Return("", "", nil)
+	sdk.On("FileExistsV1", "/my-project/.tmp/vstest.trx").Return(false)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
@@ -95,7 +95,7 @@ func (a Action) Execute() (err error) {
 	})
 
 	// release artifacts
-	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: `(artifact_type == "binary" || artifact_type == "signature")`})
+	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: common.ReleaseArtifactQuery(`(artifact_type == "binary" || artifact_type == "signature")`, d.Env["NCI_COMMIT_REF_NAME"])})
 	if err != nil {
 		return err
 	}
@@ -113,10 +113,11 @@ func (a Action) Execute() (err error) {
 	}
 
 	// options
+	_, releaseVersion := common.TagRelease(d.Env["NCI_COMMIT_REF_NAME"])
 	releaseOptions := github.CreateReleaseRequest{
 		TagName:    d.Env["NCI_COMMIT_REF_NAME"],
 		Name:       github.Ptr(d.Env["NCI_COMMIT_REF_NAME"]),
-		Prerelease: ptr.Ptr(!version.IsStable(d.Env["NCI_COMMIT_REF_NAME"])),
+		Prerelease: ptr.Ptr(!version.IsStable(releaseVersion)),
 		Draft:      ptr.True(),
 	}
 	if changelogErr == nil && changelog != nil && len(changelog.Bytes) > 0 { // use changelog generated by pipeline, default to GitHub auto generated release notes if not available
@@ -134,10 +134,13 @@ func (a Action) Execute() (err error) {
 		return fmt.Errorf("failed to parse project ID: %w", err)
 	}
 	releaseVersion := d.Env["NCI_COMMIT_REF_RELEASE"]
+	if module, moduleVersion := common.TagRelease(d.Env["NCI_COMMIT_REF_NAME"]); module != "" {
+		releaseVersion = moduleVersion
+	}
 
 	// release artifacts
 	var releaseAssets []*gitlab.ReleaseAssetLinkOptions
-	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: `artifact_type == "binary"`})
+	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: common.ReleaseArtifactQuery(`artifact_type == "binary"`, d.Env["NCI_COMMIT_REF_NAME"])})
 	if err != nil {
 		return err
 	}
@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 )
 
 const URI = "builtin://actions/go-test"
@@ -17,14 +19,18 @@ type Action struct {
 }
 
 type Config struct {
+	Quarantine []string `json:"quarantine"  env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
 	return actionsdk.ActionMetadata{
 		Name:        "go-test",
 		Description: "Runs all tests in your go project.",
-		Category:    "test",
-		Scope:       actionsdk.ActionScopeModule,
+		Documentation: `Runs all tests of the go module and publishes the coverage and test reports.
+Failures of quarantined tests (TEST_QUARANTINE or ` + testresultcommon.QuarantineFile + `) are reported, but do not fail the action.
+If a storage is configured, the test outcomes are recorded to detect flaky tests.`,
+		Category: "test",
+		Scope:    actionsdk.ActionScopeModule,
 		Rules: []actionsdk.ActionRule{
 			{
 				Type:       "cel",
@@ -32,7 +38,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name:       "go",
@@ -94,7 +100,7 @@ func (a Action) Execute() (err error) {
 	}
 
 	// parse config
-	_, err = a.GetConfig(d)
+	cfg, err := a.GetConfig(d)
 	if err != nil {
 		return err
 	}
@@ -120,8 +126,9 @@ func (a Action) Execute() (err error) {
 		return fmt.Errorf("go get failed, exit code %d", cmdResult.Code)
 	}
 
-	// run tests
+	// run tests, the json output is used for the test report and the coverage profile is written by the same run
 	testArgs := []string{
+		"-json",
 		"-vet all", // run go vet
 		"-cover",
 		"-covermode=atomic",
@@ -132,18 +139,19 @@ func (a Action) Execute() (err error) {
 		"-shuffle=on", // randomize test order to catch inter-test dependencies
 	}
 	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "running tests"})
-	cmdResult, err = a.Sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
+	testResult, err := a.Sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
 		Command: fmt.Sprintf("go test %s ./...", strings.Join(testArgs, " ")),
 		Env: map[string]string{
 			"GOTOOLCHAIN": "local",
 		},
-		WorkDir: d.Module.ModuleDir,
+		WorkDir:            d.Module.ModuleDir,
+		CaptureOutput:      true,
+		HideStandardOutput: true, // failed tests are logged based on the parsed report
 	})
 	if err != nil {
 		return errors.New("tests failed: " + err.Error())
-	} else if cmdResult.Code != 0 {
-		return fmt.Errorf("go test report generation failed, exit code %d", cmdResult.Code)
 	}
+	testsFailed := testResult.Code != 0
 
 	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
 		Module:        d.Module.Slug,
@@ -157,22 +165,7 @@ func (a Action) Execute() (err error) {
 	}
 
 	// json report
-	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "generating json coverage report"})
-	coverageJSONResult, err := a.Sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
-		Command: fmt.Sprintf("go test -coverprofile %q -json -covermode=count -parallel=4 -timeout 10s ./...", coverageOut),
-		WorkDir: d.Module.ModuleDir,
-		Env: map[string]string{
-			"GOTOOLCHAIN": "local",
-		},
-		CaptureOutput: true,
-	})
-	if err != nil {
-		return errors.New("failed to generate json test coverage report: " + err.Error())
-	} else if coverageJSONResult.Code != 0 {
-		return fmt.Errorf("go test report generation failed, exit code %d", coverageJSONResult.Code)
-	}
-
-	err = a.Sdk.FileWriteV1(coverageJSON, []byte(coverageJSONResult.Stdout))
+	err = a.Sdk.FileWriteV1(coverageJSON, []byte(testResult.Stdout))
 	if err != nil {
 		return errors.New("failed to store json test coverage report on filesystem: " + err.Error())
 	}
@@ -254,5 +247,14 @@ func (a Action) Execute() (err error) {
 		return err
 	}
 
-	return nil
+	// test results
+	report, err := testresult.ParseGoTestJSON(strings.NewReader(testResult.Stdout))
+	if err != nil {
+		return fmt.Errorf("failed to parse go test json output: %w", err)
+	}
+
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
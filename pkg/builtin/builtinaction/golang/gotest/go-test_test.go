@@ -10,9 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGoModTest(t *testing.T) {
-	sdk := common.TestSetup(t)
-	sdk.On("ModuleExecutionContextV1").Return(gocommon.ModuleTestData(), nil)
+func mockGoTest(sdk *actionsdk.MockSDKClient, testExitCode int, jsonOutput string) {
 	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
 		Command: `go get -v -t ./...`,
 		WorkDir: "/my-project",
@@ -21,12 +19,14 @@ func TestGoModTest(t *testing.T) {
 		},
 	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 0}, nil)
 	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
-		Command: `go test -vet all -cover -covermode=atomic -coverprofile "/my-project/.tmp/cover.out" -parallel=4 -timeout 10s -count=1 -shuffle=on ./...`,
+		Command: `go test -json -vet all -cover -covermode=atomic -coverprofile "/my-project/.tmp/cover.out" -parallel=4 -timeout 10s -count=1 -shuffle=on ./...`,
 		WorkDir: "/my-project",
 		Env: map[string]string{
 			"GOTOOLCHAIN": "local",
 		},
-	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 0}, nil)
+		CaptureOutput:      true,
+		HideStandardOutput: true,
+	}).Return(&actionsdk.ExecuteCommandV1Response{Code: testExitCode, Stdout: jsonOutput}, nil)
 	sdk.On("ArtifactUploadV1", actionsdk.ArtifactUploadRequest{
 		File:          "/my-project/.tmp/cover.out",
 		Module:        "github-com-cidverse-my-project",
@@ -35,15 +35,7 @@ func TestGoModTest(t *testing.T) {
 		FormatVersion: "out",
 	}).Return("", "", nil)
 
-	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
-		Command: `go test -coverprofile "/my-project/.tmp/cover.out" -json -covermode=count -parallel=4 -timeout 10s ./...`,
-		WorkDir: "/my-project",
-		Env: map[string]string{
-			"GOTOOLCHAIN": "local",
-		},
-		CaptureOutput: true,
-	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 0, Stdout: "{}"}, nil)
-	sdk.On("FileWriteV1", "/my-project/.tmp/cover.json", []byte("{}")).Return(nil)
+	sdk.On("FileWriteV1", "/my-project/.tmp/cover.json", []byte(jsonOutput)).Return(nil)
 	sdk.On("ArtifactUploadV1", actionsdk.ArtifactUploadRequest{
 		File:          "/my-project/.tmp/cover.json",
 		Module:        "github-com-cidverse-my-project",
@@ -88,6 +80,38 @@ func TestGoModTest(t *testing.T) {
 		Type:   "report",
 		Format: "cobertura",
 	}).Return("", "", nil)
+}
+
+func TestGoModTest(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("ModuleExecutionContextV1").Return(gocommon.ModuleTestData(), nil)
+	mockGoTest(sdk, 0, "{}")
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+}
+
+const failedTestJSON = `{"Action":"fail","Package":"github.com/cidverse/my-project","Test":"TestNetwork","Elapsed":0.1}`
+
+func TestGoModTestFailed(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("ModuleExecutionContextV1").Return(gocommon.ModuleTestData(), nil)
+	sdk.On("FileExistsV1", "/my-project/.cid/test-quarantine.txt").Return(false)
+	mockGoTest(sdk, 1, failedTestJSON)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.Error(t, err)
+}
+
+func TestGoModTestQuarantined(t *testing.T) {
+	sdk := common.TestSetup(t)
+	data := gocommon.ModuleTestData()
+	data.Env = map[string]string{"TEST_QUARANTINE": "TestNetwork"}
+	sdk.On("ModuleExecutionContextV1").Return(data, nil)
+	sdk.On("FileExistsV1", "/my-project/.cid/test-quarantine.txt").Return(false)
+	mockGoTest(sdk, 1, failedTestJSON)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
@@ -53,7 +53,8 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 func (a Action) GetConfig(d *actionsdk.ModuleExecutionContextV1Response) (Config, error) {
 	cfg := Config{}
 	if cfg.MavenVersion == "" {
-		cfg.MavenVersion = gradlecommon.GetVersion(d.Env["NCI_COMMIT_REF_TYPE"], d.Env["NCI_COMMIT_REF_RELEASE"], d.Env["NCI_COMMIT_HASH_SHORT"])
+		refType, release := common.ReleaseRef(d.Env)
+		cfg.MavenVersion = gradlecommon.GetVersion(refType, release, d.Env["NCI_COMMIT_HASH_SHORT"])
 	}
 
 	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
@@ -119,7 +119,8 @@ func (a Action) GetConfig(d *actionsdk.ModuleExecutionContextV1Response) (Config
 
 	// version
 	if cfg.MavenVersion == "" {
-		cfg.MavenVersion = gradlecommon.GetVersion(d.Env["NCI_COMMIT_REF_TYPE"], d.Env["NCI_COMMIT_REF_RELEASE"], d.Env["NCI_COMMIT_HASH_SHORT"])
+		refType, release := common.ReleaseRef(d.Env)
+		cfg.MavenVersion = gradlecommon.GetVersion(refType, release, d.Env["NCI_COMMIT_HASH_SHORT"])
 	}
 
 	// github packages
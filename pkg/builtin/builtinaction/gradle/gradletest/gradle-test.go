@@ -7,7 +7,9 @@ import (
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/gradle/gradlecommon"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 )
 
 const URI = "builtin://actions/gradle-test"
@@ -19,8 +21,9 @@ type Action struct {
 }
 
 type Config struct {
-	MavenVersion        string `json:"maven_version"        env:"MAVEN_VERSION"`
-	WrapperVerification bool   `json:"wrapper_verification" env:"WRAPPER_VERIFICATION"`
+	MavenVersion        string   `json:"maven_version"        env:"MAVEN_VERSION"`
+	WrapperVerification bool     `json:"wrapper_verification" env:"WRAPPER_VERIFICATION"`
+	Quarantine          []string `json:"quarantine"           env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
@@ -36,7 +39,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name:       "java",
@@ -70,7 +73,8 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 func (a Action) GetConfig(d *actionsdk.ModuleExecutionContextV1Response) (Config, error) {
 	cfg := Config{}
 	if cfg.MavenVersion == "" {
-		cfg.MavenVersion = gradlecommon.GetVersion(d.Env["NCI_COMMIT_REF_TYPE"], d.Env["NCI_COMMIT_REF_RELEASE"], d.Env["NCI_COMMIT_HASH_SHORT"])
+		refType, release := common.ReleaseRef(d.Env)
+		cfg.MavenVersion = gradlecommon.GetVersion(refType, release, d.Env["NCI_COMMIT_HASH_SHORT"])
 	}
 
 	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
@@ -125,15 +129,15 @@ func (a Action) Execute() (err error) {
 	})
 	if err != nil {
 		return err
-	} else if testResult.Code != 0 {
-		return fmt.Errorf("gradle test failed, exit code %d", testResult.Code)
 	}
+	testsFailed := testResult.Code != 0
 
 	// collect and store jacoco test reports
 	testReports, err := a.Sdk.FileListV1(actionsdk.FileV1Request{
 		Directory:  d.Module.ModuleDir,
 		Extensions: []string{"jacocoTestReport.xml", ".sarif", ".xml"},
 	})
+	var junitReports []string
 	for _, report := range testReports {
 		if strings.HasSuffix(report.Path, actionsdk.JoinPath("build", "reports", "jacoco", "test", "jacocoTestReport.xml")) {
 			_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
@@ -156,6 +160,7 @@ func (a Action) Execute() (err error) {
 				return err
 			}
 		} else if junitRegex.MatchString(report.Path) {
+			junitReports = append(junitReports, report.Path)
 			_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
 				File:   report.Path,
 				Module: d.Module.Slug,
@@ -168,5 +173,14 @@ func (a Action) Execute() (err error) {
 		}
 	}
 
-	return nil
+	// test results
+	report, err := testresultcommon.ReadReports(a.Sdk, testresult.FormatJUnit, junitReports...)
+	if err != nil {
+		return err
+	}
+
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
@@ -6,7 +6,9 @@ import (
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/gradle/gradlecommon"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/maven/mavencommon"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 
 	"regexp"
 	"strings"
@@ -22,8 +24,9 @@ type Action struct {
 }
 
 type Config struct {
-	MavenVersion        string `json:"maven_version"        env:"MAVEN_VERSION"`
-	WrapperVerification bool   `json:"wrapper_verification" env:"WRAPPER_VERIFICATION"`
+	MavenVersion        string   `json:"maven_version"        env:"MAVEN_VERSION"`
+	WrapperVerification bool     `json:"wrapper_verification" env:"WRAPPER_VERIFICATION"`
+	Quarantine          []string `json:"quarantine"           env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
@@ -39,7 +42,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name: "java",
@@ -68,7 +71,8 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 func (a Action) GetConfig(d *actionsdk.ModuleExecutionContextV1Response) (Config, error) {
 	cfg := Config{}
 	if cfg.MavenVersion == "" {
-		cfg.MavenVersion = gradlecommon.GetVersion(d.Env["NCI_COMMIT_REF_TYPE"], d.Env["NCI_COMMIT_REF_RELEASE"], d.Env["NCI_COMMIT_HASH_SHORT"])
+		refType, release := common.ReleaseRef(d.Env)
+		cfg.MavenVersion = gradlecommon.GetVersion(refType, release, d.Env["NCI_COMMIT_HASH_SHORT"])
 	}
 
 	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
@@ -113,15 +117,15 @@ func (a Action) Execute() (err error) {
 	})
 	if err != nil {
 		return err
-	} else if cmdResult.Code != 0 {
-		return fmt.Errorf("maven build failed, exit code %d", cmdResult.Code)
 	}
+	testsFailed := cmdResult.Code != 0
 
 	// collect and store test reports for Gradle and Maven
 	testReports, err := a.Sdk.FileListV1(actionsdk.FileV1Request{Directory: d.Module.ModuleDir, Extensions: []string{".xml", ".sarif"}})
 	if err != nil {
 		return err
 	}
+	var junitReports []string
 	for _, report := range testReports {
 		path := report.Path
 
@@ -136,6 +140,7 @@ func (a Action) Execute() (err error) {
 				return err
 			}
 		} else if junitRegex.MatchString(path) || junitFailSafeRegex.MatchString(path) {
+			junitReports = append(junitReports, path)
 			_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
 				File:   path,
 				Module: d.Module.Slug,
@@ -148,5 +153,14 @@ func (a Action) Execute() (err error) {
 		}
 	}
 
-	return nil
+	// test results
+	report, err := testresultcommon.ReadReports(a.Sdk, testresult.FormatJUnit, junitReports...)
+	if err != nil {
+		return err
+	}
+
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
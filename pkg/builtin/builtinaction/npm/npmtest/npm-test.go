@@ -5,7 +5,9 @@ import (
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/npm/npmcommon"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 
 	"strings"
 )
@@ -17,6 +19,7 @@ type Action struct {
 }
 
 type Config struct {
+	Quarantine []string `json:"quarantine"  env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
@@ -32,7 +35,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name: "npm",
@@ -80,7 +83,7 @@ func (a Action) Execute() (err error) {
 	}
 
 	// parse config
-	_, err = a.GetConfig(d)
+	cfg, err := a.GetConfig(d)
 	if err != nil {
 		return err
 	}
@@ -120,15 +123,18 @@ func (a Action) Execute() (err error) {
 	})
 	if err != nil {
 		return err
-	} else if cmdResult.Code != 0 {
-		return fmt.Errorf("npm test failed, exit code %d", cmdResult.Code)
 	}
+	testsFailed := cmdResult.Code != 0
 
-	// collect and store jacoco test reports
+	// collect and store test reports
 	testReports, err := a.Sdk.FileListV1(actionsdk.FileV1Request{
 		Directory:  d.Module.ModuleDir,
 		Extensions: []string{".xml"},
 	})
+	if err != nil {
+		return err
+	}
+	var junitReports []string
 	for _, report := range testReports {
 		if strings.HasSuffix(report.Path, "cobertura-coverage.xml") {
 			_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
@@ -141,6 +147,7 @@ func (a Action) Execute() (err error) {
 				return err
 			}
 		} else if strings.HasSuffix(report.Path, "junit.xml") {
+			junitReports = append(junitReports, report.Path)
 			_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
 				File:   report.Path,
 				Module: d.Module.Slug,
@@ -152,6 +159,18 @@ func (a Action) Execute() (err error) {
 			}
 		}
 	}
+	if testsFailed && len(junitReports) == 0 {
+		return fmt.Errorf("npm test failed, exit code %d", cmdResult.Code)
+	}
+
+	// test results
+	report, err := testresultcommon.ReadReports(a.Sdk, testresult.FormatJUnit, junitReports...)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
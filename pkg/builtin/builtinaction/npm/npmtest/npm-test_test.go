@@ -30,12 +30,54 @@ func TestNodeTest(t *testing.T) {
 		Type:   "report",
 		Format: "junit",
 	}).Return("", "", nil)
+	sdk.On("FileExistsV1", "/my-project/build/reports/junit.xml").Return(true)
+	sdk.On("FileReadV1", "/my-project/build/reports/junit.xml").Return(`<testsuites><testsuite name="app"><testcase name="adds"/></testsuite></testsuites>`, nil)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
 	assert.NoError(t, err)
 }
 
+func testNodeTestFailed(t *testing.T, env map[string]string) error {
+	moduleData := npmcommon.TestModuleData()
+	moduleData.Env = env
+
+	sdk := common.TestSetup(t)
+	sdk.On("ModuleExecutionContextV1").Return(moduleData, nil)
+	sdk.On("FileReadV1", "/my-project/package.json").Return(`{"scripts": {"test": ""}}`, nil)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
+		Command: "npm install",
+		WorkDir: "/my-project",
+	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 0}, nil)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
+		Command: "npm test",
+		WorkDir: "/my-project",
+	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 1}, nil)
+	sdk.On("FileListV1", actionsdk.FileV1Request{Directory: "/my-project", Extensions: []string{".xml"}}).Return([]actionsdk.File{actionsdk.NewFile("/my-project/build/reports/junit.xml")}, nil)
+	sdk.On("ArtifactUploadV1", actionsdk.ArtifactUploadRequest{
+		Module: "my-package",
+		File:   "/my-project/build/reports/junit.xml",
+		Type:   "report",
+		Format: "junit",
+	}).Return("", "", nil)
+	sdk.On("FileExistsV1", "/my-project/build/reports/junit.xml").Return(true)
+	sdk.On("FileReadV1", "/my-project/build/reports/junit.xml").Return(`<testsuites><testsuite name="app"><testcase name="fetches data"><failure message="timeout"/></testcase></testsuite></testsuites>`, nil)
+	sdk.On("FileExistsV1", ".cid/test-quarantine.txt").Return(false)
+
+	action := Action{Sdk: sdk}
+	return action.Execute()
+}
+
+func TestNodeTestFailed(t *testing.T) {
+	err := testNodeTestFailed(t, map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestNodeTestQuarantined(t *testing.T) {
+	err := testNodeTestFailed(t, map[string]string{"TEST_QUARANTINE": "app/fetches data"})
+	assert.NoError(t, err)
+}
+
 func TestNodeTestNoScript(t *testing.T) {
 	sdk := common.TestSetup(t)
 	sdk.On("ModuleExecutionContextV1").Return(npmcommon.TestModuleData(), nil)
@@ -0,0 +1,179 @@
+package sarifbaselinediff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/sarif/sarifcommon"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/mergerequest"
+	"github.com/cidverse/cid/pkg/lib/sarifpolicy"
+	"github.com/cidverse/cid/pkg/lib/storage"
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+)
+
+const URI = "builtin://actions/sarif-baseline-diff"
+
+type Action struct {
+	Sdk     actionsdk.SDKClient
+	Storage storageapi.API // Storage is used to store and retrieve the baseline, defaults to the storage configured by the environment
+	APIURL  string         // APIURL overrides the api endpoint of the repository host, defaults to the host of the repository
+}
+
+type Config struct {
+	Comment     bool   `json:"comment"       env:"SARIF_DIFF_COMMENT"`
+	GitHubToken string `json:"github_token"  env:"GITHUB_TOKEN"`
+	GitLabToken string `json:"gitlab_token"  env:"GITLAB_TOKEN"`
+}
+
+func (a Action) Metadata() actionsdk.ActionMetadata {
+	return actionsdk.ActionMetadata{
+		Name:        "sarif-baseline-diff",
+		Description: "Compares the SARIF findings of a pull request against the baseline of the default branch.",
+		Documentation: `On the default branch, the merged SARIF reports of all scan actions are stored as baseline.
+On all other branches, the findings are compared against the stored baseline and only new findings are reported in a Markdown summary.
+The summary is published as report artifact and as a comment on the pull request / merge request, an existing comment is updated instead of creating a new one.`,
+		Category: "sast",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules: []actionsdk.ActionRule{
+			{
+				Type:       "cel",
+				Expression: `getMapValue(ENV, "CID_STORAGE_S3_ENDPOINT") != "" && NCI_COMMIT_REF_TYPE == "branch"`,
+			},
+		},
+		Access: actionsdk.ActionAccess{
+			Environment: append([]actionsdk.ActionAccessEnv{
+				{
+					Name:        "SARIF_DIFF_COMMENT",
+					Description: "Comment the summary on the pull request / merge request. Defaults to true.",
+				},
+				{
+					Name:        "GITHUB_TOKEN",
+					Description: "The GitHub token used to comment on pull requests.",
+					Secret:      true,
+				},
+				{
+					Name:        "GITLAB_TOKEN",
+					Description: "The GitLab token used to comment on merge requests.",
+					Secret:      true,
+				},
+			}, sarifcommon.StorageAccessEnv...),
+			Network: []actionsdk.ActionAccessNetwork{
+				{
+					Host: actionsdk.NetworkHostRepositoryAPI,
+				},
+			},
+		},
+		Input: actionsdk.ActionInput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "sarif",
+				},
+			},
+		},
+		Output: actionsdk.ActionOutput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "markdown",
+				},
+			},
+		},
+	}
+}
+
+func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Config, error) {
+	cfg := Config{
+		Comment: true,
+	}
+
+	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func (a Action) Execute() (err error) {
+	// query action data
+	d, err := a.Sdk.ProjectExecutionContextV1()
+	if err != nil {
+		return err
+	}
+
+	// parse config
+	cfg, err := a.GetConfig(d)
+	if err != nil {
+		return err
+	}
+
+	// storage
+	store := a.Storage
+	if store == nil {
+		store, err = storage.GetStorageApiFromEnv(d.Env)
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage client: %w", err)
+		}
+	}
+
+	// collect reports
+	reports, err := sarifcommon.LoadReports(a.Sdk)
+	if err != nil {
+		return err
+	}
+
+	// default branch, store the baseline
+	if sarifcommon.IsDefaultBranch(d.Env) {
+		if store == nil {
+			return fmt.Errorf("no storage configured, can not store sarif baseline")
+		}
+
+		objectName := sarifpolicy.BaselineObjectName(d.Env["NCI_REPOSITORY_HOST_SERVER"], d.Env["NCI_PROJECT_PATH"])
+		err = sarifpolicy.StoreBaseline(context.Background(), store, sarifcommon.StorageBucket(d.Env), objectName, sarifpolicy.MergeReports(reports))
+		if err != nil {
+			return err
+		}
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "stored sarif baseline", Context: map[string]interface{}{"object": objectName, "reports": len(reports)}})
+
+		return nil
+	}
+
+	// compare against baseline
+	findings := sarifcommon.Findings(reports)
+	baselineAvailable := store != nil
+	baseline, err := sarifcommon.LoadBaselineFindings(store, d.Env)
+	if err != nil {
+		baselineAvailable = false
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "no sarif baseline available, all findings are considered new", Context: map[string]interface{}{"error": err.Error()}})
+	}
+	newFindings := sarifpolicy.NewFindings(findings, baseline)
+	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "compared sarif findings against baseline", Context: map[string]interface{}{"findings": len(findings), "new": len(newFindings), "baseline": baselineAvailable}})
+
+	// summary
+	summary, err := sarifpolicy.RenderMarkdownSummary(newFindings, len(findings), baselineAvailable)
+	if err != nil {
+		return err
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:    actionsdk.JoinPath(d.Config.TempDir, "sarif-summary.md"),
+		Content: summary,
+		Type:    "report",
+		Format:  "markdown",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload sarif summary: %w", err)
+	}
+
+	// comment on the pull request / merge request
+	if cfg.Comment && d.Env["NCI_MERGE_REQUEST_ID"] != "" {
+		repo := mergerequest.RepositoryFromEnv(d.Env, cfg.GitHubToken, cfg.GitLabToken)
+		repo.APIURL = a.APIURL
+		if err = mergerequest.UpsertComment(repo, d.Env["NCI_MERGE_REQUEST_ID"], sarifpolicy.SummaryMarker, summary); err != nil {
+			_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to comment sarif summary on merge request", Context: map[string]interface{}{"error": err.Error()}})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package sarifbaselinediff
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const sampleSarif = `{
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "gitleaks", "rules": [{"id": "generic-api-key"}]}},
+      "results": [
+        {
+          "ruleId": "generic-api-key",
+          "level": "error",
+          "message": {"text": "generic api key detected"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "config/dev.env"}, "region": {"startLine": 4}}}]
+        }
+      ]
+    }
+  ]
+}`
+
+const baselineObject = "sarif-baseline/github.com/cidverse-owner/cidverse-name/baseline.sarif.json"
+
+func setup(t *testing.T, env map[string]string) *actionsdk.MockSDKClient {
+	sdk := common.TestSetup(t)
+	data := common.TestProjectData()
+	for k, v := range env {
+		data.Env[k] = v
+	}
+	sdk.On("ProjectExecutionContextV1").Return(data, nil)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report" && format == "sarif"`}).Return([]*actionsdk.Artifact{
+		{
+			ArtifactID: "root|report|gitleaks.sarif.json",
+			Module:     "root",
+			Name:       "gitleaks.sarif.json",
+			Type:       "report",
+			Format:     "sarif",
+		},
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "root|report|gitleaks.sarif.json"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte(sampleSarif),
+	}, nil)
+
+	return sdk
+}
+
+func TestSarifBaselineDiffStoreBaseline(t *testing.T) {
+	sdk := setup(t, map[string]string{
+		"NCI_COMMIT_REF_TYPE":        "branch",
+		"NCI_COMMIT_REF_NAME":        "main",
+		"NCI_PROJECT_DEFAULT_BRANCH": "main",
+	})
+	store := storagetest.NewStorage(t)
+
+	action := Action{Sdk: sdk, Storage: store}
+	err := action.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, string(store.Object("cidverse-cid", baselineObject)), "generic-api-key")
+}
+
+func TestSarifBaselineDiffPullRequest(t *testing.T) {
+	sdk := setup(t, map[string]string{
+		"NCI_COMMIT_REF_TYPE":        "branch",
+		"NCI_COMMIT_REF_NAME":        "feature",
+		"NCI_PROJECT_DEFAULT_BRANCH": "main",
+	})
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "/my-project/.tmp/sarif-summary.md" && req.Type == "report" && req.Format == "markdown"
+	})).Return("", "", nil)
+
+	action := Action{Sdk: sdk, Storage: storagetest.NewStorage(t)}
+	err := action.Execute()
+	assert.NoError(t, err)
+}
+
+func TestSarifBaselineDiffKnownFindings(t *testing.T) {
+	sdk := setup(t, map[string]string{
+		"NCI_COMMIT_REF_TYPE":        "branch",
+		"NCI_COMMIT_REF_NAME":        "feature",
+		"NCI_PROJECT_DEFAULT_BRANCH": "main",
+	})
+	store := storagetest.NewStorage(t)
+	store.Objects["cidverse-cid/"+baselineObject] = []byte(sampleSarif)
+	var summary string
+	sdk.On("ArtifactUploadV1", mock.Anything).Run(func(args mock.Arguments) {
+		summary = args.Get(0).(actionsdk.ArtifactUploadRequest).Content
+	}).Return("", "", nil)
+
+	action := Action{Sdk: sdk, Storage: store}
+	err := action.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, summary, "No new findings introduced by this change (1 total).")
+	assert.NotContains(t, summary, "No baseline from the default branch is available")
+}
+
+func TestSarifBaselineDiffNewFindingComment(t *testing.T) {
+	sdk := setup(t, map[string]string{
+		"NCI_COMMIT_REF_TYPE":        "branch",
+		"NCI_COMMIT_REF_NAME":        "feature",
+		"NCI_PROJECT_DEFAULT_BRANCH": "main",
+		"NCI_REPOSITORY_HOST_TYPE":   "github",
+		"NCI_MERGE_REQUEST_ID":       "7",
+		"GITHUB_TOKEN":               "token",
+	})
+	store := storagetest.NewStorage(t)
+	store.Objects["cidverse-cid/"+baselineObject] = []byte(`{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "gitleaks"}}, "results": []}]}`)
+	sdk.On("ArtifactUploadV1", mock.Anything).Return("", "", nil)
+
+	// pull request comment api
+	var comment string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/cidverse-owner/cidverse-name/issues/7/comments":
+			_, _ = io.WriteString(w, `[]`)
+		case "POST /repos/cidverse-owner/cidverse-name/issues/7/comments":
+			body, _ := io.ReadAll(r.Body)
+			comment = string(body)
+			_, _ = io.WriteString(w, `{"id": 1}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	action := Action{Sdk: sdk, Storage: store, APIURL: server.URL}
+	err := action.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, comment, "**1** new finding(s) introduced by this change (1 total).")
+	assert.Contains(t, comment, "generic-api-key")
+}
@@ -0,0 +1,93 @@
+package sarifcommon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/sarifpolicy"
+	"github.com/cidverse/cid/pkg/lib/storage"
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+	"github.com/cidverse/cid/pkg/util"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+)
+
+// StorageAccessEnv are the environment variables required to store and retrieve the SARIF baseline
+var StorageAccessEnv = []actionsdk.ActionAccessEnv{
+	{
+		Name:        "CID_STORAGE_S3_ENDPOINT",
+		Description: "The S3 endpoint used to store the SARIF baseline of the default branch.",
+	},
+	{
+		Name:        "CID_STORAGE_S3_ACCESS_KEY",
+		Description: "The S3 access key.",
+		Secret:      true,
+	},
+	{
+		Name:        "CID_STORAGE_S3_SECRET_KEY",
+		Description: "The S3 secret key.",
+		Secret:      true,
+	},
+	{
+		Name:        "CID_STORAGE_S3_BUCKET",
+		Description: "The S3 bucket, defaults to cidverse-cid.",
+	},
+}
+
+// LoadReports downloads and parses all SARIF reports from the artifacts of the current workflow
+func LoadReports(sdk actionsdk.SDKClient) ([]*sarif.Report, error) {
+	var reports []*sarif.Report
+
+	artifacts, err := sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: `artifact_type == "report" && format == "sarif"`})
+	if err != nil {
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		content, err := sdk.ArtifactDownloadByteArrayV1(actionsdk.ArtifactDownloadByteArrayRequest{ID: artifact.ArtifactID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve sarif report %s: %w", artifact.ArtifactID, err)
+		}
+
+		report, err := sarif.FromBytes(content.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sarif report %s: %w", artifact.ArtifactID, err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Findings returns the findings of all reports
+func Findings(reports []*sarif.Report) []sarifpolicy.Finding {
+	var findings []sarifpolicy.Finding
+	for _, r := range reports {
+		findings = append(findings, sarifpolicy.FindingsFromReport(r)...)
+	}
+
+	return findings
+}
+
+// IsDefaultBranch checks if the workflow runs on the default branch, which is the source of the SARIF baseline
+func IsDefaultBranch(env map[string]string) bool {
+	return env["NCI_COMMIT_REF_TYPE"] == "branch" && env["NCI_PIPELINE_TRIGGER"] != "merge_request" && env["NCI_PROJECT_DEFAULT_BRANCH"] != "" && env["NCI_COMMIT_REF_NAME"] == env["NCI_PROJECT_DEFAULT_BRANCH"]
+}
+
+// StorageBucket returns the configured bucket for the SARIF baseline
+func StorageBucket(env map[string]string) string {
+	return util.GetStringOrDefault(env["CID_STORAGE_S3_BUCKET"], storage.DefaultBucket)
+}
+
+// LoadBaselineFindings loads the findings of the stored default branch baseline, returns nil if no baseline is available
+func LoadBaselineFindings(api storageapi.API, env map[string]string) ([]sarifpolicy.Finding, error) {
+	if api == nil {
+		return nil, nil
+	}
+
+	report, err := sarifpolicy.LoadBaseline(context.Background(), api, StorageBucket(env), sarifpolicy.BaselineObjectName(env["NCI_REPOSITORY_HOST_SERVER"], env["NCI_PROJECT_PATH"]))
+	if err != nil {
+		return nil, err
+	}
+
+	return sarifpolicy.FindingsFromReport(report), nil
+}
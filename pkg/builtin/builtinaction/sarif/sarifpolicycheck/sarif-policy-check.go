@@ -0,0 +1,207 @@
+package sarifpolicycheck
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/sarif/sarifcommon"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/sarifpolicy"
+	"github.com/cidverse/cid/pkg/lib/storage"
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+	"go.yaml.in/yaml/v3"
+)
+
+const URI = "builtin://actions/sarif-policy-check"
+
+type Action struct {
+	Sdk     actionsdk.SDKClient
+	Storage storageapi.API // Storage is used to retrieve the baseline, defaults to the storage configured by the environment
+}
+
+type Config struct {
+	SeverityThreshold string                    `json:"severity_threshold"  env:"SARIF_POLICY_SEVERITY_THRESHOLD"  validate:"omitempty,oneof=none info low medium high critical"`
+	FailOnRules       []string                  `json:"fail_on_rules"       env:"SARIF_POLICY_FAIL_ON_RULES"`
+	IgnoreRules       []string                  `json:"ignore_rules"        env:"SARIF_POLICY_IGNORE_RULES"`
+	NewFindingsOnly   bool                      `json:"new_findings_only"   env:"SARIF_POLICY_NEW_FINDINGS_ONLY"`
+	BaselineFile      string                    `json:"baseline_file"       env:"SARIF_POLICY_BASELINE_FILE"`
+	SuppressionsFile  string                    `json:"suppressions_file"   env:"SARIF_POLICY_SUPPRESSIONS_FILE"`
+	Suppressions      []sarifpolicy.Suppression `json:"suppressions"`
+}
+
+// SuppressionsFile is the format of the suppressions file within the repository
+type SuppressionsFile struct {
+	Suppressions []sarifpolicy.Suppression `yaml:"suppressions"`
+}
+
+func (a Action) Metadata() actionsdk.ActionMetadata {
+	return actionsdk.ActionMetadata{
+		Name:        "sarif-policy-check",
+		Description: "Evaluates all SARIF reports against a common policy and fails the workflow on violations.",
+		Documentation: `Collects the SARIF reports of all scan actions and decides pass/fail based on severity, rule ids, findings that are new compared to a baseline and suppressions with expiry dates.
+
+Suppressions can be configured in the action config or in the suppressions file (default: .cid/sarif-suppressions.yml):
+
+` + "```yaml" + `
+suppressions:
+  - rule_id: "go.lang.security.audit.crypto.use_of_weak_crypto"
+    path: "pkg/legacy/*.go"
+    reason: "legacy checksum, not used for security purposes"
+    expires: "2026-12-31"
+` + "```",
+		Category: "sast",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules: []actionsdk.ActionRule{
+			{
+				Type:       "cel",
+				Expression: `NCI_COMMIT_REF_TYPE == "branch" && size(PROJECT_BUILD_SYSTEMS) > 0`,
+			},
+		},
+		Access: actionsdk.ActionAccess{
+			Environment: append([]actionsdk.ActionAccessEnv{
+				{
+					Name:        "SARIF_POLICY_SEVERITY_THRESHOLD",
+					Description: "Minimum severity that fails the policy (none, info, low, medium, high, critical). Defaults to high.",
+				},
+				{
+					Name:        "SARIF_POLICY_FAIL_ON_RULES",
+					Description: "Comma-separated list of rule ids that always fail the policy.",
+				},
+				{
+					Name:        "SARIF_POLICY_IGNORE_RULES",
+					Description: "Comma-separated list of rule ids that never fail the policy.",
+				},
+				{
+					Name:        "SARIF_POLICY_NEW_FINDINGS_ONLY",
+					Description: "Only fail on findings that are not present in the baseline.",
+				},
+				{
+					Name:        "SARIF_POLICY_BASELINE_FILE",
+					Description: "Path to a SARIF report used as baseline, relative to the project directory. Defaults to the baseline stored by sarif-baseline-diff on the default branch.",
+				},
+				{
+					Name:        "SARIF_POLICY_SUPPRESSIONS_FILE",
+					Description: "Path to the suppressions file, relative to the project directory.",
+				},
+			}, sarifcommon.StorageAccessEnv...),
+		},
+		Input: actionsdk.ActionInput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "sarif",
+				},
+			},
+		},
+	}
+}
+
+func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Config, error) {
+	cfg := Config{
+		SeverityThreshold: string(sarifpolicy.SeverityHigh),
+		SuppressionsFile:  ".cid/sarif-suppressions.yml",
+	}
+
+	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func (a Action) Execute() (err error) {
+	// query action data
+	d, err := a.Sdk.ProjectExecutionContextV1()
+	if err != nil {
+		return err
+	}
+
+	// parse config
+	cfg, err := a.GetConfig(d)
+	if err != nil {
+		return err
+	}
+	severityThreshold, err := sarifpolicy.ParseSeverity(cfg.SeverityThreshold)
+	if err != nil {
+		return err
+	}
+	policy := sarifpolicy.Policy{
+		SeverityThreshold: severityThreshold,
+		FailOnRules:       cfg.FailOnRules,
+		IgnoreRules:       cfg.IgnoreRules,
+		NewFindingsOnly:   cfg.NewFindingsOnly,
+		Suppressions:      cfg.Suppressions,
+	}
+
+	// suppressions file
+	suppressionsFile := actionsdk.JoinPath(d.ProjectDir, cfg.SuppressionsFile)
+	if cfg.SuppressionsFile != "" && a.Sdk.FileExistsV1(suppressionsFile) {
+		content, err := a.Sdk.FileReadV1(suppressionsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read suppressions file %s: %w", suppressionsFile, err)
+		}
+
+		var sf SuppressionsFile
+		if err = yaml.Unmarshal([]byte(content), &sf); err != nil {
+			return fmt.Errorf("failed to parse suppressions file %s: %w", suppressionsFile, err)
+		}
+		policy.Suppressions = append(policy.Suppressions, sf.Suppressions...)
+	}
+
+	// collect findings from all sarif reports
+	reports, err := sarifcommon.LoadReports(a.Sdk)
+	if err != nil {
+		return err
+	}
+	findings := sarifcommon.Findings(reports)
+
+	// baseline, either from a file within the repository or stored by a previous run on the default branch
+	var baseline []sarifpolicy.Finding
+	if cfg.BaselineFile != "" {
+		content, err := a.Sdk.FileReadV1(actionsdk.JoinPath(d.ProjectDir, cfg.BaselineFile))
+		if err != nil {
+			return fmt.Errorf("failed to read baseline file %s: %w", cfg.BaselineFile, err)
+		}
+
+		report, err := sarif.FromBytes([]byte(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse baseline file %s: %w", cfg.BaselineFile, err)
+		}
+		baseline = sarifpolicy.FindingsFromReport(report)
+	} else {
+		store := a.Storage
+		if store == nil {
+			store, err = storage.GetStorageApiFromEnv(d.Env)
+			if err != nil {
+				return fmt.Errorf("failed to initialize storage client: %w", err)
+			}
+		}
+
+		baseline, err = sarifcommon.LoadBaselineFindings(store, d.Env)
+		if err != nil {
+			_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "no sarif baseline available, all findings are considered new", Context: map[string]interface{}{"error": err.Error()}})
+		}
+	}
+
+	// evaluate
+	result, err := sarifpolicy.Evaluate(policy, findings, baseline, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, s := range result.ExpiredSuppressions {
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "suppression expired", Context: map[string]interface{}{"rule_id": s.RuleID, "path": s.Path, "fingerprint": s.Fingerprint, "expires": s.Expires, "reason": s.Reason}})
+	}
+	violations := result.Violations()
+	for _, v := range violations {
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "error", Message: v.Message, Context: map[string]interface{}{"tool": v.Tool, "rule_id": v.RuleID, "severity": v.Severity, "path": v.Path, "line": v.StartLine, "new": v.New, "reason": v.Reason}})
+	}
+	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "sarif policy evaluated", Context: map[string]interface{}{"reports": len(reports), "findings": len(result.Findings), "violations": len(violations)}})
+
+	if len(violations) > 0 {
+		return fmt.Errorf("sarif policy check failed, %d of %d finding(s) violate the policy", len(violations), len(result.Findings))
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package sarifpolicycheck
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSarif = `{
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "gitleaks", "rules": [{"id": "generic-api-key"}]}},
+      "results": [
+        {
+          "ruleId": "generic-api-key",
+          "level": "error",
+          "message": {"text": "generic api key detected"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "config/dev.env"}, "region": {"startLine": 4}}}]
+        }
+      ]
+    }
+  ]
+}`
+
+func setup(t *testing.T, suppressions string) *actionsdk.MockSDKClient {
+	sdk := common.TestSetup(t)
+	sdk.On("ProjectExecutionContextV1").Return(common.TestProjectData(), nil)
+	sdk.On("FileExistsV1", "/my-project/.cid/sarif-suppressions.yml").Return(suppressions != "")
+	if suppressions != "" {
+		sdk.On("FileReadV1", "/my-project/.cid/sarif-suppressions.yml").Return(suppressions, nil)
+	}
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report" && format == "sarif"`}).Return([]*actionsdk.Artifact{
+		{
+			ArtifactID: "root|report|gitleaks.sarif.json",
+			Module:     "root",
+			Name:       "gitleaks.sarif.json",
+			Type:       "report",
+			Format:     "sarif",
+		},
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "root|report|gitleaks.sarif.json"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte(sampleSarif),
+	}, nil)
+
+	return sdk
+}
+
+func TestSarifPolicyCheckViolation(t *testing.T) {
+	sdk := setup(t, "")
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.ErrorContains(t, err, "1 of 1 finding(s) violate the policy")
+}
+
+func TestSarifPolicyCheckSuppressed(t *testing.T) {
+	sdk := setup(t, `suppressions:
+  - rule_id: generic-api-key
+    path: config/*.env
+    reason: dummy credentials for local development
+    expires: "2999-12-31"
+`)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+}
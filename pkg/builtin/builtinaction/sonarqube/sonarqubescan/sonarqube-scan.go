@@ -3,11 +3,14 @@ package sonarqubescan
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/sonarqube/sonarqubecommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
 	"github.com/cidverse/cid/pkg/util"
 	"github.com/cidverse/repoanalyzer/analyzerapi"
 
@@ -16,6 +19,14 @@ import (
 
 const URI = "builtin://actions/sonarqube-scan"
 
+// genericCoverageFormats are converted into the SonarQube generic coverage format, as the scanner only supports them for some languages
+var genericCoverageFormats = []coverage.Format{
+	coverage.FormatCobertura,
+	coverage.FormatLCOV,
+	coverage.FormatClover,
+	coverage.FormatOpenCover,
+}
+
 type Action struct {
 	Sdk actionsdk.SDKClient
 }
@@ -108,6 +119,18 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 					Type:   "report",
 					Format: "trx",
 				},
+				{
+					Type:   "report",
+					Format: "lcov",
+				},
+				{
+					Type:   "report",
+					Format: "clover",
+				},
+				{
+					Type:   "report",
+					Format: "opencover",
+				},
 			},
 		},
 	}
@@ -195,8 +218,13 @@ func (a Action) Execute() (err error) {
 			files["go-coverage-json"] = append(files["go-coverage-json"], targetFile)
 		} else if artifact.Format == "jacoco" {
 			files["java-jacoco"] = append(files["java-jacoco"], targetFile)
-		} else if artifact.Format == "cobertura" {
-			files["cobertura"] = append(files["cobertura"], targetFile)
+		} else if slices.Contains(genericCoverageFormats, coverage.Format(artifact.Format)) {
+			genericFile := targetFile + ".sonar.xml"
+			if convErr := convertToGenericCoverage(coverage.Format(artifact.Format), targetFile, genericFile, moduleRelativeDir(d, artifact.Module)); convErr != nil {
+				_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to convert coverage report", Context: map[string]interface{}{"artifact": fmt.Sprintf("%s-%s", artifact.Module, artifact.Name), "error": convErr.Error()}})
+				continue
+			}
+			files["generic-coverage"] = append(files["generic-coverage"], genericFile)
 		} else if artifact.Format == "junit" {
 			files["junit"] = append(files["junit"], targetFile)
 		} else if artifact.Format == "trx" {
@@ -215,8 +243,8 @@ func (a Action) Execute() (err error) {
 	if len(files["java-jacoco"]) > 0 {
 		scanArgs = append(scanArgs, `-D sonar.coverage.jacoco.xmlReportPaths=`+strings.Join(files["java-jacoco"], ","))
 	}
-	if len(files["cobertura"]) > 0 {
-		scanArgs = append(scanArgs, `-D sonar.python.coverage.reportPaths=`+strings.Join(files["cobertura"], ","))
+	if len(files["generic-coverage"]) > 0 {
+		scanArgs = append(scanArgs, `-D sonar.coverageReportPaths=`+strings.Join(files["generic-coverage"], ","))
 	}
 	if len(files["junit"]) > 0 {
 		scanArgs = append(scanArgs, `-D sonar.junit.reportPaths=`+strings.Join(files["junit"], ","))
@@ -304,3 +332,44 @@ func (a Action) Execute() (err error) {
 
 	return nil
 }
+
+// convertToGenericCoverage converts a coverage report into the SonarQube generic coverage format, paths are made relative to the project root
+func convertToGenericCoverage(format coverage.Format, file string, targetFile string, moduleDir string) error {
+	report, err := coverage.ParseFile(format, file, coverage.ParseOptions{})
+	if err != nil {
+		return err
+	}
+	if moduleDir != "" {
+		report = report.MapPaths(func(p string) string {
+			if filepath.IsAbs(p) {
+				return p
+			}
+			return filepath.ToSlash(filepath.Join(moduleDir, p))
+		})
+	}
+
+	out, err := os.Create(targetFile)
+	if err != nil {
+		return fmt.Errorf("failed to create generic coverage report: %w", err)
+	}
+	defer out.Close()
+
+	return coverage.WriteSonarGeneric(out, report)
+}
+
+// moduleRelativeDir returns the directory of the module relative to the project root, empty for the project root
+func moduleRelativeDir(d *actionsdk.ProjectExecutionContextV1Response, moduleSlug string) string {
+	for _, module := range d.Modules {
+		if module.Slug != moduleSlug {
+			continue
+		}
+
+		rel, err := filepath.Rel(d.ProjectDir, module.ModuleDir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return ""
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	return ""
+}
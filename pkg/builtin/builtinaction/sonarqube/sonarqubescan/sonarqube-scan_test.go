@@ -6,10 +6,12 @@ import (
 	"github.com/cidverse/cid/pkg/core/actionsdk"
 
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestSonarqubeScanGoMod(t *testing.T) {
@@ -78,3 +80,52 @@ func TestSonarqubeScanGoMod(t *testing.T) {
 	assert.NoError(t, err)
 	os.Clearenv()
 }
+
+func TestSonarqubeScanGenericCoverage(t *testing.T) {
+	tempDir := t.TempDir()
+	data := sonarqubecommon.TestModuleData()
+	data.Config.TempDir = tempDir
+	data.Modules[0].ModuleDir = "/my-project/backend"
+	data.Modules[0].BuildSystem = "npm"
+
+	sdk := common.TestSetup(t)
+	sdk.On("ProjectExecutionContextV1").Return(data, nil)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report"`}).Return([]*actionsdk.Artifact{
+		{
+			ArtifactID: "github-com-cidverse-my-project|report|lcov.info",
+			Module:     "github-com-cidverse-my-project",
+			Type:       "report",
+			Name:       "lcov.info",
+			Format:     "lcov",
+		},
+	}, nil)
+	reportFile := filepath.Join(tempDir, "github-com-cidverse-my-project-lcov.info")
+	sdk.On("ArtifactDownloadV1", actionsdk.ArtifactDownloadRequest{
+		ID:         "github-com-cidverse-my-project|report|lcov.info",
+		TargetFile: reportFile,
+	}).Run(func(args mock.Arguments) {
+		_ = os.WriteFile(reportFile, []byte("SF:src/index.js\nDA:1,1\nDA:2,0\nend_of_record\n"), 0644)
+	}).Return(nil, nil)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
+		Command: `sonar-scanner -X -D sonar.host.url=https://sonarcloud.local -D sonar.projectKey=my-project-key -D sonar.projectName=my-project-name -D sonar.sources=. -D sonar.organization=my-org -D sonar.coverageReportPaths=` + reportFile + `.sonar.xml -D sonar.exclusions=**/.git/** -D sonar.branch.name="main"`,
+		WorkDir: "/my-project",
+		Env: map[string]string{
+			"SONAR_SCANNER_OPTS": " ",
+			"SONAR_TOKEN":        "my-token",
+		},
+	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 0}, nil)
+
+	httpmock.ActivateNonDefault(sonarqubecommon.ApiClient.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", "https://sonarcloud.local/api/projects/create?mainBranch=main&name=my-project-name&organization=my-org&project=my-project-key&visibility=public", httpmock.NewStringResponder(200, ``))
+	httpmock.RegisterResponder("POST", "https://sonarcloud.local/api/project_branches/rename?name=main&project=my-project-key", httpmock.NewStringResponder(200, ``))
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+
+	generic, err := os.ReadFile(reportFile + ".sonar.xml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(generic), `<file path="backend/src/index.js">`)
+	assert.Contains(t, string(generic), `<lineToCover lineNumber="2" covered="false"></lineToCover>`)
+}
@@ -0,0 +1,206 @@
+package testreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+)
+
+const URI = "builtin://actions/test-report"
+
+// formatPriority defines which report is used if a module provides multiple test report formats, to avoid counting the same tests twice
+var formatPriority = []testresult.Format{
+	testresult.FormatJUnit,
+	testresult.FormatTRX,
+	testresult.FormatGoTestJSON,
+	testresult.FormatTAP,
+}
+
+type Action struct {
+	Sdk actionsdk.SDKClient
+}
+
+type Config struct {
+	Slowest int `json:"slowest"  env:"TEST_REPORT_SLOWEST"  validate:"gte=0"`
+}
+
+func (a Action) Metadata() actionsdk.ActionMetadata {
+	return actionsdk.ActionMetadata{
+		Name:        "test-report",
+		Description: "Aggregates the test results of all modules into a unified test report.",
+		Documentation: `Collects the test reports of all modules (JUnit XML, TRX, go test -json, TAP) and aggregates them per module into test counts, failures, durations and the slowest tests.
+If a module provides multiple formats, only one of them is used in the order listed above.
+
+The result is stored as report artifact with the format test-result, subsequent actions can use the variables TEST_TOTAL, TEST_PASSED, TEST_FAILED, TEST_SKIPPED and TEST_FAILED_MODULES in their rules.`,
+		Category: "test",
+		Scope:    actionsdk.ActionScopeProject,
+		Rules: []actionsdk.ActionRule{
+			{
+				Type:       "cel",
+				Expression: `size(PROJECT_BUILD_SYSTEMS) > 0`,
+			},
+		},
+		Access: actionsdk.ActionAccess{
+			Environment: []actionsdk.ActionAccessEnv{
+				{
+					Name:        "TEST_REPORT_SLOWEST",
+					Description: "The number of slowest tests included per module. Defaults to 10.",
+				},
+			},
+		},
+		Input: actionsdk.ActionInput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "junit",
+				},
+				{
+					Type:   "report",
+					Format: "trx",
+				},
+				{
+					Type:   "report",
+					Format: "go-coverage",
+				},
+				{
+					Type:   "report",
+					Format: "tap",
+				},
+			},
+		},
+		Output: actionsdk.ActionOutput{
+			Artifacts: []actionsdk.ActionArtifactType{
+				{
+					Type:   "report",
+					Format: "test-result",
+				},
+			},
+		},
+	}
+}
+
+func (a Action) GetConfig(d *actionsdk.ProjectExecutionContextV1Response) (Config, error) {
+	cfg := Config{
+		Slowest: 10,
+	}
+
+	if err := common.ParseAndValidateConfig(d.Config.Config, d.Env, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func (a Action) Execute() (err error) {
+	// query action data
+	d, err := a.Sdk.ProjectExecutionContextV1()
+	if err != nil {
+		return err
+	}
+
+	// parse config
+	cfg, err := a.GetConfig(d)
+	if err != nil {
+		return err
+	}
+
+	// collect test reports, grouped by module
+	artifacts, err := a.Sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: `artifact_type == "report"`})
+	if err != nil {
+		return err
+	}
+	reportsByModule := make(map[string][]*actionsdk.Artifact)
+	for _, artifact := range artifacts {
+		if artifactFormat(artifact) == "" {
+			continue
+		}
+		reportsByModule[moduleKey(artifact)] = append(reportsByModule[moduleKey(artifact)], artifact)
+	}
+
+	// parse and aggregate
+	result := testresult.ProjectResult{Modules: make(map[string]testresult.ModuleResult)}
+	for moduleSlug, moduleArtifacts := range reportsByModule {
+		format := preferredFormat(moduleArtifacts)
+
+		var moduleReports []*testresult.Report
+		for _, artifact := range moduleArtifacts {
+			if artifactFormat(artifact) != format {
+				continue
+			}
+
+			content, err := a.Sdk.ArtifactDownloadByteArrayV1(actionsdk.ArtifactDownloadByteArrayRequest{ID: artifact.ArtifactID})
+			if err != nil {
+				return fmt.Errorf("failed to retrieve test report %s: %w", artifact.ArtifactID, err)
+			}
+
+			report, err := testresult.Parse(format, bytes.NewReader(content.Bytes))
+			if err != nil {
+				return fmt.Errorf("failed to parse test report %s: %w", artifact.ArtifactID, err)
+			}
+			moduleReports = append(moduleReports, report)
+		}
+
+		moduleResult := testresult.NewModuleResult(testresult.Merge(moduleReports...), cfg.Slowest)
+		result.Modules[moduleSlug] = moduleResult
+		result.Summary = result.Summary.Add(moduleResult.Summary)
+		_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "module test results", Context: map[string]interface{}{"module": moduleSlug, "format": format, "total": moduleResult.Summary.Total, "failed": moduleResult.Summary.Failed + moduleResult.Summary.Errors, "skipped": moduleResult.Summary.Skipped, "duration": moduleResult.Summary.Duration.String()}})
+		for _, c := range moduleResult.Failures {
+			_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "test failed", Context: map[string]interface{}{"module": moduleSlug, "test": c.FullName(), "message": c.Message}})
+		}
+	}
+	_ = a.Sdk.LogV1(actionsdk.LogV1Request{Level: "info", Message: "project test results", Context: map[string]interface{}{"modules": len(result.Modules), "total": result.Summary.Total, "passed": result.Summary.Passed, "failed": result.Summary.Failed + result.Summary.Errors, "skipped": result.Summary.Skipped}})
+
+	// store result
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal test result: %w", err)
+	}
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:          actionsdk.JoinPath(d.Config.TempDir, "test-result.json"),
+		ContentBytes:  resultJSON,
+		Type:          "report",
+		Format:        "test-result",
+		FormatVersion: "json",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload test result: %w", err)
+	}
+
+	return nil
+}
+
+// artifactFormat returns the test report format of the artifact, or an empty string if the artifact is not a test report
+func artifactFormat(artifact *actionsdk.Artifact) testresult.Format {
+	switch {
+	case artifact.Format == "go-coverage" && artifact.FormatVersion == "json":
+		return testresult.FormatGoTestJSON
+	case slices.Contains(formatPriority, testresult.Format(artifact.Format)) && artifact.Format != string(testresult.FormatGoTestJSON):
+		return testresult.Format(artifact.Format)
+	}
+
+	return ""
+}
+
+// preferredFormat returns the format with the highest priority that is present in the artifacts
+func preferredFormat(artifacts []*actionsdk.Artifact) testresult.Format {
+	for _, f := range formatPriority {
+		if slices.ContainsFunc(artifacts, func(a *actionsdk.Artifact) bool { return artifactFormat(a) == f }) {
+			return f
+		}
+	}
+
+	return ""
+}
+
+func moduleKey(artifact *actionsdk.Artifact) string {
+	if artifact.Module == "" {
+		return "root"
+	}
+
+	return artifact.Module
+}
@@ -0,0 +1,44 @@
+package testreport
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTestReport(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("ProjectExecutionContextV1").Return(common.TestProjectData(), nil)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "report"`}).Return([]*actionsdk.Artifact{
+		{ArtifactID: "backend|report|cover.json", Module: "backend", Type: "report", Format: "go-coverage", FormatVersion: "json"},
+		{ArtifactID: "backend|report|cover.out", Module: "backend", Type: "report", Format: "go-coverage", FormatVersion: "out"},
+		{ArtifactID: "backend|report|junit.xml", Module: "backend", Type: "report", Format: "junit"},
+		{ArtifactID: "api|report|dotnet.trx", Module: "api", Type: "report", Format: "trx"},
+		{ArtifactID: "api|report|cobertura.xml", Module: "api", Type: "report", Format: "cobertura"},
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "backend|report|junit.xml"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte(`<testsuites><testsuite name="backend"><testcase name="TestA" time="0.1"/><testcase name="TestB" time="0.2"><failure message="wrong"/></testcase></testsuite></testsuites>`),
+	}, nil)
+	sdk.On("ArtifactDownloadByteArrayV1", actionsdk.ArtifactDownloadByteArrayRequest{ID: "api|report|dotnet.trx"}).Return(&actionsdk.ArtifactDownloadByteArrayResult{
+		Bytes: []byte(`<TestRun><Results><UnitTestResult testId="1" testName="Adds" outcome="Passed" duration="00:00:01.0000000"/></Results></TestRun>`),
+	}, nil)
+
+	var result testresult.ProjectResult
+	sdk.On("ArtifactUploadV1", mock.MatchedBy(func(req actionsdk.ArtifactUploadRequest) bool {
+		return req.File == "/my-project/.tmp/test-result.json" && req.Format == "test-result" && json.Unmarshal(req.ContentBytes, &result) == nil
+	})).Return("", "", nil)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Summary.Total)
+	assert.Equal(t, 1, result.Summary.Failed)
+	assert.Equal(t, 2, result.Modules["backend"].Summary.Total)
+	assert.Equal(t, "backend/TestB", result.Modules["backend"].Failures[0].FullName())
+	assert.Equal(t, 1, result.Modules["api"].Summary.Passed)
+}
@@ -0,0 +1,162 @@
+package testresultcommon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/flakytest"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+	"github.com/cidverse/cid/pkg/lib/storage"
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+	"github.com/cidverse/cid/pkg/util"
+)
+
+// QuarantineFile is the repository file that contains the quarantined tests, one test name or pattern per line
+const QuarantineFile = ".cid/test-quarantine.txt"
+
+// AccessEnv are the environment variables used by test actions to configure the quarantine and to store the test history
+var AccessEnv = []actionsdk.ActionAccessEnv{
+	{
+		Name:        "TEST_QUARANTINE",
+		Description: "Comma separated list of quarantined test names or patterns, in addition to the entries of " + QuarantineFile + ".",
+	},
+	{
+		Name:        "CID_STORAGE_S3_ENDPOINT",
+		Description: "The S3 endpoint used to store the test history for flaky test detection.",
+	},
+	{
+		Name:        "CID_STORAGE_S3_ACCESS_KEY",
+		Description: "The S3 access key.",
+		Secret:      true,
+	},
+	{
+		Name:        "CID_STORAGE_S3_SECRET_KEY",
+		Description: "The S3 secret key.",
+		Secret:      true,
+	},
+	{
+		Name:        "CID_STORAGE_S3_BUCKET",
+		Description: "The S3 bucket, defaults to cidverse-cid.",
+	},
+}
+
+// CheckOptions configures the evaluation of a test report
+type CheckOptions struct {
+	Quarantine    []string       // Quarantine contains test names or patterns whose failures do not fail the action
+	Storage       storageapi.API // Storage is used for the test history, defaults to the storage configured by the environment
+	CommandFailed bool           // CommandFailed indicates that the test command returned a non-zero exit code
+}
+
+// ReadReports reads and merges the given test report files, missing files are ignored
+func ReadReports(sdk actionsdk.SDKClient, format testresult.Format, files ...string) (*testresult.Report, error) {
+	var reports []*testresult.Report
+	for _, file := range files {
+		if !sdk.FileExistsV1(file) {
+			continue
+		}
+
+		content, err := sdk.FileReadV1(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read test report %s: %w", file, err)
+		}
+		report, err := testresult.Parse(format, strings.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse test report %s: %w", file, err)
+		}
+		reports = append(reports, report)
+	}
+
+	return testresult.Merge(reports...), nil
+}
+
+// CheckResults records the test outcomes in the test history, reports flaky tests and returns an error if there are failed tests that are not quarantined.
+// If the test command failed without reporting any failed test (e.g. compilation errors), an error is returned as well.
+func CheckResults(sdk actionsdk.SDKClient, d *actionsdk.ModuleExecutionContextV1Response, report *testresult.Report, opts CheckOptions) error {
+	// test history
+	store := opts.Storage
+	if store == nil {
+		var err error
+		store, err = storage.GetStorageApiFromEnv(d.Env)
+		if err != nil {
+			_ = sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to initialize storage client, skipping flaky test detection", Context: map[string]interface{}{"error": err.Error()}})
+		}
+	}
+	if store != nil {
+		recordHistory(sdk, store, d, report)
+	}
+
+	// failures
+	failures := report.Failures()
+	if len(failures) == 0 {
+		if opts.CommandFailed {
+			return fmt.Errorf("tests failed, but the test report does not contain any failed test")
+		}
+		return nil
+	}
+
+	quarantine, err := loadQuarantine(sdk, d.ProjectDir, opts.Quarantine)
+	if err != nil {
+		return err
+	}
+	quarantined, remaining := quarantine.Split(failures)
+	for _, c := range quarantined {
+		_ = sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "quarantined test failed", Context: map[string]interface{}{"test": c.FullName(), "message": c.Message}})
+	}
+	for _, c := range remaining {
+		_ = sdk.LogV1(actionsdk.LogV1Request{Level: "error", Message: "test failed", Context: map[string]interface{}{"test": c.FullName(), "message": c.Message}})
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%d test(s) failed, %d quarantined test(s) failed", len(remaining), len(quarantined))
+	}
+
+	return nil
+}
+
+func recordHistory(sdk actionsdk.SDKClient, store storageapi.API, d *actionsdk.ModuleExecutionContextV1Response, report *testresult.Report) {
+	ctx := context.Background()
+	bucket := util.GetStringOrDefault(d.Env["CID_STORAGE_S3_BUCKET"], storage.DefaultBucket)
+	objectName := flakytest.ObjectName(d.Env["NCI_REPOSITORY_HOST_SERVER"], d.Env["NCI_PROJECT_PATH"], d.Module.Slug)
+
+	history, err := flakytest.LoadHistory(ctx, store, bucket, objectName)
+	if err != nil {
+		_ = sdk.LogV1(actionsdk.LogV1Request{Level: "debug", Message: "no test history available", Context: map[string]interface{}{"error": err.Error()}})
+		history = flakytest.NewHistory()
+	}
+	history.Record(d.Env["NCI_COMMIT_HASH"], time.Now(), report)
+
+	executed := make(map[string]bool, len(report.Cases))
+	for _, c := range report.Cases {
+		executed[c.FullName()] = true
+	}
+	for _, f := range history.Flaky() {
+		if executed[f.Name] {
+			_ = sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "flaky test detected, the test passed and failed on the same commit", Context: map[string]interface{}{"test": f.Name, "commits": f.Commits, "passed": f.Passed, "failed": f.Failed}})
+		}
+	}
+
+	if err = flakytest.StoreHistory(ctx, store, bucket, objectName, history); err != nil {
+		_ = sdk.LogV1(actionsdk.LogV1Request{Level: "warn", Message: "failed to store test history", Context: map[string]interface{}{"error": err.Error()}})
+	}
+}
+
+// loadQuarantine combines the configured quarantine entries with the entries of the quarantine file of the repository
+func loadQuarantine(sdk actionsdk.SDKClient, projectDir string, entries []string) (*flakytest.Quarantine, error) {
+	file := actionsdk.JoinPath(projectDir, QuarantineFile)
+	if sdk.FileExistsV1(file) {
+		content, err := sdk.FileReadV1(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read quarantine file %s: %w", file, err)
+		}
+		fileEntries, err := flakytest.ParseQuarantine(strings.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse quarantine file %s: %w", file, err)
+		}
+		entries = slices.Concat(entries, fileEntries)
+	}
+
+	return flakytest.NewQuarantine(entries...), nil
+}
@@ -0,0 +1,67 @@
+package testresultcommon
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/golang/gocommon"
+	"github.com/cidverse/cid/pkg/lib/flakytest"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+	"github.com/cidverse/cid/pkg/lib/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+)
+
+var failedReport = &testresult.Report{Cases: []testresult.Case{
+	{Suite: "github.com/cidverse/my-project", Name: "TestOk", Status: testresult.StatusPassed},
+	{Suite: "github.com/cidverse/my-project", Name: "TestNetwork", Status: testresult.StatusFailed},
+}}
+
+func TestCheckResultsSuccess(t *testing.T) {
+	sdk := common.TestSetup(t)
+	err := CheckResults(sdk, gocommon.ModuleTestData(), &testresult.Report{Cases: failedReport.Cases[:1]}, CheckOptions{})
+	assert.NoError(t, err)
+}
+
+func TestCheckResultsFailed(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("FileExistsV1", "/my-project/.cid/test-quarantine.txt").Return(false)
+
+	err := CheckResults(sdk, gocommon.ModuleTestData(), failedReport, CheckOptions{CommandFailed: true})
+	assert.EqualError(t, err, "1 test(s) failed, 0 quarantined test(s) failed")
+}
+
+func TestCheckResultsCommandFailedWithoutFailures(t *testing.T) {
+	sdk := common.TestSetup(t)
+	err := CheckResults(sdk, gocommon.ModuleTestData(), &testresult.Report{}, CheckOptions{CommandFailed: true})
+	assert.Error(t, err)
+}
+
+func TestCheckResultsQuarantined(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("FileExistsV1", "/my-project/.cid/test-quarantine.txt").Return(true)
+	sdk.On("FileReadV1", "/my-project/.cid/test-quarantine.txt").Return("# known flaky\n*/TestNetwork\n", nil)
+
+	err := CheckResults(sdk, gocommon.ModuleTestData(), failedReport, CheckOptions{CommandFailed: true})
+	assert.NoError(t, err)
+}
+
+func TestCheckResultsQuarantinedByConfig(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("FileExistsV1", "/my-project/.cid/test-quarantine.txt").Return(false)
+
+	err := CheckResults(sdk, gocommon.ModuleTestData(), failedReport, CheckOptions{Quarantine: []string{"TestNetwork"}, CommandFailed: true})
+	assert.NoError(t, err)
+}
+
+func TestCheckResultsRecordsHistory(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("FileExistsV1", "/my-project/.cid/test-quarantine.txt").Return(false)
+	store := storagetest.NewStorage(t)
+	data := gocommon.ModuleTestData()
+	data.Env = common.TestProjectData().Env
+
+	_ = CheckResults(sdk, data, failedReport, CheckOptions{Storage: store})
+
+	objectName := flakytest.ObjectName(data.Env["NCI_REPOSITORY_HOST_SERVER"], data.Env["NCI_PROJECT_PATH"], data.Module.Slug)
+	assert.Contains(t, string(store.Object("cidverse-cid", objectName)), `"github.com/cidverse/my-project/TestNetwork":[{"commit":"abcdef123456","status":"failed"`)
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/common"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testresultcommon"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 )
 
 const URI = "builtin://actions/uv-test"
@@ -14,6 +16,7 @@ type Action struct {
 }
 
 type Config struct {
+	Quarantine []string `json:"quarantine"  env:"TEST_QUARANTINE"`
 }
 
 func (a Action) Metadata() actionsdk.ActionMetadata {
@@ -32,7 +35,7 @@ func (a Action) Metadata() actionsdk.ActionMetadata {
 			},
 		},
 		Access: actionsdk.ActionAccess{
-			Environment: []actionsdk.ActionAccessEnv{},
+			Environment: testresultcommon.AccessEnv,
 			Executables: []actionsdk.ActionAccessExecutable{
 				{
 					Name: "uv",
@@ -80,7 +83,7 @@ func (a Action) Execute() (err error) {
 	}
 
 	// parse config
-	_, err = a.GetConfig(d)
+	cfg, err := a.GetConfig(d)
 	if err != nil {
 		return err
 	}
@@ -89,49 +92,58 @@ func (a Action) Execute() (err error) {
 	reportFile := actionsdk.JoinPath(d.Config.TempDir, "pytest.junit.xml")
 	coverageFile := actionsdk.JoinPath(d.Config.TempDir, "pytest.coverage.xml")
 
-	if d.Module.HasDependencyByTypeAndId("pypi", "pytest") {
-		if d.Module.HasDependencyByTypeAndId("pypi", "pytest-cov") {
-			cmdResult, err := a.Sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
-				Command: fmt.Sprintf(`uv run pytest -v --cov --cov-report term --cov-report xml:%q --junit-xml=%q`, coverageFile, reportFile),
-				WorkDir: d.Module.ModuleDir,
-			})
-			if err != nil {
-				return err
-			} else if cmdResult.Code != 0 {
-				return fmt.Errorf("command failed, exit code %d", cmdResult.Code)
-			}
-
-			_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
-				File:   coverageFile,
-				Module: d.Module.Slug,
-				Type:   "report",
-				Format: "cobertura",
-			})
-			if err != nil {
-				return err
-			}
-		} else {
-			cmdResult, err := a.Sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
-				Command: fmt.Sprintf(`uv run pytest -v --junit-xml=%q`, reportFile),
-				WorkDir: d.Module.ModuleDir,
-			})
-			if err != nil {
-				return err
-			} else if cmdResult.Code != 0 {
-				return fmt.Errorf("command failed, exit code %d", cmdResult.Code)
-			}
-		}
+	if !d.Module.HasDependencyByTypeAndId("pypi", "pytest") {
+		return nil
+	}
+
+	// test
+	withCoverage := d.Module.HasDependencyByTypeAndId("pypi", "pytest-cov")
+	command := fmt.Sprintf(`uv run pytest -v --junit-xml=%q`, reportFile)
+	if withCoverage {
+		command = fmt.Sprintf(`uv run pytest -v --cov --cov-report term --cov-report xml:%q --junit-xml=%q`, coverageFile, reportFile)
+	}
+	cmdResult, err := a.Sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
+		Command: command,
+		WorkDir: d.Module.ModuleDir,
+	})
+	if err != nil {
+		return err
+	}
+	testsFailed := cmdResult.Code != 0
+	if testsFailed && !a.Sdk.FileExistsV1(reportFile) {
+		return fmt.Errorf("command failed, exit code %d", cmdResult.Code)
+	}
 
+	if withCoverage {
 		_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
-			File:   reportFile,
+			File:   coverageFile,
 			Module: d.Module.Slug,
 			Type:   "report",
-			Format: "junit",
+			Format: "cobertura",
 		})
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	_, _, err = a.Sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+		File:   reportFile,
+		Module: d.Module.Slug,
+		Type:   "report",
+		Format: "junit",
+	})
+	if err != nil {
+		return err
+	}
+
+	// test results
+	report, err := testresultcommon.ReadReports(a.Sdk, testresult.FormatJUnit, reportFile)
+	if err != nil {
+		return err
+	}
+
+	return testresultcommon.CheckResults(a.Sdk, d, report, testresultcommon.CheckOptions{
+		Quarantine:    cfg.Quarantine,
+		CommandFailed: testsFailed,
+	})
 }
@@ -23,6 +23,8 @@ func TestPythonUVPyTest(t *testing.T) {
 		Type:   "report",
 		Format: "junit",
 	}).Return("", "", nil)
+	sdk.On("FileExistsV1", ".tmp/pytest.junit.xml").Return(true)
+	sdk.On("FileReadV1", ".tmp/pytest.junit.xml").Return(`<testsuites><testsuite name="pytest"><testcase classname="tests.test_app" name="test_add"/></testsuite></testsuites>`, nil)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
@@ -54,8 +56,58 @@ func TestPythonUVPyTestCoverage(t *testing.T) {
 		Type:   "report",
 		Format: "junit",
 	}).Return("", "", nil)
+	sdk.On("FileExistsV1", ".tmp/pytest.junit.xml").Return(true)
+	sdk.On("FileReadV1", ".tmp/pytest.junit.xml").Return(`<testsuites><testsuite name="pytest"><testcase classname="tests.test_app" name="test_add"/></testsuite></testsuites>`, nil)
 
 	action := Action{Sdk: sdk}
 	err := action.Execute()
 	assert.NoError(t, err)
 }
+
+func testPythonUVPyTestFailed(t *testing.T, env map[string]string) error {
+	moduleData := uvcommon.TestModuleData()
+	moduleData.Env = env
+
+	sdk := common.TestSetup(t)
+	sdk.On("ModuleExecutionContextV1").Return(moduleData, nil)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
+		Command: `uv run pytest -v --junit-xml=".tmp/pytest.junit.xml"`,
+		WorkDir: "/my-project",
+	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 1}, nil)
+	sdk.On("ArtifactUploadV1", actionsdk.ArtifactUploadRequest{
+		Module: "my-package",
+		File:   ".tmp/pytest.junit.xml",
+		Type:   "report",
+		Format: "junit",
+	}).Return("", "", nil)
+	sdk.On("FileExistsV1", ".tmp/pytest.junit.xml").Return(true)
+	sdk.On("FileReadV1", ".tmp/pytest.junit.xml").Return(`<testsuites><testsuite name="pytest"><testcase classname="tests.test_app" name="test_network"><failure message="timeout"/></testcase></testsuite></testsuites>`, nil)
+	sdk.On("FileExistsV1", ".cid/test-quarantine.txt").Return(false)
+
+	action := Action{Sdk: sdk}
+	return action.Execute()
+}
+
+func TestPythonUVPyTestFailed(t *testing.T) {
+	err := testPythonUVPyTestFailed(t, map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestPythonUVPyTestQuarantined(t *testing.T) {
+	err := testPythonUVPyTestFailed(t, map[string]string{"TEST_QUARANTINE": "test_network"})
+	assert.NoError(t, err)
+}
+
+func TestPythonUVPyTestCommandFailed(t *testing.T) {
+	sdk := common.TestSetup(t)
+	sdk.On("ModuleExecutionContextV1").Return(uvcommon.TestModuleData(), nil)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{
+		Command: `uv run pytest -v --junit-xml=".tmp/pytest.junit.xml"`,
+		WorkDir: "/my-project",
+	}).Return(&actionsdk.ExecuteCommandV1Response{Code: 4}, nil)
+	sdk.On("FileExistsV1", ".tmp/pytest.junit.xml").Return(false)
+
+	action := Action{Sdk: sdk}
+	err := action.Execute()
+	assert.ErrorContains(t, err, "exit code 4")
+}
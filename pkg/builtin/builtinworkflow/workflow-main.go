@@ -6,6 +6,9 @@ import (
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/cargo/cargobuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/cargo/cargotest"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changeloggenerate"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/changelog/changelogupdate"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/coverage/coveragecheck"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/coverage/coveragemerge"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/dotnet/dotnetbuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/dotnet/dotnettest"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/github/githubreleasepublish"
@@ -32,8 +35,11 @@ import (
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/npm/npmtest"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/poetry/poetrybuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/poetry/poetrytest"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/sarif/sarifbaselinediff"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/sarif/sarifpolicycheck"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/semgrep/semgrepscan"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/sonarqube/sonarqubescan"
+	"github.com/cidverse/cid/pkg/builtin/builtinaction/testresult/testreport"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/trivy/trivyfsscan"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/uv/uvbuild"
 	"github.com/cidverse/cid/pkg/builtin/builtinaction/uv/uvtest"
@@ -125,6 +131,17 @@ func GetWorkflows() []catalog.Workflow {
 					{
 						ID: cargotest.URI,
 					},
+					// coverage
+					{
+						ID: coveragemerge.URI,
+					},
+					{
+						ID: coveragecheck.URI,
+					},
+					// test results
+					{
+						ID: testreport.URI,
+					},
 				},
 			},
 			{
@@ -187,6 +204,13 @@ func GetWorkflows() []catalog.Workflow {
 					{
 						ID: zizmorscan.URI,
 					},
+					// policy
+					{
+						ID: sarifbaselinediff.URI,
+					},
+					{
+						ID: sarifpolicycheck.URI,
+					},
 					// reporting
 					/*
 						{
@@ -238,6 +262,15 @@ func GetWorkflows() []catalog.Workflow {
 							},
 						},
 					},
+					{
+						ID: changelogupdate.URI,
+						Rules: []catalog.WorkflowRule{
+							{
+								Type:       "cel",
+								Expression: `CID_WORKFLOW_TYPE == "release" && ENV["CHANGELOG_UPDATE"] == "true"`,
+							},
+						},
+					},
 					// release
 					{
 						ID: githubreleasepublish.URI,
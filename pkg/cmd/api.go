@@ -29,6 +29,7 @@ cid api --type http --listen localhost:7400`,
 			socketFile, _ := cmd.Flags().GetString("socket")
 			secret, _ := cmd.Flags().GetString("secret")
 			currentModuleID, _ := cmd.Flags().GetInt("current-module")
+			metrics, _ := cmd.Flags().GetBool("metrics")
 
 			// app context
 			cid, err := context.NewAppContext()
@@ -83,6 +84,9 @@ cid api --type http --listen localhost:7400`,
 			if len(secret) > 0 {
 				restapi.SecureWithAPIKey(apiEngine, secret)
 			}
+			if metrics {
+				restapi.ExposeMetrics(apiEngine)
+			}
 			if apiType == "socket" {
 				restapi.ListenOnSocket(apiEngine, socketFile)
 			} else if apiType == "http" {
@@ -97,6 +101,7 @@ cid api --type http --listen localhost:7400`,
 	cmd.Flags().StringP("listen", "l", ":7400", "http listen addr (type=http)")
 	cmd.Flags().String("socket", "", "socket file location (type=socket)")
 	cmd.Flags().String("secret", "", "protects the api with the provided api key")
+	cmd.Flags().Bool("metrics", false, "expose prometheus metrics on /metrics")
 	cmd.Flags().Int("current-module", -1, "which module should be the current module (experimental)")
 
 	return cmd
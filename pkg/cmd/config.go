@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/core/config"
+	"github.com/cidverse/cidverseutils/filesystem"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func configRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Aliases: []string{},
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(0)
+		},
+	}
+
+	cmd.AddCommand(configShowCmd())
+	cmd.AddCommand(configValidateCmd())
+
+	return cmd
+}
+
+func configShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: `prints the effective configuration, merged from the embedded defaults, org, user, project and environment configuration`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			origin, _ := cmd.Flags().GetBool("origin")
+			env, _ := cmd.Flags().GetString("env")
+			log.Debug().Str("command", "config show").Bool("origin", origin).Str("env", env).Msg("running command")
+
+			projectDir, err := filesystem.GetProjectDirectory()
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to get project directory")
+			}
+			if env != "" {
+				_ = os.Setenv(config.EnvironmentVariable, env)
+			}
+
+			cfg, merged, err := config.LoadLayeredConfig(projectDir)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to load config")
+			}
+			if origin {
+				for _, layer := range merged.Layers {
+					log.Debug().Str("origin", layer.Origin).Msg("config layer")
+				}
+			}
+
+			// the registry holds the catalog content, which is not part of the configuration layers
+			effective := *cfg
+			effective.Registry = catalog.Config{}
+			effective.CatalogSources = nil
+
+			var node yaml.Node
+			if err = node.Encode(effective); err != nil {
+				log.Fatal().Err(err).Msg("failed to encode config")
+			}
+			if origin {
+				config.AnnotateOrigins(&node, merged.Origins(), "CID")
+			}
+
+			encoder := yaml.NewEncoder(os.Stdout)
+			encoder.SetIndent(2)
+			if err = encoder.Encode(&node); err != nil {
+				log.Fatal().Err(err).Msg("failed to print config")
+			}
+		},
+	}
+
+	cmd.Flags().Bool("origin", false, "annotate each value with its origin, e.g. the file that sets it")
+	cmd.Flags().String("env", "", "environment overlay to apply, e.g. production loads cid.production.yml")
+
+	return cmd
+}
+
+func configValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: `validates the project configuration, reporting unknown keys, type errors and invalid action configs`,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			file := "cid.yml"
+			if len(args) > 0 {
+				file = args[0]
+			} else if projectDir, err := filesystem.GetProjectDirectory(); err == nil {
+				file = filepath.Join(projectDir, "cid.yml")
+			}
+			log.Debug().Str("command", "config validate").Str("file", file).Msg("running command")
+
+			validationErrors, err := config.ValidateConfigFile(file)
+			if errors.Is(err, os.ErrNotExist) {
+				log.Fatal().Str("file", file).Msg("config file does not exist")
+			} else if err != nil {
+				log.Fatal().Err(err).Str("file", file).Msg("failed to validate config file")
+			}
+
+			for _, e := range validationErrors {
+				_, _ = fmt.Fprintf(os.Stdout, "%s:%s\n", file, e.Error())
+			}
+			if len(validationErrors) > 0 {
+				log.Error().Str("file", file).Int("errors", len(validationErrors)).Msg("config file is invalid")
+				os.Exit(1)
+			}
+			log.Info().Str("file", file).Msg("config file is valid")
+		},
+	}
+
+	return cmd
+}
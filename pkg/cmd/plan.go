@@ -5,13 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/cidverse/cid/pkg/app/appconfig"
 	"github.com/cidverse/cid/pkg/context"
+	"github.com/cidverse/cid/pkg/core/config"
 	"github.com/cidverse/cid/pkg/core/planexecute"
 	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/cid/pkg/util"
+	"github.com/cidverse/cidverseutils/core/clioutputwriter"
+	"github.com/cidverse/cidverseutils/redact"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -30,6 +39,7 @@ func planRootCmd() *cobra.Command {
 
 	cmd.AddCommand(planGenerateCmd())
 	cmd.AddCommand(planExecuteCmd())
+	cmd.AddCommand(planExplainCmd())
 
 	return cmd
 }
@@ -41,6 +51,8 @@ func planGenerateCmd() *cobra.Command {
 		Short:   "",
 		Run: func(cmd *cobra.Command, args []string) {
 			pin, _ := cmd.Flags().GetBool("pin")
+			workflow, _ := cmd.Flags().GetString("workflow")
+			strictRules, _ := cmd.Flags().GetBool("strict-rules")
 
 			// app context
 			cid, err := context.NewAppContext()
@@ -58,6 +70,8 @@ func planGenerateCmd() *cobra.Command {
 				Executables:  cid.Executables,
 				PinVersions:  pin,
 				WorkflowType: "",
+				Workflow:     selectedWorkflow(cid.Config, workflow),
+				StrictRules:  strictRules,
 			})
 			if err != nil {
 				log.Fatal().Err(err).Msg("failed to generate action plan")
@@ -75,6 +89,8 @@ func planGenerateCmd() *cobra.Command {
 	}
 
 	cmd.Flags().Bool("pin", false, "pin all versions when generating the plan")
+	cmd.Flags().StringP("workflow", "w", "", "workflow reference, e.g. myorg/main@1.0.0 (default: workflow of the project configuration or selected by rules)")
+	cmd.Flags().Bool("strict-rules", false, "fail if a rule expression can not be evaluated")
 
 	return cmd
 }
@@ -89,6 +105,10 @@ func planExecuteCmd() *cobra.Command {
 			steps, _ := cmd.Flags().GetStringArray("step")
 			stateFile, _ := cmd.Flags().GetString("state-file")
 			stateWfName, _ := cmd.Flags().GetString("state-wf-name")
+			workflow, _ := cmd.Flags().GetString("workflow")
+			strictRules, _ := cmd.Flags().GetBool("strict-rules")
+			approvals, _ := cmd.Flags().GetStringArray("approve")
+			approvalFile, _ := cmd.Flags().GetString("approval-file")
 			if stateFile == "" {
 				stateFile = filepath.Join(".cid", "state.json")
 			}
@@ -131,6 +151,8 @@ func planExecuteCmd() *cobra.Command {
 					PinVersions:  false,
 					Environments: nil,
 					WorkflowType: "",
+					Workflow:     selectedWorkflow(cid.Config, workflow),
+					StrictRules:  strictRules,
 				})
 				if err != nil {
 					log.Fatal().Err(err).Msg("failed to generate action plan")
@@ -138,6 +160,15 @@ func planExecuteCmd() *cobra.Command {
 				}
 			}
 
+			// approvals, prompt only if attached to a terminal
+			if approvalFile == "" {
+				approvalFile = filepath.Join(util.CIDConfigDir(), "approvals")
+			}
+			var prompt planexecute.ApprovalPrompt
+			if isTerminal(os.Stdin) {
+				prompt = planexecute.TerminalPrompt(os.Stdin, os.Stderr)
+			}
+
 			// run plan
 			planexecute.RunPlan(plan, planexecute.ExecuteContext{
 				Cfg:           cid.Config,
@@ -147,6 +178,9 @@ func planExecuteCmd() *cobra.Command {
 				StagesFilter:  stages,
 				ModulesFilter: []string{},
 				StepFilter:    steps,
+				Approvals:     approvals,
+				ApprovalFile:  approvalFile,
+				Prompt:        prompt,
 			})
 		},
 	}
@@ -155,6 +189,122 @@ func planExecuteCmd() *cobra.Command {
 	cmd.Flags().StringArray("step", []string{}, "limit execution to the specified step(s)")
 	cmd.Flags().String("state-file", "", "path to the state file, defaults to .cid/state.json")
 	cmd.Flags().String("state-wf-name", "", "workflow name, MUST BE present in .cid/state.json")
+	cmd.Flags().String("workflow", "", "workflow reference, e.g. myorg/main@1.0.0 (default: workflow of the project configuration or selected by rules)")
+	cmd.Flags().Bool("strict-rules", false, "fail if a rule expression can not be evaluated")
+	cmd.Flags().StringArray("approve", []string{}, "approve the specified step(s) that require approval, e.g. deployments - all approves every step")
+	cmd.Flags().String("approval-file", "", "path to a file containing approved steps, one per line, defaults to approvals in the cid config directory - files within the project are ignored in ci")
+
+	return cmd
+}
+
+func planExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "explains the rule evaluation of the plan generation",
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			actionFilter, _ := cmd.Flags().GetString("action")
+			moduleFilter, _ := cmd.Flags().GetString("module")
+			workflow, _ := cmd.Flags().GetString("workflow")
+			strictRules, _ := cmd.Flags().GetBool("strict-rules")
+
+			// app context
+			cid, err := context.NewAppContext()
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to prepare app context")
+				os.Exit(1)
+			}
+
+			// evaluate
+			evaluations, planErr := plangenerate.ExplainPlan(plangenerate.GeneratePlanRequest{
+				Modules:      cid.Modules,
+				Registry:     cid.Config.Registry,
+				ProjectDir:   cid.ProjectDir,
+				Env:          cid.Env,
+				Executables:  cid.Executables,
+				WorkflowType: "",
+				Workflow:     selectedWorkflow(cid.Config, workflow),
+				StrictRules:  strictRules,
+			})
+
+			// data
+			data := clioutputwriter.TabularData{
+				Headers: []string{"TYPE", "NAME", "SOURCE", "MODULE", "ENVIRONMENT", "EXPRESSION", "VALUES", "MATCH", "ERROR"},
+				Rows:    [][]interface{}{},
+			}
+			for _, evaluation := range evaluations {
+				if evaluation.Type == "action" && !matchesExplainFilter(evaluation, actionFilter, moduleFilter) {
+					continue
+				}
+
+				if len(evaluation.Rules) == 0 {
+					data.Rows = append(data.Rows, []interface{}{evaluation.Type, evaluation.Name, evaluation.Source, evaluation.Module, evaluation.Environment, "", "", strconv.FormatBool(evaluation.Match), ""})
+				}
+				for _, rule := range evaluation.Rules {
+					data.Rows = append(data.Rows, []interface{}{evaluation.Type, evaluation.Name, evaluation.Source, evaluation.Module, evaluation.Environment, rule.Expression, formatRuleValues(rule.Values), strconv.FormatBool(rule.Match), rule.Error})
+				}
+			}
+
+			// print
+			writer := redact.NewProtectedWriter(nil, os.Stdout, &sync.Mutex{}, nil)
+			err = clioutputwriter.PrintData(writer, data, clioutputwriter.Format(format))
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to print data")
+				os.Exit(1)
+			}
+			if planErr != nil {
+				log.Fatal().Err(planErr).Msg("failed to generate action plan")
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("format", "f", string(clioutputwriter.DefaultOutputFormat()), fmt.Sprintf("output format %s", clioutputwriter.SupportedOutputFormats()))
+	cmd.Flags().StringP("action", "a", "", "limit the action rules to the specified action id")
+	cmd.Flags().StringP("module", "m", "", "limit the action rules to the specified module slug")
+	cmd.Flags().StringP("workflow", "w", "", "workflow reference, e.g. myorg/main@1.0.0 (default: workflow of the project configuration or selected by rules)")
+	cmd.Flags().Bool("strict-rules", false, "fail if a rule expression can not be evaluated")
 
 	return cmd
 }
+
+// matchesExplainFilter checks if the action evaluation matches the action and module filter, matrix combinations of an action match the action id
+func matchesExplainFilter(evaluation plangenerate.RuleEvaluation, action string, module string) bool {
+	if action != "" && evaluation.Name != action && !strings.HasPrefix(evaluation.Name, action+" {") {
+		return false
+	}
+	if module != "" && evaluation.Module != module {
+		return false
+	}
+
+	return true
+}
+
+// formatRuleValues formats the context values referenced by a rule as sorted key=value list
+func formatRuleValues(values map[string]interface{}) string {
+	var result []string
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		result = append(result, fmt.Sprintf("%s=%v", key, values[key]))
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// selectedWorkflow returns the workflow reference of the flag, falling back to the project configuration
+func selectedWorkflow(cfg *config.CIDConfig, flag string) string {
+	if flag != "" {
+		return flag
+	}
+
+	return cfg.Workflow
+}
+
+// isTerminal returns true if the file is an interactive terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
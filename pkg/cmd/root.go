@@ -38,6 +38,8 @@ func RootCmd() *cobra.Command {
 	cmd.AddCommand(docsCmd())
 	cmd.AddCommand(infoCmd())
 	cmd.AddCommand(moduleRootCmd())
+	cmd.AddCommand(schemaCmd())
+	cmd.AddCommand(configRootCmd())
 
 	// execute
 	cmd.AddCommand(planRootCmd())
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/core/config"
+	"github.com/cidverse/cid/pkg/lib/jsonschema"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func schemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema [cid|catalog|workflow|<action>]",
+		Short: `prints the JSON schema of the cid.yml, catalog files, workflows or the config of a builtin action`,
+		Long: `prints the JSON schema of the cid.yml, catalog files, workflows or the config of a builtin action.
+Use --output-dir to write all schemas, editors supporting the yaml-language-server can reference them with a modeline:
+  # yaml-language-server: $schema=` + config.SchemaBaseURI + config.SchemaFileConfig,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			outputDir, _ := cmd.Flags().GetString("output-dir")
+			log.Debug().Str("command", "schema").Strs("args", args).Str("output-dir", outputDir).Msg("running command")
+
+			schemas := config.Schemas()
+			if outputDir != "" {
+				for _, file := range slices.Sorted(maps.Keys(schemas)) {
+					if err := writeSchema(filepath.Join(outputDir, file), schemas[file]); err != nil {
+						log.Fatal().Err(err).Str("file", file).Msg("failed to write schema")
+					}
+				}
+				return
+			}
+
+			name := "cid"
+			if len(args) > 0 {
+				name = args[0]
+			}
+			file, ok := map[string]string{"cid": config.SchemaFileConfig, "catalog": config.SchemaFileCatalog, "workflow": config.SchemaFileWorkflow}[name]
+			if !ok {
+				file = "actions/" + name + ".schema.json"
+			}
+			s, ok := schemas[file]
+			if !ok {
+				log.Fatal().Str("name", name).Msg("unknown schema, expected cid, catalog, workflow or the name of a builtin action")
+			}
+
+			out, err := json.MarshalIndent(s, "", "  ")
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to marshal schema")
+			}
+			_, _ = fmt.Fprintln(os.Stdout, string(out))
+		},
+	}
+
+	cmd.Flags().StringP("output-dir", "o", "", "write all schemas into the directory")
+
+	return cmd
+}
+
+func writeSchema(file string, s *jsonschema.Schema) error {
+	out, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return os.WriteFile(file, []byte(strings.TrimSpace(string(out))+"\n"), 0644)
+}
@@ -52,13 +52,13 @@ func stageListCmd() *cobra.Command {
 				Rows:    [][]interface{}{},
 			}
 			for _, wf := range cid.Config.Registry.Workflows {
-				if len(workflows) > 0 && !slices.Contains(workflows, wf.Name) {
+				if len(workflows) > 0 && !slices.Contains(workflows, wf.Name) && !slices.Contains(workflows, wf.ID()) {
 					continue
 				}
 
 				for _, stage := range wf.Stages {
 					data.Rows = append(data.Rows, []interface{}{
-						wf.Name,
+						wf.ID(),
 						stage.Name,
 						rules.EvaluateRulesAsText(stage.Rules, rules.GetRuleContext(cid.Env)),
 						strconv.Itoa(len(stage.Actions)),
@@ -4,14 +4,21 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"sync"
 
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
 	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/context"
+	"github.com/cidverse/cidverseutils/core/clioutputwriter"
+	"github.com/cidverse/cidverseutils/redact"
 	"github.com/cidverse/cidverseutils/version"
+	"github.com/cidverse/go-vcs"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 func versionCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number of cid",
 		Long:  `All software has versions. This is cid's`,
@@ -30,4 +37,92 @@ func versionCmd() *cobra.Command {
 			_, _ = fmt.Fprintf(os.Stdout, "Platform:      %s\n", runtime.GOOS+"/"+runtime.GOARCH)
 		},
 	}
+
+	cmd.AddCommand(versionNextCmd())
+
+	return cmd
+}
+
+func versionNextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "next",
+		Short: "prints the next release version of the project, based on the commits since the latest release",
+		Run: func(cmd *cobra.Command, args []string) {
+			explain, _ := cmd.Flags().GetBool("explain")
+			format, _ := cmd.Flags().GetString("format")
+
+			// app context
+			cid, err := context.NewAppContext()
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to prepare app context")
+				os.Exit(1)
+			}
+
+			client, err := vcs.GetVCSClient(cid.ProjectDir)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to open vcs repository")
+				os.Exit(1)
+			}
+
+			branch := ""
+			if cid.Env["NCI_COMMIT_REF_TYPE"] == "branch" {
+				branch = cid.Env["NCI_COMMIT_REF_NAME"]
+			}
+			result, err := commitanalyser.NextVersion(client, branch, cid.Config.Conventions.CommitPatterns(), cid.Config.Versioning.ReleaseRules(), cid.Config.Versioning.ReleaseChannels(cid.Config.Conventions.Branching))
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to determinate next version")
+				os.Exit(1)
+			}
+
+			_, _ = fmt.Fprintln(os.Stdout, result.Version)
+			if !explain {
+				return
+			}
+
+			// explain which commits caused the release
+			channel := "stable"
+			if result.Channel != nil && result.Channel.ReleaseLine {
+				channel = "release line " + branch
+			} else if result.Channel != nil {
+				channel = result.Channel.PreRelease
+			}
+			_, _ = fmt.Fprintf(os.Stdout, "\nprevious release %s, %d commit(s) analyzed, channel %s\n\n", result.Previous, len(result.Commits), channel)
+
+			data := clioutputwriter.TabularData{
+				Headers: []string{"COMMIT", "TYPE", "SCOPE", "RULE", "RELEASE", "MESSAGE"},
+				Rows:    [][]interface{}{},
+			}
+			for _, c := range result.Commits {
+				if c.Release == version.ReleaseNone {
+					continue
+				}
+
+				rule := ""
+				if c.Breaking {
+					rule = "breaking (!)"
+				} else if c.Rule != nil {
+					rule = fmt.Sprintf("type=%s scope=%s footer=%s", c.Rule.Type, c.Rule.Scope, c.Rule.Footer)
+				}
+				data.Rows = append(data.Rows, []interface{}{
+					c.Commit.ShortHash,
+					c.Type,
+					c.Scope,
+					rule,
+					commitanalyser.ReleaseTypeName(c.Release),
+					c.Commit.Message,
+				})
+			}
+
+			writer := redact.NewProtectedWriter(nil, os.Stdout, &sync.Mutex{}, nil)
+			err = clioutputwriter.PrintData(writer, data, clioutputwriter.Format(format))
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to print data")
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Bool("explain", false, "explain which commits caused the version bump")
+	cmd.Flags().StringP("format", "f", string(clioutputwriter.DefaultOutputFormat()), fmt.Sprintf("output format of the explanation %s", clioutputwriter.SupportedOutputFormats()))
+
+	return cmd
 }
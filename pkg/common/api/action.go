@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"log/slog"
 	"os/user"
 	"path/filepath"
@@ -52,6 +53,7 @@ type ActionExecutionContext struct {
 	CurrentUser     user.User                    // CurrentUser holds information about the user running this process
 	Modules         []*analyzerapi.ProjectModule // Modules contains the project modules
 	CurrentModule   *analyzerapi.ProjectModule   // CurrentModule contains the module that is currently being build
+	TraceContext    context.Context              // TraceContext holds the span of the current step, used as parent for nested spans
 }
 
 // CoverageReport contains a generic coverage report
@@ -81,6 +81,9 @@ func GetCIDEnvironment(configEnv map[string]string, projectDirectory string) (ma
 	// append cid vars
 	env["CID_CONVENTION_BRANCHING"] = string(config.Current.Conventions.Branching)
 	env["CID_CONVENTION_COMMIT"] = string(config.Current.Conventions.Commit)
+	if config.Current.Conventions.CommitPattern != "" {
+		env["CID_CONVENTION_COMMIT_PATTERN"] = config.Current.Conventions.CommitPattern
+	}
 
 	// prio 3: cid config file
 	for key, value := range configEnv {
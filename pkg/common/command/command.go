@@ -1,12 +1,16 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/cidverse/cid/pkg/common/executable"
+	"github.com/cidverse/cid/pkg/lib/telemetry"
 	"github.com/cidverse/go-ptr"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -29,6 +33,7 @@ type Opts struct {
 	UserProvidedConstraint string
 	Constraints            map[string]string
 	Stdin                  io.Reader
+	TraceContext           context.Context // TraceContext is the parent of the executable resolution span, optional
 }
 
 // Execute gets called from actions or the api to execute commands
@@ -55,17 +60,23 @@ func Execute(opts Opts) (stdout string, stderr string, cand executable.Executabl
 		versionConstraint = opts.UserProvidedConstraint
 	}
 
-	// select candidate
+	// select candidate, the span is only recorded if tracing is enabled
+	start := time.Now()
 	c := executable.SelectCandidate(opts.Candidates, executable.CandidateFilter{
 		Types:             opts.CandidateTypes,
 		Executable:        cmdBinary,
 		VersionPreference: executable.PreferHighest,
 		VersionConstraint: versionConstraint,
 	})
+	telemetry.ExecutableResolve.WithLabelValues(cmdBinary).Observe(time.Since(start).Seconds())
+	spanAttributes := []attribute.KeyValue{attribute.String("cid.executable", cmdBinary), attribute.String("cid.executable.constraint", versionConstraint)}
 	if c == nil {
-		return "", "", nil, fmt.Errorf("no candidate found for %s fulfilling constraint %s", cmdBinary, versionConstraint)
+		err = fmt.Errorf("no candidate found for %s fulfilling constraint %s", cmdBinary, versionConstraint)
+		telemetry.RecordSpan(opts.TraceContext, "resolve "+cmdBinary, start, err, spanAttributes...)
+		return "", "", nil, err
 	}
 	cand = ptr.Value(c)
+	telemetry.RecordSpan(opts.TraceContext, "resolve "+cmdBinary, start, nil, append(spanAttributes, attribute.String("cid.executable.uri", cand.GetUri()))...)
 
 	// run command
 	stdout, stderr, err = cand.Run(executable.RunParameters{
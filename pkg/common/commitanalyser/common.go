@@ -1,69 +1,195 @@
 package commitanalyser
 
 import (
+	"fmt"
+	"path"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/cidverse/cidverseutils/version"
 	"github.com/cidverse/go-vcs/vcsapi"
 
-	"github.com/oriser/regroup"
 	"github.com/rs/zerolog/log"
 )
 
+var footerRegex = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z-]+)(?:: | #)`)
+
+// CommitAnalysis holds the release relevant information of a single commit
+type CommitAnalysis struct {
+	Commit   vcsapi.Commit
+	Type     string
+	Scope    string
+	Breaking bool
+	Footers  []string
+	Rule     *CommitVersionRule // Rule is the first matching rule, nil if no rule matched
+	Release  version.ReleaseType
+}
+
 func DeterminateNextReleaseVersion(commits []vcsapi.Commit, commitPatternList []string, rules []CommitVersionRule, previousVersionStr string) (string, error) {
-	var releaseTypes = []version.ReleaseType{version.ReleaseNone}
+	// bump version
+	highestReleaseType := DeterminateReleaseType(AnalyzeCommits(commits, commitPatternList, rules))
+	nextVersion, nextVersionErr := version.Bump(previousVersionStr, highestReleaseType)
+	if nextVersionErr != nil {
+		return "", nextVersionErr
+	}
+	return nextVersion, nil
+}
 
-	var commitExpr []*regexp.Regexp
-	var commitGroupExpr []*regroup.ReGroup
-	for _, commitPattern := range commitPatternList {
-		commitExpr = append(commitExpr, regexp.MustCompile(commitPattern))
-		commitGroupExpr = append(commitGroupExpr, regroup.MustCompile(commitPattern))
+// DeterminateReleaseType returns the highest release type of all analyzed commits
+func DeterminateReleaseType(analysis []CommitAnalysis) version.ReleaseType {
+	var releaseTypes = []version.ReleaseType{version.ReleaseNone}
+	for _, a := range analysis {
+		releaseTypes = append(releaseTypes, a.Release)
 	}
 
-	for i := 0; i < len(commits); i++ {
-		commit := commits[i]
+	return version.HighestReleaseType(releaseTypes)
+}
 
-		for id := range commitPatternList {
-			// check if commit matches the pattern
-			if !commitExpr[id].MatchString(commit.Message) {
-				continue
-			}
+// AnalyzeCommits determinates the release type of each commit matching one of the commit patterns
+func AnalyzeCommits(commits []vcsapi.Commit, commitPatternList []string, rules []CommitVersionRule) []CommitAnalysis {
+	parser := NewCommitParser(commitPatternList)
 
-			match, matchErr := commitGroupExpr[id].Groups(commit.Message)
-			if matchErr != nil {
-				log.Err(matchErr).Msg("failed to match commit pattern")
-				continue
-			}
+	var result []CommitAnalysis
+	for _, commit := range commits {
+		match, ok := parser.Parse(commit.Message)
+		if !ok {
+			continue
+		}
 
-			var commitType = match["type"]
-			var commitScope = match["scope"]
-			var isBreaking = len(match["breaking"]) > 0
-			var subject = match["subject"]
-			log.Trace().Str("commit-type", commitType).Str("commit-scope", commitScope).Bool("is-breaking-change", isBreaking).Str("commit-message", subject).Msg("analyzing commit ...")
+		a := CommitAnalysis{
+			Commit:   commit,
+			Type:     match["type"],
+			Scope:    match["scope"],
+			Breaking: len(match["breaking"]) > 0,
+			Footers:  ParseFooters(commit.Description),
+		}
+		log.Trace().Str("commit-type", a.Type).Str("commit-scope", a.Scope).Bool("is-breaking-change", a.Breaking).Str("commit-message", match["subject"]).Msg("analyzing commit ...")
 
-			if isBreaking {
-				releaseTypes = append(releaseTypes, version.ReleaseMajor)
+		for i := range rules {
+			if rules[i].Matches(a.Type, a.Scope, a.Footers) {
+				a.Rule = &rules[i]
+				a.Release = parseReleaseType(rules[i].Release)
+				break
 			}
+		}
+		if a.Breaking {
+			a.Release = version.ReleaseMajor
+		}
 
-			for _, rule := range rules {
-				if commitType == rule.Type && commitScope == rule.Scope {
-					if rule.Release == "patch" {
-						releaseTypes = append(releaseTypes, version.ReleasePatch)
-					} else if rule.Release == "minor" {
-						releaseTypes = append(releaseTypes, version.ReleaseMinor)
-					} else if rule.Release == "major" {
-						releaseTypes = append(releaseTypes, version.ReleaseMajor)
-					}
-				}
-			}
+		result = append(result, a)
+	}
+
+	return result
+}
+
+// Matches checks if the rule applies to a commit with the given type, scope and footer tokens
+func (r CommitVersionRule) Matches(commitType string, commitScope string, footers []string) bool {
+	if !matchWildcard(r.Type, commitType) {
+		return false
+	}
+
+	// multiple scopes can be separated by a comma, e.g. feat(api,cli)
+	scopes := strings.Split(commitScope, ",")
+	scopeMatch := false
+	for _, scope := range scopes {
+		if matchWildcard(r.Scope, strings.TrimSpace(scope)) {
+			scopeMatch = true
+			break
 		}
 	}
+	if !scopeMatch {
+		return false
+	}
 
-	// bump version
-	highestReleaseType := version.HighestReleaseType(releaseTypes)
-	nextVersion, nextVersionErr := version.Bump(previousVersionStr, highestReleaseType)
-	if nextVersionErr != nil {
-		return "", nextVersionErr
+	if r.Footer == "" {
+		return true
 	}
-	return nextVersion, nil
+	for _, footer := range footers {
+		if matchWildcard(r.Footer, footer) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFooters returns the footer tokens of a commit description, e.g. `BREAKING CHANGE` or `Refs`
+func ParseFooters(description string) []string {
+	var footers []string
+	for _, line := range strings.Split(description, "\n") {
+		match := footerRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match != nil {
+			footers = append(footers, match[1])
+		}
+	}
+
+	return footers
+}
+
+// FindReleaseChannel returns the first release channel matching the branch, nil if the branch publishes stable releases
+func FindReleaseChannel(channels []ReleaseChannel, branch string) *ReleaseChannel {
+	for i := range channels {
+		if channels[i].Branch != "" && matchWildcard(channels[i].Branch, branch) {
+			return &channels[i]
+		}
+	}
+
+	return nil
+}
+
+// PreReleaseVersion returns the next pre-release of a version by counting up the existing tags of the channel, e.g. 1.2.0-rc.3
+func PreReleaseVersion(ver string, identifier string, tags []vcsapi.VCSRef) string {
+	ver = strings.TrimPrefix(ver, "v")
+	prefix := ver + "-" + identifier + "."
+
+	number := 0
+	for _, tag := range tags {
+		value := strings.TrimPrefix(tag.Value, "v")
+		if !strings.HasPrefix(value, prefix) {
+			continue
+		}
+
+		if n, err := strconv.Atoi(strings.TrimPrefix(value, prefix)); err == nil && n > number {
+			number = n
+		}
+	}
+
+	return fmt.Sprintf("%s%d", prefix, number+1)
+}
+
+func parseReleaseType(release string) version.ReleaseType {
+	switch release {
+	case "major":
+		return version.ReleaseMajor
+	case "minor":
+		return version.ReleaseMinor
+	case "patch":
+		return version.ReleasePatch
+	default:
+		return version.ReleaseNone
+	}
+}
+
+// ReleaseTypeName returns the name of the release type, as used in the release rules
+func ReleaseTypeName(releaseType version.ReleaseType) string {
+	switch releaseType {
+	case version.ReleaseMajor:
+		return "major"
+	case version.ReleaseMinor:
+		return "minor"
+	case version.ReleasePatch:
+		return "patch"
+	default:
+		return "none"
+	}
+}
+
+// matchWildcard matches the value against a pattern with wildcards, an empty pattern matches everything
+func matchWildcard(pattern string, value string) bool {
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
 }
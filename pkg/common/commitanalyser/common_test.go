@@ -3,6 +3,7 @@ package commitanalyser
 import (
 	"testing"
 
+	"github.com/cidverse/cidverseutils/version"
 	"github.com/cidverse/go-vcs/vcsapi"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +47,78 @@ func TestDeterminateNextReleaseVersionFix(t *testing.T) {
 	var nextVersion, _ = DeterminateNextReleaseVersion(commits, []string{ConventionalCommitPattern}, DefaultReleaseVersionRules, "v1.0.0")
 	assert.Equal(t, "1.0.1", nextVersion, "they should be equal")
 }
+
+func TestDeterminateNextReleaseVersionScope(t *testing.T) {
+	var commits = []vcsapi.Commit{
+		{
+			Message: `feat(api): adds new endpoint`,
+		},
+	}
+
+	var nextVersion, _ = DeterminateNextReleaseVersion(commits, []string{ConventionalCommitPattern}, DefaultReleaseVersionRules, "v1.0.0")
+	assert.Equal(t, "1.1.0", nextVersion)
+}
+
+func TestDeterminateNextReleaseVersionFooter(t *testing.T) {
+	var commits = []vcsapi.Commit{
+		{
+			Message:     `fix: change config format`,
+			Description: "the config format changed\n\nBREAKING CHANGE: the config key foo was removed\nRefs: #123",
+		},
+	}
+
+	var nextVersion, _ = DeterminateNextReleaseVersion(commits, []string{ConventionalCommitPattern}, DefaultReleaseVersionRules, "v1.0.0")
+	assert.Equal(t, "2.0.0", nextVersion)
+}
+
+func TestAnalyzeCommitsFirstRuleWins(t *testing.T) {
+	rules := []CommitVersionRule{
+		{Type: "feat", Scope: "deps*", Release: "patch"},
+		{Type: "chore", Scope: "deps", Release: "none"},
+		{Type: "*", Release: "minor"},
+	}
+	var commits = []vcsapi.Commit{
+		{Message: `feat(deps-dev): update dependency`},
+		{Message: `chore(deps): update lockfile`},
+		{Message: `docs: update readme`},
+	}
+
+	analysis := AnalyzeCommits(commits, []string{ConventionalCommitPattern}, rules)
+	assert.Len(t, analysis, 3)
+	assert.Equal(t, version.ReleasePatch, analysis[0].Release)
+	assert.Equal(t, version.ReleaseNone, analysis[1].Release)
+	assert.Equal(t, &rules[1], analysis[1].Rule)
+	assert.Equal(t, version.ReleaseMinor, analysis[2].Release)
+}
+
+func TestPreReleaseVersion(t *testing.T) {
+	channel := FindReleaseChannel(DefaultReleaseChannels, "release/1.2")
+	assert.NotNil(t, channel)
+	assert.Equal(t, "rc", channel.PreRelease)
+	assert.Nil(t, FindReleaseChannel(DefaultReleaseChannels, "main"))
+
+	tags := []vcsapi.VCSRef{
+		{Type: "tag", Value: "v1.2.0-rc.1"},
+		{Type: "tag", Value: "v1.2.0-rc.2"},
+		{Type: "tag", Value: "v1.2.0-beta.5"},
+		{Type: "tag", Value: "v1.1.0"},
+	}
+	assert.Equal(t, "1.2.0-rc.3", PreReleaseVersion("1.2.0", "rc", tags))
+	assert.Equal(t, "1.2.0-beta.6", PreReleaseVersion("v1.2.0", "beta", tags))
+	assert.Equal(t, "1.3.0-rc.1", PreReleaseVersion("1.3.0", "rc", tags))
+}
+
+func TestLatestReleaseTag(t *testing.T) {
+	tags := []vcsapi.VCSRef{
+		{Type: "tag", Value: "v1.2.0"},
+		{Type: "tag", Value: "v1.10.0"},
+		{Type: "tag", Value: "v2.0.0-rc.1"},
+		{Type: "tag", Value: "go-lib/v3.0.0"},
+		{Type: "tag", Value: "latest"},
+	}
+
+	tag, ver, found := LatestReleaseTag(tags)
+	assert.True(t, found)
+	assert.Equal(t, "v1.10.0", tag.Value)
+	assert.Equal(t, "1.10.0", ver)
+}
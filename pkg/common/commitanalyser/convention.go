@@ -0,0 +1,164 @@
+package commitanalyser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/oriser/regroup"
+	"github.com/rs/zerolog/log"
+)
+
+// commit conventions, the values match config.CommitConventionType
+const (
+	ConventionConventionalCommits = "ConventionalCommits"
+	ConventionGitmoji             = "Gitmoji"
+	ConventionJira                = "Jira"
+)
+
+// GitmojiCommitPattern matches gitmoji commits using a shortcode or the emoji itself, e.g. `:sparkles: add login` or `✨ (api): add login` - https://gitmoji.dev
+var GitmojiCommitPattern = `^(?P<type>:[a-z0-9_+-]+:|[\x{1F000}-\x{1FAFF}\x{2600}-\x{27BF}\x{2B00}-\x{2BFF}\x{2190}-\x{21FF}\x{2300}-\x{23FF}]\x{FE0F}?)\s*(?:\((?P<scope>[^()\r\n]*)\))?(?P<breaking>!)?:?\s*(?P<subject>.*)$`
+
+// JiraCommitPattern matches commits prefixed with a Jira issue key, the remainder may follow the conventional commits spec, e.g. `ABC-123 feat(api): add login` or `[ABC-123] add login`
+var JiraCommitPattern = `^\[?(?P<issue>[A-Z][A-Z0-9]+-\d+)\]?[\s:]+(?:(?P<type>[a-z]+)(?:\((?P<scope>[^()\r\n]*)\))?(?P<breaking>!)?:\s*)?(?P<subject>.*)$`
+
+// UntypedCommitType is the type of commits that match a pattern without a type group, e.g. a Jira commit without conventional prefix
+const UntypedCommitType = "chore"
+
+// GitmojiTypes maps gitmojis to conventional commit types, the breaking change gitmoji maps to a breaking feature
+var GitmojiTypes = map[string]string{
+	":sparkles:":                  "feat",
+	"✨":                           "feat",
+	":boom:":                      "feat!",
+	"💥":                           "feat!",
+	":bug:":                       "fix",
+	"🐛":                           "fix",
+	":ambulance:":                 "fix",
+	"🚑":                           "fix",
+	":adhesive_bandage:":          "fix",
+	"🩹":                           "fix",
+	":lock:":                      "fix",
+	"🔒":                           "fix",
+	":zap:":                       "perf",
+	"⚡":                           "perf",
+	":recycle:":                   "refactor",
+	"♻":                           "refactor",
+	":art:":                       "style",
+	"🎨":                           "style",
+	":lipstick:":                  "style",
+	"💄":                           "style",
+	":memo:":                      "docs",
+	"📝":                           "docs",
+	":white_check_mark:":          "test",
+	"✅":                           "test",
+	":test_tube:":                 "test",
+	"🧪":                           "test",
+	":construction_worker:":       "ci",
+	"👷":                           "ci",
+	":green_heart:":               "ci",
+	"💚":                           "ci",
+	":building_construction:":     "build",
+	"🏗":                           "build",
+	":arrow_up:":                  "chore",
+	"⬆":                           "chore",
+	":arrow_down:":                "chore",
+	"⬇":                           "chore",
+	":heavy_plus_sign:":           "chore",
+	"➕":                           "chore",
+	":heavy_minus_sign:":          "chore",
+	"➖":                           "chore",
+	":pushpin:":                   "chore",
+	"📌":                           "chore",
+	":wrench:":                    "chore",
+	"🔧":                           "chore",
+	":bookmark:":                  "chore",
+	"🔖":                           "chore",
+	":fire:":                      "chore",
+	"🔥":                           "chore",
+	":rotating_light:":            "chore",
+	"🚨":                           "chore",
+	":see_no_evil:":               "chore",
+	"🙈":                           "chore",
+	":card_file_box:":             "chore",
+	"🗃":                           "chore",
+	":globe_with_meridians:":      "feat",
+	"🌐":                           "feat",
+	":children_crossing:":         "feat",
+	"🚸":                           "feat",
+	":wheelchair:":                "feat",
+	"♿":                           "feat",
+	":chart_with_upwards_trend:":  "feat",
+	"📈":                           "feat",
+	":passport_control:":          "feat",
+	"🛂":                           "feat",
+	":rewind:":                    "revert",
+	"⏪":                           "revert",
+	":twisted_rightwards_arrows:": "chore",
+	"🔀":                           "chore",
+}
+
+// CommitPatterns returns the commit patterns of a commit convention, the custom pattern takes precedence if set
+func CommitPatterns(convention string, customPattern string) []string {
+	if customPattern != "" {
+		return []string{customPattern}
+	}
+
+	switch convention {
+	case ConventionGitmoji:
+		return []string{GitmojiCommitPattern, "^" + ConventionalCommitPattern + "$"}
+	case ConventionJira:
+		return []string{JiraCommitPattern}
+	default:
+		return []string{"^" + ConventionalCommitPattern + "$"}
+	}
+}
+
+// CommitParser extracts type, scope, breaking, subject and issue of commit messages using a list of commit patterns, the first matching pattern wins
+type CommitParser struct {
+	expr      []*regexp.Regexp
+	groupExpr []*regroup.ReGroup
+}
+
+// NewCommitParser compiles the commit patterns
+func NewCommitParser(commitPatternList []string) *CommitParser {
+	p := &CommitParser{}
+	for _, commitPattern := range commitPatternList {
+		p.expr = append(p.expr, regexp.MustCompile(commitPattern))
+		p.groupExpr = append(p.groupExpr, regroup.MustCompile(commitPattern))
+	}
+
+	return p
+}
+
+// Parse returns the named groups of the first matching pattern, gitmojis are translated into conventional commit types
+func (p *CommitParser) Parse(message string) (map[string]string, bool) {
+	for id := range p.expr {
+		// check if commit matches the pattern
+		if !p.expr[id].MatchString(message) {
+			continue
+		}
+
+		match, matchErr := p.groupExpr[id].Groups(message)
+		if matchErr != nil {
+			log.Err(matchErr).Msg("failed to match commit pattern")
+			continue
+		}
+
+		normalizeCommitType(match)
+		return match, true
+	}
+
+	return nil, false
+}
+
+func normalizeCommitType(match map[string]string) {
+	if commitType, ok := GitmojiTypes[strings.TrimSuffix(match["type"], "\uFE0F")]; ok {
+		if strings.HasSuffix(commitType, "!") {
+			commitType = strings.TrimSuffix(commitType, "!")
+			match["breaking"] = "!"
+		}
+		match["type"] = commitType
+	}
+	if match["type"] == "" {
+		match["type"] = UntypedCommitType
+	}
+}
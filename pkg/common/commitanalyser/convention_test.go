@@ -0,0 +1,90 @@
+package commitanalyser
+
+import (
+	"testing"
+
+	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitParserGitmoji(t *testing.T) {
+	parser := NewCommitParser(CommitPatterns(ConventionGitmoji, ""))
+
+	match, ok := parser.Parse(":sparkles: add login")
+	assert.True(t, ok)
+	assert.Equal(t, "feat", match["type"])
+	assert.Equal(t, "add login", match["subject"])
+
+	match, ok = parser.Parse("🐛(api): fix token refresh")
+	assert.True(t, ok)
+	assert.Equal(t, "fix", match["type"])
+	assert.Equal(t, "api", match["scope"])
+
+	match, ok = parser.Parse("♻️ simplify config loading")
+	assert.True(t, ok)
+	assert.Equal(t, "refactor", match["type"])
+
+	match, ok = parser.Parse("💥 drop v1 api")
+	assert.True(t, ok)
+	assert.Equal(t, "feat", match["type"])
+	assert.Equal(t, "!", match["breaking"])
+
+	// conventional commits are accepted as fallback
+	match, ok = parser.Parse("fix: resolve crash")
+	assert.True(t, ok)
+	assert.Equal(t, "fix", match["type"])
+
+	_, ok = parser.Parse("update readme")
+	assert.False(t, ok)
+}
+
+func TestCommitParserJira(t *testing.T) {
+	parser := NewCommitParser(CommitPatterns(ConventionJira, ""))
+
+	match, ok := parser.Parse("ABC-123 feat(api): add login")
+	assert.True(t, ok)
+	assert.Equal(t, "ABC-123", match["issue"])
+	assert.Equal(t, "feat", match["type"])
+	assert.Equal(t, "api", match["scope"])
+	assert.Equal(t, "add login", match["subject"])
+
+	match, ok = parser.Parse("[OPS-7] rotate certificates")
+	assert.True(t, ok)
+	assert.Equal(t, "OPS-7", match["issue"])
+	assert.Equal(t, UntypedCommitType, match["type"])
+	assert.Equal(t, "rotate certificates", match["subject"])
+
+	_, ok = parser.Parse("feat: add login")
+	assert.False(t, ok)
+}
+
+func TestCommitPatternsCustom(t *testing.T) {
+	patterns := CommitPatterns(ConventionJira, `^(?P<issue>PROJ-\d+): (?P<subject>.*)$`)
+	assert.Len(t, patterns, 1)
+
+	var commits = []vcsapi.Commit{
+		{Message: `PROJ-42: improve search`},
+		{Message: `ABC-1 feat: not matching the custom pattern`},
+	}
+	analysis := AnalyzeCommits(commits, patterns, DefaultReleaseVersionRules)
+	assert.Len(t, analysis, 1)
+	assert.Equal(t, UntypedCommitType, analysis[0].Type)
+}
+
+func TestReleaseLine(t *testing.T) {
+	line, ok := ParseReleaseLine("release/1.4")
+	assert.True(t, ok)
+	assert.Equal(t, "1.4", line)
+	line, ok = ParseReleaseLine("release/v2.10.x")
+	assert.True(t, ok)
+	assert.Equal(t, "2.10", line)
+	_, ok = ParseReleaseLine("release/next")
+	assert.False(t, ok)
+
+	next, err := ReleaseLineVersion("1.4", "1.4.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.4.3", next)
+	next, err = ReleaseLineVersion("1.4", "1.6.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.4.0", next)
+}
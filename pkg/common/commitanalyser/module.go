@@ -0,0 +1,136 @@
+package commitanalyser
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cidverseutils/version"
+	"github.com/cidverse/go-vcs/vcsapi"
+)
+
+// ModuleTagSeparator separates the module slug from the version in module tags, e.g. my-module/v1.2.3
+const ModuleTagSeparator = "/"
+
+var moduleTagRegex = regexp.MustCompile(`^(?P<module>.+)/v?(?P<version>\d+\.\d+\.\d+\S*)$`)
+
+// ModuleScope describes which commits belong to a module
+type ModuleScope struct {
+	Slug      string   // Slug is used for module tags and matched against the commit scope
+	Name      string   // Name is matched against the commit scope
+	Directory string   // Directory relative to the repository root, empty for the root module
+	Exclude   []string // Exclude contains directories of nested modules, relative to the repository root
+}
+
+// ModuleTag returns the tag name of a module release, e.g. my-module/v1.2.3
+func ModuleTag(slug string, ver string) string {
+	return slug + ModuleTagSeparator + "v" + strings.TrimPrefix(ver, "v")
+}
+
+// ParseModuleTag splits a module tag into the module slug and the version
+func ParseModuleTag(tag string) (slug string, ver string, ok bool) {
+	match := moduleTagRegex.FindStringSubmatch(tag)
+	if match == nil || !version.IsValidSemver(match[2]) {
+		return "", "", false
+	}
+
+	return match[1], match[2], true
+}
+
+// LatestModuleTag returns the tag of the highest stable version of a module
+func LatestModuleTag(tags []vcsapi.VCSRef, slug string) (tag vcsapi.VCSRef, ver string, found bool) {
+	for _, t := range tags {
+		tagSlug, tagVersion, ok := ParseModuleTag(t.Value)
+		if !ok || tagSlug != slug || !version.IsStable(tagVersion) {
+			continue
+		}
+
+		if found {
+			if cmp, err := version.Compare(tagVersion, ver); err != nil || cmp <= 0 {
+				continue
+			}
+		}
+		tag, ver, found = t, tagVersion, true
+	}
+
+	return tag, ver, found
+}
+
+// ModulePreReleaseVersion returns the next pre-release of a module version by counting up the existing module tags of the channel, e.g. my-module/v1.2.0-rc.3 results in 1.2.0-rc.4
+func ModulePreReleaseVersion(slug string, ver string, identifier string, tags []vcsapi.VCSRef) string {
+	var moduleTags []vcsapi.VCSRef
+	for _, t := range tags {
+		if tagSlug, tagVersion, ok := ParseModuleTag(t.Value); ok && tagSlug == slug {
+			moduleTags = append(moduleTags, vcsapi.VCSRef{Type: t.Type, Value: tagVersion, Hash: t.Hash})
+		}
+	}
+
+	return PreReleaseVersion(ver, identifier, moduleTags)
+}
+
+// isModuleCommit checks if a commit belongs to the module, either by the conventional commit scope or by the changed files
+func isModuleCommit(commit vcsapi.Commit, module ModuleScope, parser *CommitParser) bool {
+	if match, ok := parser.Parse(commit.Message); ok && match["scope"] != "" {
+		for _, scope := range strings.Split(match["scope"], ",") {
+			scope = strings.TrimSpace(scope)
+			if scope == module.Slug || (module.Name != "" && scope == module.Name) {
+				return true
+			}
+		}
+	}
+
+	for _, change := range commit.Changes {
+		for _, file := range []string{change.FileFrom.Name, change.FileTo.Name} {
+			if file != "" && isModuleFile(file, module) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ModuleCommits filters the commits that belong to the module, either by the conventional commit scope or by the changed files
+func ModuleCommits(commits []vcsapi.Commit, module ModuleScope, commitPatternList []string) []vcsapi.Commit {
+	parser := NewCommitParser(commitPatternList)
+
+	var result []vcsapi.Commit
+	for _, c := range commits {
+		if isModuleCommit(c, module, parser) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// DeterminateNextModuleReleaseVersion calculates the next version of a module based on the commits that belong to the module.
+// If no commit requires a release the previous version is returned.
+func DeterminateNextModuleReleaseVersion(commits []vcsapi.Commit, module ModuleScope, commitPatternList []string, rules []CommitVersionRule, previousVersionStr string) (string, error) {
+	moduleCommits := ModuleCommits(commits, module, commitPatternList)
+	releaseType := DeterminateReleaseType(AnalyzeCommits(moduleCommits, commitPatternList, rules))
+	if releaseType == version.ReleaseNone {
+		return version.Format(previousVersionStr)
+	}
+
+	return version.Bump(previousVersionStr, releaseType)
+}
+
+func isModuleFile(file string, module ModuleScope) bool {
+	file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+	if slices.ContainsFunc(module.Exclude, func(dir string) bool { return isInDirectory(file, dir) }) {
+		return false
+	}
+
+	return isInDirectory(file, module.Directory)
+}
+
+func isInDirectory(file string, dir string) bool {
+	dir = strings.Trim(path.Clean("/"+strings.ReplaceAll(dir, "\\", "/")), "/")
+	if dir == "" {
+		return true
+	}
+
+	return file == dir || strings.HasPrefix(file, dir+"/")
+}
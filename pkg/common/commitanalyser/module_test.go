@@ -0,0 +1,76 @@
+package commitanalyser
+
+import (
+	"testing"
+
+	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseModuleTag(t *testing.T) {
+	slug, ver, ok := ParseModuleTag("go-lib/v1.2.3")
+	assert.True(t, ok)
+	assert.Equal(t, "go-lib", slug)
+	assert.Equal(t, "1.2.3", ver)
+
+	_, _, ok = ParseModuleTag("v1.2.3")
+	assert.False(t, ok)
+	assert.Equal(t, "go-lib/v1.2.3", ModuleTag("go-lib", "v1.2.3"))
+}
+
+func TestLatestModuleTag(t *testing.T) {
+	tags := []vcsapi.VCSRef{
+		{Type: "tag", Value: "go-lib/v1.2.3", Hash: "a"},
+		{Type: "tag", Value: "go-lib/v1.10.0", Hash: "b"},
+		{Type: "tag", Value: "go-lib/v2.0.0-rc.1", Hash: "c"},
+		{Type: "tag", Value: "npm-lib/v3.0.0", Hash: "d"},
+		{Type: "tag", Value: "v4.0.0", Hash: "e"},
+	}
+
+	tag, ver, found := LatestModuleTag(tags, "go-lib")
+	assert.True(t, found)
+	assert.Equal(t, "1.10.0", ver)
+	assert.Equal(t, "b", tag.Hash)
+
+	_, _, found = LatestModuleTag(tags, "other")
+	assert.False(t, found)
+}
+
+func TestDeterminateNextModuleReleaseVersion(t *testing.T) {
+	commits := []vcsapi.Commit{
+		{Message: "feat: add endpoint", Changes: []vcsapi.CommitChange{{FileTo: vcsapi.CommitFile{Name: "libs/go/api.go"}}}},
+		{Message: "fix(npm-lib): resolve issue"},
+		{Message: "fix: update readme", Changes: []vcsapi.CommitChange{{FileTo: vcsapi.CommitFile{Name: "README.md"}}}},
+	}
+	goLib := ModuleScope{Slug: "go-lib", Directory: "libs/go"}
+	npmLib := ModuleScope{Slug: "npm-lib", Name: "@acme/npm-lib", Directory: "libs/npm"}
+	root := ModuleScope{Slug: "root", Exclude: []string{"libs/go", "libs/npm"}}
+	other := ModuleScope{Slug: "other", Directory: "libs/other"}
+
+	next, err := DeterminateNextModuleReleaseVersion(commits, goLib, []string{ConventionalCommitPattern}, DefaultReleaseVersionRules, "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", next)
+
+	next, err = DeterminateNextModuleReleaseVersion(commits, npmLib, []string{ConventionalCommitPattern}, DefaultReleaseVersionRules, "2.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.1", next)
+
+	assert.Len(t, ModuleCommits(commits, root, []string{ConventionalCommitPattern}), 1)
+
+	next, err = DeterminateNextModuleReleaseVersion(commits, other, []string{ConventionalCommitPattern}, DefaultReleaseVersionRules, "v0.3.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3.0", next)
+}
+
+func TestModulePreReleaseVersion(t *testing.T) {
+	tags := []vcsapi.VCSRef{
+		{Type: "tag", Value: "api/v1.2.0-rc.1"},
+		{Type: "tag", Value: "api/v1.2.0-rc.2"},
+		{Type: "tag", Value: "web/v1.2.0-rc.5"},
+		{Type: "tag", Value: "v1.2.0-rc.7"},
+	}
+
+	assert.Equal(t, "1.2.0-rc.3", ModulePreReleaseVersion("api", "1.2.0", "rc", tags))
+	assert.Equal(t, "1.2.0-rc.6", ModulePreReleaseVersion("web", "1.2.0", "rc", tags))
+	assert.Equal(t, "1.2.0-beta.1", ModulePreReleaseVersion("api", "1.2.0", "beta", tags))
+}
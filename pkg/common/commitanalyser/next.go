@@ -0,0 +1,141 @@
+package commitanalyser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cidverse/cidverseutils/version"
+	"github.com/cidverse/go-vcs/vcsapi"
+)
+
+var releaseLineRegex = regexp.MustCompile(`(?:^|/)v?(\d+)\.(\d+)(?:\.x)?$`)
+
+// NextVersionResult holds the next version and the commits that caused it
+type NextVersionResult struct {
+	Version     string              // Version is the next version, equal to Previous if no commit requires a release
+	Previous    string              // Previous is the latest stable release, 0.0.0 if the repository has no releases
+	PreviousTag *vcsapi.VCSRef      // PreviousTag is the tag of the latest stable release
+	Release     version.ReleaseType // Release is the highest release type of all commits
+	Channel     *ReleaseChannel     // Channel is the release channel of the branch, nil for stable releases
+	Commits     []CommitAnalysis    // Commits since the previous release
+}
+
+// LatestReleaseTag returns the tag of the highest stable release, module tags are ignored
+func LatestReleaseTag(tags []vcsapi.VCSRef) (tag vcsapi.VCSRef, ver string, found bool) {
+	for _, t := range tags {
+		if _, _, isModuleTag := ParseModuleTag(t.Value); isModuleTag || !version.IsValidSemver(t.Value) || !version.IsStable(t.Value) {
+			continue
+		}
+
+		tagVersion, err := version.Format(t.Value)
+		if err != nil {
+			continue
+		}
+		if found {
+			if cmp, err := version.Compare(tagVersion, ver); err != nil || cmp <= 0 {
+				continue
+			}
+		}
+		tag, ver, found = t, tagVersion, true
+	}
+
+	return tag, ver, found
+}
+
+// NextVersion determinates the next version based on the commits since the latest stable release, branches of a release channel receive a pre-release version.
+// Branches of a release line channel (e.g. release/1.4) receive the next patch release of that line instead.
+func NextVersion(client vcsapi.Client, branch string, commitPatternList []string, rules []CommitVersionRule, channels []ReleaseChannel) (NextVersionResult, error) {
+	result := NextVersionResult{Previous: "0.0.0"}
+
+	tags := client.GetTags()
+	channel := FindReleaseChannel(channels, branch)
+	line, isReleaseLine := "", false
+	if channel != nil && channel.ReleaseLine {
+		line, isReleaseLine = ParseReleaseLine(branch)
+	}
+
+	// a release line continues from its latest release, the first release of a line is based on the latest release of trunk
+	releaseTags := tags
+	if lineTags := releaseLineTags(tags, line); isReleaseLine && len(lineTags) > 0 {
+		releaseTags = lineTags
+	}
+	if tag, ver, found := LatestReleaseTag(releaseTags); found {
+		result.Previous = ver
+		result.PreviousTag = &tag
+	}
+
+	commits, err := client.FindCommitsBetween(nil, result.PreviousTag, false, 0)
+	if err != nil {
+		return result, fmt.Errorf("failed to query commits since %s: %w", result.Previous, err)
+	}
+	result.Commits = AnalyzeCommits(commits, commitPatternList, rules)
+	result.Release = DeterminateReleaseType(result.Commits)
+
+	// a new release line starts with its first release, even without new commits
+	if isReleaseLine && (result.Release != version.ReleaseNone || !isReleaseLineVersion(line, result.Previous)) {
+		result.Channel = channel
+		result.Version, err = ReleaseLineVersion(line, result.Previous)
+		return result, err
+	}
+
+	if result.Release == version.ReleaseNone {
+		result.Version = result.Previous
+		return result, nil
+	}
+
+	result.Channel = channel
+
+	result.Version, err = version.Bump(result.Previous, result.Release)
+	if err != nil {
+		return result, fmt.Errorf("failed to bump version %s: %w", result.Previous, err)
+	}
+
+	if result.Channel != nil {
+		result.Version = PreReleaseVersion(result.Version, result.Channel.PreRelease, tags)
+	}
+
+	return result, nil
+}
+
+// ParseReleaseLine returns the release line of a release branch, e.g. 1.4 for release/1.4, release/v1.4 or release/1.4.x
+func ParseReleaseLine(branch string) (string, bool) {
+	match := releaseLineRegex.FindStringSubmatch(branch)
+	if match == nil {
+		return "", false
+	}
+
+	return match[1] + "." + match[2], true
+}
+
+// ReleaseLineVersion returns the next patch release of a release line, or the first release of the line if the previous version belongs to another line
+func ReleaseLineVersion(line string, previous string) (string, error) {
+	if !isReleaseLineVersion(line, previous) {
+		return line + ".0", nil
+	}
+
+	next, err := version.Bump(previous, version.ReleasePatch)
+	if err != nil {
+		return "", fmt.Errorf("failed to bump version %s: %w", previous, err)
+	}
+	return next, nil
+}
+
+func releaseLineTags(tags []vcsapi.VCSRef, line string) []vcsapi.VCSRef {
+	if line == "" {
+		return nil
+	}
+
+	var result []vcsapi.VCSRef
+	for _, t := range tags {
+		if isReleaseLineVersion(line, t.Value) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// isReleaseLineVersion returns true if the version belongs to the release line, e.g. 1.4.2 to 1.4
+func isReleaseLineVersion(line string, ver string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(ver, "v"), line+".")
+}
@@ -0,0 +1,46 @@
+package commitanalyser
+
+import (
+	"testing"
+
+	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient returns fixed tags and commits, the commits are returned for every range
+type fakeClient struct {
+	vcsapi.Client
+	tags    []vcsapi.VCSRef
+	commits []vcsapi.Commit
+}
+
+func (c fakeClient) GetTags() []vcsapi.VCSRef {
+	return c.tags
+}
+
+func (c fakeClient) FindCommitsBetween(_ *vcsapi.VCSRef, _ *vcsapi.VCSRef, _ bool, _ int) ([]vcsapi.Commit, error) {
+	return c.commits, nil
+}
+
+func TestNextVersionReleaseLine(t *testing.T) {
+	channels := []ReleaseChannel{{Branch: "release/*", ReleaseLine: true}}
+	patterns := CommitPatterns(ConventionConventionalCommits, "")
+	tags := []vcsapi.VCSRef{{Type: "tag", Value: "v1.5.0"}, {Type: "tag", Value: "v1.4.2"}}
+
+	// a new release line without commits starts with its first release instead of the latest release of trunk
+	result, err := NextVersion(fakeClient{tags: tags}, "release/1.6", patterns, DefaultReleaseVersionRules, channels)
+	require.NoError(t, err)
+	assert.Equal(t, "1.6.0", result.Version)
+	assert.Equal(t, "1.5.0", result.Previous)
+
+	// an existing release line without commits stays on its latest release
+	result, err = NextVersion(fakeClient{tags: tags}, "release/1.4", patterns, DefaultReleaseVersionRules, channels)
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.2", result.Version)
+
+	// commits on an existing release line create a patch release of the line
+	result, err = NextVersion(fakeClient{tags: tags, commits: []vcsapi.Commit{{Message: "feat: add login"}}}, "release/1.4", patterns, DefaultReleaseVersionRules, channels)
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.3", result.Version)
+}
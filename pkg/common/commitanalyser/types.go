@@ -3,13 +3,24 @@ package commitanalyser
 // ConventionalCommitPattern is a regex pattern of the Conventional Commits spec - https://www.conventionalcommits.org/en/v1.0.0/
 var ConventionalCommitPattern = `(?P<type>[A-Za-z]+)((?:\((?P<scope>[^()\r\n]*)\)|\()?(?P<breaking>!)?)(:\s?(?P<subject>.*))?`
 
+// CommitVersionRule maps commits to a release type, the first matching rule of a commit wins.
+// Type, Scope and Footer support wildcards (e.g. `*`, `api-*`), an empty value matches everything.
 type CommitVersionRule struct {
-	Type    string
-	Scope   string
-	Release string // major / minor / patch
+	Type    string `yaml:"type,omitempty"`
+	Scope   string `yaml:"scope,omitempty"`
+	Footer  string `yaml:"footer,omitempty"` // Footer requires a footer token in the commit description, e.g. `BREAKING CHANGE`
+	Release string `yaml:"release"`          // major / minor / patch / none
 }
 
 var DefaultReleaseVersionRules = []CommitVersionRule{
+	{
+		Footer:  `BREAKING CHANGE`,
+		Release: `major`,
+	},
+	{
+		Footer:  `BREAKING-CHANGE`,
+		Release: `major`,
+	},
 	{
 		Type:    `feat`,
 		Release: `minor`,
@@ -51,3 +62,29 @@ var DefaultReleaseVersionRules = []CommitVersionRule{
 		Release: `patch`,
 	},
 }
+
+// ReleaseChannel publishes pre-releases for matching branches, e.g. `-rc.1` for `release/*`
+type ReleaseChannel struct {
+	Branch      string `yaml:"branch"`                 // Branch name, supports wildcards
+	PreRelease  string `yaml:"prerelease"`             // PreRelease identifier, e.g. rc or beta
+	ReleaseLine bool   `yaml:"release-line,omitempty"` // ReleaseLine branches are named after a release line (e.g. release/1.4) and publish patch releases of that line
+}
+
+var DefaultReleaseChannels = []ReleaseChannel{
+	{
+		Branch:     `release/*`,
+		PreRelease: `rc`,
+	},
+	{
+		Branch:     `develop`,
+		PreRelease: `beta`,
+	},
+}
+
+// TrunkBasedReleaseChannels are used for trunk-based development, release branches are cut from trunk and only receive patch releases
+var TrunkBasedReleaseChannels = []ReleaseChannel{
+	{
+		Branch:      `release/*`,
+		ReleaseLine: true,
+	},
+}
@@ -40,9 +40,12 @@ func RunWorkflowAction(cfg *config.CIDConfig, action *catalog.WorkflowAction, en
 	configAsJSON, _ := json.Marshal(&action.Config)
 	ctx.Config = string(configAsJSON)
 
+	// test results of previous actions
+	testResult := rules.TestResultFromState(state.GetStateFromDirectory(ctx.Paths.Artifact))
+
 	// project-scoped actions
 	if catalogAction.Metadata.Scope == actionsdk.ActionScopeProject {
-		ruleContext := rules.GetProjectRuleContext(ctx.Env, ctx.Modules)
+		ruleContext := rules.AddTestResultContext(rules.GetProjectRuleContext(ctx.Env, ctx.Modules), testResult)
 		ruleMatch := rules.AnyRuleMatches(append(action.Rules, catalogAction.Metadata.Rules...), ruleContext)
 		log.Debug().Str("Trace", action.ID).Bool("rules_match", ruleMatch).Msg("check action rules")
 		if ruleMatch {
@@ -66,7 +69,7 @@ func RunWorkflowAction(cfg *config.CIDConfig, action *catalog.WorkflowAction, en
 				continue
 			}
 
-			var ruleContext = rules.GetModuleRuleContext(ctx.Env, &moduleRef)
+			var ruleContext = rules.AddTestResultContext(rules.GetModuleRuleContext(ctx.Env, &moduleRef), testResult)
 			ruleMatch := rules.AnyRuleMatches(append(action.Rules, catalogAction.Metadata.Rules...), ruleContext)
 			log.Trace().Str("action", action.ID).Str("module", moduleRef.Name).Bool("rules_match", ruleMatch).Msg("check action rules")
 			if ruleMatch {
@@ -33,7 +33,10 @@ var (
 
 func NewAppContextFromDir(projectDir string, workDir string) (*CIDContext, error) {
 	slog.With("dir", projectDir).Debug("initializing cid context")
-	cfg := config.LoadConfig(projectDir)
+	cfg, err := config.LoadConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
 
 	// env
 	env, err := api.GetCIDEnvironment(cfg.Env, projectDir)
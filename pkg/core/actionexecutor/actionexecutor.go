@@ -5,6 +5,8 @@ import (
 	"github.com/cidverse/cid/pkg/core/actionexecutor/builtin"
 	"github.com/cidverse/cid/pkg/core/actionexecutor/containeraction"
 	"github.com/cidverse/cid/pkg/core/actionexecutor/githubaction"
+	"github.com/cidverse/cid/pkg/core/actionexecutor/scriptaction"
+	"github.com/cidverse/cid/pkg/core/actionexecutor/wasmaction"
 )
 
 func GetExecutors() []api.ActionExecutor {
@@ -12,6 +14,8 @@ func GetExecutors() []api.ActionExecutor {
 	executors = append(executors, builtin.Executor{})
 	executors = append(executors, containeraction.Executor{})
 	executors = append(executors, githubaction.Executor{})
+	executors = append(executors, scriptaction.Executor{})
+	executors = append(executors, wasmaction.Executor{})
 	return executors
 }
 
@@ -8,10 +8,12 @@ import (
 
 func TestGetExecutors(t *testing.T) {
 	executors := GetExecutors()
-	assert.Equal(t, 3, len(executors))
+	assert.Equal(t, 5, len(executors))
 	assert.Equal(t, "builtin", executors[0].GetType())
 	assert.Equal(t, "container", executors[1].GetType())
 	assert.Equal(t, "githubaction", executors[2].GetType())
+	assert.Equal(t, "script", executors[3].GetType())
+	assert.Equal(t, "wasm", executors[4].GetType())
 }
 
 func TestFindExecutorByType(t *testing.T) {
@@ -21,6 +23,12 @@ func TestFindExecutorByType(t *testing.T) {
 	executor = FindExecutorByType("githubaction")
 	assert.Equal(t, "githubaction", executor.GetType())
 
+	executor = FindExecutorByType("script")
+	assert.Equal(t, "script", executor.GetType())
+
+	executor = FindExecutorByType("wasm")
+	assert.Equal(t, "wasm", executor.GetType())
+
 	executor = FindExecutorByType("invalid_type")
 	assert.Nil(t, executor)
 }
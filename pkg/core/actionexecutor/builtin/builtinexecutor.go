@@ -33,10 +33,34 @@ func (e Executor) GetType() string {
 }
 
 func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *state.ActionStateContext, catalogAction *catalog.Action, step plangenerate.Step) error {
+	sdkClient, cleanup, err := NewActionSDK(ctx, localState, catalogAction, step)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// lookup in action by name map - TODO: make function in actions for lookup
+	actionLookup := builtinaction.GetActions(sdkClient)
+	action, ok := actionLookup[catalogAction.Metadata.Name]
+	if !ok {
+		return fmt.Errorf("action %s not found", catalogAction.Metadata.Name)
+	}
+
+	// run action
+	err = action.Execute()
+	if err != nil {
+		return fmt.Errorf("failed to execute action: %w", err)
+	}
+
+	return nil
+}
+
+// NewActionSDK creates the action directories and the sdk client for an action running in-process, the returned function removes the temporary directory
+func NewActionSDK(ctx *commonapi.ActionExecutionContext, localState *state.ActionStateContext, catalogAction *catalog.Action, step plangenerate.Step) (ActionSDK, func(), error) {
 	// temp dir
 	tempBaseDir, err := util.CITempDir(ctx.NCI.ServiceSlug)
 	if err != nil {
-		return err
+		return ActionSDK{}, nil, err
 	}
 
 	// properties
@@ -45,24 +69,26 @@ func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *sta
 	artifactDir := filepath.Join(ctx.ProjectDir, ".dist")
 	tempDir, err := os.MkdirTemp(tempBaseDir, "cid-job-")
 	if err != nil {
-		return fmt.Errorf("failed to create temporary directory: %w", err)
+		return ActionSDK{}, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() {
+		log.Debug().Str("dir", tempDir).Msg("cleaning up temp dir")
+		_ = os.RemoveAll(tempDir)
 	}
 
 	// create dirs
 	log.Debug().Str("temp-dir", tempDir).Str("artifact-dir", artifactDir).Msg("creating action directories")
 	err = os.MkdirAll(artifactDir, 0770)
 	if err != nil {
-		return fmt.Errorf("failed to create artifact directory: %w", err)
+		cleanup()
+		return ActionSDK{}, nil, fmt.Errorf("failed to create artifact directory: %w", err)
 	}
 	err = os.MkdirAll(tempDir, 0770)
 	if err != nil {
-		return fmt.Errorf("failed to create artifact directory: %w", err)
+		cleanup()
+		return ActionSDK{}, nil, fmt.Errorf("failed to create artifact directory: %w", err)
 	}
 	_ = os.Chmod(tempDir, 0770) // MkdirTemp creates the dir chmod 0700, which is not accessible for other users in the group
-	defer func() {
-		log.Debug().Str("dir", tempDir).Msg("cleaning up temp dir")
-		_ = os.RemoveAll(tempDir)
-	}()
 
 	// executables
 	executableCandidates, err := command.CandidatesFromConfig(config.Current)
@@ -77,7 +103,7 @@ func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *sta
 	}
 
 	// sdk client
-	sdkClient := ActionSDK{
+	return ActionSDK{
 		BuildID:              buildID,
 		JobID:                jobID,
 		ProjectDir:           ctx.ProjectDir,
@@ -93,20 +119,6 @@ func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *sta
 		TempDir:              tempDir,
 		ArtifactDir:          artifactDir,
 		ExecutableCandidates: executableCandidates,
-	}
-
-	// lookup in action by name map - TODO: make function in actions for lookup
-	actionLookup := builtinaction.GetActions(sdkClient)
-	action, ok := actionLookup[catalogAction.Metadata.Name]
-	if !ok {
-		return fmt.Errorf("action %s not found", catalogAction.Metadata.Name)
-	}
-
-	// run action
-	err = action.Execute()
-	if err != nil {
-		return fmt.Errorf("failed to execute action: %w", err)
-	}
-
-	return nil
+		TraceContext:         ctx.TraceContext,
+	}, cleanup, nil
 }
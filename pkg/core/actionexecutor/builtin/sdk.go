@@ -1,6 +1,8 @@
 package builtin
 
 import (
+	"context"
+
 	"github.com/cidverse/cid/internal/state"
 	"github.com/cidverse/cid/pkg/common/executable"
 	"github.com/cidverse/cid/pkg/core/catalog"
@@ -25,4 +27,5 @@ type ActionSDK struct {
 	TempDir              string
 	ArtifactDir          string
 	ExecutableCandidates []executable.Executable
+	TraceContext         context.Context
 }
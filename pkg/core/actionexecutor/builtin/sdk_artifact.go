@@ -13,17 +13,20 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/cidverse/cid/internal/state"
 	"github.com/cidverse/cid/pkg/core/actionsdk"
 	"github.com/cidverse/cid/pkg/core/provenance"
+	"github.com/cidverse/cid/pkg/core/rules"
 	"github.com/cidverse/cid/pkg/lib/formats/cobertura"
+	"github.com/cidverse/cid/pkg/lib/formats/gitlabreport"
 	"github.com/cidverse/cid/pkg/lib/formats/jacoco"
 	"github.com/cidverse/cid/pkg/lib/githublib"
 	"github.com/cidverse/cid/pkg/util"
 	"github.com/cidverse/cidverseutils/compress"
-	"github.com/cidverse/go-rules/pkg/expr"
 	v1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
 	"github.com/rs/zerolog/log"
 )
 
@@ -35,7 +38,7 @@ func (sdk ActionSDK) ArtifactListV1(request actionsdk.ArtifactListRequest) ([]*a
 	// filter artifacts
 	var result = make([]*actionsdk.Artifact, 0)
 	for _, artifact := range sdk.State.Artifacts {
-		add, err := expr.EvalBooleanExpression(expression, map[string]interface{}{
+		add, err := rules.EvalExpression(expression, map[string]interface{}{
 			"id":             artifact.ArtifactID,
 			"module":         artifact.Module,
 			"artifact_type":  artifact.Type,
@@ -331,42 +334,60 @@ func postProcessArtifact(sdk *ActionSDK, targetFile string, fileType string, for
 			fmt.Printf("Test-Coverage:%.2f%%\n", coverage) // some platforms parse the test-coverage from stdout (e.g. GitLab)
 		}
 
-	case fileType == "report" && format == "sarif" && formatVersion == "2.1.0" && sdk.NCI.Repository.HostType == "gitlab": // TD-001: automatic conversion of SARIF to GitLab Code Quality due to missing SARIF support in GitLab
-		// temp file
-		codeQualityFile, err := os.CreateTemp(os.TempDir(), "gl-code-quality-report-*.json")
+	case fileType == "report" && format == "sarif" && formatVersion == "2.1.0" && sdk.NCI.Repository.HostType == "gitlab": // TD-001: automatic conversion of SARIF to GitLab Code Quality and SAST due to missing SARIF support in GitLab
+		content, err := os.ReadFile(targetFile)
 		if err != nil {
-			return fmt.Errorf("failed to create temporary file: %w", err)
+			return fmt.Errorf("failed to read sarif report: %w", err)
 		}
-		defer codeQualityFile.Close() // Ensure the temporary file is closed when done
-
-		// code-quality report
-		cmdResult, err := sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
-			WorkDir: sdk.ProjectDir,
-			Command: fmt.Sprintf("gitlab-sarif-converter --type=codequality %q %q", targetFile, codeQualityFile.Name()),
-		})
+		report, err := sarif.FromBytes(content)
 		if err != nil {
-			return fmt.Errorf("failed to execute gitlab-sarif-converter: %w", err)
-		} else if cmdResult.Code != 0 {
-			return fmt.Errorf("gitlab-sarif-converter failed, exit code %d: %s", cmdResult.Code, cmdResult.Error)
+			return fmt.Errorf("failed to parse sarif report: %w", err)
 		}
 
-		// upload
 		moduleSlug := ""
 		if sdk.CurrentModule != nil {
 			moduleSlug = sdk.CurrentModule.Slug
 		}
+		baseName := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(targetFile), filepath.Ext(targetFile)), ".sarif")
 
+		// code-quality report
+		codeQuality, err := json.Marshal(gitlabreport.SarifToCodeQuality(report))
+		if err != nil {
+			return fmt.Errorf("failed to marshal code quality report: %w", err)
+		}
+		codeQualityFile := filepath.Join(sdk.TempDir, fmt.Sprintf("%s-gl-code-quality-report.json", baseName))
+		if err = os.WriteFile(codeQualityFile, codeQuality, 0644); err != nil {
+			return fmt.Errorf("failed to write code quality report: %w", err)
+		}
 		_, _, err = sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
-			File:          codeQualityFile.Name(),
-			Content:       "",
-			ContentBytes:  nil,
+			File:   codeQualityFile,
+			Module: moduleSlug,
+			Type:   "report",
+			Format: "gl-codequality",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store converted code quality report: %w", err)
+		}
+
+		// sast report
+		now := time.Now()
+		sast, err := json.Marshal(gitlabreport.SarifToSAST(report, now, now))
+		if err != nil {
+			return fmt.Errorf("failed to marshal sast report: %w", err)
+		}
+		sastFile := filepath.Join(sdk.TempDir, fmt.Sprintf("%s-gl-sast-report.json", baseName))
+		if err = os.WriteFile(sastFile, sast, 0644); err != nil {
+			return fmt.Errorf("failed to write sast report: %w", err)
+		}
+		_, _, err = sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+			File:          sastFile,
 			Module:        moduleSlug,
 			Type:          "report",
-			Format:        "gl-codequality",
-			FormatVersion: "",
+			Format:        "gl-sast",
+			FormatVersion: gitlabreport.SASTSchemaVersion,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to store converted code quality report: %w", err)
+			return fmt.Errorf("failed to store converted sast report: %w", err)
 		}
 
 	case fileType == "report" && format == "sarif" && formatVersion == "2.1.0" && sdk.NCI.Repository.HostType == "github":
@@ -71,6 +71,7 @@ func (sdk ActionSDK) ExecuteCommandV1(req actionsdk.ExecuteCommandV1Request) (*a
 		UserProvidedConstraint: req.Constraint,
 		Constraints:            constraints,
 		Stdin:                  nil,
+		TraceContext:           sdk.TraceContext,
 	})
 	var exitErr *exec.ExitError
 	isExitError := errors.As(cmdErr, &exitErr)
@@ -48,7 +48,7 @@ func (sdk ActionSDK) ProjectExecutionContextV1() (*actionsdk.ProjectExecutionCon
 	response := actionsdk.ProjectExecutionContextV1Response{
 		ProjectDir: sdk.ProjectDir,
 		Config:     cfg,
-		Env:        sdk.ActionEnv,
+		Env:        sdk.nextVersionEnv(sdk.ActionEnv),
 		Modules:    modules,
 	}
 
@@ -64,7 +64,7 @@ func (sdk ActionSDK) ModuleExecutionContextV1() (*actionsdk.ModuleExecutionConte
 	response := actionsdk.ModuleExecutionContextV1Response{
 		ProjectDir: sdk.ProjectDir,
 		Config:     cfg,
-		Env:        sdk.ActionEnv,
+		Env:        sdk.moduleVersionEnv(sdk.nextVersionEnv(sdk.ActionEnv)),
 		Module:     convertProjectModule(sdk.CurrentModule),
 		Deployment: nil,
 	}
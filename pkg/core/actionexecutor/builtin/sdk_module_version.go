@@ -0,0 +1,121 @@
+package builtin
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
+	"github.com/cidverse/cid/pkg/core/config"
+	"github.com/cidverse/go-vcs"
+	"github.com/cidverse/go-vcs/vcsapi"
+	"github.com/cidverse/repoanalyzer/analyzerapi"
+)
+
+// moduleVersionEnv returns a copy of the env, enriched with the independent version of the current module if the repository uses module tags (<module-slug>/v1.2.3).
+// The next version honors the configured release rules and pre-release channels.
+// The version is determined on a best-effort basis, the env is returned unchanged if it can not be determined (e.g. outside a git repository).
+// The version is determined once per repository state, see versionCache.
+func (sdk ActionSDK) moduleVersionEnv(env map[string]string) map[string]string {
+	module := sdk.CurrentModule
+	if module == nil {
+		return env
+	}
+
+	client, err := vcs.GetVCSClient(sdk.ProjectDir)
+	if err != nil {
+		slog.With("err", err).Debug("skipping module version, failed to open vcs repository")
+		return env
+	}
+	tags := client.GetTags()
+
+	values := versionCache.get(versionCacheKey(sdk.ProjectDir, client, tags, env, "module", module.Slug), func() map[string]string {
+		return sdk.moduleVersion(client, tags, module, env)
+	})
+	return withEnvValues(env, values)
+}
+
+// moduleVersion determines the version env values of the module, nil is returned if the module has no independent version
+func (sdk ActionSDK) moduleVersion(client vcsapi.Client, tags []vcsapi.VCSRef, module *analyzerapi.ProjectModule, env map[string]string) map[string]string {
+	// only monorepos with module tags use per-module versioning
+	currentTagSlug, currentTagVersion, currentIsModuleTag := "", "", false
+	if env["NCI_COMMIT_REF_TYPE"] == "tag" {
+		currentTagSlug, currentTagVersion, currentIsModuleTag = commitanalyser.ParseModuleTag(env["NCI_COMMIT_REF_NAME"])
+	}
+	latestTag, latestVersion, found := commitanalyser.LatestModuleTag(tags, module.Slug)
+	if !found && !currentIsModuleTag {
+		return nil
+	}
+
+	// commits since the latest module release, or the full history for unreleased modules
+	var sinceRef *vcsapi.VCSRef
+	if found {
+		sinceRef = &latestTag
+	} else {
+		latestVersion = "0.0.0"
+	}
+	commits, err := client.FindCommitsBetween(nil, sinceRef, true, 0)
+	if err != nil {
+		slog.With("err", err).With("module", module.Slug).Warn("skipping module version, failed to query commits of module")
+		return nil
+	}
+
+	nextVersion, err := commitanalyser.DeterminateNextModuleReleaseVersion(commits, sdk.moduleScope(module), config.Current.Conventions.CommitPatterns(), config.Current.Versioning.ReleaseRules(), latestVersion)
+	if err != nil {
+		slog.With("err", err).With("module", module.Slug).Warn("skipping module version, failed to determinate next version of module")
+		return nil
+	}
+
+	// branches of a pre-release channel build pre-releases of the module
+	if env["NCI_COMMIT_REF_TYPE"] == "branch" && nextVersion != latestVersion {
+		channel := commitanalyser.FindReleaseChannel(config.Current.Versioning.ReleaseChannels(config.Current.Conventions.Branching), env["NCI_COMMIT_REF_NAME"])
+		if channel != nil && channel.PreRelease != "" {
+			nextVersion = commitanalyser.ModulePreReleaseVersion(module.Slug, nextVersion, channel.PreRelease, tags)
+		}
+	}
+
+	// a module tag only releases the module it belongs to, all other modules are built as snapshots
+	result := map[string]string{
+		"NCI_MODULE_VERSION":      latestVersion,
+		"NCI_MODULE_NEXT_VERSION": nextVersion,
+		"NCI_MODULE_RELEASE":      "false",
+	}
+	if currentIsModuleTag && currentTagSlug == module.Slug {
+		result["NCI_MODULE_VERSION"] = currentTagVersion
+		result["NCI_MODULE_RELEASE"] = "true"
+	}
+
+	return result
+}
+
+// moduleScope returns the scope of a module, nested modules are excluded from the module directory
+func (sdk ActionSDK) moduleScope(module *analyzerapi.ProjectModule) commitanalyser.ModuleScope {
+	scope := commitanalyser.ModuleScope{
+		Slug:      module.Slug,
+		Name:      module.Name,
+		Directory: sdk.relativeDirectory(module.Directory),
+	}
+
+	for _, m := range sdk.Modules {
+		if m == nil || m.Slug == module.Slug {
+			continue
+		}
+
+		dir := sdk.relativeDirectory(m.Directory)
+		if dir != scope.Directory && (scope.Directory == "" || strings.HasPrefix(dir, scope.Directory+"/")) {
+			scope.Exclude = append(scope.Exclude, dir)
+		}
+	}
+
+	return scope
+}
+
+// relativeDirectory returns the directory relative to the project root, using forward slashes
+func (sdk ActionSDK) relativeDirectory(dir string) string {
+	rel, err := filepath.Rel(sdk.ProjectDir, dir)
+	if err != nil || rel == "." {
+		return ""
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,50 @@
+package builtin
+
+import (
+	"log/slog"
+
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
+	"github.com/cidverse/cid/pkg/core/config"
+	"github.com/cidverse/go-vcs"
+	"github.com/cidverse/go-vcs/vcsapi"
+)
+
+// nextVersionEnv returns a copy of the env, enriched with the next release version of the project on branch builds.
+// The version honors the configured release rules and the release channel of the branch (e.g. 1.3.0-rc.1 on release branches), see `cid version next`.
+// The version is determined on a best-effort basis, the env is returned unchanged if it can not be determined (e.g. outside a git repository).
+// The version is determined once per repository state, see versionCache.
+func (sdk ActionSDK) nextVersionEnv(env map[string]string) map[string]string {
+	if env["NCI_COMMIT_REF_TYPE"] != "branch" {
+		return env
+	}
+
+	client, err := vcs.GetVCSClient(sdk.ProjectDir)
+	if err != nil {
+		slog.With("err", err).Debug("skipping next version, failed to open vcs repository")
+		return env
+	}
+
+	values := versionCache.get(versionCacheKey(sdk.ProjectDir, client, client.GetTags(), env, "next"), func() map[string]string {
+		return nextVersion(client, env["NCI_COMMIT_REF_NAME"])
+	})
+	return withEnvValues(env, values)
+}
+
+// nextVersion determines the next version env values of the project on the branch, nil is returned if the version can not be determined
+func nextVersion(client vcsapi.Client, branch string) map[string]string {
+	next, err := commitanalyser.NextVersion(client, branch, config.Current.Conventions.CommitPatterns(), config.Current.Versioning.ReleaseRules(), config.Current.Versioning.ReleaseChannels(config.Current.Conventions.Branching))
+	if err != nil {
+		slog.With("err", err).Warn("skipping next version, failed to determinate next version")
+		return nil
+	}
+
+	result := map[string]string{
+		"NCI_NEXT_VERSION":         next.Version,
+		"NCI_NEXT_VERSION_CHANNEL": "",
+	}
+	if next.Channel != nil {
+		result["NCI_NEXT_VERSION_CHANNEL"] = next.Channel.PreRelease
+	}
+
+	return result
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/lib/gitdiff"
 	"github.com/cidverse/cid/pkg/util"
 	"github.com/cidverse/go-vcs"
 	"github.com/cidverse/go-vcs/vcsapi"
@@ -126,12 +127,46 @@ func (sdk ActionSDK) VCSDiffV1(request actionsdk.VCSDiffRequest) ([]actionsdk.VC
 		return nil, fmt.Errorf("failed to open vcs repository: %w", err)
 	}
 
+	if request.MergeBase {
+		baseHash, baseErr := gitdiff.MergeBase(sdk.ProjectDir, vcsRefToRevision(fromRef), vcsRefToRevision(toRef))
+		if baseErr != nil {
+			return nil, fmt.Errorf("failed to find merge base: %w", baseErr)
+		}
+		fromRef = &vcsapi.VCSRef{Type: "hash", Hash: baseHash}
+	}
+
 	diff, err := client.Diff(fromRef, toRef)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate diff: %w", err)
 	}
+	hunks, err := gitdiff.Hunks(sdk.ProjectDir, vcsRefToRevision(fromRef), vcsRefToRevision(toRef))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate diff hunks: %w", err)
+	}
+
+	result := convertToVCSDiff(diff)
+	for i, d := range result {
+		path := d.FileTo.Name
+		if path == "" {
+			path = d.FileFrom.Name
+		}
+		for _, h := range hunks[path] {
+			result[i].Hunks = append(result[i].Hunks, actionsdk.VCSDiffHunk{FromLine: h.FromLine, FromLines: h.FromLines, ToLine: h.ToLine, ToLines: h.ToLines})
+		}
+	}
+
+	return result, nil
+}
+
+// vcsRefToRevision converts the reference into a git revision, an empty reference is the current commit
+func vcsRefToRevision(ref *vcsapi.VCSRef) string {
+	if ref == nil {
+		return "HEAD"
+	} else if ref.Type == "hash" {
+		return ref.Hash
+	}
 
-	return convertToVCSDiff(diff), nil
+	return ref.Value
 }
 
 func convertToVCSCommits(commits []vcsapi.Commit) []*actionsdk.VCSCommit {
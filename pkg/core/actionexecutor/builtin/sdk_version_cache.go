@@ -0,0 +1,55 @@
+package builtin
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cidverse/go-vcs/vcsapi"
+)
+
+// versionCache memoizes the versions of the project and its modules, determining them walks the commit history which is too expensive for every sdk call
+var versionCache = &versionEnvCache{entries: make(map[string]map[string]string)}
+
+// versionEnvCache holds the env values of a version per repository state, new commits or tags invalidate the entries
+type versionEnvCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string]string
+}
+
+// get returns the cached values of the key, compute is only called once per key - results without values are cached as well
+func (c *versionEnvCache) get(key string, compute func() map[string]string) map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if values, ok := c.entries[key]; ok {
+		return values
+	}
+	values := compute()
+	c.entries[key] = values
+
+	return values
+}
+
+// versionCacheKey identifies the repository state (head commit and tags) and the ref that is built
+func versionCacheKey(projectDir string, client vcsapi.Client, tags []vcsapi.VCSRef, env map[string]string, parts ...string) string {
+	head, err := client.VCSHead()
+	if err != nil {
+		head = vcsapi.VCSRef{}
+	}
+
+	return strings.Join(append([]string{projectDir, head.Hash, strconv.Itoa(len(tags)), env["NCI_COMMIT_REF_TYPE"], env["NCI_COMMIT_REF_NAME"]}, parts...), "|")
+}
+
+// withEnvValues returns a copy of the env with the values added, the env is returned unchanged if there are no values
+func withEnvValues(env map[string]string, values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return env
+	}
+
+	result := maps.Clone(env)
+	maps.Copy(result, values)
+
+	return result
+}
@@ -0,0 +1,34 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionEnvCache(t *testing.T) {
+	cache := &versionEnvCache{entries: make(map[string]map[string]string)}
+	calls := 0
+	compute := func() map[string]string {
+		calls++
+		return map[string]string{"NCI_MODULE_VERSION": "1.2.0"}
+	}
+
+	assert.Equal(t, map[string]string{"NCI_MODULE_VERSION": "1.2.0"}, cache.get("a", compute))
+	assert.Equal(t, map[string]string{"NCI_MODULE_VERSION": "1.2.0"}, cache.get("a", compute))
+	assert.Equal(t, 1, calls)
+
+	// results without values are cached as well
+	assert.Nil(t, cache.get("b", func() map[string]string { calls++; return nil }))
+	assert.Nil(t, cache.get("b", func() map[string]string { calls++; return nil }))
+	assert.Equal(t, 2, calls)
+}
+
+func TestWithEnvValues(t *testing.T) {
+	env := map[string]string{"NCI_COMMIT_REF_TYPE": "branch"}
+
+	assert.Equal(t, env, withEnvValues(env, nil))
+	result := withEnvValues(env, map[string]string{"NCI_NEXT_VERSION": "1.3.0"})
+	assert.Equal(t, map[string]string{"NCI_COMMIT_REF_TYPE": "branch", "NCI_NEXT_VERSION": "1.3.0"}, result)
+	assert.NotContains(t, env, "NCI_NEXT_VERSION")
+}
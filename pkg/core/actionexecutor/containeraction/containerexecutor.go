@@ -1,6 +1,7 @@
 package containeraction
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,6 +27,7 @@ import (
 	"github.com/cidverse/cid/pkg/core/config"
 	"github.com/cidverse/cid/pkg/core/plangenerate"
 	"github.com/cidverse/cid/pkg/core/restapi"
+	"github.com/cidverse/cid/pkg/lib/telemetry"
 	"github.com/cidverse/cid/pkg/util"
 	"github.com/cidverse/cidverseutils/ci"
 	"github.com/cidverse/cidverseutils/containerruntime"
@@ -35,6 +37,7 @@ import (
 	"github.com/cidverse/cidverseutils/redact"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Executor struct{}
@@ -124,6 +127,7 @@ func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *sta
 			TempDir:              tempDir,
 			ArtifactDir:          artifactDir,
 			ExecutableCandidates: executableCandidates,
+			TraceContext:         ctx.TraceContext,
 		},
 	})
 	restapi.SecureWithAPIKey(apiEngine, secret)
@@ -214,10 +218,12 @@ func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *sta
 		}
 	}
 
-	containerCmd, err := containerExec.GetRunCommand(containerExec.DetectRuntime())
+	containerRuntime := containerExec.DetectRuntime()
+	containerCmd, err := containerExec.GetRunCommand(containerRuntime)
 	if err != nil {
 		return err
 	}
+	pullImage(ctx.TraceContext, &containerExec, containerRuntime)
 
 	cmd, err := shellcommand.PrepareCommand(containerCmd, runtime.GOOS, "", true, nil, "", nil, redact.NewProtectedWriter(os.Stdout, nil, &sync.Mutex{}, nil), redact.NewProtectedWriter(os.Stderr, nil, &sync.Mutex{}, nil))
 	if err != nil {
@@ -239,3 +245,19 @@ func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *sta
 
 	return nil
 }
+
+// pullImage pulls the image ahead of the run if it is not present locally, to record the pull time separately from the action
+func pullImage(traceCtx context.Context, containerExec *containerruntime.Container, containerRuntime string) {
+	if exec.Command(containerRuntime, "image", "inspect", containerExec.Image).Run() == nil {
+		return
+	}
+
+	start := time.Now()
+	err := containerExec.PullImage()
+	telemetry.RecordSpan(traceCtx, "pull "+containerExec.Image, start, err, attribute.String("cid.container.image", containerExec.Image), attribute.String("cid.container.runtime", containerRuntime))
+	telemetry.ContainerPull.WithLabelValues(containerExec.Image, telemetry.Result(err)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		// the run command pulls the image as fallback
+		slog.With("image", containerExec.Image).With("err", err).Warn("failed to pull image")
+	}
+}
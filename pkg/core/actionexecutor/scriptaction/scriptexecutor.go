@@ -0,0 +1,138 @@
+package scriptaction
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cidverse/cid/internal/state"
+	commonapi "github.com/cidverse/cid/pkg/common/api"
+	"github.com/cidverse/cid/pkg/core/actionexecutor/builtin"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/rs/zerolog/log"
+)
+
+// InputDirEnv holds the directory with the input artifacts of a script, also available as {CID_INPUT_DIR} placeholder
+const InputDirEnv = "CID_INPUT_DIR"
+
+type Executor struct{}
+
+func (e Executor) GetName() string {
+	return "script"
+}
+
+func (e Executor) GetVersion() string {
+	return "0.1.0"
+}
+
+func (e Executor) GetType() string {
+	return string(catalog.ActionTypeScript)
+}
+
+func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *state.ActionStateContext, catalogAction *catalog.Action, step plangenerate.Step) error {
+	sdk, cleanup, err := builtin.NewActionSDK(ctx, localState, catalogAction, step)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// inputs are passed to the commands via CID_INPUT_DIR
+	inputDir := filepath.Join(sdk.TempDir, "input")
+	actionEnv := make(map[string]string, len(sdk.ActionEnv)+1)
+	maps.Copy(actionEnv, sdk.ActionEnv)
+	actionEnv[InputDirEnv] = inputDir
+	sdk.ActionEnv = actionEnv
+
+	workDir, moduleSlug := scriptWorkDir(ctx)
+	return runScript(sdk, catalogAction, workDir, moduleSlug, inputDir)
+}
+
+// scriptWorkDir returns the directory the commands run in and the slug of the module, module scoped scripts run in the module directory
+func scriptWorkDir(ctx *commonapi.ActionExecutionContext) (workDir string, moduleSlug string) {
+	if ctx.CurrentModule != nil {
+		return ctx.CurrentModule.Directory, ctx.CurrentModule.Slug
+	}
+
+	return ctx.ProjectDir, ""
+}
+
+// runScript downloads the inputs, runs the commands and uploads the outputs of a script action
+func runScript(sdk actionsdk.SDKClient, catalogAction *catalog.Action, workDir string, moduleSlug string, inputDir string) error {
+	for _, input := range catalogAction.Metadata.Input.Artifacts {
+		if err := downloadInputs(sdk, input, moduleSlug, inputDir); err != nil {
+			return err
+		}
+	}
+
+	// run commands, the executables are resolved and audited like commands of builtin actions
+	for _, run := range catalogAction.Script.Run {
+		if strings.ContainsAny(strings.TrimSpace(run), "\r\n") {
+			return fmt.Errorf("command [%s] spans multiple lines, each entry of run must be a single command", run)
+		}
+
+		resp, cmdErr := sdk.ExecuteCommandV1(actionsdk.ExecuteCommandV1Request{
+			Command: run,
+			WorkDir: workDir,
+		})
+		if cmdErr != nil {
+			return fmt.Errorf("failed to execute command [%s]: %w", run, cmdErr)
+		} else if resp.Code != 0 {
+			return fmt.Errorf("command [%s] failed with exit code %d: %s", run, resp.Code, resp.Error)
+		}
+	}
+
+	// outputs
+	for _, output := range catalogAction.Script.Outputs {
+		files, globErr := filepath.Glob(filepath.Join(workDir, output.Path))
+		if globErr != nil {
+			return fmt.Errorf("invalid output path %s: %w", output.Path, globErr)
+		} else if len(files) == 0 {
+			return fmt.Errorf("output %s was not created by the script", output.Path)
+		}
+
+		for _, file := range files {
+			_, _, err := sdk.ArtifactUploadV1(actionsdk.ArtifactUploadRequest{
+				File:          file,
+				Module:        moduleSlug,
+				Type:          output.Type,
+				Format:        output.Format,
+				FormatVersion: output.FormatVersion,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to upload artifact %s: %w", file, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// downloadInputs downloads the artifacts matching an input into <inputDir>/<module>/<type>/<name>, module scoped scripts only receive the artifacts of their module
+func downloadInputs(sdk actionsdk.SDKClient, input actionsdk.ActionArtifactType, moduleSlug string, inputDir string) error {
+	query := "artifact_type == " + strconv.Quote(input.Type)
+	if input.Format != "" {
+		query += " && format == " + strconv.Quote(input.Format)
+	}
+	if moduleSlug != "" {
+		query += " && module == " + strconv.Quote(moduleSlug)
+	}
+
+	artifacts, err := sdk.ArtifactListV1(actionsdk.ArtifactListRequest{Query: query})
+	if err != nil {
+		return fmt.Errorf("failed to list input artifacts: %w", err)
+	}
+
+	for _, artifact := range artifacts {
+		target := filepath.Join(inputDir, artifact.Module, artifact.Type, artifact.Name)
+		log.Debug().Str("artifact", artifact.ArtifactID).Str("target", target).Msg("downloading script input")
+		if _, err = sdk.ArtifactDownloadV1(actionsdk.ArtifactDownloadRequest{ID: artifact.ArtifactID, TargetFile: target}); err != nil {
+			return fmt.Errorf("failed to download input artifact %s: %w", artifact.ArtifactID, err)
+		}
+	}
+
+	return nil
+}
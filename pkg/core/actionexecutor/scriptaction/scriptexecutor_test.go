@@ -0,0 +1,90 @@
+package scriptaction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonapi "github.com/cidverse/cid/pkg/common/api"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/repoanalyzer/analyzerapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testScriptAction(run []string, outputs []catalog.ScriptOutput, inputs ...actionsdk.ActionArtifactType) *catalog.Action {
+	return &catalog.Action{
+		Type:     catalog.ActionTypeScript,
+		Script:   catalog.ScriptAction{Run: run, Outputs: outputs},
+		Metadata: catalog.ActionMetadata{Name: "schema-generate", Input: actionsdk.ActionInput{Artifacts: inputs}},
+	}
+}
+
+func TestScriptWorkDir(t *testing.T) {
+	workDir, moduleSlug := scriptWorkDir(&commonapi.ActionExecutionContext{ProjectDir: "/my-project"})
+	assert.Equal(t, "/my-project", workDir)
+	assert.Equal(t, "", moduleSlug)
+
+	workDir, moduleSlug = scriptWorkDir(&commonapi.ActionExecutionContext{
+		ProjectDir:    "/my-project",
+		CurrentModule: &analyzerapi.ProjectModule{Directory: "/my-project/api", Slug: "api"},
+	})
+	assert.Equal(t, "/my-project/api", workDir)
+	assert.Equal(t, "api", moduleSlug)
+}
+
+func TestRunScriptInputs(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.On("ArtifactListV1", actionsdk.ArtifactListRequest{Query: `artifact_type == "binary" && format == "go" && module == "api"`}).Return([]*actionsdk.Artifact{
+		{ArtifactID: "api|binary|api-linux", Module: "api", Type: "binary", Name: "api-linux"},
+	}, nil)
+	sdk.On("ArtifactDownloadV1", actionsdk.ArtifactDownloadRequest{ID: "api|binary|api-linux", TargetFile: "/tmp/input/api/binary/api-linux"}).Return(&actionsdk.ArtifactDownloadResult{}, nil)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{Command: "sha256sum {CID_INPUT_DIR}/api/binary/api-linux", WorkDir: "/my-project/api"}).Return(&actionsdk.ExecuteCommandV1Response{}, nil)
+
+	action := testScriptAction([]string{"sha256sum {CID_INPUT_DIR}/api/binary/api-linux"}, nil, actionsdk.ActionArtifactType{Type: "binary", Format: "go"})
+	require.NoError(t, runScript(sdk, action, "/my-project/api", "api", "/tmp/input"))
+}
+
+func TestRunScriptOutputs(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "dist"), 0755))
+	for _, file := range []string{"a.json", "b.json", "c.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "dist", file), []byte("{}"), 0644))
+	}
+
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.On("ExecuteCommandV1", mock.Anything).Return(&actionsdk.ExecuteCommandV1Response{}, nil)
+	for _, file := range []string{"a.json", "b.json"} {
+		sdk.On("ArtifactUploadV1", actionsdk.ArtifactUploadRequest{File: filepath.Join(workDir, "dist", file), Type: "report", Format: "json-schema"}).Return("", "", nil).Once()
+	}
+
+	action := testScriptAction([]string{"go run ./cmd/schema dist"}, []catalog.ScriptOutput{{Path: "dist/*.json", Type: "report", Format: "json-schema"}})
+	require.NoError(t, runScript(sdk, action, workDir, "", "/tmp/input"))
+}
+
+func TestRunScriptMissingOutput(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.On("ExecuteCommandV1", mock.Anything).Return(&actionsdk.ExecuteCommandV1Response{}, nil)
+
+	action := testScriptAction([]string{"go run ./cmd/schema dist"}, []catalog.ScriptOutput{{Path: "dist/*.json", Type: "report"}})
+	assert.ErrorContains(t, runScript(sdk, action, t.TempDir(), "", "/tmp/input"), "was not created by the script")
+}
+
+func TestRunScriptExitCode(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.On("ExecuteCommandV1", actionsdk.ExecuteCommandV1Request{Command: "go vet ./...", WorkDir: "/my-project"}).Return(&actionsdk.ExecuteCommandV1Response{Code: 1, Error: "vet failed"}, nil)
+
+	// later commands and outputs are skipped
+	action := testScriptAction([]string{"go vet ./...", "go build ./..."}, []catalog.ScriptOutput{{Path: "dist/*", Type: "binary"}})
+	err := runScript(sdk, action, "/my-project", "", "/tmp/input")
+	assert.EqualError(t, err, "command [go vet ./...] failed with exit code 1: vet failed")
+}
+
+func TestRunScriptMultiLineCommand(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+
+	action := testScriptAction([]string{"go vet ./...\ngo build ./..."}, nil)
+	assert.ErrorContains(t, runScript(sdk, action, "/my-project", "", "/tmp/input"), "each entry of run must be a single command")
+}
@@ -0,0 +1,17 @@
+;; minimal wasm action, built with: wat2wasm guest.wat -o guest.wasm
+;; cid_execute removes out.txt using the sdk and reports success
+(module
+  (import "cid" "sdk_call" (func $sdk_call (param i32 i32 i32 i32) (result i32)))
+  (import "cid" "sdk_result" (func $sdk_result (param i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "{\22name\22:\22wasm-test\22}")
+  (data (i32.const 32) "FileRemoveV1")
+  (data (i32.const 64) "{\22file\22:\22out.txt\22}")
+  (func (export "_initialize"))
+  (func (export "cid_metadata") (result i64)
+    ;; packed pointer: offset 0, length 20
+    (i64.const 20))
+  (func (export "cid_execute") (result i64)
+    (drop (call $sdk_call (i32.const 32) (i32.const 12) (i32.const 64) (i32.const 18)))
+    (call $sdk_result (i32.const 256))
+    (i64.const 0)))
@@ -0,0 +1,240 @@
+package wasmaction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/internal/state"
+	commonapi "github.com/cidverse/cid/pkg/common/api"
+	"github.com/cidverse/cid/pkg/core/actionexecutor/builtin"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/actionsdk/wasmsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/cid/pkg/util"
+	"github.com/cidverse/cidverseutils/hash"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/tetratelabs/wazero"
+	wasmapi "github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// downloadTimeout limits the download of remote wasm modules
+const downloadTimeout = 5 * time.Minute
+
+var sha256Regex = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+
+type Executor struct{}
+
+func (e Executor) GetName() string {
+	return "wasm"
+}
+
+func (e Executor) GetVersion() string {
+	return "0.1.0"
+}
+
+func (e Executor) GetType() string {
+	return string(catalog.ActionTypeWASM)
+}
+
+func (e Executor) Execute(ctx *commonapi.ActionExecutionContext, localState *state.ActionStateContext, catalogAction *catalog.Action, step plangenerate.Step) error {
+	wasm, err := loadModule(ctx.ProjectDir, filepath.Join(util.CIDCacheDir(), "wasm"), catalogAction.WASM)
+	if err != nil {
+		return err
+	}
+
+	sdk, cleanup, err := builtin.NewActionSDK(ctx, localState, catalogAction, step)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// the module is closed if the step is cancelled
+	runCtx := ctx.TraceContext
+	if runCtx == nil {
+		runCtx = context.Background()
+	}
+
+	return Run(runCtx, wasm, sdk)
+}
+
+// Run instantiates the wasm module and executes the action, all sdk calls of the module are handled by the sdk client
+//
+// The module does not get access to the filesystem or environment, everything goes through the sdk.
+func Run(ctx context.Context, wasm []byte, sdk actionsdk.SDKClient) error {
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	defer runtime.Close(ctx)
+
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+	if _, err := newHostModule(runtime, sdk).Instantiate(ctx); err != nil {
+		return fmt.Errorf("failed to instantiate host module: %w", err)
+	}
+
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		return fmt.Errorf("failed to compile wasm module: %w", err)
+	}
+	for _, name := range []string{wasmsdk.GuestFunctionMetadata, wasmsdk.GuestFunctionExecute} {
+		if _, ok := compiled.ExportedFunctions()[name]; !ok {
+			return fmt.Errorf("wasm module does not export %s, the action must be built with wasmsdk.Register", name)
+		}
+	}
+
+	// reactor modules (-buildmode=c-shared) are initialized by _initialize instead of _start
+	module, err := runtime.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().
+		WithStdout(os.Stdout).
+		WithStderr(os.Stderr).
+		WithStartFunctions("_initialize"))
+	if err != nil {
+		return fmt.Errorf("failed to instantiate wasm module: %w", err)
+	}
+	defer module.Close(ctx)
+
+	metadata, err := callGuest(ctx, module, wasmsdk.GuestFunctionMetadata)
+	if err != nil {
+		return err
+	}
+	log.Debug().RawJSON("metadata", metadata).Msg("loaded wasm action")
+
+	message, err := callGuest(ctx, module, wasmsdk.GuestFunctionExecute)
+	if err != nil {
+		return err
+	} else if len(message) > 0 {
+		return fmt.Errorf("failed to execute action: %w", errors.New(string(message)))
+	}
+
+	return nil
+}
+
+// newHostModule builds the host functions, the response of sdk_call is kept until the guest fetches it with sdk_result
+func newHostModule(runtime wazero.Runtime, sdk actionsdk.SDKClient) wazero.HostModuleBuilder {
+	var pending []byte
+
+	return runtime.NewHostModuleBuilder(wasmsdk.HostModule).
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m wasmapi.Module, methodPtr, methodLen, reqPtr, reqLen uint32) uint32 {
+			method, ok := m.Memory().Read(methodPtr, methodLen)
+			if !ok {
+				panic(fmt.Errorf("sdk method out of memory range"))
+			}
+			request, ok := m.Memory().Read(reqPtr, reqLen)
+			if !ok {
+				panic(fmt.Errorf("sdk request of %s out of memory range", method))
+			}
+
+			pending = wasmsdk.Dispatch(sdk, string(method), request)
+			return uint32(len(pending))
+		}).
+		Export(wasmsdk.HostFunctionCall).
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m wasmapi.Module, ptr uint32) {
+			if !m.Memory().Write(ptr, pending) {
+				panic(fmt.Errorf("sdk response out of memory range"))
+			}
+			pending = nil
+		}).
+		Export(wasmsdk.HostFunctionResult)
+}
+
+// callGuest calls a guest function returning a packed pointer and copies the referenced memory
+func callGuest(ctx context.Context, module wasmapi.Module, name string) ([]byte, error) {
+	result, err := module.ExportedFunction(name).Call(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", name, err)
+	}
+
+	ptr, length := wasmsdk.UnpackPointer(result[0])
+	if length == 0 {
+		return nil, nil
+	}
+	data, ok := module.Memory().Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("result of %s out of memory range", name)
+	}
+
+	return bytes.Clone(data), nil
+}
+
+// loadModule reads the wasm module from the project directory or downloads it, the hash is verified if configured
+func loadModule(projectDir string, cacheDir string, action catalog.WASMAction) ([]byte, error) {
+	if strings.HasPrefix(action.Module, "http://") || strings.HasPrefix(action.Module, "https://") {
+		return loadRemoteModule(cacheDir, action)
+	}
+
+	file := action.Module
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(projectDir, file)
+	}
+	wasm, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wasm module %s: %w", file, err)
+	}
+
+	if action.SHA256 != "" {
+		if err = verifyHash(wasm, action); err != nil {
+			return nil, err
+		}
+	}
+
+	return wasm, nil
+}
+
+// loadRemoteModule downloads the wasm module, remote modules are cached by their sha256 hash
+func loadRemoteModule(cacheDir string, action catalog.WASMAction) ([]byte, error) {
+	if action.SHA256 == "" {
+		return nil, fmt.Errorf("sha256 is required for remote wasm module %s", action.Module)
+	} else if !sha256Regex.MatchString(action.SHA256) {
+		return nil, fmt.Errorf("invalid sha256 %s for remote wasm module %s", action.SHA256, action.Module)
+	}
+
+	cacheFile := filepath.Join(cacheDir, strings.ToLower(action.SHA256)+".wasm")
+	if wasm, err := os.ReadFile(cacheFile); err == nil {
+		if err = verifyHash(wasm, action); err == nil {
+			return wasm, nil
+		}
+		log.Warn().Err(err).Str("file", cacheFile).Msg("cached wasm module is invalid, downloading it again")
+	}
+
+	resp, err := resty.New().SetTimeout(downloadTimeout).R().Get(action.Module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download wasm module %s: %w", action.Module, err)
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("failed to download wasm module %s: %s", action.Module, resp.Status())
+	}
+	wasm := resp.Body()
+	if err = verifyHash(wasm, action); err != nil {
+		return nil, err
+	}
+
+	// the module is still usable if it can't be cached
+	if err = os.MkdirAll(cacheDir, 0755); err == nil {
+		err = os.WriteFile(cacheFile, wasm, 0644)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("file", cacheFile).Msg("failed to cache wasm module")
+	}
+
+	return wasm, nil
+}
+
+// verifyHash compares the sha256 hash of the module with the configured hash
+func verifyHash(wasm []byte, action catalog.WASMAction) error {
+	moduleHash, err := hash.SHA256Hash(bytes.NewReader(wasm))
+	if err != nil {
+		return fmt.Errorf("failed to calculate hash of wasm module %s: %w", action.Module, err)
+	}
+	if !strings.EqualFold(moduleHash, action.SHA256) {
+		return fmt.Errorf("hash mismatch for wasm module %s: expected %s, got %s", action.Module, action.SHA256, moduleHash)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package wasmaction
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guestSHA256 is the hash of testdata/guest.wasm
+const guestSHA256 = "e7ec589b1e933d20d33587747e24a2d4ce6e95b8d04c3ef7bf05b3a3cade42d5"
+
+func TestRun(t *testing.T) {
+	wasm, err := loadModule("testdata", t.TempDir(), catalog.WASMAction{Module: "guest.wasm", SHA256: guestSHA256})
+	require.NoError(t, err)
+
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.EXPECT().FileRemoveV1("out.txt").Return(nil)
+
+	assert.NoError(t, Run(context.Background(), wasm, sdk))
+}
+
+func TestRunCancelled(t *testing.T) {
+	wasm, err := os.ReadFile(filepath.Join("testdata", "guest.wasm"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Error(t, Run(ctx, wasm, actionsdk.NewMockSDKClient(t)))
+}
+
+func TestLoadModuleHashMismatch(t *testing.T) {
+	_, err := loadModule("testdata", t.TempDir(), catalog.WASMAction{Module: "guest.wasm", SHA256: "0000000000000000000000000000000000000000000000000000000000000000"})
+	assert.ErrorContains(t, err, "hash mismatch")
+}
+
+func TestLoadRemoteModuleCache(t *testing.T) {
+	wasm, err := os.ReadFile(filepath.Join("testdata", "guest.wasm"))
+	require.NoError(t, err)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write(wasm)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	action := catalog.WASMAction{Module: server.URL + "/guest.wasm", SHA256: guestSHA256}
+	for range 2 {
+		content, err := loadModule("", cacheDir, action)
+		require.NoError(t, err)
+		assert.Equal(t, wasm, content)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+	assert.FileExists(t, filepath.Join(cacheDir, guestSHA256+".wasm"))
+}
+
+func TestLoadRemoteModuleInvalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not a wasm module"))
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+
+	_, err := loadModule("", cacheDir, catalog.WASMAction{Module: server.URL + "/guest.wasm"})
+	assert.ErrorContains(t, err, "sha256 is required")
+
+	_, err = loadModule("", cacheDir, catalog.WASMAction{Module: server.URL + "/guest.wasm", SHA256: "../guest"})
+	assert.ErrorContains(t, err, "invalid sha256")
+
+	// modules with a hash mismatch are not cached
+	_, err = loadModule("", cacheDir, catalog.WASMAction{Module: server.URL + "/guest.wasm", SHA256: guestSHA256})
+	assert.ErrorContains(t, err, "hash mismatch")
+	assert.NoFileExists(t, filepath.Join(cacheDir, guestSHA256+".wasm"))
+}
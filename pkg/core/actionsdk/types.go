@@ -68,6 +68,9 @@ type ActionAccessNetwork struct {
 	Host string `json:"host"`
 }
 
+// NetworkHostRepositoryAPI is a placeholder for the api host of the repository (e.g. api.github.com:443 or the self-hosted GitLab server), it is resolved during plan generation
+const NetworkHostRepositoryAPI = "repository-api"
+
 type ActionAccessResource string
 
 const (
@@ -1,9 +1,5 @@
 package actionsdk
 
-import (
-	"github.com/cidverse/cid/pkg/util"
-)
-
 type Artifact struct {
 	BuildID       string `json:"build_id"`
 	JobID         string `json:"job_id"`
@@ -34,7 +30,10 @@ type ArtifactUploadRequest struct {
 }
 
 func (r ArtifactUploadRequest) ModuleSlug() string {
-	return util.GetStringOrDefault(r.Module, "root")
+	if r.Module == "" {
+		return "root"
+	}
+	return r.Module
 }
 
 type ArtifactDownloadRequest struct {
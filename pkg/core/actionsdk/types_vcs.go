@@ -53,6 +53,15 @@ type VCSDiff struct {
 	FileFrom VCSFile       `json:"file_from"`
 	FileTo   VCSFile       `json:"file_to"`
 	Lines    []VCSDiffLine `json:"lines,omitempty"`
+	Hunks    []VCSDiffHunk `json:"hunks,omitempty"`
+}
+
+// VCSDiffHunk is a changed region of a file with unified diff semantics, line numbers start at 1 and a side without lines refers to the line before the change
+type VCSDiffHunk struct {
+	FromLine  int `json:"from_line"`
+	FromLines int `json:"from_lines"`
+	ToLine    int `json:"to_line"`
+	ToLines   int `json:"to_lines"`
 }
 
 type VCSDiffLine struct {
@@ -77,6 +86,7 @@ type VCSReleasesRequest struct {
 }
 
 type VCSDiffRequest struct {
-	FromHash string `json:"from"`
-	ToHash   string `json:"to"`
+	FromHash  string `json:"from"`
+	ToHash    string `json:"to"`
+	MergeBase bool   `json:"merge_base"` // MergeBase diffs against the common ancestor of from and to, like git diff from...to
 }
@@ -0,0 +1,74 @@
+// Package wasmsdk implements the interface between cid and actions compiled to WebAssembly (GOOS=wasip1 -buildmode=c-shared).
+//
+// The guest exports the action via Register, the host routes SDK calls of the guest to an actionsdk.SDKClient using Dispatch.
+// All values are passed as json, the guest allocates the memory for values returned by the host.
+package wasmsdk
+
+import (
+	"encoding/json"
+)
+
+// HostModule is the name of the module providing the host functions
+const HostModule = "cid"
+
+// host functions, imported by the guest
+const (
+	HostFunctionCall   = "sdk_call"   // HostFunctionCall(methodPtr, methodLen, reqPtr, reqLen uint32) uint32 calls a sdk method and returns the length of the response
+	HostFunctionResult = "sdk_result" // HostFunctionResult(ptr uint32) copies the response of the last call into guest memory
+)
+
+// guest functions, exported by the action module
+const (
+	GuestFunctionMetadata = "cid_metadata" // GuestFunctionMetadata() uint64 returns the action metadata as packed pointer
+	GuestFunctionExecute  = "cid_execute"  // GuestFunctionExecute() uint64 executes the action and returns the error message as packed pointer, 0 on success
+)
+
+// Response is the envelope of sdk call responses
+type Response struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// FileRequest is the request of sdk methods taking a single file
+type FileRequest struct {
+	File string `json:"file"`
+}
+
+// FileWriteRequest is the request of FileWriteV1
+type FileWriteRequest struct {
+	File    string `json:"file"`
+	Content []byte `json:"content"`
+}
+
+// FileMoveRequest is the request of FileCopyV1 and FileRenameV1
+type FileMoveRequest struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ArchiveRequest is the request of the archive methods, e.g. ZIPCreateV1
+type ArchiveRequest struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// IDRequest is the request of sdk methods taking a single id
+type IDRequest struct {
+	ID string `json:"id"`
+}
+
+// ArtifactUploadResponse is the response of ArtifactUploadV1
+type ArtifactUploadResponse struct {
+	FilePath string `json:"file_path"`
+	FileHash string `json:"file_hash"`
+}
+
+// PackPointer packs a pointer and length into a single value, used to return memory regions from the guest
+func PackPointer(ptr uint32, length uint32) uint64 {
+	return uint64(ptr)<<32 | uint64(length)
+}
+
+// UnpackPointer unpacks a value created by PackPointer
+func UnpackPointer(packed uint64) (ptr uint32, length uint32) {
+	return uint32(packed >> 32), uint32(packed)
+}
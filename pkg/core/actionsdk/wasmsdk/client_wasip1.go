@@ -0,0 +1,204 @@
+//go:build wasip1
+
+package wasmsdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+)
+
+//go:wasmimport cid sdk_call
+func hostCall(methodPtr, methodLen, reqPtr, reqLen uint32) uint32
+
+//go:wasmimport cid sdk_result
+func hostResult(ptr uint32)
+
+// call invokes a sdk method of the host, the request and result are passed as json
+func call[Res any](method string, req any) (Res, error) {
+	var res Res
+
+	reqData := []byte{}
+	if req != nil {
+		var err error
+		if reqData, err = json.Marshal(req); err != nil {
+			return res, fmt.Errorf("failed to encode request of %s: %w", method, err)
+		}
+	}
+
+	// the host keeps the response until it is requested with the guest buffer
+	methodData := []byte(method)
+	length := hostCall(bytesPointer(methodData), uint32(len(methodData)), bytesPointer(reqData), uint32(len(reqData)))
+	out := make([]byte, length)
+	hostResult(bytesPointer(out))
+
+	var response Response
+	if err := json.Unmarshal(out, &response); err != nil {
+		return res, fmt.Errorf("failed to decode response of %s: %w", method, err)
+	}
+	if response.Error != "" {
+		return res, errors.New(response.Error)
+	}
+	if len(response.Result) > 0 {
+		if err := json.Unmarshal(response.Result, &res); err != nil {
+			return res, fmt.Errorf("failed to decode result of %s: %w", method, err)
+		}
+	}
+
+	return res, nil
+}
+
+func bytesPointer(data []byte) uint32 {
+	if len(data) == 0 {
+		return 0
+	}
+	return uint32(uintptr(unsafe.Pointer(unsafe.SliceData(data))))
+}
+
+// Client implements the actionsdk.SDKClient by calling the host functions
+type Client struct{}
+
+var _ actionsdk.SDKClient = Client{}
+
+func (c Client) HealthV1() (actionsdk.HealthV1Response, error) {
+	return call[actionsdk.HealthV1Response]("HealthV1", nil)
+}
+
+func (c Client) LogV1(req actionsdk.LogV1Request) error {
+	_, err := call[none]("LogV1", req)
+	return err
+}
+
+func (c Client) UUIDV4() string {
+	id, _ := call[string]("UUIDV4", nil)
+	return id
+}
+
+func (c Client) ConfigV1() (*actionsdk.ConfigV1Response, error) {
+	return call[*actionsdk.ConfigV1Response]("ConfigV1", nil)
+}
+
+func (c Client) ProjectExecutionContextV1() (*actionsdk.ProjectExecutionContextV1Response, error) {
+	return call[*actionsdk.ProjectExecutionContextV1Response]("ProjectExecutionContextV1", nil)
+}
+
+func (c Client) ModuleExecutionContextV1() (*actionsdk.ModuleExecutionContextV1Response, error) {
+	return call[*actionsdk.ModuleExecutionContextV1Response]("ModuleExecutionContextV1", nil)
+}
+
+func (c Client) EnvironmentV1() (*actionsdk.EnvironmentV1Response, error) {
+	return call[*actionsdk.EnvironmentV1Response]("EnvironmentV1", nil)
+}
+
+func (c Client) DeploymentV1() (*actionsdk.DeploymentV1Response, error) {
+	return call[*actionsdk.DeploymentV1Response]("DeploymentV1", nil)
+}
+
+func (c Client) ModuleListV1() ([]*actionsdk.ProjectModule, error) {
+	return call[[]*actionsdk.ProjectModule]("ModuleListV1", nil)
+}
+
+func (c Client) ModuleCurrentV1() (*actionsdk.ProjectModule, error) {
+	return call[*actionsdk.ProjectModule]("ModuleCurrentV1", nil)
+}
+
+func (c Client) ExecuteCommandV1(req actionsdk.ExecuteCommandV1Request) (*actionsdk.ExecuteCommandV1Response, error) {
+	return call[*actionsdk.ExecuteCommandV1Response]("ExecuteCommandV1", req)
+}
+
+func (c Client) VCSCommitsV1(req actionsdk.VCSCommitsRequest) ([]*actionsdk.VCSCommit, error) {
+	return call[[]*actionsdk.VCSCommit]("VCSCommitsV1", req)
+}
+
+func (c Client) VCSCommitByHashV1(req actionsdk.VCSCommitByHashRequest) (*actionsdk.VCSCommit, error) {
+	return call[*actionsdk.VCSCommit]("VCSCommitByHashV1", req)
+}
+
+func (c Client) VCSTagsV1() ([]actionsdk.VCSTag, error) {
+	return call[[]actionsdk.VCSTag]("VCSTagsV1", nil)
+}
+
+func (c Client) VCSReleasesV1(req actionsdk.VCSReleasesRequest) ([]actionsdk.VCSRelease, error) {
+	return call[[]actionsdk.VCSRelease]("VCSReleasesV1", req)
+}
+
+func (c Client) VCSDiffV1(req actionsdk.VCSDiffRequest) ([]actionsdk.VCSDiff, error) {
+	return call[[]actionsdk.VCSDiff]("VCSDiffV1", req)
+}
+
+func (c Client) FileReadV1(file string) (string, error) {
+	return call[string]("FileReadV1", FileRequest{File: file})
+}
+
+func (c Client) FileWriteV1(file string, content []byte) error {
+	_, err := call[none]("FileWriteV1", FileWriteRequest{File: file, Content: content})
+	return err
+}
+
+func (c Client) FileRemoveV1(file string) error {
+	_, err := call[none]("FileRemoveV1", FileRequest{File: file})
+	return err
+}
+
+func (c Client) FileCopyV1(old string, new string) error {
+	_, err := call[none]("FileCopyV1", FileMoveRequest{Old: old, New: new})
+	return err
+}
+
+func (c Client) FileRenameV1(old string, new string) error {
+	_, err := call[none]("FileRenameV1", FileMoveRequest{Old: old, New: new})
+	return err
+}
+
+func (c Client) FileListV1(req actionsdk.FileV1Request) ([]actionsdk.File, error) {
+	return call[[]actionsdk.File]("FileListV1", req)
+}
+
+func (c Client) FileExistsV1(file string) bool {
+	exists, _ := call[bool]("FileExistsV1", FileRequest{File: file})
+	return exists
+}
+
+func (c Client) ArtifactListV1(req actionsdk.ArtifactListRequest) ([]*actionsdk.Artifact, error) {
+	return call[[]*actionsdk.Artifact]("ArtifactListV1", req)
+}
+
+func (c Client) ArtifactByIdV1(id string) (*actionsdk.Artifact, error) {
+	return call[*actionsdk.Artifact]("ArtifactByIdV1", IDRequest{ID: id})
+}
+
+func (c Client) ArtifactUploadV1(req actionsdk.ArtifactUploadRequest) (filePath string, fileHash string, err error) {
+	res, err := call[ArtifactUploadResponse]("ArtifactUploadV1", req)
+	return res.FilePath, res.FileHash, err
+}
+
+func (c Client) ArtifactDownloadV1(req actionsdk.ArtifactDownloadRequest) (*actionsdk.ArtifactDownloadResult, error) {
+	return call[*actionsdk.ArtifactDownloadResult]("ArtifactDownloadV1", req)
+}
+
+func (c Client) ArtifactDownloadByteArrayV1(req actionsdk.ArtifactDownloadByteArrayRequest) (*actionsdk.ArtifactDownloadByteArrayResult, error) {
+	return call[*actionsdk.ArtifactDownloadByteArrayResult]("ArtifactDownloadByteArrayV1", req)
+}
+
+func (c Client) ZIPCreateV1(inputDirectory string, outputFile string) error {
+	_, err := call[none]("ZIPCreateV1", ArchiveRequest{Input: inputDirectory, Output: outputFile})
+	return err
+}
+
+func (c Client) ZIPExtractV1(archiveFile string, outputDirectory string) error {
+	_, err := call[none]("ZIPExtractV1", ArchiveRequest{Input: archiveFile, Output: outputDirectory})
+	return err
+}
+
+func (c Client) TARCreateV1(inputDirectory string, outputFile string) error {
+	_, err := call[none]("TARCreateV1", ArchiveRequest{Input: inputDirectory, Output: outputFile})
+	return err
+}
+
+func (c Client) TARExtractV1(archiveFile string, outputDirectory string) error {
+	_, err := call[none]("TARExtractV1", ArchiveRequest{Input: archiveFile, Output: outputDirectory})
+	return err
+}
@@ -0,0 +1,141 @@
+package wasmsdk
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+)
+
+type handler func(sdk actionsdk.SDKClient, request []byte) (any, error)
+
+// handle decodes the json request and calls the sdk method
+func handle[Req any, Res any](fn func(sdk actionsdk.SDKClient, req Req) (Res, error)) handler {
+	return func(sdk actionsdk.SDKClient, request []byte) (any, error) {
+		var req Req
+		if len(request) > 0 {
+			if err := json.Unmarshal(request, &req); err != nil {
+				return nil, fmt.Errorf("invalid request: %w", err)
+			}
+		}
+
+		return fn(sdk, req)
+	}
+}
+
+// none is the request and response of methods without parameters or result
+type none struct{}
+
+var handlers = map[string]handler{
+	// misc operations
+	"HealthV1": handle(func(sdk actionsdk.SDKClient, _ none) (actionsdk.HealthV1Response, error) { return sdk.HealthV1() }),
+	"LogV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.LogV1Request) (none, error) {
+		return none{}, sdk.LogV1(req)
+	}),
+	"UUIDV4": handle(func(sdk actionsdk.SDKClient, _ none) (string, error) { return sdk.UUIDV4(), nil }),
+
+	// config operations
+	"ConfigV1": handle(func(sdk actionsdk.SDKClient, _ none) (*actionsdk.ConfigV1Response, error) { return sdk.ConfigV1() }),
+	"ProjectExecutionContextV1": handle(func(sdk actionsdk.SDKClient, _ none) (*actionsdk.ProjectExecutionContextV1Response, error) {
+		return sdk.ProjectExecutionContextV1()
+	}),
+	"ModuleExecutionContextV1": handle(func(sdk actionsdk.SDKClient, _ none) (*actionsdk.ModuleExecutionContextV1Response, error) {
+		return sdk.ModuleExecutionContextV1()
+	}),
+	"EnvironmentV1": handle(func(sdk actionsdk.SDKClient, _ none) (*actionsdk.EnvironmentV1Response, error) {
+		return sdk.EnvironmentV1()
+	}),
+	"DeploymentV1": handle(func(sdk actionsdk.SDKClient, _ none) (*actionsdk.DeploymentV1Response, error) {
+		return sdk.DeploymentV1()
+	}),
+	"ModuleListV1":    handle(func(sdk actionsdk.SDKClient, _ none) ([]*actionsdk.ProjectModule, error) { return sdk.ModuleListV1() }),
+	"ModuleCurrentV1": handle(func(sdk actionsdk.SDKClient, _ none) (*actionsdk.ProjectModule, error) { return sdk.ModuleCurrentV1() }),
+
+	// command operations
+	"ExecuteCommandV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.ExecuteCommandV1Request) (*actionsdk.ExecuteCommandV1Response, error) {
+		return sdk.ExecuteCommandV1(req)
+	}),
+
+	// vcs operations
+	"VCSCommitsV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.VCSCommitsRequest) ([]*actionsdk.VCSCommit, error) {
+		return sdk.VCSCommitsV1(req)
+	}),
+	"VCSCommitByHashV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.VCSCommitByHashRequest) (*actionsdk.VCSCommit, error) {
+		return sdk.VCSCommitByHashV1(req)
+	}),
+	"VCSTagsV1": handle(func(sdk actionsdk.SDKClient, _ none) ([]actionsdk.VCSTag, error) { return sdk.VCSTagsV1() }),
+	"VCSReleasesV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.VCSReleasesRequest) ([]actionsdk.VCSRelease, error) {
+		return sdk.VCSReleasesV1(req)
+	}),
+	"VCSDiffV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.VCSDiffRequest) ([]actionsdk.VCSDiff, error) {
+		return sdk.VCSDiffV1(req)
+	}),
+
+	// file operations
+	"FileReadV1": handle(func(sdk actionsdk.SDKClient, req FileRequest) (string, error) { return sdk.FileReadV1(req.File) }),
+	"FileWriteV1": handle(func(sdk actionsdk.SDKClient, req FileWriteRequest) (none, error) {
+		return none{}, sdk.FileWriteV1(req.File, req.Content)
+	}),
+	"FileRemoveV1": handle(func(sdk actionsdk.SDKClient, req FileRequest) (none, error) {
+		return none{}, sdk.FileRemoveV1(req.File)
+	}),
+	"FileCopyV1": handle(func(sdk actionsdk.SDKClient, req FileMoveRequest) (none, error) {
+		return none{}, sdk.FileCopyV1(req.Old, req.New)
+	}),
+	"FileRenameV1": handle(func(sdk actionsdk.SDKClient, req FileMoveRequest) (none, error) {
+		return none{}, sdk.FileRenameV1(req.Old, req.New)
+	}),
+	"FileListV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.FileV1Request) ([]actionsdk.File, error) {
+		return sdk.FileListV1(req)
+	}),
+	"FileExistsV1": handle(func(sdk actionsdk.SDKClient, req FileRequest) (bool, error) { return sdk.FileExistsV1(req.File), nil }),
+
+	// artifact operations
+	"ArtifactListV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.ArtifactListRequest) ([]*actionsdk.Artifact, error) {
+		return sdk.ArtifactListV1(req)
+	}),
+	"ArtifactByIdV1": handle(func(sdk actionsdk.SDKClient, req IDRequest) (*actionsdk.Artifact, error) {
+		return sdk.ArtifactByIdV1(req.ID)
+	}),
+	"ArtifactUploadV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.ArtifactUploadRequest) (ArtifactUploadResponse, error) {
+		filePath, fileHash, err := sdk.ArtifactUploadV1(req)
+		return ArtifactUploadResponse{FilePath: filePath, FileHash: fileHash}, err
+	}),
+	"ArtifactDownloadV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.ArtifactDownloadRequest) (*actionsdk.ArtifactDownloadResult, error) {
+		return sdk.ArtifactDownloadV1(req)
+	}),
+	"ArtifactDownloadByteArrayV1": handle(func(sdk actionsdk.SDKClient, req actionsdk.ArtifactDownloadByteArrayRequest) (*actionsdk.ArtifactDownloadByteArrayResult, error) {
+		return sdk.ArtifactDownloadByteArrayV1(req)
+	}),
+
+	// archive operations
+	"ZIPCreateV1": handle(func(sdk actionsdk.SDKClient, req ArchiveRequest) (none, error) {
+		return none{}, sdk.ZIPCreateV1(req.Input, req.Output)
+	}),
+	"ZIPExtractV1": handle(func(sdk actionsdk.SDKClient, req ArchiveRequest) (none, error) {
+		return none{}, sdk.ZIPExtractV1(req.Input, req.Output)
+	}),
+	"TARCreateV1": handle(func(sdk actionsdk.SDKClient, req ArchiveRequest) (none, error) {
+		return none{}, sdk.TARCreateV1(req.Input, req.Output)
+	}),
+	"TARExtractV1": handle(func(sdk actionsdk.SDKClient, req ArchiveRequest) (none, error) {
+		return none{}, sdk.TARExtractV1(req.Input, req.Output)
+	}),
+}
+
+// Dispatch calls the sdk method with the json request and returns the json encoded Response
+func Dispatch(sdk actionsdk.SDKClient, method string, request []byte) []byte {
+	var response Response
+
+	h, ok := handlers[method]
+	if !ok {
+		response.Error = fmt.Sprintf("unknown sdk method %s", method)
+	} else if result, err := h(sdk, request); err != nil {
+		response.Error = err.Error()
+	} else if response.Result, err = json.Marshal(result); err != nil {
+		response.Error = fmt.Sprintf("failed to encode result of %s: %s", method, err.Error())
+	}
+
+	out, _ := json.Marshal(response)
+	return out
+}
@@ -0,0 +1,51 @@
+package wasmsdk
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatch(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.EXPECT().FileReadV1("README.md").Return("hello", nil)
+
+	var response Response
+	assert.NoError(t, json.Unmarshal(Dispatch(sdk, "FileReadV1", []byte(`{"file":"README.md"}`)), &response))
+	assert.Empty(t, response.Error)
+	assert.JSONEq(t, `"hello"`, string(response.Result))
+}
+
+func TestDispatchError(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+	sdk.EXPECT().FileRemoveV1("missing.txt").Return(errors.New("file not found"))
+
+	var response Response
+	assert.NoError(t, json.Unmarshal(Dispatch(sdk, "FileRemoveV1", []byte(`{"file":"missing.txt"}`)), &response))
+	assert.Equal(t, "file not found", response.Error)
+}
+
+func TestDispatchUnknownMethod(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+
+	var response Response
+	assert.NoError(t, json.Unmarshal(Dispatch(sdk, "ShellV1", nil), &response))
+	assert.Equal(t, "unknown sdk method ShellV1", response.Error)
+}
+
+func TestDispatchInvalidRequest(t *testing.T) {
+	sdk := actionsdk.NewMockSDKClient(t)
+
+	var response Response
+	assert.NoError(t, json.Unmarshal(Dispatch(sdk, "FileReadV1", []byte(`{`)), &response))
+	assert.Contains(t, response.Error, "invalid request")
+}
+
+func TestPackPointer(t *testing.T) {
+	ptr, length := UnpackPointer(PackPointer(1024, 42))
+	assert.Equal(t, uint32(1024), ptr)
+	assert.Equal(t, uint32(42), length)
+}
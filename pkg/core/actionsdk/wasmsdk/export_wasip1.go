@@ -0,0 +1,53 @@
+//go:build wasip1
+
+package wasmsdk
+
+import (
+	"encoding/json"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+)
+
+var (
+	registered actionsdk.Action
+	retained   []byte // retained keeps the last value returned to the host alive until the next call
+)
+
+// Register registers the action of the module, it must be called from init - the sdk client of the action is Client{}
+//
+//	func init() {
+//		wasmsdk.Register(MyAction{Sdk: wasmsdk.Client{}})
+//	}
+func Register(action actionsdk.Action) {
+	registered = action
+}
+
+//go:wasmexport cid_metadata
+func exportMetadata() uint64 {
+	if registered == nil {
+		return 0
+	}
+
+	data, err := json.Marshal(registered.Metadata())
+	if err != nil {
+		return 0
+	}
+	return retain(data)
+}
+
+//go:wasmexport cid_execute
+func exportExecute() uint64 {
+	if registered == nil {
+		return retain([]byte("no action registered, call wasmsdk.Register in init"))
+	}
+
+	if err := registered.Execute(); err != nil {
+		return retain([]byte(err.Error()))
+	}
+	return 0
+}
+
+func retain(data []byte) uint64 {
+	retained = data
+	return PackPointer(bytesPointer(data), uint32(len(data)))
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -53,7 +54,7 @@ func LoadSources() map[string]*Source {
 
 func LoadCatalogs(sources map[string]*Source) Config {
 	var cfg Config
-	for name := range sources {
+	for _, name := range slices.Sorted(maps.Keys(sources)) {
 		file := filepath.Join(util.CIDConfigDir(), "repo.d", name+".json")
 
 		if _, err := os.Stat(file); os.IsNotExist(err) {
@@ -85,6 +86,9 @@ func LoadCatalogs(sources map[string]*Source) Config {
 		cfg.Actions = append(cfg.Actions, fileCfg.Actions...)
 		cfg.Workflows = append(cfg.Workflows, fileCfg.Workflows...)
 		cfg.Executables = append(cfg.Executables, fileCfg.Executables...)
+		if len(fileCfg.Config) > 0 {
+			cfg.SourceConfigs = append(cfg.SourceConfigs, SourceConfig{Source: name, Config: fileCfg.Config})
+		}
 	}
 
 	return cfg
@@ -244,6 +248,9 @@ func updateCatalogFile(file string, source *Source) error {
 	if len(source.Filter) > 0 && !slices.Contains(source.Filter, "executables") {
 		config.Executables = nil
 	}
+	if len(source.Filter) > 0 && !slices.Contains(source.Filter, "config") {
+		config.Config = ""
+	}
 
 	// persist
 	content, err = json.Marshal(config)
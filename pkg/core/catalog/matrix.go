@@ -0,0 +1,40 @@
+package catalog
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// MatrixCombinations returns all combinations of the matrix values, ordered by key name and value order - an empty matrix results in a single empty combination
+func MatrixCombinations(matrix map[string][]string) []map[string]string {
+	combinations := []map[string]string{{}}
+	for _, key := range slices.Sorted(maps.Keys(matrix)) {
+		if len(matrix[key]) == 0 {
+			continue
+		}
+
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range matrix[key] {
+				c := maps.Clone(combination)
+				c[key] = value
+				next = append(next, c)
+			}
+		}
+		combinations = next
+	}
+
+	return combinations
+}
+
+// MatrixName returns the display name of a matrix combination, e.g. go=1.22, os=linux
+func MatrixName(combination map[string]string) string {
+	var parts []string
+	for _, key := range slices.Sorted(maps.Keys(combination)) {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, combination[key]))
+	}
+
+	return strings.Join(parts, ", ")
+}
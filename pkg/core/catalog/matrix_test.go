@@ -0,0 +1,45 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixCombinations(t *testing.T) {
+	combinations := MatrixCombinations(map[string][]string{
+		"os": {"linux", "darwin"},
+		"go": {"1.22", "1.23"},
+	})
+
+	assert.Equal(t, []map[string]string{
+		{"go": "1.22", "os": "linux"},
+		{"go": "1.22", "os": "darwin"},
+		{"go": "1.23", "os": "linux"},
+		{"go": "1.23", "os": "darwin"},
+	}, combinations)
+}
+
+func TestMatrixCombinationsEmpty(t *testing.T) {
+	assert.Equal(t, []map[string]string{{}}, MatrixCombinations(nil))
+	assert.Equal(t, []map[string]string{{"go": "1.22"}}, MatrixCombinations(map[string][]string{"go": {"1.22"}, "os": {}}))
+}
+
+func TestMatrixName(t *testing.T) {
+	assert.Equal(t, "go=1.22, os=linux", MatrixName(map[string]string{"os": "linux", "go": "1.22"}))
+	assert.Equal(t, "", MatrixName(map[string]string{}))
+}
+
+func TestExtendWorkflowMatrix(t *testing.T) {
+	parent := testWorkflowRegistry().Workflows[0]
+	child := Workflow{
+		Name: "custom",
+		Stages: []WorkflowStage{
+			{Name: "test", Actions: []WorkflowAction{{ID: "builtin://actions/go-test", Matrix: map[string][]string{"go": {"1.22", "1.23"}}}}},
+		},
+	}
+
+	result := ExtendWorkflow(parent, child)
+	assert.Equal(t, map[string][]string{"go": {"1.22", "1.23"}}, result.Stages[1].Actions[0].Matrix)
+	assert.Nil(t, parent.Stages[1].Actions[0].Matrix)
+}
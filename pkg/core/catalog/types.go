@@ -1,14 +1,17 @@
 package catalog
 
 import (
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/cidverse/cid/pkg/common/executable"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 )
 
-var workflowRegexp = regexp.MustCompile(`(?P<repo>\w+)/?(?P<workflow>\w+)@?(?P<version>[\w.]+)?`)
+var workflowRegexp = regexp.MustCompile(`^(?:(?P<repo>[\w.-]+)/)?(?P<workflow>[\w.-]+)(?:@(?P<version>[\w.+-]+))?$`)
 
 // Config is a registry configuration with placeholders
 type Config struct {
@@ -23,16 +26,55 @@ type Config struct {
 
 	// Executables
 	Executables []executable.TypedCandidate `yaml:"executables,omitempty" json:"executables,omitempty"`
+
+	// Config is an org-level configuration, merged below the user and project configuration
+	Config OrgConfig `yaml:"config,omitempty" json:"config,omitempty"`
+
+	// SourceConfigs holds the org-level configuration of each catalog source, ordered by source name
+	SourceConfigs []SourceConfig `yaml:"-" json:"-"`
+}
+
+// SourceConfig is the org-level configuration provided by a catalog source
+type SourceConfig struct {
+	Source string
+	Config OrgConfig
+}
+
+// OrgConfig is the org-level configuration of a catalog as raw yaml, which keeps the merge tags (!append, !replace, !merge) of the layer
+type OrgConfig string
+
+// UnmarshalYAML keeps the yaml of the config node, including its tags
+func (c *OrgConfig) UnmarshalYAML(node *yaml.Node) error {
+	content, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("failed to encode org config: %w", err)
+	}
+
+	*c = OrgConfig(content)
+	return nil
+}
+
+// UnmarshalJSON accepts the raw yaml of a cached catalog or the object of a json catalog, json is valid yaml
+func (c *OrgConfig) UnmarshalJSON(data []byte) error {
+	var content string
+	if err := json.Unmarshal(data, &content); err == nil {
+		*c = OrgConfig(content)
+		return nil
+	}
+
+	*c = OrgConfig(data)
+	return nil
 }
 
 type ExecutableDiscovery struct {
 	ContainerDiscovery executable.DiscoverContainerOptions `yaml:"container,omitempty"`
 }
 
-// FindWorkflow finds a workflow by name
+// FindWorkflow finds a workflow by reference, e.g. main, builtin/main or builtin/main@1.0.0 - the first match is returned if the repository or version is omitted
 func (r *Config) FindWorkflow(id string) *Workflow {
-	for _, w := range r.Workflows {
-		if isMatchingWorkflow(id, &w) {
+	for i := range r.Workflows {
+		if isMatchingWorkflow(id, &r.Workflows[i]) {
+			w := r.Workflows[i]
 			return &w
 		}
 	}
@@ -57,25 +99,28 @@ func (r *Config) FindAction(name string) *Action {
 }
 
 func isMatchingWorkflow(id string, workflow *Workflow) bool {
-	// parse id
-	if !strings.Contains(id, "/") {
-		id = "cid/" + id
+	repo, name, version, err := ParseWorkflowID(id)
+	if err != nil {
+		log.Warn().Err(err).Str("workflow", id).Msg("invalid workflow reference")
+		return false
 	}
-	match := workflowRegexp.FindStringSubmatch(id)
-	if match == nil {
-		log.Fatal().Msg("invalid workflow name, please use the following format <repository>/<workflow>@<workflowVersion>")
+
+	if repo != "" && workflow.Repository != repo {
+		return false
 	}
-	repo := match[1]
-	name := match[2]
-	version := match[3]
-
-	if workflow.Repository == repo && workflow.Name == name {
-		if len(version) > 0 && workflow.Version == version {
-			return true
-		} else if version == "" {
-			return true
-		}
+	if workflow.Name != name {
+		return false
+	}
+
+	return version == "" || workflow.Version == version
+}
+
+// ParseWorkflowID parses a workflow reference in the format <repository>/<workflow>@<version>, repository and version are optional
+func ParseWorkflowID(id string) (repo string, name string, version string, err error) {
+	match := workflowRegexp.FindStringSubmatch(strings.TrimSpace(id))
+	if match == nil {
+		return "", "", "", fmt.Errorf("invalid workflow reference %q, please use the format <repository>/<workflow>@<version>", id)
 	}
 
-	return false
+	return match[1], match[2], match[3], nil
 }
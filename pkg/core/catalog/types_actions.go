@@ -9,6 +9,8 @@ type Action struct {
 	URI        string          `yaml:"uri" json:"uri"` // URI is a unique absolute identifier for the action
 	Type       ActionType      `required:"true" yaml:"type" json:"type"`
 	Container  ContainerAction `yaml:"container,omitempty" json:"container,omitempty"` // Container contains the configuration for containerized actions
+	Script     ScriptAction    `yaml:"script,omitempty" json:"script,omitempty"`       // Script contains the configuration for script actions
+	WASM       WASMAction      `yaml:"wasm,omitempty" json:"wasm,omitempty"`           // WASM contains the configuration for WebAssembly actions
 	Version    string          `yaml:"version,omitempty" json:"version,omitempty"`
 	Metadata   ActionMetadata  `yaml:"metadata" json:"metadata"`
 }
@@ -33,6 +35,8 @@ const (
 	ActionTypeBuiltIn      ActionType = "builtin"
 	ActionTypeContainer    ActionType = "container"
 	ActionTypeGitHubAction ActionType = "githubaction"
+	ActionTypeScript       ActionType = "script"
+	ActionTypeWASM         ActionType = "wasm"
 )
 
 type ContainerAction struct {
@@ -40,3 +44,20 @@ type ContainerAction struct {
 	Command string       `json:"command"` // Command is the command that should be executed in the container image to start the action.
 	Certs   []ImageCerts `json:"certs,omitempty"`
 }
+
+type ScriptAction struct {
+	Run     []string       `json:"run"`               // Run holds the commands of the script, one command per entry. A command is split on spaces into the executable and its arguments, shell syntax like pipes, redirects or quoting is not supported. The executable of each command must be declared in the action access
+	Outputs []ScriptOutput `json:"outputs,omitempty"` // Outputs are files that are uploaded as artifacts after the script completed
+}
+
+type WASMAction struct {
+	Module string `json:"module"`           // Module is the path of the wasm module relative to the project directory or a http(s) url
+	SHA256 string `json:"sha256,omitempty"` // SHA256 is the expected hash of the module, required for remote modules
+}
+
+type ScriptOutput struct {
+	Path          string `yaml:"path" json:"path"` // Path is a file or glob pattern, relative to the module or project directory
+	Type          string `yaml:"type" json:"type"`
+	Format        string `yaml:"format,omitempty" json:"format,omitempty"`
+	FormatVersion string `yaml:"format-version,omitempty" json:"format_version,omitempty"`
+}
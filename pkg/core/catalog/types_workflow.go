@@ -16,17 +16,21 @@ type WorkflowRule struct {
 }
 
 type WorkflowAction struct {
-	ID     string                     `required:"true" yaml:"id"`
-	Rules  []WorkflowRule             `yaml:"rules,omitempty"`
-	Config interface{}                `yaml:"config,omitempty"`
-	Module *analyzerapi.ProjectModule `yaml:"-"`
-	Stage  string                     `yaml:"-"`
+	ID       string                     `required:"true" yaml:"id"`
+	Rules    []WorkflowRule             `yaml:"rules,omitempty"`
+	Config   interface{}                `yaml:"config,omitempty"`
+	Matrix   map[string][]string        `yaml:"matrix,omitempty"` // Matrix expands the action into one step per combination of values, e.g. go: [1.22, 1.23]
+	Module   *analyzerapi.ProjectModule `yaml:"-"`
+	Stage    string                     `yaml:"-"`
+	Remove   bool                       `yaml:"remove,omitempty"`   // Remove drops the action from the extended workflow
+	Approval *bool                      `yaml:"approval,omitempty"` // Approval requires a manual approval before the action starts, defaults to true for deployment actions
 }
 
 type WorkflowStage struct {
 	Name    string           `required:"true" yaml:"name,omitempty"`
 	Rules   []WorkflowRule   `yaml:"rules,omitempty"`
 	Actions []WorkflowAction `yaml:"actions,omitempty"`
+	Remove  bool             `yaml:"remove,omitempty"` // Remove drops the stage from the extended workflow
 }
 
 type Workflow struct {
@@ -34,10 +38,24 @@ type Workflow struct {
 	Name        string          `required:"true" yaml:"name,omitempty"`
 	Description string          `yaml:"description,omitempty"`
 	Version     string          `yaml:"version,omitempty"`
+	Extends     string          `yaml:"extends,omitempty"` // Extends references the parent workflow, e.g. builtin/main
 	Rules       []WorkflowRule  `yaml:"rules,omitempty"`
 	Stages      []WorkflowStage `yaml:"stages,omitempty"`
 }
 
+// ID returns the reference of the workflow in the format <repository>/<workflow>@<version>
+func (w *Workflow) ID() string {
+	id := w.Name
+	if w.Repository != "" {
+		id = w.Repository + "/" + id
+	}
+	if w.Version != "" {
+		id += "@" + w.Version
+	}
+
+	return id
+}
+
 // ActionCount returns the total count of actions across all stages
 func (w *Workflow) ActionCount() int {
 	actionCount := 0
@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ProjectRepository is the repository of workflows declared in the project configuration
+const ProjectRepository = "project"
+
+// ResolveWorkflows resolves the extends reference of all workflows, workflows that can't be resolved are removed from the registry
+func (r *Config) ResolveWorkflows() error {
+	var errs []error
+	resolved := make([]Workflow, 0, len(r.Workflows))
+	for _, w := range r.Workflows {
+		result, err := r.resolveWorkflow(w, nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resolved = append(resolved, result)
+	}
+	r.Workflows = resolved
+
+	return errors.Join(errs...)
+}
+
+func (r *Config) resolveWorkflow(w Workflow, chain []string) (Workflow, error) {
+	if w.Extends == "" {
+		return w, nil
+	}
+
+	chain = append(chain, w.ID())
+	if _, _, _, err := ParseWorkflowID(w.Extends); err != nil {
+		return w, fmt.Errorf("workflow %s: %w", w.ID(), err)
+	}
+
+	// workflows of the chain are skipped, a project workflow main can extend the builtin main workflow
+	var parent *Workflow
+	for i := range r.Workflows {
+		if isMatchingWorkflow(w.Extends, &r.Workflows[i]) && !slices.Contains(chain, r.Workflows[i].ID()) {
+			parent = &r.Workflows[i]
+			break
+		}
+	}
+	if parent == nil {
+		if r.FindWorkflow(w.Extends) != nil {
+			return w, fmt.Errorf("workflow %s has a circular extends chain: %s -> %s", w.ID(), strings.Join(chain, " -> "), w.Extends)
+		}
+		return w, fmt.Errorf("workflow %s extends %s, which does not exist", w.ID(), w.Extends)
+	}
+
+	base, err := r.resolveWorkflow(*parent, chain)
+	if err != nil {
+		return w, err
+	}
+
+	return ExtendWorkflow(base, w), nil
+}
+
+// ExtendWorkflow applies the stages of the child to the parent workflow.
+// Stages and actions are matched by name / id, they can be removed (remove: true), overridden (rules, config) or added.
+func ExtendWorkflow(parent Workflow, child Workflow) Workflow {
+	result := Workflow{
+		Repository:  child.Repository,
+		Name:        child.Name,
+		Description: child.Description,
+		Version:     child.Version,
+		Rules:       child.Rules,
+		Stages:      cloneStages(parent.Stages),
+	}
+	if result.Description == "" {
+		result.Description = parent.Description
+	}
+	if len(result.Rules) == 0 {
+		result.Rules = parent.Rules
+	}
+
+	for _, stage := range child.Stages {
+		index := slices.IndexFunc(result.Stages, func(s WorkflowStage) bool { return s.Name == stage.Name })
+		if stage.Remove {
+			if index >= 0 {
+				result.Stages = slices.Delete(result.Stages, index, index+1)
+			}
+			continue
+		}
+		if index < 0 {
+			result.Stages = append(result.Stages, stage)
+			continue
+		}
+
+		target := &result.Stages[index]
+		if len(stage.Rules) > 0 {
+			target.Rules = stage.Rules
+		}
+		target.Actions = extendActions(target.Actions, stage.Actions)
+	}
+
+	return result
+}
+
+func extendActions(actions []WorkflowAction, changes []WorkflowAction) []WorkflowAction {
+	for _, change := range changes {
+		index := slices.IndexFunc(actions, func(a WorkflowAction) bool { return a.ID == change.ID })
+		if change.Remove {
+			if index >= 0 {
+				actions = slices.Delete(actions, index, index+1)
+			}
+			continue
+		}
+		if index < 0 {
+			actions = append(actions, change)
+			continue
+		}
+
+		if len(change.Rules) > 0 {
+			actions[index].Rules = change.Rules
+		}
+		if change.Config != nil {
+			actions[index].Config = change.Config
+		}
+		if len(change.Matrix) > 0 {
+			actions[index].Matrix = change.Matrix
+		}
+		if change.Approval != nil {
+			actions[index].Approval = change.Approval
+		}
+	}
+
+	return actions
+}
+
+func cloneStages(stages []WorkflowStage) []WorkflowStage {
+	result := make([]WorkflowStage, len(stages))
+	for i, stage := range stages {
+		result[i] = stage
+		result[i].Actions = slices.Clone(stage.Actions)
+	}
+
+	return result
+}
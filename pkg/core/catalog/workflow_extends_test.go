@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/cidverse/go-ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkflowRegistry() Config {
+	return Config{
+		Workflows: []Workflow{
+			{
+				Repository: "builtin",
+				Name:       "main",
+				Version:    "1.0.0",
+				Stages: []WorkflowStage{
+					{Name: "build", Actions: []WorkflowAction{{ID: "builtin://actions/go-build"}, {ID: "builtin://actions/gradle-build"}}},
+					{Name: "test", Actions: []WorkflowAction{{ID: "builtin://actions/go-test"}}},
+					{Name: "deploy", Actions: []WorkflowAction{{ID: "builtin://actions/helm-deploy"}}},
+				},
+			},
+		},
+	}
+}
+
+func TestParseWorkflowID(t *testing.T) {
+	repo, name, version, err := ParseWorkflowID("my-org/main-v2@1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, "my-org", repo)
+	assert.Equal(t, "main-v2", name)
+	assert.Equal(t, "1.2.3", version)
+
+	repo, name, version, err = ParseWorkflowID("main")
+	require.NoError(t, err)
+	assert.Equal(t, "", repo)
+	assert.Equal(t, "main", name)
+	assert.Equal(t, "", version)
+
+	_, _, _, err = ParseWorkflowID("a/b/c")
+	assert.Error(t, err)
+}
+
+func TestFindWorkflow(t *testing.T) {
+	cfg := testWorkflowRegistry()
+
+	assert.NotNil(t, cfg.FindWorkflow("main"))
+	assert.NotNil(t, cfg.FindWorkflow("builtin/main"))
+	assert.NotNil(t, cfg.FindWorkflow("builtin/main@1.0.0"))
+	assert.Nil(t, cfg.FindWorkflow("builtin/main@2.0.0"))
+	assert.Nil(t, cfg.FindWorkflow("myorg/main"))
+}
+
+func TestResolveWorkflowsExtends(t *testing.T) {
+	cfg := testWorkflowRegistry()
+	cfg.Workflows = append([]Workflow{{
+		Repository: ProjectRepository,
+		Name:       "main",
+		Extends:    "main",
+		Stages: []WorkflowStage{
+			{Name: "build", Actions: []WorkflowAction{
+				{ID: "builtin://actions/gradle-build", Remove: true},
+				{ID: "builtin://actions/go-build", Config: map[string]string{"platform": "linux/amd64"}},
+				{ID: "myorg://actions/license-check"},
+			}},
+			{Name: "deploy", Remove: true},
+			{Name: "compliance", Actions: []WorkflowAction{{ID: "myorg://actions/sbom-upload"}}},
+		},
+	}}, cfg.Workflows...)
+
+	require.NoError(t, cfg.ResolveWorkflows())
+	wf := cfg.FindWorkflow("project/main")
+	require.NotNil(t, wf)
+	require.Len(t, wf.Stages, 3)
+	assert.Equal(t, "build", wf.Stages[0].Name)
+	assert.Equal(t, []WorkflowAction{
+		{ID: "builtin://actions/go-build", Config: map[string]string{"platform": "linux/amd64"}},
+		{ID: "myorg://actions/license-check"},
+	}, wf.Stages[0].Actions)
+	assert.Equal(t, "test", wf.Stages[1].Name)
+	assert.Equal(t, "compliance", wf.Stages[2].Name)
+
+	// the parent workflow is not modified
+	parent := cfg.FindWorkflow("builtin/main")
+	assert.Len(t, parent.Stages, 3)
+	assert.Len(t, parent.Stages[0].Actions, 2)
+}
+
+func TestResolveWorkflowsExtendsApproval(t *testing.T) {
+	cfg := testWorkflowRegistry()
+	cfg.Workflows = append([]Workflow{{
+		Repository: ProjectRepository,
+		Name:       "main",
+		Extends:    "main",
+		Stages: []WorkflowStage{
+			{Name: "deploy", Actions: []WorkflowAction{{ID: "builtin://actions/helm-deploy", Approval: ptr.False()}}},
+		},
+	}}, cfg.Workflows...)
+
+	require.NoError(t, cfg.ResolveWorkflows())
+	wf := cfg.FindWorkflow("project/main")
+	require.NotNil(t, wf)
+	assert.Equal(t, []WorkflowAction{{ID: "builtin://actions/helm-deploy", Approval: ptr.False()}}, wf.Stages[2].Actions)
+
+	// the parent workflow keeps the default
+	assert.Nil(t, cfg.FindWorkflow("builtin/main").Stages[2].Actions[0].Approval)
+}
+
+func TestResolveWorkflowsErrors(t *testing.T) {
+	cfg := testWorkflowRegistry()
+	cfg.Workflows = append(cfg.Workflows,
+		Workflow{Repository: "myorg", Name: "missing", Extends: "myorg/unknown"},
+		Workflow{Repository: "myorg", Name: "a", Extends: "myorg/b"},
+		Workflow{Repository: "myorg", Name: "b", Extends: "myorg/a"},
+	)
+
+	err := cfg.ResolveWorkflows()
+	assert.ErrorContains(t, err, "extends myorg/unknown, which does not exist")
+	assert.ErrorContains(t, err, "circular extends chain")
+	assert.Len(t, cfg.Workflows, 1)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+)
+
+// ScriptActionURIPrefix is the uri prefix of script actions declared in the project configuration, e.g. script://actions/schema-generate
+const ScriptActionURIPrefix = "script://actions/"
+
+// ProjectAction is a script action declared in the project configuration, for one-off steps that don't warrant a catalog action
+type ProjectAction struct {
+	Name        string                             `required:"true" yaml:"name"`
+	Description string                             `yaml:"description,omitempty"`
+	Scope       actionsdk.ActionScope              `yaml:"scope,omitempty"`       // Scope is project (default) or module, module scoped scripts run once per module in the module directory
+	Rules       []catalog.WorkflowRule             `yaml:"rules,omitempty"`       // Rules define conditions that must be met for the action to be executed
+	Executables []actionsdk.ActionAccessExecutable `yaml:"executables,omitempty"` // Executables that the script may invoke, with an optional version constraint
+	Env         []actionsdk.ActionAccessEnv        `yaml:"env,omitempty"`         // Env holds the environment variables that the script may access
+	Inputs      []actionsdk.ActionArtifactType     `yaml:"inputs,omitempty"`      // Inputs are artifacts of previous steps, they are downloaded into CID_INPUT_DIR
+	Outputs     []catalog.ScriptOutput             `yaml:"outputs,omitempty"`     // Outputs are files that are uploaded as artifacts after the script completed
+	Run         []string                           `required:"true" yaml:"run"`   // Run holds the commands of the script, executed in order - one command per entry, split on spaces without shell syntax
+}
+
+// CatalogAction converts the project action into a catalog action of the project repository, it can be referenced as project/<name>
+func (a ProjectAction) CatalogAction() catalog.Action {
+	scope := a.Scope
+	if scope == "" {
+		scope = actionsdk.ActionScopeProject
+	}
+
+	var outputs []actionsdk.ActionArtifactType
+	for _, o := range a.Outputs {
+		outputs = append(outputs, actionsdk.ActionArtifactType{Type: o.Type, Format: o.Format, FormatVersion: o.FormatVersion})
+	}
+
+	return catalog.Action{
+		Repository: catalog.ProjectRepository,
+		URI:        ScriptActionURIPrefix + a.Name,
+		Type:       catalog.ActionTypeScript,
+		Script: catalog.ScriptAction{
+			Run:     a.Run,
+			Outputs: a.Outputs,
+		},
+		Metadata: catalog.ActionMetadata{
+			Name:        a.Name,
+			Description: a.Description,
+			Category:    "script",
+			Scope:       scope,
+			Rules:       a.Rules,
+			Access: actionsdk.ActionAccess{
+				Environment: a.Env,
+				Executables: a.Executables,
+			},
+			Input:  actionsdk.ActionInput{Artifacts: a.Inputs},
+			Output: actionsdk.ActionOutput{Artifacts: outputs},
+		},
+	}
+}
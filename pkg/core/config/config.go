@@ -2,14 +2,18 @@ package config
 
 import (
 	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 
 	"github.com/cidverse/cid/pkg/builtin/builtincatalog"
 
 	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/util"
 	"github.com/cidverse/cidverseutils/filesystem"
 	"github.com/jinzhu/configor"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/yaml.v3"
 )
 
 //go:embed files
@@ -28,21 +32,39 @@ func LoadConfigurationFile(config interface{}, file string) (err error) {
 
 var Current = CIDConfig{}
 
-func LoadConfig(projectDirectory string) *CIDConfig {
-	cfg := CIDConfig{}
+// EnvironmentVariable selects the environment overlay of the project configuration, e.g. production loads cid.production.yml
+const EnvironmentVariable = "CID_ENVIRONMENT"
 
-	// defaults
-	unmarshalNoError("files/cid-main.yaml", yaml.Unmarshal([]byte(getEmbeddedConfig("files/cid-main.yaml")), &cfg))
-	unmarshalNoError("files/cid-tools.yaml", yaml.Unmarshal([]byte(getEmbeddedConfig("files/cid-tools.yaml")), &cfg))
+func LoadConfig(projectDirectory string) (*CIDConfig, error) {
+	cfg, _, err := LoadLayeredConfig(projectDirectory)
+	return cfg, err
+}
+
+// LoadLayeredConfig loads the configuration and returns the merged layers, which hold the origin of each value - an error is returned if a workflow can't be resolved
+func LoadLayeredConfig(projectDirectory string) (*CIDConfig, *MergedLayers, error) {
+	cfg := CIDConfig{}
 
 	// default os cache dir
 	catalogSources := catalog.LoadSources()
-	cfg.CatalogSources = catalogSources
 	data := catalog.LoadCatalogs(catalogSources)
-	log.Debug().Int("catalogs", len(cfg.CatalogSources)).Int("action", len(data.Actions)).Int("workflows", len(data.Workflows)).Int("executables", len(data.Executables)).Msg("loaded catalog from registries")
+	log.Debug().Int("catalogs", len(catalogSources)).Int("action", len(data.Actions)).Int("workflows", len(data.Workflows)).Int("executables", len(data.Executables)).Msg("loaded catalog from registries")
+
+	// merge embedded defaults, org, user, project and environment configuration
+	merged := MergeLayers(ConfigLayers(projectDirectory, data.SourceConfigs))
+	if err := merged.Decode(&cfg); err != nil {
+		log.Err(err).Msg("failed to parse config")
+	}
+
+	// defaults and environment variables
+	if err := configor.New(&configor.Config{ENVPrefix: "CID", Silent: true}).Load(&cfg); err != nil {
+		log.Warn().Msg("failed to load configuration > " + err.Error())
+	}
 
+	cfg.CatalogSources = catalogSources
+	for _, a := range cfg.Actions {
+		cfg.Registry.Actions = append(cfg.Registry.Actions, a.CatalogAction())
+	}
 	cfg.Registry.Actions = append(cfg.Registry.Actions, data.Actions...)
-	//cfg.Registry.Workflows = append(cfg.Registry.Workflows, data.Workflows...)
 	cfg.Registry.Executables = append(cfg.Registry.Executables, data.Executables...)
 
 	// internal catalog
@@ -51,20 +73,79 @@ func LoadConfig(projectDirectory string) *CIDConfig {
 	cfg.Registry.Workflows = append(cfg.Registry.Workflows, internalCatalog.Workflows...)
 	cfg.Registry.Executables = append(cfg.Registry.Executables, internalCatalog.Executables...)
 
-	// load project config
-	if filesystem.FileExists(projectDirectory + "/cid.yml") {
-		loadConfigErr := LoadConfigurationFile(&cfg, projectDirectory+"/cid.yml")
-		if loadConfigErr != nil {
-			log.Err(loadConfigErr).Msg("failed to parse config")
-		}
-	}
+	// catalog workflows are placed after the builtin workflows, they are only used if selected by reference or extended
+	cfg.Registry.Workflows = append(cfg.Registry.Workflows, data.Workflows...)
 
 	if cfg.Dependencies == nil {
 		cfg.Dependencies = make(map[string]string)
 	}
 
+	// project workflows are placed first, a reference to e.g. main selects the project workflow over the builtin one
+	cfg.Registry.Workflows = append(projectWorkflows(cfg.Workflows), cfg.Registry.Workflows...)
+	if err := cfg.Registry.ResolveWorkflows(); err != nil {
+		return &cfg, merged, fmt.Errorf("failed to resolve workflows: %w", err)
+	}
+
 	Current = cfg
-	return &cfg
+	return &cfg, merged, nil
+}
+
+// ConfigLayers returns the configuration layers ordered by precedence: embedded defaults, org config of the catalog sources, user config, project config and the environment overlay of the project config
+func ConfigLayers(projectDirectory string, sourceConfigs []catalog.SourceConfig) []Layer {
+	var layers []Layer
+
+	// defaults
+	for _, file := range []string{"files/cid-main.yaml", "files/cid-tools.yaml"} {
+		layer, err := ParseLayer("embedded:"+file, []byte(getEmbeddedConfig(file)))
+		unmarshalNoError(file, err)
+		layers = append(layers, layer)
+	}
+
+	// org
+	for _, sc := range sourceConfigs {
+		layer, err := ParseLayer("catalog:"+sc.Source, []byte(sc.Config))
+		if err != nil {
+			log.Warn().Err(err).Str("catalog", sc.Source).Msg("failed to load org config of catalog source")
+			continue
+		}
+		layers = append(layers, layer)
+	}
+
+	// user and project
+	files := []string{UserConfigFile(), filepath.Join(projectDirectory, "cid.yml")}
+	if env := os.Getenv(EnvironmentVariable); env != "" {
+		files = append(files, filepath.Join(projectDirectory, "cid."+env+".yml"))
+	}
+	for _, file := range files {
+		if !filesystem.FileExists(file) {
+			continue
+		}
+
+		log.Debug().Str("file", file).Msg("loading configuration file ...")
+		layer, err := ParseLayerFile(file)
+		if err != nil {
+			log.Err(err).Str("file", file).Msg("failed to parse config")
+			continue
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers
+}
+
+// UserConfigFile returns the path of the user configuration, e.g. ~/.config/cid/config.yml
+func UserConfigFile() string {
+	return filepath.Join(util.CIDConfigDir(), "config.yml")
+}
+
+// projectWorkflows returns the workflows of the project configuration, they are part of the project repository
+func projectWorkflows(workflows []catalog.Workflow) []catalog.Workflow {
+	result := slices.Clone(workflows)
+	for i := range result {
+		result[i].Repository = catalog.ProjectRepository
+	}
+
+	return result
 }
 
 func unmarshalNoError(file string, err error) {
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"encoding/json"
+	"slices"
 	"testing"
 
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/lib/jsonschema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,3 +22,93 @@ func TestEmbeddedConfigValid(t *testing.T) {
 	err = yaml.Unmarshal([]byte(getEmbeddedConfig("files/cid-tools.yaml")), &cfg)
 	assert.NoError(t, err)
 }
+
+func TestEmbeddedConfigMatchesSchema(t *testing.T) {
+	s := ConfigSchema()
+	for _, file := range []string{"files/cid-main.yaml", "files/cid-tools.yaml"} {
+		errs, err := jsonschema.ValidateYAML(s, []byte(getEmbeddedConfig(file)))
+		assert.NoError(t, err)
+		assert.Empty(t, errs, file)
+	}
+}
+
+func TestConfigSchemaActionConfig(t *testing.T) {
+	errs, err := jsonschema.ValidateYAML(ConfigSchema(), []byte(`conventions:
+  branching: GitFlow
+  comit: Gitmoji
+workflows:
+  - name: main
+    extends: builtin/main
+    stages:
+      - name: build
+        actions:
+          - id: builtin://actions/changelog-generate
+            config:
+              templates: [github.changelog]
+              issue_prefx: "#"
+`))
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "3:3: conventions.comit: unknown property comit, did you mean commit?", errs[0].Error())
+	assert.Equal(t, "13:15: workflows[0].stages[0].actions[0].config.issue_prefx: unknown property issue_prefx, did you mean issue_prefix?", errs[1].Error())
+}
+
+func TestProjectActionCatalogAction(t *testing.T) {
+	var cfg CIDConfig
+	err := yaml.Unmarshal([]byte(`
+actions:
+  - name: schema-generate
+    executables:
+      - name: go
+        constraint: ">= 1.22"
+    outputs:
+      - path: dist/schema.json
+        type: report
+        format: json-schema
+    run:
+      - go run ./cmd/schema dist/schema.json
+`), &cfg)
+	assert.NoError(t, err)
+
+	registry := catalog.Config{Actions: []catalog.Action{cfg.Actions[0].CatalogAction()}}
+	action := registry.FindAction("project/schema-generate")
+	assert.NotNil(t, action)
+	assert.Equal(t, "script://actions/schema-generate", action.URI)
+	assert.Equal(t, catalog.ActionTypeScript, action.Type)
+	assert.Equal(t, actionsdk.ActionScopeProject, action.Metadata.Scope)
+	assert.Equal(t, []actionsdk.ActionAccessExecutable{{Name: "go", Constraint: ">= 1.22"}}, action.Metadata.Access.Executables)
+	assert.Equal(t, []actionsdk.ActionArtifactType{{Type: "report", Format: "json-schema"}}, action.Metadata.Output.Artifacts)
+	assert.Equal(t, []string{"go run ./cmd/schema dist/schema.json"}, action.Script.Run)
+}
+
+func TestConfigLayersOrgAppend(t *testing.T) {
+	var catalogCfg catalog.Config
+	require.NoError(t, yaml.Unmarshal([]byte("config:\n  tools: !append [java]\n"), &catalogCfg))
+
+	// the catalog is cached as json
+	content, err := json.Marshal(catalogCfg)
+	require.NoError(t, err)
+	var cached catalog.Config
+	require.NoError(t, json.Unmarshal(content, &cached))
+
+	layers := ConfigLayers(t.TempDir(), []catalog.SourceConfig{{Source: "org", Config: cached.Config}})
+	index := slices.IndexFunc(layers, func(l Layer) bool { return l.Origin == "catalog:org" })
+	require.GreaterOrEqual(t, index, 0)
+
+	base, err := ParseLayer("defaults", []byte("tools: [go]\n"))
+	require.NoError(t, err)
+	var cfg layerTestConfig
+	require.NoError(t, MergeLayers([]Layer{base, layers[index]}).Decode(&cfg))
+	assert.Equal(t, []string{"go", "java"}, cfg.Tools)
+}
+
+func TestOrgConfigJSONCatalog(t *testing.T) {
+	var catalogCfg catalog.Config
+	require.NoError(t, json.Unmarshal([]byte(`{"config": {"tools": ["java"]}}`), &catalogCfg))
+
+	layer, err := ParseLayer("catalog:org", []byte(catalogCfg.Config))
+	require.NoError(t, err)
+	var cfg layerTestConfig
+	require.NoError(t, MergeLayers([]Layer{layer}).Decode(&cfg))
+	assert.Equal(t, []string{"java"}, cfg.Tools)
+}
@@ -1,18 +1,32 @@
 package config
 
+import (
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
+)
+
 type ProjectConventions struct {
-	Branching BranchingConventionType `default:"GitFlow"`
-	Commit    CommitConventionType    `default:"ConventionalCommits"`
+	Branching     BranchingConventionType `default:"GitFlow"`
+	Commit        CommitConventionType    `default:"ConventionalCommits"`
+	CommitPattern string                  `yaml:"commit-pattern,omitempty"` // CommitPattern is a custom regex with the named groups type, scope, breaking, subject and issue, replaces the pattern of the commit convention
 }
 
 type BranchingConventionType string
 
 const (
-	BranchingGitFlow BranchingConventionType = "GitFlow"
+	BranchingGitFlow    BranchingConventionType = "GitFlow"
+	BranchingGitHubFlow BranchingConventionType = "GitHubFlow"
+	BranchingTrunkBased BranchingConventionType = "TrunkBased"
 )
 
 type CommitConventionType string
 
 const (
-	ConventionalCommits CommitConventionType = "ConventionalCommits"
+	ConventionalCommits CommitConventionType = commitanalyser.ConventionConventionalCommits
+	Gitmoji             CommitConventionType = commitanalyser.ConventionGitmoji
+	JiraCommits         CommitConventionType = commitanalyser.ConventionJira
 )
+
+// CommitPatterns returns the commit patterns of the commit convention
+func (c ProjectConventions) CommitPatterns() []string {
+	return commitanalyser.CommitPatterns(string(c.Commit), c.CommitPattern)
+}
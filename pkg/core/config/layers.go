@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// merge tags change how a value is merged into the value of the previous layers, e.g. `workflows: !append`
+const (
+	MergeTagReplace = "!replace" // MergeTagReplace replaces the previous value, default for scalars and sequences
+	MergeTagAppend  = "!append"  // MergeTagAppend appends the items of a sequence to the previous items
+	MergeTagMerge   = "!merge"   // MergeTagMerge merges mappings key by key (default for mappings), sequence items are merged by their name or id
+)
+
+// OriginDefault is the origin of values set by struct defaults
+const OriginDefault = "default"
+
+// Layer is a configuration source, layers are merged in order and later layers take precedence
+type Layer struct {
+	Origin string     // Origin describes the source of the layer, e.g. the file path
+	Node   *yaml.Node // Node is the root mapping of the layer
+}
+
+// MergedLayers is the result of merging all layers
+type MergedLayers struct {
+	Layers []Layer
+	Root   *yaml.Node
+	origin map[*yaml.Node]string
+}
+
+// ParseLayer parses the yaml content of a layer, an empty document results in an empty mapping
+func ParseLayer(origin string, content []byte) (Layer, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return Layer{}, fmt.Errorf("failed to parse config layer %s: %w", origin, err)
+	}
+	if len(doc.Content) == 0 {
+		return Layer{Origin: origin, Node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}}, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return Layer{}, fmt.Errorf("config layer %s must be a mapping", origin)
+	}
+
+	return Layer{Origin: origin, Node: doc.Content[0]}, nil
+}
+
+// ParseLayerFile parses a layer from a file, the file path is used as origin
+func ParseLayerFile(file string) (Layer, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return Layer{}, fmt.Errorf("failed to read config layer %s: %w", file, err)
+	}
+
+	return ParseLayer(file, content)
+}
+
+// MergeLayers merges the layers in order, the merge tags are removed from the result
+func MergeLayers(layers []Layer) *MergedLayers {
+	m := &MergedLayers{Layers: layers, Root: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, origin: make(map[*yaml.Node]string)}
+	for _, layer := range layers {
+		node := cloneNode(layer.Node)
+		m.trackOrigin(node, layer.Origin)
+		m.Root = m.merge(m.Root, node, MergeTagMerge)
+	}
+	stripMergeTags(m.Root)
+
+	return m
+}
+
+// Decode decodes the merged configuration into the target struct
+func (m *MergedLayers) Decode(target any) error {
+	return m.Root.Decode(target)
+}
+
+// Origins returns the origin of each value by path, e.g. paths.artifact or localtools[0].binary[0]
+func (m *MergedLayers) Origins() map[string]string {
+	origins := make(map[string]string)
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], joinConfigPath(path, node.Content[i].Value))
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, path+"["+strconv.Itoa(i)+"]")
+			}
+		}
+		if origin, ok := m.origin[node]; ok && path != "" {
+			origins[path] = origin
+		}
+	}
+	walk(m.Root, "")
+
+	return origins
+}
+
+func (m *MergedLayers) trackOrigin(node *yaml.Node, origin string) {
+	m.origin[node] = origin
+	for _, child := range node.Content {
+		m.trackOrigin(child, origin)
+	}
+}
+
+func (m *MergedLayers) merge(base *yaml.Node, overlay *yaml.Node, strategy string) *yaml.Node {
+	if overlay.Tag == MergeTagReplace || overlay.Tag == MergeTagAppend || overlay.Tag == MergeTagMerge {
+		strategy = overlay.Tag
+	}
+	if base == nil || base.Kind != overlay.Kind || strategy == MergeTagReplace {
+		return overlay
+	}
+
+	switch {
+	case overlay.Kind == yaml.MappingNode:
+		return m.mergeMapping(base, overlay)
+	case overlay.Kind == yaml.SequenceNode && strategy == MergeTagAppend:
+		result := *base
+		result.Content = append(slices.Clone(base.Content), overlay.Content...)
+		m.origin[&result] = m.origin[base]
+		return &result
+	case overlay.Kind == yaml.SequenceNode && strategy == MergeTagMerge && overlay.Tag == MergeTagMerge:
+		return m.mergeSequence(base, overlay)
+	default:
+		return overlay
+	}
+}
+
+// mergeMapping merges the keys of the overlay into a copy of the base mapping
+func (m *MergedLayers) mergeMapping(base *yaml.Node, overlay *yaml.Node) *yaml.Node {
+	result := *base
+	result.Content = slices.Clone(base.Content)
+	m.origin[&result] = m.origin[base]
+
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		index := mappingIndex(&result, key.Value)
+		if index < 0 {
+			result.Content = append(result.Content, key, value)
+			continue
+		}
+
+		result.Content[index+1] = m.merge(result.Content[index+1], value, MergeTagMerge)
+	}
+
+	return &result
+}
+
+// mergeSequence merges sequence items with the same name or id, other items are appended
+func (m *MergedLayers) mergeSequence(base *yaml.Node, overlay *yaml.Node) *yaml.Node {
+	result := *base
+	result.Content = slices.Clone(base.Content)
+	m.origin[&result] = m.origin[base]
+
+	for _, item := range overlay.Content {
+		index := slices.IndexFunc(result.Content, func(n *yaml.Node) bool {
+			id := sequenceItemID(item)
+			return id != "" && sequenceItemID(n) == id
+		})
+		if index < 0 {
+			result.Content = append(result.Content, item)
+			continue
+		}
+
+		result.Content[index] = m.merge(result.Content[index], item, MergeTagMerge)
+	}
+
+	return &result
+}
+
+// sequenceItemID returns the identity of a sequence item, the name or id of a mapping
+func sequenceItemID(node *yaml.Node) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for _, key := range []string{"name", "id"} {
+		if index := mappingIndex(node, key); index >= 0 {
+			return key + "=" + node.Content[index+1].Value
+		}
+	}
+
+	return ""
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// cloneNode returns a deep copy of a node, the merge result must not share nodes with the layers
+func cloneNode(node *yaml.Node) *yaml.Node {
+	result := *node
+	result.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		result.Content[i] = cloneNode(child)
+	}
+
+	return &result
+}
+
+// stripMergeTags removes the merge tags, the tag of scalars is resolved from the value again
+func stripMergeTags(node *yaml.Node) {
+	if node.Tag == MergeTagReplace || node.Tag == MergeTagAppend || node.Tag == MergeTagMerge {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		stripMergeTags(child)
+	}
+}
+
+// AnnotateOrigins sets the origin of each value as line comment, values without a known origin are annotated using the environment variable that can set them or as default
+func AnnotateOrigins(node *yaml.Node, origins map[string]string, envPrefix string) {
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				node.Content[i].HeadComment, node.Content[i].LineComment, node.Content[i].FootComment = "", "", ""
+				walk(node.Content[i+1], joinConfigPath(path, node.Content[i].Value))
+			}
+			return
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, path+"["+strconv.Itoa(i)+"]")
+			}
+			return
+		}
+
+		if env := configEnvName(envPrefix, path); env != "" && os.Getenv(env) != "" {
+			node.LineComment = "env:" + env
+		} else if origin, ok := origins[path]; ok {
+			node.LineComment = origin
+		} else {
+			node.LineComment = OriginDefault
+		}
+	}
+	walk(node, "")
+}
+
+// configEnvName returns the environment variable that overrides a value, e.g. CID_PATHS_ARTIFACT for paths.artifact - values within sequences can't be set by environment variables
+func configEnvName(prefix string, path string) string {
+	if prefix == "" || strings.Contains(path, "[") {
+		return ""
+	}
+
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(strings.NewReplacer("-", "", "_", "").Replace(part))
+	}
+	return prefix + "_" + strings.Join(parts, "_")
+}
+
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
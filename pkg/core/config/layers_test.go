@@ -0,0 +1,109 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type layerTestConfig struct {
+	Paths     map[string]string `yaml:"paths"`
+	Tools     []string          `yaml:"tools"`
+	Workflows []struct {
+		Name   string   `yaml:"name"`
+		Stages []string `yaml:"stages"`
+	} `yaml:"workflows"`
+}
+
+func mergeTestLayers(t *testing.T, layers ...string) (*MergedLayers, layerTestConfig) {
+	var parsed []Layer
+	for i, content := range layers {
+		layer, err := ParseLayer([]string{"defaults", "org", "user", "project"}[i], []byte(content))
+		require.NoError(t, err)
+		parsed = append(parsed, layer)
+	}
+
+	merged := MergeLayers(parsed)
+	var cfg layerTestConfig
+	require.NoError(t, merged.Decode(&cfg))
+	return merged, cfg
+}
+
+func TestMergeLayersDefaults(t *testing.T) {
+	merged, cfg := mergeTestLayers(t,
+		"paths:\n  artifact: dist\n  temp: .tmp\ntools: [go, java]\n",
+		"paths:\n  artifact: out\ntools: [node]\n",
+	)
+
+	// mappings are merged, sequences are replaced
+	assert.Equal(t, map[string]string{"artifact": "out", "temp": ".tmp"}, cfg.Paths)
+	assert.Equal(t, []string{"node"}, cfg.Tools)
+
+	origins := merged.Origins()
+	assert.Equal(t, "org", origins["paths.artifact"])
+	assert.Equal(t, "defaults", origins["paths.temp"])
+	assert.Equal(t, "org", origins["tools[0]"])
+}
+
+func TestMergeLayersTags(t *testing.T) {
+	merged, cfg := mergeTestLayers(t,
+		"paths:\n  artifact: dist\n  temp: .tmp\ntools: [go]\nworkflows:\n  - name: main\n    stages: [build]\n  - name: release\n    stages: [publish]\n",
+		"paths: !replace\n  artifact: out\n",
+		"tools: !append [java]\n",
+		"workflows: !merge\n  - name: main\n    stages: !append [lint]\n  - name: nightly\n    stages: [scan]\n",
+	)
+
+	assert.Equal(t, map[string]string{"artifact": "out"}, cfg.Paths)
+	assert.Equal(t, []string{"go", "java"}, cfg.Tools)
+	require.Len(t, cfg.Workflows, 3)
+	assert.Equal(t, "main", cfg.Workflows[0].Name)
+	assert.Equal(t, []string{"build", "lint"}, cfg.Workflows[0].Stages)
+	assert.Equal(t, "release", cfg.Workflows[1].Name)
+	assert.Equal(t, "nightly", cfg.Workflows[2].Name)
+
+	origins := merged.Origins()
+	assert.Equal(t, "defaults", origins["tools[0]"])
+	assert.Equal(t, "user", origins["tools[1]"])
+	assert.Equal(t, "defaults", origins["workflows[0].stages[0]"])
+	assert.Equal(t, "project", origins["workflows[0].stages[1]"])
+	assert.Equal(t, "project", origins["workflows[2].name"])
+}
+
+func TestMergeLayersKeepsInput(t *testing.T) {
+	base, err := ParseLayer("defaults", []byte("tools: [go]\n"))
+	require.NoError(t, err)
+	overlay, err := ParseLayer("project", []byte("tools: !append [java]\n"))
+	require.NoError(t, err)
+
+	for range 2 {
+		var cfg layerTestConfig
+		require.NoError(t, MergeLayers([]Layer{base, overlay}).Decode(&cfg))
+		assert.Equal(t, []string{"go", "java"}, cfg.Tools)
+	}
+	assert.Len(t, base.Node.Content[1].Content, 1)
+	assert.Equal(t, MergeTagAppend, overlay.Node.Content[1].Tag)
+}
+
+func TestAnnotateOrigins(t *testing.T) {
+	t.Setenv("CID_PATHS_TEMP", "/tmp/cid")
+	merged, _ := mergeTestLayers(t, "paths:\n  artifact: dist\n")
+
+	var node yaml.Node
+	require.NoError(t, node.Encode(map[string]any{"paths": map[string]string{"artifact": "dist", "temp": "/tmp/cid"}, "env": map[string]string{"A": "b"}}))
+	AnnotateOrigins(&node, merged.Origins(), "CID")
+
+	out, err := yaml.Marshal(&node)
+	require.NoError(t, err)
+	assert.Equal(t, "env:\n    A: b # default\npaths:\n    artifact: dist # defaults\n    temp: /tmp/cid # env:CID_PATHS_TEMP\n", string(out))
+}
+
+func TestParseLayerInvalid(t *testing.T) {
+	_, err := ParseLayer("project", []byte("- a\n- b\n"))
+	assert.Error(t, err)
+
+	layer, err := ParseLayer("project", []byte(""))
+	assert.NoError(t, err)
+	assert.Equal(t, yaml.MappingNode, layer.Node.Kind)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/cidverse/cid/pkg/builtin/builtinaction"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/lib/jsonschema"
+)
+
+// schema file names, as written by `cid schema`
+const (
+	SchemaFileConfig   = "cid.schema.json"
+	SchemaFileCatalog  = "catalog.schema.json"
+	SchemaFileWorkflow = "workflow.schema.json"
+)
+
+// SchemaBaseURI is the base uri of the schema ids
+const SchemaBaseURI = "https://cidverse.github.io/cid/schema/"
+
+var yamlReflector = jsonschema.Reflector{TagName: "yaml"}
+
+// ConfigSchema returns the schema of the project configuration file (cid.yml), the config of builtin actions is validated based on the action id
+func ConfigSchema() *jsonschema.Schema {
+	s := yamlReflector.Reflect(reflect.TypeOf(CIDConfig{}))
+	s.ID = SchemaBaseURI + SchemaFileConfig
+	s.Title = "cid project configuration"
+	s.Properties["conventions"].Properties["branching"].Enum = []any{BranchingGitFlow, BranchingGitHubFlow, BranchingTrunkBased}
+	s.Properties["conventions"].Properties["commit"].Enum = []any{ConventionalCommits, Gitmoji, JiraCommits}
+	actionConfigs := ActionConfigSchemas()
+	addActionConfigSchemas(s.Properties["workflows"].Items, actionConfigs)
+	addActionConfigSchemas(s.Properties["registry"].Properties["workflows"].Items, actionConfigs)
+
+	return s
+}
+
+// CatalogSchema returns the schema of catalog files, containing actions, workflows and executables
+func CatalogSchema() *jsonschema.Schema {
+	s := yamlReflector.Reflect(reflect.TypeOf(catalog.Config{}))
+	s.ID = SchemaBaseURI + SchemaFileCatalog
+	s.Title = "cid catalog"
+	addActionConfigSchemas(s.Properties["workflows"].Items, ActionConfigSchemas())
+
+	return s
+}
+
+// WorkflowSchema returns the schema of a single workflow
+func WorkflowSchema() *jsonschema.Schema {
+	s := yamlReflector.Reflect(reflect.TypeOf(catalog.Workflow{}))
+	s.ID = SchemaBaseURI + SchemaFileWorkflow
+	s.Title = "cid workflow"
+	addActionConfigSchemas(s, ActionConfigSchemas())
+
+	return s
+}
+
+// ActionConfigSchemas returns the config schema of each builtin action, action configs are passed as json and use the json field names
+func ActionConfigSchemas() map[string]*jsonschema.Schema {
+	reflector := jsonschema.Reflector{TagName: "json"}
+	result := make(map[string]*jsonschema.Schema)
+	for name, configType := range builtinaction.GetConfigTypes() {
+		s := reflector.Reflect(configType)
+		s.ID = SchemaBaseURI + "actions/" + name + ".schema.json"
+		s.Title = name + " config"
+		result[name] = s
+	}
+
+	return result
+}
+
+// Schemas returns all schemas by file name
+func Schemas() map[string]*jsonschema.Schema {
+	result := map[string]*jsonschema.Schema{
+		SchemaFileConfig:   ConfigSchema(),
+		SchemaFileCatalog:  CatalogSchema(),
+		SchemaFileWorkflow: WorkflowSchema(),
+	}
+	for name, s := range ActionConfigSchemas() {
+		result["actions/"+name+".schema.json"] = s
+	}
+
+	return result
+}
+
+// ValidateConfigFile validates a configuration file (project, user or environment overlay), reporting unknown keys, type errors and invalid action configs
+func ValidateConfigFile(file string) ([]jsonschema.ValidationError, error) {
+	layer, err := ParseLayerFile(file)
+	if err != nil {
+		return nil, err
+	}
+	stripMergeTags(layer.Node)
+
+	return jsonschema.ValidateNode(ConfigSchema(), layer.Node, ""), nil
+}
+
+// addActionConfigSchemas adds the config schema of builtin actions to the actions of a workflow schema, matched by the action id
+func addActionConfigSchemas(workflow *jsonschema.Schema, configSchemas map[string]*jsonschema.Schema) {
+	action := workflow.Properties["stages"].Items.Properties["actions"].Items
+
+	for _, name := range slices.Sorted(maps.Keys(configSchemas)) {
+		configSchema := *configSchemas[name]
+		configSchema.Version, configSchema.ID = "", ""
+
+		action.AllOf = append(action.AllOf, &jsonschema.Schema{
+			If: &jsonschema.Schema{
+				Properties: map[string]*jsonschema.Schema{"id": {Enum: []any{"builtin://actions/" + name, "builtin/" + name}}},
+				Required:   []string{"id"},
+			},
+			Then: &jsonschema.Schema{
+				Properties: map[string]*jsonschema.Schema{"config": &configSchema},
+			},
+		})
+	}
+}
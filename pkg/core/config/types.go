@@ -33,6 +33,7 @@ type CIDConfig struct {
 
 	Paths       PathConfig
 	Conventions ProjectConventions
+	Versioning  VersioningConfig `yaml:"versioning,omitempty"`
 	Env         map[string]string
 
 	// Dependencies holds a key value map of required versions
@@ -41,6 +42,15 @@ type CIDConfig struct {
 	// LocalTools holds a list to lookup locally installed tools for command execution
 	LocalTools []PathDiscoveryRule `yaml:"localtools,omitempty"`
 
+	// Workflow selects the workflow by reference, e.g. myorg/main@1.0.0 - the workflow is selected by rules if empty
+	Workflow string `yaml:"workflow,omitempty"`
+
+	// Actions holds project-defined script actions, they can be used in workflows as project/<name>
+	Actions []ProjectAction `yaml:"actions,omitempty"`
+
+	// Workflows holds project-defined workflows, they can extend other workflows and are only used if selected by Workflow
+	Workflows []catalog.Workflow `yaml:"workflows,omitempty"`
+
 	// CatalogSources
 	CatalogSources map[string]*catalog.Source `yaml:"catalog_sources,omitempty"`
 
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/cidverse/cid/pkg/common/commitanalyser"
+)
+
+// VersioningConfig configures how the next release version is determinated from the commit history.
+// The version is printed by `cid version next` and provided to actions as NCI_NEXT_VERSION (branch builds) and NCI_MODULE_NEXT_VERSION (modules with module tags),
+// which is used by the build and publish actions of modules with independent versions and by changelog-generate to preview the next release.
+type VersioningConfig struct {
+	Rules    []commitanalyser.CommitVersionRule `yaml:"rules,omitempty"`    // Rules map commits to release types, defaults to commitanalyser.DefaultReleaseVersionRules
+	Channels []commitanalyser.ReleaseChannel    `yaml:"channels,omitempty"` // Channels publish pre-releases for matching branches, defaults depend on the branching convention
+}
+
+// ReleaseRules returns the configured release rules or the defaults
+func (c VersioningConfig) ReleaseRules() []commitanalyser.CommitVersionRule {
+	if len(c.Rules) > 0 {
+		return c.Rules
+	}
+	return commitanalyser.DefaultReleaseVersionRules
+}
+
+// ReleaseChannels returns the configured release channels or the defaults of the branching model.
+// GitFlow publishes pre-releases from release branches and develop, TrunkBased publishes patch releases from release line branches and GitHubFlow only publishes stable releases.
+func (c VersioningConfig) ReleaseChannels(branching BranchingConventionType) []commitanalyser.ReleaseChannel {
+	if len(c.Channels) > 0 {
+		return c.Channels
+	}
+
+	switch branching {
+	case BranchingGitHubFlow:
+		return nil
+	case BranchingTrunkBased:
+		return commitanalyser.TrunkBasedReleaseChannels
+	default:
+		return commitanalyser.DefaultReleaseChannels
+	}
+}
@@ -0,0 +1,267 @@
+package planexecute
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/internal/state"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/cid/pkg/util"
+	"github.com/rs/zerolog/log"
+)
+
+// ApprovalAll approves all gate steps of the plan
+const ApprovalAll = "all"
+
+type ApprovalSource string
+
+const (
+	ApprovalSourceFlag                ApprovalSource = "flag"
+	ApprovalSourceFile                ApprovalSource = "file"
+	ApprovalSourceEnv                 ApprovalSource = "env"    // CID_APPROVE, comma separated list of steps
+	ApprovalSourcePrompt              ApprovalSource = "prompt" // interactive confirmation on the terminal
+	ApprovalSourceGitHubEnvironment   ApprovalSource = "github-environment"
+	ApprovalSourceGitLabManualJob     ApprovalSource = "gitlab-manual-job"
+	ApprovalSourceGiteaManualRun      ApprovalSource = "gitea-manual-run" // the gate job only runs if the workflow has been dispatched with the gate
+	ApprovalSourceAzureEnvironment    ApprovalSource = "azure-environment"
+	ApprovalSourceBitbucketManualStep ApprovalSource = "bitbucket-manual-step"
+)
+
+var (
+	ErrApprovalRequired = errors.New("approval required")
+	ErrApprovalDenied   = errors.New("approval denied")
+)
+
+// Approval is the approval of a gate step
+type Approval struct {
+	Source   ApprovalSource
+	Approver string
+}
+
+// ApprovalPrompt asks the user to approve a step, returns true if the step has been approved
+type ApprovalPrompt func(message string) (bool, error)
+
+// ApprovalGateEnv is set to the slug of the gate step by generated workflows, the job of the gate is protected by the ci platform
+const ApprovalGateEnv = "CID_APPROVAL_GATE"
+
+// ResolveApproval checks if the gate step has been approved, in the order flag, file, environment, ci platform and interactive prompt
+//
+// Generated workflows protect the gate using the ci platform, e.g. an environment with required reviewers or a manual job, the platform approves the job before the gate runs.
+// The approval of the platform is only trusted for the job of the gate, other jobs fail with ErrApprovalRequired.
+// Approval files within the project directory are ignored in ci, as everyone who can push a branch could approve the gate.
+func ResolveApproval(planContext ExecuteContext, step plangenerate.Step) (Approval, error) {
+	currentUser := util.GetCurrentUser().Username
+
+	if approvedStep(planContext.Approvals, step) {
+		return Approval{Source: ApprovalSourceFlag, Approver: currentUser}, nil
+	}
+
+	if planContext.ApprovalFile != "" {
+		if isCI(planContext.Env) && isWithinDir(planContext.ProjectDir, planContext.ApprovalFile) {
+			log.Warn().Str("file", planContext.ApprovalFile).Msg("approval file is part of the repository, ignoring it in ci")
+		} else {
+			approvals, err := ReadApprovalFile(planContext.ApprovalFile)
+			if err != nil {
+				return Approval{}, err
+			}
+			if approvedStep(approvals, step) {
+				return Approval{Source: ApprovalSourceFile, Approver: currentUser}, nil
+			}
+		}
+	}
+
+	if approvedStep(splitApprovals(os.Getenv("CID_APPROVE")), step) {
+		return Approval{Source: ApprovalSourceEnv, Approver: currentUser}, nil
+	}
+
+	if approval, ok := platformApproval(step); ok {
+		return approval, nil
+	}
+
+	if planContext.Prompt != nil {
+		approved, err := planContext.Prompt(fmt.Sprintf("approve %s?", stepDisplayName(step)))
+		if err != nil {
+			return Approval{}, fmt.Errorf("failed to prompt for approval: %w", err)
+		} else if !approved {
+			return Approval{}, fmt.Errorf("%w: %s", ErrApprovalDenied, stepDisplayName(step))
+		}
+
+		return Approval{Source: ApprovalSourcePrompt, Approver: currentUser}, nil
+	}
+
+	return Approval{}, fmt.Errorf("%w: %s, approve using --approve %s", ErrApprovalRequired, stepDisplayName(step), step.Gates)
+}
+
+// ReadApprovalFile reads the approved steps from a file, one step slug per line - empty lines and comments starting with # are ignored
+func ReadApprovalFile(file string) ([]string, error) {
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read approval file %s: %w", file, err)
+	}
+
+	var approvals []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		approvals = append(approvals, line)
+	}
+
+	return approvals, nil
+}
+
+// TerminalPrompt returns a prompt reading the answer from the input, answers other than y or yes deny the approval
+func TerminalPrompt(in io.Reader, out io.Writer) ApprovalPrompt {
+	reader := bufio.NewReader(in)
+
+	return func(message string) (bool, error) {
+		_, _ = fmt.Fprintf(out, "%s [y/N]: ", message)
+		answer, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes", nil
+	}
+}
+
+// RunGate waits for the approval of the gate step and records it in the audit log of the step state
+func RunGate(planContext ExecuteContext, step plangenerate.Step) error {
+	log.Info().Str("step", step.Name).Str("gates", step.Gates).Str("environment", step.Environment).Msg("approval required")
+
+	approval, err := ResolveApproval(planContext, step)
+	if err != nil {
+		return err
+	}
+
+	artifactDir := filepath.Join(planContext.ProjectDir, ".dist")
+	localState := state.GetStateFromDirectory(artifactDir)
+	localState.Modules = planContext.Modules
+	localState.AuditLog = append(localState.AuditLog, state.AuditEvents{
+		Timestamp: time.Now(),
+		Type:      "approval",
+		Payload: map[string]string{
+			"step":        step.Gates,
+			"gate":        step.Slug,
+			"environment": step.Environment,
+			"source":      string(approval.Source),
+			"approver":    approval.Approver,
+		},
+	})
+
+	err = state.WriteStateFile(filepath.Join(artifactDir, step.Slug, "state.json"), localState)
+	if err != nil {
+		return fmt.Errorf("failed to record approval: %w", err)
+	}
+
+	log.Info().Str("step", step.Name).Str("source", string(approval.Source)).Str("approver", approval.Approver).Msg("step approved")
+	return nil
+}
+
+// requireApproval makes sure the step has been approved before it starts, the gate of the step runs first if the state has no approval yet - e.g. if the step has been selected directly
+func requireApproval(plan plangenerate.Plan, planContext ExecuteContext, step plangenerate.Step) error {
+	localState := state.GetStateFromDirectory(filepath.Join(planContext.ProjectDir, ".dist"))
+	if slices.ContainsFunc(localState.AuditLog, func(event state.AuditEvents) bool {
+		return event.Type == "approval" && event.Payload["step"] == step.Slug
+	}) {
+		return nil
+	}
+
+	i := slices.IndexFunc(plan.Steps, func(s plangenerate.Step) bool {
+		return s.IsGate() && s.Gates == step.Slug
+	})
+	if i < 0 {
+		return fmt.Errorf("%w: %s, the plan has no approval gate for the step", ErrApprovalRequired, step.Name)
+	}
+
+	return RunGate(planContext, plan.Steps[i])
+}
+
+// platformApproval returns the approval of the ci platform for the job of the gate
+func platformApproval(step plangenerate.Step) (Approval, bool) {
+	// the gate is a manual job, started by the approver
+	if os.Getenv("GITLAB_CI") == "true" {
+		if os.Getenv("CI_JOB_MANUAL") == "true" {
+			return Approval{Source: ApprovalSourceGitLabManualJob, Approver: os.Getenv("GITLAB_USER_LOGIN")}, true
+		}
+		return Approval{}, false
+	}
+
+	// all other platforms protect the job that sets the marker
+	if os.Getenv(ApprovalGateEnv) != step.Slug {
+		return Approval{}, false
+	}
+	switch {
+	case os.Getenv("GITEA_ACTIONS") == "true" || os.Getenv("FORGEJO_ACTIONS") == "true":
+		// the gate job only runs for dispatched workflows that list the gate, the actor dispatched the workflow
+		return Approval{Source: ApprovalSourceGiteaManualRun, Approver: os.Getenv("GITHUB_ACTOR")}, true
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		// the reviewer of the environment is not exposed to the workflow
+		return Approval{Source: ApprovalSourceGitHubEnvironment}, true
+	case strings.EqualFold(os.Getenv("TF_BUILD"), "true"):
+		// the deployment job waits for the approvals and checks of the environment
+		return Approval{Source: ApprovalSourceAzureEnvironment}, true
+	case os.Getenv("BITBUCKET_BUILD_NUMBER") != "":
+		// the gate is a manual step, started by the approver
+		return Approval{Source: ApprovalSourceBitbucketManualStep, Approver: os.Getenv("BITBUCKET_STEP_TRIGGERER_UUID")}, true
+	}
+
+	return Approval{}, false
+}
+
+// isCI returns true if cid runs in a ci pipeline
+func isCI(env map[string]string) bool {
+	return env["NCI_SERVICE_SLUG"] != "" && !strings.HasPrefix(env["NCI_SERVICE_SLUG"], "local")
+}
+
+// isWithinDir returns true if the file is located in the directory or any of its subdirectories
+func isWithinDir(dir string, file string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(absDir, absFile)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// approvedStep returns true if the list contains the gate step, the guarded step or all
+func approvedStep(approvals []string, step plangenerate.Step) bool {
+	return slices.ContainsFunc(approvals, func(approval string) bool {
+		return approval == ApprovalAll || approval == step.ID || approval == step.Slug || (step.Gates != "" && approval == step.Gates)
+	})
+}
+
+func splitApprovals(value string) []string {
+	var approvals []string
+	for _, approval := range strings.Split(value, ",") {
+		if approval = strings.TrimSpace(approval); approval != "" {
+			approvals = append(approvals, approval)
+		}
+	}
+
+	return approvals
+}
+
+func stepDisplayName(step plangenerate.Step) string {
+	if step.Environment != "" {
+		return fmt.Sprintf("%s to environment %s", step.Gates, step.Environment)
+	}
+
+	return step.Gates
+}
@@ -0,0 +1,193 @@
+package planexecute
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cidverse/cid/internal/state"
+	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDeployStep = plangenerate.Step{ID: "1", Type: plangenerate.StepTypeAction, Name: "deploy", Slug: "deploy", Environment: "production", Approval: true}
+	testGateStep   = plangenerate.Step{ID: "2", Type: plangenerate.StepTypeGate, Name: "approve deploy", Slug: "approve-deploy", Environment: "production", Gates: "deploy"}
+)
+
+// clearApprovalEnv unsets the environment variables that approve gates, in case the tests run in a ci job
+func clearApprovalEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"CID_APPROVE", ApprovalGateEnv, "GITHUB_ACTIONS", "GITHUB_ACTOR", "GITLAB_CI", "CI_JOB_MANUAL", "GITLAB_USER_LOGIN", "GITEA_ACTIONS", "FORGEJO_ACTIONS", "TF_BUILD", "BITBUCKET_BUILD_NUMBER", "BITBUCKET_STEP_TRIGGERER_UUID"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestResolveApproval(t *testing.T) {
+	clearApprovalEnv(t)
+
+	approval, err := ResolveApproval(ExecuteContext{Approvals: []string{"deploy"}}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSourceFlag, approval.Source)
+
+	approval, err = ResolveApproval(ExecuteContext{Approvals: []string{ApprovalAll}}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSourceFlag, approval.Source)
+
+	approvalFile := filepath.Join(t.TempDir(), "approvals")
+	require.NoError(t, os.WriteFile(approvalFile, []byte("# deployments\n\napprove-deploy\n"), 0644))
+	approval, err = ResolveApproval(ExecuteContext{ApprovalFile: approvalFile}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSourceFile, approval.Source)
+
+	t.Setenv("CID_APPROVE", "build, deploy")
+	approval, err = ResolveApproval(ExecuteContext{}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSourceEnv, approval.Source)
+}
+
+func TestResolveApprovalRequired(t *testing.T) {
+	clearApprovalEnv(t)
+
+	_, err := ResolveApproval(ExecuteContext{Approvals: []string{"build"}, ApprovalFile: filepath.Join(t.TempDir(), "approvals")}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+	assert.ErrorContains(t, err, "deploy to environment production")
+
+	// running on a ci platform does not approve the gate
+	t.Setenv("GITHUB_ACTIONS", "true")
+	_, err = ResolveApproval(ExecuteContext{}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITLAB_CI", "true")
+	_, err = ResolveApproval(ExecuteContext{}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+}
+
+func TestResolveApprovalFileInRepository(t *testing.T) {
+	clearApprovalEnv(t)
+	projectDir := t.TempDir()
+	approvalFile := filepath.Join(projectDir, ".cid", "approvals")
+	require.NoError(t, os.MkdirAll(filepath.Dir(approvalFile), 0755))
+	require.NoError(t, os.WriteFile(approvalFile, []byte("all\n"), 0644))
+
+	// everyone who can push a branch could approve the gate
+	_, err := ResolveApproval(ExecuteContext{ProjectDir: projectDir, ApprovalFile: approvalFile, Env: map[string]string{"NCI_SERVICE_SLUG": "github-actions"}}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+
+	approval, err := ResolveApproval(ExecuteContext{ProjectDir: projectDir, ApprovalFile: approvalFile, Env: map[string]string{"NCI_SERVICE_SLUG": "localgit"}}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSourceFile, approval.Source)
+}
+
+func TestResolveApprovalPlatform(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		approval Approval
+	}{
+		{name: "github", env: map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_ACTOR": "author"}, approval: Approval{Source: ApprovalSourceGitHubEnvironment}},
+		{name: "gitea", env: map[string]string{"GITHUB_ACTIONS": "true", "GITEA_ACTIONS": "true", "GITHUB_ACTOR": "reviewer"}, approval: Approval{Source: ApprovalSourceGiteaManualRun, Approver: "reviewer"}},
+		{name: "forgejo", env: map[string]string{"GITHUB_ACTIONS": "true", "FORGEJO_ACTIONS": "true", "GITHUB_ACTOR": "reviewer"}, approval: Approval{Source: ApprovalSourceGiteaManualRun, Approver: "reviewer"}},
+		{name: "azure", env: map[string]string{"TF_BUILD": "True"}, approval: Approval{Source: ApprovalSourceAzureEnvironment}},
+		{name: "bitbucket", env: map[string]string{"BITBUCKET_BUILD_NUMBER": "42", "BITBUCKET_STEP_TRIGGERER_UUID": "{reviewer}"}, approval: Approval{Source: ApprovalSourceBitbucketManualStep, Approver: "{reviewer}"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearApprovalEnv(t)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			// the marker is only valid for the job of the gate
+			t.Setenv(ApprovalGateEnv, "approve-other")
+			_, err := ResolveApproval(ExecuteContext{}, testGateStep)
+			assert.ErrorIs(t, err, ErrApprovalRequired)
+
+			t.Setenv(ApprovalGateEnv, "approve-deploy")
+			approval, err := ResolveApproval(ExecuteContext{}, testGateStep)
+			require.NoError(t, err)
+			assert.Equal(t, tc.approval, approval)
+		})
+	}
+}
+
+func TestResolveApprovalGitLabManualJob(t *testing.T) {
+	clearApprovalEnv(t)
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv("GITLAB_USER_LOGIN", "reviewer")
+
+	// the marker is not trusted on gitlab, the gate has to be a manual job
+	t.Setenv(ApprovalGateEnv, "approve-deploy")
+	_, err := ResolveApproval(ExecuteContext{}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+
+	t.Setenv("CI_JOB_MANUAL", "true")
+	approval, err := ResolveApproval(ExecuteContext{}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, Approval{Source: ApprovalSourceGitLabManualJob, Approver: "reviewer"}, approval)
+
+	// the variable is only trusted on gitlab
+	t.Setenv("GITLAB_CI", "")
+	t.Setenv(ApprovalGateEnv, "")
+	_, err = ResolveApproval(ExecuteContext{}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+}
+
+func TestResolveApprovalPrompt(t *testing.T) {
+	clearApprovalEnv(t)
+
+	var message string
+	approval, err := ResolveApproval(ExecuteContext{Prompt: func(m string) (bool, error) {
+		message = m
+		return true, nil
+	}}, testGateStep)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSourcePrompt, approval.Source)
+	assert.Equal(t, "approve deploy to environment production?", message)
+
+	_, err = ResolveApproval(ExecuteContext{Prompt: func(string) (bool, error) { return false, nil }}, testGateStep)
+	assert.ErrorIs(t, err, ErrApprovalDenied)
+}
+
+func TestTerminalPrompt(t *testing.T) {
+	var out bytes.Buffer
+	prompt := TerminalPrompt(strings.NewReader("y\nYes\nno\n\nmaybe"), &out)
+
+	for _, expected := range []bool{true, true, false, false, false, false} {
+		approved, err := prompt("approve deploy?")
+		require.NoError(t, err)
+		assert.Equal(t, expected, approved)
+	}
+	assert.True(t, strings.HasPrefix(out.String(), "approve deploy? [y/N]: "))
+}
+
+func TestRequireApproval(t *testing.T) {
+	clearApprovalEnv(t)
+	plan := plangenerate.Plan{Steps: []plangenerate.Step{testGateStep, testDeployStep}}
+	planContext := ExecuteContext{ProjectDir: t.TempDir()}
+
+	// the guarded step can not run without approval
+	err := requireApproval(plan, planContext, testDeployStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+	err = requireApproval(plangenerate.Plan{Steps: []plangenerate.Step{testDeployStep}}, planContext, testDeployStep)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+	assert.ErrorContains(t, err, "the plan has no approval gate for the step")
+
+	// selecting the step runs the gate first, which records the approval
+	planContext.Approvals = []string{"deploy"}
+	require.NoError(t, requireApproval(plan, planContext, testDeployStep))
+	gateState := state.GetStateFromDirectory(filepath.Join(planContext.ProjectDir, ".dist"))
+	require.Len(t, gateState.AuditLog, 1)
+	assert.Equal(t, "approval", gateState.AuditLog[0].Type)
+	assert.Equal(t, "deploy", gateState.AuditLog[0].Payload["step"])
+	assert.Equal(t, "approve-deploy", gateState.AuditLog[0].Payload["gate"])
+	assert.Equal(t, string(ApprovalSourceFlag), gateState.AuditLog[0].Payload["source"])
+
+	// the recorded approval is used by the guarded step
+	planContext.Approvals = nil
+	assert.NoError(t, requireApproval(plan, planContext, testDeployStep))
+}
@@ -1,7 +1,9 @@
 package planexecute
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -15,8 +17,11 @@ import (
 	"github.com/cidverse/cid/pkg/core/catalog"
 	"github.com/cidverse/cid/pkg/core/config"
 	"github.com/cidverse/cid/pkg/core/plangenerate"
+	"github.com/cidverse/cid/pkg/core/rules"
+	"github.com/cidverse/cid/pkg/lib/telemetry"
 	"github.com/cidverse/repoanalyzer/analyzerapi"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v3"
 )
 
@@ -28,11 +33,17 @@ type ExecuteContext struct {
 	StagesFilter  []string
 	ModulesFilter []string
 	StepFilter    []string
+	Context       context.Context // Context is the parent of the workflow trace, optional
+	Approvals     []string        // Approvals are the slugs of approved steps or gates, all approves every gate
+	ApprovalFile  string          // ApprovalFile contains the slugs of approved steps, one per line
+	Prompt        ApprovalPrompt  // Prompt asks for the approval of gates interactively, optional
 }
 
 func RunPlan(plan plangenerate.Plan, planContext ExecuteContext) {
 	log.Debug().Str("plan", plan.Name).Strs("stages", plan.Stages).Msg("workflow start")
 	start := time.Now()
+	ctx, span := telemetry.StartSpan(planContext.Context, "workflow "+plan.Name, attribute.String("cid.workflow", plan.Name))
+	planContext.Context = ctx
 
 	if planContext.StepFilter != nil && len(planContext.StepFilter) > 0 {
 		// run steps directly, match via id or slug
@@ -54,12 +65,29 @@ func RunPlan(plan plangenerate.Plan, planContext ExecuteContext) {
 		}
 	}
 
+	// summary
+	if testResult := rules.TestResultFromState(state.GetStateFromDirectory(filepath.Join(planContext.ProjectDir, ".dist"))); testResult != nil {
+		for _, slug := range slices.Sorted(maps.Keys(testResult.Modules)) {
+			m := testResult.Modules[slug]
+			log.Info().Str("module", slug).Int("total", m.Summary.Total).Int("failed", m.Summary.Failed+m.Summary.Errors).Int("skipped", m.Summary.Skipped).Str("duration", m.Summary.Duration.String()).Msg("test results")
+		}
+		log.Info().Int("total", testResult.Summary.Total).Int("passed", testResult.Summary.Passed).Int("failed", testResult.Summary.Failed+testResult.Summary.Errors).Int("skipped", testResult.Summary.Skipped).Msg("test summary")
+	}
+
 	log.Info().Str("plan", plan.Name).Str("duration", time.Since(start).String()).Msg("workflow completed")
+
+	// export trace
+	span.End()
+	if err := telemetry.Shutdown(context.Background(), nil); err != nil {
+		log.Warn().Err(err).Msg("failed to export trace")
+	}
 }
 
 func RunPlanStage(plan plangenerate.Plan, planContext ExecuteContext, stageName string) {
 	log.Debug().Str("stage", stageName).Msg("stage start")
 	start := time.Now()
+	ctx, span := telemetry.StartSpan(planContext.Context, "stage "+stageName, attribute.String("cid.workflow", plan.Name), attribute.String("cid.stage", stageName))
+	planContext.Context = ctx
 
 	for _, step := range plan.Steps {
 		if step.Stage != stageName {
@@ -70,10 +98,25 @@ func RunPlanStage(plan plangenerate.Plan, planContext ExecuteContext, stageName
 	}
 
 	// complete
+	span.End()
 	log.Info().Str("stage", stageName).Str("duration", time.Since(start).String()).Msg("stage completed")
 }
 
 func RunPlanStep(plan plangenerate.Plan, planContext ExecuteContext, step plangenerate.Step) {
+	if step.IsGate() {
+		if err := RunGate(planContext, step); err != nil {
+			log.Fatal().Err(err).Str("step", step.Name).Msg("step has not been approved")
+			os.Exit(1)
+		}
+		return
+	}
+	if step.Approval {
+		if err := requireApproval(plan, planContext, step); err != nil {
+			log.Fatal().Err(err).Str("step", step.Name).Msg("step has not been approved")
+			os.Exit(1)
+		}
+	}
+
 	log.Debug().Str("action", step.Name).Msg("action start")
 	catalogAction := planContext.Cfg.Registry.FindAction(step.Action)
 	if catalogAction == nil {
@@ -101,18 +144,56 @@ func RunPlanStep(plan plangenerate.Plan, planContext ExecuteContext, step plange
 		actionContext.CurrentModule = &moduleRef
 	}
 
-	RunAction(actionContext, catalogAction, step)
+	if !conditionsMatch(actionContext, step) {
+		log.Info().Str("action", step.Name).Msg("action skipped, conditions do not match the test result")
+		return
+	}
+
+	RunAction(planContext.Context, actionContext, catalogAction, step)
 
 	log.Debug().Str("action", step.Name).Msg("action end")
 }
 
-func RunAction(actionContext api.ActionExecutionContext, catalogAction *catalog.Action, step plangenerate.Step) {
+// conditionsMatch evaluates the conditions of the step, which reference the test result of the previous steps
+func conditionsMatch(actionContext api.ActionExecutionContext, step plangenerate.Step) bool {
+	if len(step.Conditions) == 0 {
+		return true
+	}
+
+	var ruleContext map[string]interface{}
+	if actionContext.CurrentModule != nil {
+		ruleContext = rules.GetModuleRuleContext(actionContext.Env, actionContext.CurrentModule)
+	} else {
+		ruleContext = rules.GetProjectRuleContext(actionContext.Env, actionContext.Modules)
+	}
+	ruleContext[rules.Matrix] = step.Matrix
+	ruleContext = rules.AddTestResultContext(ruleContext, rules.TestResultFromState(state.GetStateFromDirectory(actionContext.Paths.Artifact)))
+
+	for _, condition := range step.Conditions {
+		if !rules.AnyRuleMatches(condition, ruleContext) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func RunAction(ctx context.Context, actionContext api.ActionExecutionContext, catalogAction *catalog.Action, step plangenerate.Step) {
 	start := time.Now()
 
 	currentModule := "root"
 	if actionContext.CurrentModule != nil {
 		currentModule = actionContext.CurrentModule.Slug
 	}
+	traceCtx, span := telemetry.StartSpan(ctx, "step "+step.Name,
+		attribute.String("cid.step.id", step.ID),
+		attribute.String("cid.step.slug", step.Slug),
+		attribute.String("cid.stage", step.Stage),
+		attribute.String("cid.action", step.Action),
+		attribute.String("cid.action.type", string(catalogAction.Type)),
+		attribute.String("cid.module", currentModule),
+	)
+	actionContext.TraceContext = traceCtx
 	log.Info().Str("action", step.Name).Str("module", currentModule).Msg("action start")
 
 	// state: retrieve/init
@@ -142,6 +223,10 @@ func RunAction(actionContext api.ActionExecutionContext, catalogAction *catalog.
 	if actionExecutor != nil {
 		err := actionExecutor.Execute(&actionContext, &localState, catalogAction, step)
 		if err != nil {
+			telemetry.RecordError(span, err)
+			telemetry.StepDuration.WithLabelValues(step.Action, currentModule, "error").Observe(time.Since(start).Seconds())
+			_ = telemetry.Shutdown(context.Background(), err)
+
 			// TODO: handle error
 			log.Fatal().Err(err).Str("action", step.Name).Str("duration", time.Since(start).String()).Str("module", currentModule).Msg("action error")
 			return
@@ -159,5 +244,7 @@ func RunAction(actionContext api.ActionExecutionContext, catalogAction *catalog.
 	}
 
 	// complete
+	span.End()
+	telemetry.StepDuration.WithLabelValues(step.Action, currentModule, "success").Observe(time.Since(start).Seconds())
 	log.Info().Str("action", step.Name).Str("duration", time.Since(start).String()).Str("module", currentModule).Msg("action completed")
 }
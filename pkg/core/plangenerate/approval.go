@@ -0,0 +1,65 @@
+package plangenerate
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/util"
+	"github.com/gosimple/slug"
+)
+
+// approvalActions are the actions that require a manual approval by default, as they deploy to an environment
+var approvalActions = []string{"helm-deploy", "helmfile-deploy", "ansible-deploy"}
+
+// requiresApproval returns true if a step of the action must be approved before it starts, the workflow can override the default
+func requiresApproval(catalogAction catalog.Action, action catalog.WorkflowAction) bool {
+	if action.Approval != nil {
+		return *action.Approval
+	}
+
+	return slices.Contains(approvalActions, catalogAction.Metadata.Name)
+}
+
+// addApprovalGates inserts a gate step in front of every step that requires approval
+//
+// The gate takes over the dependencies of the guarded step, the guarded step runs after the gate and receives its state to record the approval.
+func addApprovalGates(steps []Step) []Step {
+	stepSlugToName := make(map[string]string, len(steps))
+	for _, step := range steps {
+		stepSlugToName[step.Slug] = step.Name
+	}
+
+	count := len(steps)
+	for i := 0; i < count; i++ {
+		if !steps[i].Approval {
+			continue
+		}
+
+		name := "approve " + steps[i].Name
+		gate := Step{
+			ID:             strconv.Itoa(len(steps)),
+			Type:           StepTypeGate,
+			Name:           name,
+			Slug:           slug.Make(name),
+			Stage:          steps[i].Stage,
+			Scope:          steps[i].Scope,
+			Module:         steps[i].Module,
+			ModuleDir:      steps[i].ModuleDir,
+			RunAfter:       slices.Clone(steps[i].RunAfter),
+			RunAfterByName: slices.Clone(steps[i].RunAfterByName),
+			Environment:    steps[i].Environment,
+			Order:          1,
+			Gates:          steps[i].Slug,
+		}
+		stepSlugToName[gate.Slug] = gate.Name
+
+		steps[i].RunAfter = util.CompactAndSort(append(steps[i].RunAfter, gate.Slug))
+		steps[i].RunAfterByName = slicesReplaceByLookup(steps[i].RunAfter, stepSlugToName)
+		steps[i].UsesOutputOf = util.CompactAndSort(append(steps[i].UsesOutputOf, gate.Slug))
+		steps[i].UsesOutputOfByName = slicesReplaceByLookup(steps[i].UsesOutputOf, stepSlugToName)
+		steps = append(steps, gate)
+	}
+
+	return steps
+}
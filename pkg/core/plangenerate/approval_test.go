@@ -0,0 +1,81 @@
+package plangenerate
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiresApproval(t *testing.T) {
+	approval := false
+
+	assert.True(t, requiresApproval(testAction("helm-deploy", actionsdk.ActionScopeModule), catalog.WorkflowAction{}))
+	assert.False(t, requiresApproval(testAction("helm-deploy", actionsdk.ActionScopeModule), catalog.WorkflowAction{Approval: &approval}))
+	assert.False(t, requiresApproval(testAction("go-build", actionsdk.ActionScopeModule), catalog.WorkflowAction{}))
+}
+
+func TestAddApprovalGates(t *testing.T) {
+	steps := addApprovalGates([]Step{
+		{ID: "0", Type: StepTypeAction, Name: "build", Slug: "build", Stage: "build"},
+		{ID: "1", Type: StepTypeAction, Name: "deploy", Slug: "deploy", Stage: "deploy", Environment: "production", Approval: true, RunAfter: []string{"build"}, RunAfterByName: []string{"build"}, UsesOutputOf: []string{"build"}, UsesOutputOfByName: []string{"build"}},
+	})
+	require.Len(t, steps, 3)
+
+	gate := steps[2]
+	assert.Equal(t, "2", gate.ID)
+	assert.True(t, gate.IsGate())
+	assert.Equal(t, "approve deploy", gate.Name)
+	assert.Equal(t, "approve-deploy", gate.Slug)
+	assert.Equal(t, "deploy", gate.Stage)
+	assert.Equal(t, "production", gate.Environment)
+	assert.Equal(t, "deploy", gate.Gates)
+	assert.Equal(t, []string{"build"}, gate.RunAfter)
+	assert.False(t, gate.Approval)
+
+	// the guarded step runs after the gate and receives its state
+	deploy := steps[1]
+	assert.Equal(t, []string{"approve-deploy", "build"}, deploy.RunAfter)
+	assert.Equal(t, []string{"approve deploy", "build"}, deploy.RunAfterByName)
+	assert.Equal(t, []string{"approve-deploy", "build"}, deploy.UsesOutputOf)
+	assert.Equal(t, []string{"approve deploy", "build"}, deploy.UsesOutputOfByName)
+	assert.Empty(t, steps[0].RunAfter)
+}
+
+func TestGeneratePlanApprovalGates(t *testing.T) {
+	approval := false
+	request := testPlanRequest(
+		[]catalog.Action{
+			testAction("go-build", actionsdk.ActionScopeModule),
+			testAction("helm-deploy", actionsdk.ActionScopeModule),
+			testAction("ansible-deploy", actionsdk.ActionScopeProject),
+		},
+		[]catalog.WorkflowStage{
+			{Name: "build", Actions: []catalog.WorkflowAction{{ID: "test/go-build"}}},
+			{Name: "deploy", Actions: []catalog.WorkflowAction{{ID: "test/helm-deploy"}, {ID: "test/ansible-deploy", Approval: &approval}}},
+		},
+	)
+
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 4)
+
+	deploy := findStep(t, plan, "helm-deploy [my-project]")
+	gate := findStep(t, plan, "approve helm-deploy [my-project]")
+	assert.True(t, deploy.Approval)
+	assert.Equal(t, deploy.Slug, gate.Gates)
+	assert.Equal(t, "deploy", gate.Stage)
+	assert.Contains(t, deploy.RunAfter, gate.Slug)
+	assert.False(t, findStep(t, plan, "ansible-deploy").Approval)
+
+	// the gate runs before the guarded step after sorting the steps
+	assert.Less(t, gate.Order, deploy.Order)
+	order := make([]string, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		order = append(order, step.Name)
+	}
+	assert.Less(t, slices.Index(order, gate.Name), slices.Index(order, deploy.Name))
+}
@@ -3,6 +3,7 @@ package plangenerate
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/cidverse/cid/pkg/app/appcommon"
 	actionApi "github.com/cidverse/cid/pkg/common/api"
@@ -28,9 +29,35 @@ type GeneratePlanRequest struct {
 	Variables    []api.CIVariable                    `json:"variables"`
 	Environments map[string]appcommon.VCSEnvironment `json:"environments"`
 	WorkflowType string                              `json:"workflow_type"`
+	Workflow     string                              `json:"workflow"`      // Workflow is the reference of the workflow to use, e.g. myorg/main@1.0.0 - the workflow is selected by rules if empty
+	ChangedFiles []string                            `json:"changed_files"` // ChangedFiles are the files changed by the commit or merge request - detected using the repository if nil
+	StrictRules  bool                                `json:"strict_rules"`  // StrictRules fails the plan generation if a rule can not be evaluated, e.g. because of a typo in the expression
+	AllChanges   bool                                `json:"all_changes"`   // AllChanges treats rules that reference the changed files as matching, used for generated workflows that run for all changes
 }
 
 func GeneratePlan(request GeneratePlanRequest) (Plan, error) {
+	return generatePlan(request, nil)
+}
+
+// ExplainPlan generates the plan and returns all rule evaluations, the evaluations up to the failure are returned in case of an error
+func ExplainPlan(request GeneratePlanRequest) ([]RuleEvaluation, error) {
+	evaluations := []RuleEvaluation{}
+	_, err := generatePlan(request, &evaluations)
+
+	return evaluations, err
+}
+
+func generatePlan(request GeneratePlanRequest, evaluations *[]RuleEvaluation) (Plan, error) {
+	if request.AllChanges {
+		request.ChangedFiles = []string{}
+	} else if request.ChangedFiles == nil {
+		files, err := rules.DetectChangedFiles(request.ProjectDir, request.Env)
+		if err != nil {
+			slog.With("err", err).Warn("failed to detect changed files, rules that reference CHANGED_FILES will not match")
+		}
+		request.ChangedFiles = files
+	}
+
 	planContext := PlanContext{
 		ProjectDir:      request.ProjectDir,
 		Registry:        request.Registry,
@@ -39,8 +66,12 @@ func GeneratePlan(request GeneratePlanRequest) (Plan, error) {
 		VCSVariables:    request.Variables,
 		VCSEnvironments: request.Environments,
 		Modules:         request.Modules,
+		ChangedFiles:    request.ChangedFiles,
+		StrictRules:     request.StrictRules,
+		AllChanges:      request.AllChanges,
+		evaluations:     evaluations,
 	}
-	ruleContext := rules.GetRuleContext(request.Env)
+	ruleContext := rules.AddChangedFilesContext(rules.GetRuleContext(request.Env), request.ChangedFiles)
 	ruleContext["CID_WORKFLOW_TYPE"] = request.WorkflowType
 
 	// lookup environment info via api - TODO: move to a separate function
@@ -106,14 +137,15 @@ func GeneratePlan(request GeneratePlanRequest) (Plan, error) {
 	}
 
 	// select workflow
-	workflow, err := selectWorkflow(planContext, ruleContext)
+	workflow, err := selectWorkflow(planContext, request.Workflow, ruleContext)
 	if err != nil {
 		return Plan{}, err
 	}
-	log.Debug().Str("workflow-name", workflow.Name).Msg("selected workflow")
+	planContext.Stages = workflowStages(planContext.Stages, workflow)
+	log.Debug().Str("workflow-name", workflow.Name).Str("workflow-id", workflow.ID()).Msg("selected workflow")
 
 	// collect all actions
-	actions, err := getWorkflowActions(workflow, ruleContext)
+	actions, err := getWorkflowActions(planContext, workflow, ruleContext)
 	if err != nil {
 		return Plan{}, err
 	}
@@ -126,8 +158,13 @@ func GeneratePlan(request GeneratePlanRequest) (Plan, error) {
 	}
 	log.Debug().Int("steps", len(steps)).Msg("workflow steps generated")
 
+	for i := range steps {
+		steps[i].Access.Network = resolveNetworkAccess(steps[i].Access.Network, request.Env)
+	}
+
 	// determine dependencies
 	steps = assignStepDependencies(steps, planContext)
+	steps = addApprovalGates(steps)
 	slog.With("step_len", len(steps)).Debug("assigned workflow step dependencies")
 	for _, s := range steps {
 		slog.With("module", s.Module).With("name", s.Name).With("slug", s.Slug).With("run_after", s.RunAfter).With("inputs_from", s.UsesOutputOf).Debug("workflow step")
@@ -149,6 +186,32 @@ func GeneratePlan(request GeneratePlanRequest) (Plan, error) {
 	}, nil
 }
 
+// resolveNetworkAccess replaces the repository api placeholder with the api host of the repository, the placeholder is removed if the repository host is unknown
+func resolveNetworkAccess(network []actionsdk.ActionAccessNetwork, env map[string]string) []actionsdk.ActionAccessNetwork {
+	if !slices.ContainsFunc(network, func(n actionsdk.ActionAccessNetwork) bool { return n.Host == actionsdk.NetworkHostRepositoryAPI }) {
+		return network
+	}
+
+	result := make([]actionsdk.ActionAccessNetwork, 0, len(network))
+	for _, n := range network {
+		if n.Host != actionsdk.NetworkHostRepositoryAPI {
+			result = append(result, n)
+			continue
+		}
+
+		switch server := env["NCI_REPOSITORY_HOST_SERVER"]; server {
+		case "":
+			continue
+		case "github.com":
+			result = append(result, actionsdk.ActionAccessNetwork{Host: "api.github.com:443"})
+		default:
+			result = append(result, actionsdk.ActionAccessNetwork{Host: server + ":443"})
+		}
+	}
+
+	return result
+}
+
 func generateFlatExecutionPlan(context PlanContext, actions []catalog.WorkflowAction, executables []executable.Executable, pinVersions bool, workflowType string) ([]Step, error) {
 	var steps []Step
 
@@ -159,83 +222,123 @@ func generateFlatExecutionPlan(context PlanContext, actions []catalog.WorkflowAc
 			return nil, fmt.Errorf("action [%s] not found in registry", action.ID)
 		}
 		catalogAction := ptr.Value(catalogActionPtr)
+		if catalogAction.Metadata.Scope != actionsdk.ActionScopeProject && catalogAction.Metadata.Scope != actionsdk.ActionScopeModule {
+			return nil, fmt.Errorf("unsupported action scope [%s]: %s", catalogAction.URI, catalogAction.Metadata.Scope)
+		}
 		ctx := actionApi.GetActionContext(context.Modules, context.ProjectDir, context.Environment, catalogAction.Metadata.Access)
 
-		// pin executable constraints
-		var executableConstraints []actionsdk.ActionAccessExecutable
-		for _, ex := range catalogAction.Metadata.Access.Executables {
-			versionConstraint := ex.Constraint
-			if versionConstraint == "" {
-				versionConstraint = executable.AnyVersionConstraint
+		// each matrix combination results in a separate step, the matrix values are passed via config and rule context
+		for _, matrix := range catalog.MatrixCombinations(action.Matrix) {
+			matrixAction := action
+			matrixAction.Config = matrixConfig(action.Config, matrix)
+			executableConstraints := stepExecutableConstraints(catalogAction, executables, pinVersions, matrix)
+			evaluationName := action.ID
+			if len(matrix) > 0 {
+				evaluationName += " {" + catalog.MatrixName(matrix) + "}"
 			}
 
-			// exact version constraints
-			if pinVersions {
-				c := executable.SelectCandidate(executables, executable.CandidateFilter{
-					Types:             nil,
-					Executable:        ex.Name,
-					VersionPreference: executable.PreferHighest,
-					VersionConstraint: versionConstraint,
-				})
-				if c != nil {
-					versionConstraint = fmt.Sprintf("= %s", ptr.Value(c).GetVersion())
-				}
-			}
-
-			executableConstraints = append(executableConstraints, actionsdk.ActionAccessExecutable{
-				Name:       ex.Name,
-				Constraint: versionConstraint,
-			})
-		}
-
-		// create steps without stage grouping, but store the stage name
-		if catalogAction.Metadata.Scope == actionsdk.ActionScopeProject {
-			ruleContext := rules.GetProjectRuleContext(projectEnv(ctx.Env, context.VCSVariables), ctx.Modules)
-			ruleContext["CID_WORKFLOW_TYPE"] = workflowType
-
-			// check if the action rules match, if not check again for each environment
-			if rules.AnyRuleMatches(catalogAction.Metadata.Rules, ruleContext) && rules.AnyRuleMatches(action.Rules, ruleContext) {
-				steps = append(steps, buildStep(catalogAction, action, len(steps), catalogAction.Metadata.Name, nil, "", executableConstraints))
-			} else {
-				for _, env := range context.VCSEnvironments {
-					envRuleContext := rules.GetProjectRuleContext(projectEnvironmentEnv(ctx.Env, context.VCSVariables, env), ctx.Modules)
-					envRuleContext["CID_WORKFLOW_TYPE"] = workflowType
-					if rules.AnyRuleMatches(catalogAction.Metadata.Rules, envRuleContext) && rules.AnyRuleMatches(action.Rules, envRuleContext) {
-						steps = append(steps, buildStep(catalogAction, action, len(steps), catalogAction.Metadata.Name, nil, env.Env.Name, executableConstraints))
-					} else {
-						log.Debug().Str("action", action.ID).Str("environment", env.Env.Name).Msg("action skipped by environment filter")
-					}
-				}
-			}
-		} else if catalogAction.Metadata.Scope == actionsdk.ActionScopeModule {
-			for _, m := range ctx.Modules {
-				moduleRef := ptr.Value(m)
-				ruleContext := rules.GetModuleRuleContext(projectEnv(ctx.Env, context.VCSVariables), &moduleRef)
-				ruleContext["CID_WORKFLOW_TYPE"] = workflowType
+			// create steps without stage grouping, but store the stage name
+			if catalogAction.Metadata.Scope == actionsdk.ActionScopeProject {
+				ruleContext := stepRuleContext(rules.GetProjectRuleContext(projectEnv(ctx.Env, context.VCSVariables), ctx.Modules), context, workflowType, matrix)
 
 				// check if the action rules match, if not check again for each environment
-				if rules.AnyRuleMatches(catalogAction.Metadata.Rules, ruleContext) && rules.AnyRuleMatches(action.Rules, ruleContext) {
-					steps = append(steps, buildStep(catalogAction, action, len(steps), catalogAction.Metadata.Name, &moduleRef, "", executableConstraints))
+				match, conditions, err := context.matchActionRules(catalogAction, action, evaluationName, "", "", ruleContext)
+				if err != nil {
+					return nil, err
+				}
+				if match {
+					steps = append(steps, buildStep(catalogAction, matrixAction, len(steps), catalogAction.Metadata.Name, nil, "", matrix, executableConstraints, conditions))
 				} else {
 					for _, env := range context.VCSEnvironments {
-						envRuleContext := rules.GetModuleRuleContext(projectEnvironmentEnv(ctx.Env, context.VCSVariables, env), &moduleRef)
-						envRuleContext["CID_WORKFLOW_TYPE"] = workflowType
-						if rules.AnyRuleMatches(catalogAction.Metadata.Rules, envRuleContext) && rules.AnyRuleMatches(action.Rules, envRuleContext) {
-							steps = append(steps, buildStep(catalogAction, action, len(steps), catalogAction.Metadata.Name, &moduleRef, env.Env.Name, executableConstraints))
+						envRuleContext := stepRuleContext(rules.GetProjectRuleContext(projectEnvironmentEnv(ctx.Env, context.VCSVariables, env), ctx.Modules), context, workflowType, matrix)
+						envMatch, envConditions, envErr := context.matchActionRules(catalogAction, action, evaluationName, "", env.Env.Name, envRuleContext)
+						if envErr != nil {
+							return nil, envErr
+						}
+						if envMatch {
+							steps = append(steps, buildStep(catalogAction, matrixAction, len(steps), catalogAction.Metadata.Name, nil, env.Env.Name, matrix, executableConstraints, envConditions))
 						} else {
 							log.Debug().Str("action", action.ID).Str("environment", env.Env.Name).Msg("action skipped by environment filter")
 						}
 					}
 				}
+			} else if catalogAction.Metadata.Scope == actionsdk.ActionScopeModule {
+				for _, m := range ctx.Modules {
+					moduleRef := ptr.Value(m)
+					ruleContext := stepRuleContext(rules.GetModuleRuleContext(projectEnv(ctx.Env, context.VCSVariables), &moduleRef), context, workflowType, matrix)
+
+					// check if the action rules match, if not check again for each environment
+					match, conditions, err := context.matchActionRules(catalogAction, action, evaluationName, moduleRef.Slug, "", ruleContext)
+					if err != nil {
+						return nil, err
+					}
+					if match {
+						steps = append(steps, buildStep(catalogAction, matrixAction, len(steps), catalogAction.Metadata.Name, &moduleRef, "", matrix, executableConstraints, conditions))
+					} else {
+						for _, env := range context.VCSEnvironments {
+							envRuleContext := stepRuleContext(rules.GetModuleRuleContext(projectEnvironmentEnv(ctx.Env, context.VCSVariables, env), &moduleRef), context, workflowType, matrix)
+							envMatch, envConditions, envErr := context.matchActionRules(catalogAction, action, evaluationName, moduleRef.Slug, env.Env.Name, envRuleContext)
+							if envErr != nil {
+								return nil, envErr
+							}
+							if envMatch {
+								steps = append(steps, buildStep(catalogAction, matrixAction, len(steps), catalogAction.Metadata.Name, &moduleRef, env.Env.Name, matrix, executableConstraints, envConditions))
+							} else {
+								log.Debug().Str("action", action.ID).Str("environment", env.Env.Name).Msg("action skipped by environment filter")
+							}
+						}
+					}
+				}
 			}
-		} else {
-			return nil, fmt.Errorf("unsupported action scope [%s]: %s", catalogAction.URI, catalogAction.Metadata.Scope)
 		}
 	}
 
 	return steps, nil
 }
 
+// stepRuleContext adds the plan specific values to the rule context of a step
+func stepRuleContext(ruleContext map[string]interface{}, context PlanContext, workflowType string, matrix map[string]string) map[string]interface{} {
+	ruleContext = rules.AddChangedFilesContext(ruleContext, context.ChangedFiles)
+	ruleContext["CID_WORKFLOW_TYPE"] = workflowType
+	ruleContext[rules.Matrix] = matrix
+
+	return ruleContext
+}
+
+// stepExecutableConstraints returns the executable constraints of a step, a matrix value with the name of an executable selects its version, e.g. go: 1.22 results in ~ 1.22
+func stepExecutableConstraints(catalogAction catalog.Action, executables []executable.Executable, pinVersions bool, matrix map[string]string) []actionsdk.ActionAccessExecutable {
+	var executableConstraints []actionsdk.ActionAccessExecutable
+	for _, ex := range catalogAction.Metadata.Access.Executables {
+		versionConstraint := ex.Constraint
+		if versionConstraint == "" {
+			versionConstraint = executable.AnyVersionConstraint
+		}
+		if v, ok := matrix[ex.Name]; ok {
+			versionConstraint = matrixVersionConstraint(v)
+		}
+
+		// exact version constraints
+		if pinVersions {
+			c := executable.SelectCandidate(executables, executable.CandidateFilter{
+				Types:             nil,
+				Executable:        ex.Name,
+				VersionPreference: executable.PreferHighest,
+				VersionConstraint: versionConstraint,
+			})
+			if c != nil {
+				versionConstraint = fmt.Sprintf("= %s", ptr.Value(c).GetVersion())
+			}
+		}
+
+		executableConstraints = append(executableConstraints, actionsdk.ActionAccessExecutable{
+			Name:       ex.Name,
+			Constraint: versionConstraint,
+		})
+	}
+
+	return executableConstraints
+}
+
 func assignStepDependencies(steps []Step, context PlanContext) []Step {
 	actionInstances := make(map[string][]string)   // Track instances of each action
 	artifactProducers := make(map[string][]string) // Track artifact producers
@@ -275,6 +378,17 @@ func assignStepDependencies(steps []Step, context PlanContext) []Step {
 			}
 		}
 
+		// conditions are evaluated using the test result, which requires all steps that produce test results to be completed
+		if len(step.Conditions) > 0 {
+			testResultKey := actionsdk.ActionArtifactType{Type: "report", Format: "test-result"}.Key()
+			for _, producer := range artifactProducers[testResultKey] {
+				if producer != step.Slug {
+					dependencies = append(dependencies, producer)
+					usesOutputOf = append(usesOutputOf, producer)
+				}
+			}
+		}
+
 		// add dependencies based on explicit `RunAfter`
 		for _, requiredAction := range step.RunAfter {
 			if instances, exists := actionInstances[requiredAction]; exists {
@@ -0,0 +1,318 @@
+package plangenerate
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/app/appcommon"
+	"github.com/cidverse/cid/pkg/core/actionsdk"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/core/rules"
+	"github.com/cidverse/go-vcsapp/pkg/platform/api"
+	"github.com/cidverse/repoanalyzer/analyzerapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPlanRequest returns a plan request for a single go module, using a workflow with the provided stages
+func testPlanRequest(actions []catalog.Action, stages []catalog.WorkflowStage) GeneratePlanRequest {
+	return GeneratePlanRequest{
+		Modules: []*analyzerapi.ProjectModule{
+			{
+				ID:            "github.com/cidverse/my-project",
+				RootDirectory: "/my-project",
+				Directory:     "/my-project",
+				Name:          "my-project",
+				Slug:          "my-project",
+				BuildSystem:   analyzerapi.BuildSystemGoMod,
+			},
+		},
+		Registry: catalog.Config{
+			Actions:   actions,
+			Workflows: []catalog.Workflow{{Repository: "test", Name: "main", Stages: stages}},
+		},
+		ProjectDir:   "/my-project",
+		Env:          map[string]string{"NCI_COMMIT_REF_TYPE": "branch", "NCI_COMMIT_REF_NAME": "main"},
+		Environments: map[string]appcommon.VCSEnvironment{},
+		ChangedFiles: []string{},
+	}
+}
+
+// testAction returns a builtin action of the test repository
+func testAction(name string, scope actionsdk.ActionScope, output ...actionsdk.ActionArtifactType) catalog.Action {
+	return catalog.Action{
+		Repository: "test",
+		URI:        "test/" + name,
+		Type:       catalog.ActionTypeBuiltIn,
+		Metadata: catalog.ActionMetadata{
+			Name:   name,
+			Scope:  scope,
+			Output: actionsdk.ActionOutput{Artifacts: output},
+		},
+	}
+}
+
+func findStep(t *testing.T, plan Plan, name string) Step {
+	t.Helper()
+	for _, step := range plan.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	require.Failf(t, "step not found", "step %s is not part of the plan", name)
+
+	return Step{}
+}
+
+func TestGeneratePlanTestResultConditions(t *testing.T) {
+	testResult := actionsdk.ActionArtifactType{Type: "report", Format: "test-result"}
+	request := testPlanRequest(
+		[]catalog.Action{
+			testAction("go-test", actionsdk.ActionScopeModule, testResult),
+			testAction("notify", actionsdk.ActionScopeProject),
+			testAction("lint", actionsdk.ActionScopeProject),
+		},
+		[]catalog.WorkflowStage{
+			{Name: "test", Actions: []catalog.WorkflowAction{{ID: "test/go-test"}}},
+			{Name: "lint", Actions: []catalog.WorkflowAction{
+				{ID: "test/notify", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: "TEST_FAILED > 0"}}},
+				{ID: "test/lint", Rules: []catalog.WorkflowRule{
+					{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_TYPE == "branch"`},
+					{Type: catalog.WorkflowExpressionCEL, Expression: "TEST_FAILED > 0"},
+				}},
+			}},
+		},
+	)
+
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+
+	goTest := findStep(t, plan, "go-test [my-project]")
+	notify := findStep(t, plan, "notify")
+	assert.Equal(t, [][]catalog.WorkflowRule{{{Type: catalog.WorkflowExpressionCEL, Expression: "TEST_FAILED > 0"}}}, notify.Conditions)
+	assert.Contains(t, notify.RunAfter, goTest.Slug)
+	assert.Contains(t, notify.UsesOutputOf, goTest.Slug)
+
+	// a matching plan rule makes the test result irrelevant
+	lint := findStep(t, plan, "lint")
+	assert.Empty(t, lint.Conditions)
+	assert.NotContains(t, lint.RunAfter, goTest.Slug)
+}
+
+func TestExplainPlanDeferredRules(t *testing.T) {
+	request := testPlanRequest(
+		[]catalog.Action{testAction("notify", actionsdk.ActionScopeProject)},
+		[]catalog.WorkflowStage{
+			{Name: "lint", Actions: []catalog.WorkflowAction{
+				{ID: "test/notify", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: "TEST_FAILED > 0"}}},
+			}},
+		},
+	)
+	request.StrictRules = true
+
+	evaluations, err := ExplainPlan(request)
+	require.NoError(t, err)
+
+	var workflowEvaluation *RuleEvaluation
+	for i, e := range evaluations {
+		if e.Type == "action" && e.Source == "workflow" {
+			workflowEvaluation = &evaluations[i]
+		}
+	}
+	require.NotNil(t, workflowEvaluation)
+	assert.True(t, workflowEvaluation.Match)
+	assert.Empty(t, workflowEvaluation.Rules)
+	assert.Equal(t, []string{"TEST_FAILED > 0"}, workflowEvaluation.Deferred)
+}
+
+func TestGeneratePlanProjectWorkflowByReference(t *testing.T) {
+	request := testPlanRequest(
+		[]catalog.Action{testAction("go-build", actionsdk.ActionScopeModule), testAction("lint", actionsdk.ActionScopeProject)},
+		[]catalog.WorkflowStage{{Name: "build", Actions: []catalog.WorkflowAction{{ID: "test/go-build"}}}},
+	)
+	request.Registry.Workflows = append([]catalog.Workflow{{
+		Repository: catalog.ProjectRepository,
+		Name:       "main",
+		Stages:     []catalog.WorkflowStage{{Name: "lint", Actions: []catalog.WorkflowAction{{ID: "test/lint"}}}},
+	}}, request.Registry.Workflows...)
+
+	// project workflows without a reference are not selected by rules
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+	findStep(t, plan, "go-build [my-project]")
+
+	request.Workflow = "project/main"
+	plan, err = GeneratePlan(request)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 1)
+	assert.Equal(t, "lint", plan.Steps[0].Name)
+}
+
+func TestGeneratePlanMatrix(t *testing.T) {
+	source := actionsdk.ActionArtifactType{Type: "source", Format: "generated"}
+	binary := actionsdk.ActionArtifactType{Type: "binary"}
+	build := testAction("go-build", actionsdk.ActionScopeModule, binary)
+	build.Metadata.Input = actionsdk.ActionInput{Artifacts: []actionsdk.ActionArtifactType{source}}
+	publish := testAction("publish", actionsdk.ActionScopeProject)
+	publish.Metadata.Input = actionsdk.ActionInput{Artifacts: []actionsdk.ActionArtifactType{binary}}
+	request := testPlanRequest(
+		[]catalog.Action{testAction("generate", actionsdk.ActionScopeProject, source), build, publish},
+		[]catalog.WorkflowStage{
+			{Name: "build", Actions: []catalog.WorkflowAction{
+				{ID: "test/generate"},
+				{
+					ID:     "test/go-build",
+					Config: map[string]interface{}{"platform": "linux/amd64"},
+					Matrix: map[string][]string{"go": {"1.21", "1.22", "1.23"}},
+					Rules:  []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `MATRIX["go"] != "1.21"`}},
+				},
+			}},
+			{Name: "publish", Actions: []catalog.WorkflowAction{{ID: "test/publish"}}},
+		},
+	)
+
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+
+	// the rule context holds the matrix values, each remaining combination results in a separate step
+	generate := findStep(t, plan, "generate")
+	go122 := findStep(t, plan, "go-build [my-project] {go=1.22}")
+	go123 := findStep(t, plan, "go-build [my-project] {go=1.23}")
+	for _, step := range plan.Steps {
+		assert.NotContains(t, step.Name, "1.21")
+	}
+	assert.NotEqual(t, go122.Slug, go123.Slug)
+
+	// the matrix values are passed via the step and the action config
+	assert.Equal(t, map[string]string{"go": "1.22"}, go122.Matrix)
+	assert.Equal(t, map[string]interface{}{"platform": "linux/amd64", "matrix": map[string]string{"go": "1.22"}}, go122.Config)
+	assert.Equal(t, map[string]interface{}{"platform": "linux/amd64", "matrix": map[string]string{"go": "1.23"}}, go123.Config)
+
+	// fan-out: all matrix steps depend on the producer of their input
+	assert.Equal(t, []string{generate.Slug}, go122.RunAfter)
+	assert.Equal(t, []string{generate.Slug}, go123.RunAfter)
+
+	// fan-in: the consumer depends on all matrix steps
+	publishStep := findStep(t, plan, "publish")
+	assert.Subset(t, publishStep.RunAfter, []string{go122.Slug, go123.Slug})
+	assert.ElementsMatch(t, []string{go122.Slug, go123.Slug}, publishStep.UsesOutputOf)
+}
+
+func TestGeneratePlanAllChanges(t *testing.T) {
+	request := testPlanRequest(
+		[]catalog.Action{testAction("lint", actionsdk.ActionScopeProject), testAction("docs", actionsdk.ActionScopeProject)},
+		[]catalog.WorkflowStage{
+			{Name: "lint", Actions: []catalog.WorkflowAction{
+				{ID: "test/lint", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `glob(CHANGED_FILES, "**/*.go")`}}},
+				{ID: "test/docs", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_TYPE == "tag"`}}},
+			}},
+		},
+	)
+
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Steps)
+
+	// generated workflows run for all changes, rules that reference the changed files match
+	request.AllChanges = true
+	plan, err = GeneratePlan(request)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 1)
+	assert.Equal(t, "lint", plan.Steps[0].Name)
+
+	evaluations, err := ExplainPlan(request)
+	require.NoError(t, err)
+	assert.Contains(t, evaluations, RuleEvaluation{
+		Type:   "action",
+		Name:   "test/lint",
+		Source: "workflow",
+		Match:  true,
+		Rules:  []rules.RuleResult{{Expression: `glob(CHANGED_FILES, "**/*.go")`, Match: true}},
+	})
+}
+
+func TestGeneratePlanStrictRules(t *testing.T) {
+	request := testPlanRequest(
+		[]catalog.Action{testAction("lint", actionsdk.ActionScopeProject)},
+		[]catalog.WorkflowStage{
+			{Name: "lint", Actions: []catalog.WorkflowAction{
+				{ID: "test/lint", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_TYP == "branch"`}}},
+			}},
+		},
+	)
+
+	// invalid rules do not match
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Steps)
+
+	request.StrictRules = true
+	_, err = GeneratePlan(request)
+	assert.ErrorContains(t, err, `invalid rule of action test/lint [NCI_COMMIT_REF_TYP == "branch"]`)
+}
+
+func TestExplainPlanEnvironments(t *testing.T) {
+	request := testPlanRequest(
+		[]catalog.Action{testAction("deploy", actionsdk.ActionScopeProject)},
+		[]catalog.WorkflowStage{
+			{Name: "deploy", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_NAME == "main"`}}, Actions: []catalog.WorkflowAction{
+				{ID: "test/deploy", Rules: []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `ENV["DEPLOY_TARGET"] == "production"`}}},
+			}},
+		},
+	)
+	request.Registry.Workflows[0].Rules = []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_TYPE == "branch"`}}
+	request.Environments = map[string]appcommon.VCSEnvironment{
+		"staging":    {Env: api.CIEnvironment{Name: "staging"}, Vars: []api.CIVariable{{Name: "DEPLOY_TARGET", Value: "staging"}}},
+		"production": {Env: api.CIEnvironment{Name: "production"}, Vars: []api.CIVariable{{Name: "DEPLOY_TARGET", Value: "production"}}},
+	}
+
+	evaluations, err := ExplainPlan(request)
+	require.NoError(t, err)
+
+	find := func(evaluationType string, source string, environment string) RuleEvaluation {
+		t.Helper()
+		for _, e := range evaluations {
+			if e.Type == evaluationType && e.Source == source && e.Environment == environment {
+				return e
+			}
+		}
+		require.Failf(t, "evaluation not found", "%s evaluation (source: %s, environment: %s) is not recorded", evaluationType, source, environment)
+		return RuleEvaluation{}
+	}
+
+	workflow := find("workflow", "", "")
+	assert.Equal(t, "main", workflow.Name)
+	assert.True(t, workflow.Match)
+	assert.Equal(t, []rules.RuleResult{{Expression: `NCI_COMMIT_REF_TYPE == "branch"`, Match: true, Values: map[string]interface{}{"NCI_COMMIT_REF_TYPE": "branch"}}}, workflow.Rules)
+
+	stage := find("stage", "", "")
+	assert.Equal(t, "deploy", stage.Name)
+	assert.True(t, stage.Match)
+
+	// the action does not match without environment, the variable is only defined by the environments
+	action := find("action", "workflow", "")
+	assert.Equal(t, "test/deploy", action.Name)
+	assert.False(t, action.Match)
+	require.Len(t, action.Rules, 1)
+	assert.NotEmpty(t, action.Rules[0].Error)
+
+	staging := find("action", "workflow", "staging")
+	assert.False(t, staging.Match)
+	assert.Empty(t, staging.Rules[0].Error)
+
+	production := find("action", "workflow", "production")
+	assert.True(t, production.Match)
+	assert.True(t, find("action", "catalog", "production").Match)
+
+	plan, err := GeneratePlan(request)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 1)
+	assert.Equal(t, "production", plan.Steps[0].Environment)
+}
+
+func TestResolveNetworkAccess(t *testing.T) {
+	network := []actionsdk.ActionAccessNetwork{{Host: "proxy.golang.org:443"}, {Host: actionsdk.NetworkHostRepositoryAPI}}
+
+	assert.Equal(t, []actionsdk.ActionAccessNetwork{{Host: "proxy.golang.org:443"}, {Host: "api.github.com:443"}}, resolveNetworkAccess(network, map[string]string{"NCI_REPOSITORY_HOST_SERVER": "github.com"}))
+	assert.Equal(t, []actionsdk.ActionAccessNetwork{{Host: "proxy.golang.org:443"}, {Host: "gitlab.example.com:443"}}, resolveNetworkAccess(network, map[string]string{"NCI_REPOSITORY_HOST_SERVER": "gitlab.example.com"}))
+	assert.Equal(t, []actionsdk.ActionAccessNetwork{{Host: "proxy.golang.org:443"}}, resolveNetworkAccess(network, map[string]string{}))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cidverse/cid/pkg/app/appcommon"
 	"github.com/cidverse/cid/pkg/common/executable"
 	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/core/rules"
 	"github.com/cidverse/repoanalyzer/analyzerapi"
 	"github.com/gosimple/slug"
 )
@@ -33,26 +34,43 @@ type Stage struct {
 	Needs []string `json:"needs,omitempty"`
 }
 
+type StepType string
+
+const (
+	StepTypeAction StepType = "action"
+	StepTypeGate   StepType = "gate" // StepTypeGate pauses the plan until the guarded step has been approved
+)
+
 type Step struct {
-	ID                 string                 `json:"id"`
-	Name               string                 `json:"name"`
-	Slug               string                 `json:"slug"`
-	Stage              string                 `json:"stage"`
-	Scope              actionsdk.ActionScope  `json:"scope"`
-	Action             string                 `json:"action"`
-	Module             string                 `json:"module,omitempty"`
-	ModuleDir          string                 `json:"module-dir,omitempty"`             // Directory of the module, if applicable
-	RunAfter           []string               `json:"run-after,omitempty"`              // List of steps that need to be completed before this step starts (by slug)
-	RunAfterByName     []string               `json:"run-after-by-name,omitempty"`      // List of steps that need to be completed before this step starts (by name)
-	RunIfChanged       []string               `json:"run-if-changed,omitempty"`         // List of files that trigger this step if changed
-	UsesOutputOf       []string               `json:"uses-output-of,omitempty"`         // List of steps whose outputs need to be downloaded (by slug)
-	UsesOutputOfByName []string               `json:"uses-output-of-by-name,omitempty"` // List of steps whose outputs need to be downloaded (by name)
-	Environment        string                 `json:"environment,omitempty"`
-	Access             actionsdk.ActionAccess `json:"access,omitempty"`
-	Inputs             actionsdk.ActionInput  `json:"inputs,omitempty"`
-	Outputs            actionsdk.ActionOutput `json:"outputs,omitempty"`
-	Order              int                    `json:"order"` // Topological order
-	Config             interface{}            `json:"config,omitempty"`
+	ID                 string                   `json:"id"`
+	Type               StepType                 `json:"type,omitempty"` // Type of the step, action if empty
+	Name               string                   `json:"name"`
+	Slug               string                   `json:"slug"`
+	Stage              string                   `json:"stage"`
+	Scope              actionsdk.ActionScope    `json:"scope"`
+	Action             string                   `json:"action"`
+	Module             string                   `json:"module,omitempty"`
+	ModuleDir          string                   `json:"module-dir,omitempty"`             // Directory of the module, if applicable
+	RunAfter           []string                 `json:"run-after,omitempty"`              // List of steps that need to be completed before this step starts (by slug)
+	RunAfterByName     []string                 `json:"run-after-by-name,omitempty"`      // List of steps that need to be completed before this step starts (by name)
+	RunIfChanged       []string                 `json:"run-if-changed,omitempty"`         // List of files that trigger this step if changed
+	UsesOutputOf       []string                 `json:"uses-output-of,omitempty"`         // List of steps whose outputs need to be downloaded (by slug)
+	UsesOutputOfByName []string                 `json:"uses-output-of-by-name,omitempty"` // List of steps whose outputs need to be downloaded (by name)
+	Environment        string                   `json:"environment,omitempty"`
+	Access             actionsdk.ActionAccess   `json:"access,omitempty"`
+	Inputs             actionsdk.ActionInput    `json:"inputs,omitempty"`
+	Outputs            actionsdk.ActionOutput   `json:"outputs,omitempty"`
+	Order              int                      `json:"order"` // Topological order
+	Config             interface{}              `json:"config,omitempty"`
+	Matrix             map[string]string        `json:"matrix,omitempty"`     // Matrix values of the step, if the action uses a matrix
+	Approval           bool                     `json:"approval,omitempty"`   // Approval is required before the step starts, enforced by a gate step
+	Gates              string                   `json:"gates,omitempty"`      // Gates is the slug of the step guarded by this gate step
+	Conditions         [][]catalog.WorkflowRule `json:"conditions,omitempty"` // Conditions reference the test result and are evaluated before the step starts, any rule of each list must match
+}
+
+// IsGate returns true if the step is an approval gate instead of an action
+func (s *Step) IsGate() bool {
+	return s.Type == StepTypeGate
 }
 
 func (s *Step) HasOutputWithTypeAndFormat(artifactType string, artifactFormat string) bool {
@@ -65,7 +83,7 @@ func (s *Step) HasOutputWithTypeAndFormat(artifactType string, artifactFormat st
 	return false
 }
 
-func buildStep(catalogAction catalog.Action, action catalog.WorkflowAction, id int, name string, moduleRef *analyzerapi.ProjectModule, environment string, executableConstraints []actionsdk.ActionAccessExecutable) Step {
+func buildStep(catalogAction catalog.Action, action catalog.WorkflowAction, id int, name string, moduleRef *analyzerapi.ProjectModule, environment string, matrix map[string]string, executableConstraints []actionsdk.ActionAccessExecutable, conditions [][]catalog.WorkflowRule) Step {
 	moduleName := ""
 	moduleDir := ""
 	if moduleRef != nil {
@@ -80,12 +98,16 @@ func buildStep(catalogAction catalog.Action, action catalog.WorkflowAction, id i
 
 		name = fmt.Sprintf("%s [%s]", name, moduleRef.Name)
 	}
+	if len(matrix) > 0 {
+		name = fmt.Sprintf("%s {%s}", name, catalog.MatrixName(matrix))
+	}
 	if environment != "" {
 		name = fmt.Sprintf("%s (%s)", name, environment)
 	}
 
 	return Step{
 		ID:           strconv.Itoa(id),
+		Type:         StepTypeAction,
 		Name:         name,
 		Slug:         slug.Make(name),
 		Stage:        action.Stage,
@@ -102,10 +124,13 @@ func buildStep(catalogAction catalog.Action, action catalog.WorkflowAction, id i
 			Network:     catalogAction.Metadata.Access.Network,
 			Resources:   catalogAction.Metadata.Access.Resources,
 		},
-		Inputs:  catalogAction.Metadata.Input,
-		Outputs: catalogAction.Metadata.Output,
-		Order:   1,
-		Config:  action.Config,
+		Inputs:     catalogAction.Metadata.Input,
+		Outputs:    catalogAction.Metadata.Output,
+		Order:      1,
+		Config:     action.Config,
+		Matrix:     matrix,
+		Approval:   requiresApproval(catalogAction, action),
+		Conditions: conditions,
 	}
 }
 
@@ -121,4 +146,20 @@ type PlanContext struct {
 	VCSEnvironments map[string]appcommon.VCSEnvironment
 	Registry        catalog.Config
 	Modules         []*analyzerapi.ProjectModule
+	ChangedFiles    []string
+	StrictRules     bool              // StrictRules fails the plan generation if a rule can not be evaluated
+	AllChanges      bool              // AllChanges treats rules that reference the changed files as matching
+	evaluations     *[]RuleEvaluation // evaluations records all rule evaluations, only set when explaining the plan
+}
+
+// RuleEvaluation is the evaluation of the rules of a workflow, stage or action during plan generation
+type RuleEvaluation struct {
+	Type        string             `json:"type"` // Type is workflow, stage or action
+	Name        string             `json:"name"`
+	Source      string             `json:"source,omitempty"` // Source of action rules, catalog or workflow
+	Module      string             `json:"module,omitempty"`
+	Environment string             `json:"environment,omitempty"`
+	Match       bool               `json:"match"`
+	Rules       []rules.RuleResult `json:"rules"`
+	Deferred    []string           `json:"deferred,omitempty"` // Deferred rules reference the test result and are evaluated before the step starts
 }
@@ -1,6 +1,8 @@
 package plangenerate
 
 import (
+	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -10,26 +12,70 @@ import (
 
 	"github.com/cidverse/cid/pkg/core/catalog"
 	"github.com/cidverse/cid/pkg/core/rules"
+	"github.com/rs/zerolog/log"
 )
 
-// selectWorkflow returns the best matching workflow based on the rules
-func selectWorkflow(context PlanContext, ruleContext map[string]interface{}) (catalog.Workflow, error) {
+// selectWorkflow returns the referenced workflow, or the best matching workflow based on the rules if no reference is provided - project workflows are only selected by reference
+func selectWorkflow(context PlanContext, ref string, ruleContext map[string]interface{}) (catalog.Workflow, error) {
+	if ref != "" {
+		workflow := context.Registry.FindWorkflow(ref)
+		if workflow == nil {
+			return catalog.Workflow{}, fmt.Errorf("%w: workflow %s not found in registry", ErrNoSuitableWorkflowFound, ref)
+		}
+
+		return *workflow, nil
+	}
+
+	// all workflows are evaluated when explaining the plan, the first matching workflow is selected
+	var selected *catalog.Workflow
 	for _, workflow := range context.Registry.Workflows {
-		if !rules.AnyRuleMatches(workflow.Rules, ruleContext) {
+		if workflow.Repository == catalog.ProjectRepository {
 			continue
 		}
 
-		return workflow, nil
+		match, err := context.matchRules(RuleEvaluation{Type: "workflow", Name: workflow.Name}, workflow.Rules, ruleContext)
+		if err != nil {
+			return catalog.Workflow{}, err
+		} else if match && selected == nil {
+			selected = &workflow
+			if context.evaluations == nil {
+				break
+			}
+		}
 	}
 
-	return catalog.Workflow{}, ErrNoSuitableWorkflowFound
+	if selected == nil {
+		return catalog.Workflow{}, ErrNoSuitableWorkflowFound
+	}
+	return *selected, nil
 }
 
-func getWorkflowActions(workflow catalog.Workflow, ruleContext map[string]interface{}) ([]catalog.WorkflowAction, error) {
+// workflowStages returns the stage order, custom stages of the workflow are inserted after the preceding stage of the workflow
+func workflowStages(stages []string, workflow catalog.Workflow) []string {
+	result := slices.Clone(stages)
+	previous := ""
+	for _, stage := range workflow.Stages {
+		if !slices.Contains(result, stage.Name) {
+			index := len(result)
+			if i := slices.Index(result, previous); i >= 0 {
+				index = i + 1
+			}
+			result = slices.Insert(result, index, stage.Name)
+		}
+		previous = stage.Name
+	}
+
+	return result
+}
+
+func getWorkflowActions(context PlanContext, workflow catalog.Workflow, ruleContext map[string]interface{}) ([]catalog.WorkflowAction, error) {
 	var actions []catalog.WorkflowAction
 
 	for _, stage := range workflow.Stages {
-		if !rules.AnyRuleMatches(stage.Rules, ruleContext) {
+		match, err := context.matchRules(RuleEvaluation{Type: "stage", Name: stage.Name}, stage.Rules, ruleContext)
+		if err != nil {
+			return nil, err
+		} else if !match {
 			continue
 		}
 
@@ -42,6 +88,94 @@ func getWorkflowActions(workflow catalog.Workflow, ruleContext map[string]interf
 	return actions, nil
 }
 
+// matchRules checks if any rule matches, the evaluation is recorded when explaining the plan and invalid expressions fail in strict mode
+func (c PlanContext) matchRules(evaluation RuleEvaluation, ruleList []catalog.WorkflowRule, ruleContext map[string]interface{}) (bool, error) {
+	if c.evaluations == nil && !c.StrictRules {
+		return c.anyRuleMatches(ruleList, ruleContext), nil
+	}
+
+	var changedFilesRules []catalog.WorkflowRule
+	if c.AllChanges {
+		ruleList, changedFilesRules = rules.SplitChangedFilesRules(ruleList)
+	}
+	evaluation.Rules = rules.ExplainRules(ruleList, ruleContext)
+	for _, rule := range changedFilesRules {
+		evaluation.Rules = append(evaluation.Rules, rules.RuleResult{Expression: rule.Expression, Match: true})
+	}
+	evaluation.Match = len(ruleList) == 0 || len(evaluation.Deferred) > 0
+	for _, result := range evaluation.Rules {
+		evaluation.Match = evaluation.Match || result.Match
+	}
+	if c.evaluations != nil {
+		*c.evaluations = append(*c.evaluations, evaluation)
+	}
+
+	if c.StrictRules {
+		for _, result := range evaluation.Rules {
+			if result.Error != "" {
+				return false, fmt.Errorf("invalid rule of %s %s [%s]: %s", evaluation.Type, evaluation.Name, result.Expression, result.Error)
+			}
+		}
+	}
+
+	return evaluation.Match, nil
+}
+
+// anyRuleMatches checks if any rule matches, rules that reference the changed files always match when planning for all changes
+func (c PlanContext) anyRuleMatches(ruleList []catalog.WorkflowRule, ruleContext map[string]interface{}) bool {
+	if c.AllChanges {
+		otherRules, changedFilesRules := rules.SplitChangedFilesRules(ruleList)
+		if len(changedFilesRules) > 0 {
+			return true
+		}
+		ruleList = otherRules
+	}
+
+	return rules.AnyRuleMatches(ruleList, ruleContext)
+}
+
+// matchDeferredRules checks the rules like matchRules, but rules that reference the test result can only be evaluated during the execution - they are returned as deferred rules if no other rule matches
+func (c PlanContext) matchDeferredRules(evaluation RuleEvaluation, ruleList []catalog.WorkflowRule, ruleContext map[string]interface{}) (bool, []catalog.WorkflowRule, error) {
+	planRules, deferred := rules.SplitTestResultRules(ruleList)
+	if len(deferred) == 0 {
+		match, err := c.matchRules(evaluation, ruleList, ruleContext)
+		return match, nil, err
+	}
+
+	if len(planRules) > 0 && c.anyRuleMatches(planRules, ruleContext) {
+		deferred = nil
+	}
+	for _, rule := range deferred {
+		evaluation.Deferred = append(evaluation.Deferred, rule.Expression)
+	}
+
+	match, err := c.matchRules(evaluation, planRules, ruleContext)
+	return match, deferred, err
+}
+
+// matchActionRules checks the rules of the catalog action and the rules of the workflow action, both must match - rules that reference the test result are returned as conditions of the step
+func (c PlanContext) matchActionRules(catalogAction catalog.Action, action catalog.WorkflowAction, name string, module string, environment string, ruleContext map[string]interface{}) (bool, [][]catalog.WorkflowRule, error) {
+	var conditions [][]catalog.WorkflowRule
+
+	catalogMatch, catalogDeferred, err := c.matchDeferredRules(RuleEvaluation{Type: "action", Name: name, Source: "catalog", Module: module, Environment: environment}, catalogAction.Metadata.Rules, ruleContext)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(catalogDeferred) > 0 {
+		conditions = append(conditions, catalogDeferred)
+	}
+
+	workflowMatch, workflowDeferred, err := c.matchDeferredRules(RuleEvaluation{Type: "action", Name: name, Source: "workflow", Module: module, Environment: environment}, action.Rules, ruleContext)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(workflowDeferred) > 0 {
+		conditions = append(conditions, workflowDeferred)
+	}
+
+	return catalogMatch && workflowMatch, conditions, nil
+}
+
 func isReservedVariable(name string) bool {
 	if strings.HasPrefix(name, "NCI_") {
 		return true
@@ -97,3 +231,33 @@ func projectEnvironmentEnv(env map[string]string, vars []api.CIVariable, environ
 
 	return result
 }
+
+// matrixConfig returns the action config with the matrix values added as `matrix` key
+func matrixConfig(config interface{}, matrix map[string]string) interface{} {
+	if len(matrix) == 0 {
+		return config
+	}
+
+	result := map[string]interface{}{}
+	switch c := config.(type) {
+	case nil:
+	case map[string]interface{}:
+		result = maps.Clone(c)
+	default:
+		log.Warn().Str("type", fmt.Sprintf("%T", config)).Msg("matrix values can't be added to action config")
+		return config
+	}
+	result["matrix"] = matrix
+
+	return result
+}
+
+// matrixVersionConstraint converts a matrix value into a version constraint, plain versions match all patch versions, e.g. 1.22 results in ~ 1.22
+func matrixVersionConstraint(value string) string {
+	v := strings.TrimPrefix(value, "v")
+	if v != "" && v[0] >= '0' && v[0] <= '9' {
+		return "~ " + v
+	}
+
+	return value
+}
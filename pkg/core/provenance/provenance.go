@@ -39,8 +39,19 @@ func GeneratePredicate(env map[string]string, state *state.ActionStateContext) v
 		Name: fmt.Sprintf("%s:%s", nci.Worker.Type, nci.Worker.OS),
 	})
 
+	var byproducts []v1.ResourceDescriptor
 	for _, record := range state.AuditLog {
-		if record.Type == "action" {
+		if record.Type == "approval" {
+			byproducts = append(byproducts, v1.ResourceDescriptor{
+				Name: "approval/" + record.Payload["step"],
+				Annotations: map[string]interface{}{
+					"timestamp":   record.Timestamp.UTC().Format(time.RFC3339),
+					"environment": record.Payload["environment"],
+					"source":      record.Payload["source"],
+					"approver":    record.Payload["approver"],
+				},
+			})
+		} else if record.Type == "action" {
 			resolvedDependencies = append(resolvedDependencies, v1.ResourceDescriptor{
 				URI: record.Payload["uri"],
 				Digest: map[string]string{
@@ -90,7 +101,7 @@ func GeneratePredicate(env map[string]string, state *state.ActionStateContext) v
 			StartedOn:    &startedAt,
 			FinishedOn:   &finishedAt,
 		},
-		Byproducts: nil,
+		Byproducts: byproducts,
 	}
 
 	return prov
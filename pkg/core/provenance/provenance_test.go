@@ -0,0 +1,36 @@
+package provenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/internal/state"
+	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePredicateApprovalByproducts(t *testing.T) {
+	approvedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	predicate := GeneratePredicate(map[string]string{}, &state.ActionStateContext{
+		AuditLog: []state.AuditEvents{
+			{Timestamp: approvedAt, Type: "action", Payload: map[string]string{"action": "cid/helm-deploy@1.0.0", "uri": "oci://ghcr.io/cidverse/helm"}},
+			{Timestamp: approvedAt, Type: "approval", Payload: map[string]string{
+				"step":        "helm-deploy-my-project",
+				"gate":        "approve-helm-deploy-my-project",
+				"environment": "production",
+				"source":      "gitlab-manual-job",
+				"approver":    "reviewer",
+			}},
+		},
+	})
+
+	assert.Equal(t, []v1.ResourceDescriptor{{
+		Name: "approval/helm-deploy-my-project",
+		Annotations: map[string]interface{}{
+			"timestamp":   "2026-01-02T02:04:05Z",
+			"environment": "production",
+			"source":      "gitlab-manual-job",
+			"approver":    "reviewer",
+		},
+	}}, predicate.RunDetails.Byproducts)
+}
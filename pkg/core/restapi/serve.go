@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/cidverse/cid/pkg/lib/telemetry"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
 )
@@ -74,6 +75,11 @@ func SecureWithAPIKey(e *echo.Echo, secret string) {
 	}))
 }
 
+// ExposeMetrics serves the prometheus metrics on /metrics
+func ExposeMetrics(e *echo.Echo) {
+	e.GET("/metrics", echo.WrapHandler(telemetry.Handler()))
+}
+
 func ListenOnSocket(e *echo.Echo, file string) error {
 	// start server
 	sc := echo.StartConfig{
@@ -0,0 +1,234 @@
+package rules
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cidverseutils/version"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+var (
+	stringListType = reflect.TypeOf([]string{})
+	stringMapType  = reflect.TypeOf(map[string]string{})
+
+	// celFunctions are the functions available in rule expressions, including the helpers of go-rules to keep existing expressions working
+	celFunctions = []cel.EnvOption{
+		cel.Function("contains",
+			cel.Overload("string_contains_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					return types.Bool(strings.Contains(string(lhs.(types.String)), string(rhs.(types.String))))
+				}),
+			),
+			cel.Overload("stringslice_contains_string",
+				[]*cel.Type{cel.ListType(cel.StringType), cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					list, err := lhs.ConvertToNative(stringListType)
+					if err != nil {
+						return types.NewErr("%s", err.Error())
+					}
+					return types.Bool(slices.Contains(list.([]string), string(rhs.(types.String))))
+				}),
+			),
+		),
+		cel.Function("containsKey",
+			cel.Overload("containsKey_map",
+				[]*cel.Type{cel.MapType(cel.StringType, cel.StringType), cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					mapVal, err := lhs.ConvertToNative(stringMapType)
+					if err != nil {
+						return types.NewErr("%s", err.Error())
+					}
+					_, ok := mapVal.(map[string]string)[string(rhs.(types.String))]
+					return types.Bool(ok)
+				}),
+			),
+		),
+		cel.Function("getMapValue",
+			cel.Overload("getMapValue_map",
+				[]*cel.Type{cel.MapType(cel.StringType, cel.StringType), cel.StringType},
+				cel.StringType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					mapVal, err := lhs.ConvertToNative(stringMapType)
+					if err != nil {
+						return types.NewErr("%s", err.Error())
+					}
+					return types.String(mapVal.(map[string]string)[string(rhs.(types.String))])
+				}),
+			),
+		),
+		cel.Function("hasPrefix",
+			cel.Overload("hasPrefix_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					return types.Bool(strings.HasPrefix(string(lhs.(types.String)), string(rhs.(types.String))))
+				}),
+			),
+		),
+		cel.Function("inPath",
+			cel.Overload("inPath",
+				[]*cel.Type{cel.StringType},
+				cel.BoolType,
+				cel.UnaryBinding(func(key ref.Val) ref.Val {
+					_, err := exec.LookPath(string(key.(types.String)))
+					return types.Bool(err == nil)
+				}),
+			),
+		),
+		cel.Function("regex",
+			cel.Overload("regex_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					matched, err := regexp.MatchString(string(rhs.(types.String)), string(lhs.(types.String)))
+					if err != nil {
+						return types.NewErr("%s", err.Error())
+					}
+					return types.Bool(matched)
+				}),
+			),
+		),
+		cel.Function("glob",
+			cel.Overload("glob_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					return types.Bool(MatchGlob(string(rhs.(types.String)), string(lhs.(types.String))))
+				}),
+			),
+			cel.Overload("glob_stringslice",
+				[]*cel.Type{cel.ListType(cel.StringType), cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					list, err := lhs.ConvertToNative(stringListType)
+					if err != nil {
+						return types.NewErr("%s", err.Error())
+					}
+					return types.Bool(slices.ContainsFunc(list.([]string), func(file string) bool {
+						return MatchGlob(string(rhs.(types.String)), file)
+					}))
+				}),
+			),
+		),
+		cel.Function("semver_match",
+			cel.Overload("semver_match_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					return types.Bool(version.FulfillsConstraint(string(lhs.(types.String)), string(rhs.(types.String))))
+				}),
+			),
+		),
+	}
+)
+
+// EvalExpression evaluates a boolean CEL expression, the variables are declared based on the types of the context values
+func EvalExpression(expression string, context map[string]interface{}) (bool, error) {
+	// empty expression always evaluates to false
+	if expression == "" {
+		return false, nil
+	}
+
+	options := slices.Clone(celFunctions)
+	for key, value := range context {
+		t, err := celType(value)
+		if err != nil {
+			return false, fmt.Errorf("unsupported type of context value %s: %w", key, err)
+		}
+		options = append(options, cel.Variable(key, t))
+	}
+
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return false, fmt.Errorf("failed to create cel environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return false, fmt.Errorf("failed to compile expression: %w", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return false, fmt.Errorf("expression must evaluate to bool, got %s", ast.OutputType())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return false, fmt.Errorf("failed to construct program: %w", err)
+	}
+
+	out, _, err := prg.Eval(context)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate expression: %w", err)
+	}
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression did not evaluate to bool, got %s", out.Type())
+	}
+
+	return match, nil
+}
+
+// celType returns the cel type of a context value, named types are declared by their underlying kind
+func celType(value interface{}) (*cel.Type, error) {
+	switch value.(type) {
+	case []string:
+		return cel.ListType(cel.StringType), nil
+	case map[string]string:
+		return cel.MapType(cel.StringType, cel.StringType), nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool:
+		return cel.BoolType, nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return cel.IntType, nil
+	case reflect.Float32, reflect.Float64:
+		return cel.DoubleType, nil
+	case reflect.String:
+		return cel.StringType, nil
+	default:
+		return nil, fmt.Errorf("%T", value)
+	}
+}
+
+// MatchGlob matches a slash separated file path against a glob pattern, ** matches any number of directories and patterns without a slash match the file name in any directory
+func MatchGlob(pattern string, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, err := path.Match(pattern, path.Base(file))
+		return err == nil && ok
+	}
+
+	return matchGlobSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(file, "/"))
+}
+
+func matchGlobSegments(pattern []string, file []string) bool {
+	if len(pattern) == 0 {
+		return len(file) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(file); i++ {
+			if matchGlobSegments(pattern[1:], file[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(file) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], file[0])
+	return err == nil && ok && matchGlobSegments(pattern[1:], file[1:])
+}
@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalExpression(t *testing.T) {
+	ctx := map[string]interface{}{
+		ChangedFiles:    []string{"build/Dockerfile", "pkg/core/rules/cel.go"},
+		IsDefaultBranch: true,
+		TagSemver:       "1.4.0",
+		"ENV":           map[string]string{"NAME": "value"},
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`IS_DEFAULT_BRANCH && glob(CHANGED_FILES, "Dockerfile")`, true},
+		{`glob(CHANGED_FILES, "pkg/**/*.go")`, true},
+		{`glob(CHANGED_FILES, "docs/**")`, false},
+		{`glob("pkg/core/rules/cel.go", "pkg/*/rules/*.go")`, true},
+		{`semver_match(TAG_SEMVER, ">= 1.0.0")`, true},
+		{`semver_match(TAG_SEMVER, "< 1.0.0")`, false},
+		{`semver_match("", ">= 1.0.0")`, false},
+		{`containsKey(ENV, "NAME") && getMapValue(ENV, "NAME") == "value"`, true},
+		{`contains(CHANGED_FILES, "build/Dockerfile")`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			result, err := EvalExpression(test.expression, ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	_, err := EvalExpression(`UNKNOWN == "value"`, map[string]interface{}{})
+	assert.ErrorContains(t, err, "failed to compile expression")
+
+	_, err = EvalExpression(`"value"`, map[string]interface{}{})
+	assert.ErrorContains(t, err, "must evaluate to bool")
+
+	_, err = EvalExpression(`true`, map[string]interface{}{"INVALID": struct{}{}})
+	assert.ErrorContains(t, err, "unsupported type of context value INVALID")
+}
+
+func TestMatchGlob(t *testing.T) {
+	assert.True(t, MatchGlob("Dockerfile", "Dockerfile"))
+	assert.True(t, MatchGlob("Dockerfile", "build/Dockerfile"))
+	assert.True(t, MatchGlob("*.md", "docs/README.md"))
+	assert.True(t, MatchGlob("docs/**", "docs/guide/index.md"))
+	assert.True(t, MatchGlob("**/go.mod", "go.mod"))
+	assert.True(t, MatchGlob("/pkg/*.go", "pkg/main.go"))
+	assert.False(t, MatchGlob("pkg/*.go", "pkg/core/main.go"))
+	assert.False(t, MatchGlob("docs/**", "pkg/docs.go"))
+}
@@ -0,0 +1,136 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/lib/gitdiff"
+	"github.com/cidverse/cidverseutils/version"
+	"github.com/cidverse/go-vcs"
+	"github.com/cidverse/go-vcs/vcsapi"
+)
+
+// changedFilesVariable matches rules that reference the changed files
+var changedFilesVariable = regexp.MustCompile(`\b` + ChangedFiles + `\b`)
+
+// SplitChangedFilesRules splits the rules into rules that reference the changed files and all other rules
+func SplitChangedFilesRules(ruleList []catalog.WorkflowRule) (otherRules []catalog.WorkflowRule, changedFilesRules []catalog.WorkflowRule) {
+	for _, rule := range ruleList {
+		if changedFilesVariable.MatchString(rule.Expression) {
+			changedFilesRules = append(changedFilesRules, rule)
+		} else {
+			otherRules = append(otherRules, rule)
+		}
+	}
+
+	return otherRules, changedFilesRules
+}
+
+// AddChangedFilesContext adds the files changed by the commit or merge request to the rule context
+func AddChangedFilesContext(ctx map[string]interface{}, files []string) map[string]interface{} {
+	if files == nil {
+		files = []string{}
+	}
+	ctx[ChangedFiles] = files
+
+	return ctx
+}
+
+// DetectChangedFiles returns the files changed since the merge base with the merge request target branch, or by the current commit outside of merge requests - binary files are not included
+func DetectChangedFiles(projectDir string, env map[string]string) ([]string, error) {
+	client, err := vcs.GetVCSClient(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vcs repository: %w", err)
+	}
+
+	base, err := changedFilesBase(projectDir, client, env)
+	if err != nil {
+		return nil, err
+	} else if base == "" {
+		return []string{}, nil
+	}
+
+	diff, err := client.Diff(nil, &vcsapi.VCSRef{Type: "hash", Hash: base})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find changed files: %w", err)
+	}
+
+	files := []string{}
+	for _, d := range diff {
+		for _, file := range []string{d.FileFrom.Name, d.FileTo.Name} {
+			if file != "" && !slices.Contains(files, file) {
+				files = append(files, file)
+			}
+		}
+	}
+	slices.Sort(files)
+
+	return files, nil
+}
+
+// changedFilesBase returns the commit to compare the current commit with, empty if the current commit has no parent - shallow clones and target branches that are not fetched are reported as error
+func changedFilesBase(projectDir string, client vcsapi.Client, env map[string]string) (string, error) {
+	if target := env["NCI_MERGE_REQUEST_TARGET_BRANCH_NAME"]; target != "" {
+		base, err := gitdiff.MergeBase(projectDir, "HEAD", target)
+		if err != nil {
+			return "", fmt.Errorf("failed to find merge base with target branch %s: %w", target, err)
+		}
+
+		return base, nil
+	}
+
+	history, err := client.FindCommitsBetween(nil, nil, false, 2)
+	if err == nil && len(history) >= 2 {
+		return history[1].Hash, nil
+	}
+	if shallow, shallowErr := gitdiff.IsShallow(projectDir); shallowErr == nil && shallow {
+		return "", fmt.Errorf("failed to find parent commit: %w", gitdiff.ErrShallowRepository)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to find base commit: %w", err)
+	}
+
+	return "", nil
+}
+
+// commitMessage returns the full commit message, title and description
+func commitMessage(env map[string]string) string {
+	if env["NCI_COMMIT_DESCRIPTION"] == "" {
+		return env["NCI_COMMIT_TITLE"]
+	}
+
+	return env["NCI_COMMIT_TITLE"] + "\n\n" + env["NCI_COMMIT_DESCRIPTION"]
+}
+
+// mergeRequestLabels returns the labels of the merge request, passed as comma separated list in NCI_MERGE_REQUEST_LABELS or CI_MERGE_REQUEST_LABELS (GitLab)
+func mergeRequestLabels(env map[string]string) []string {
+	value := env["NCI_MERGE_REQUEST_LABELS"]
+	if value == "" {
+		value = env["CI_MERGE_REQUEST_LABELS"]
+	}
+
+	labels := []string{}
+	for _, label := range strings.Split(value, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
+}
+
+// tagSemver returns the semantic version of the tag without v prefix, empty if the ref is not a semver tag
+func tagSemver(env map[string]string) string {
+	if env["NCI_COMMIT_REF_TYPE"] != "tag" {
+		return ""
+	}
+
+	v, err := version.Format(env["NCI_COMMIT_REF_NAME"])
+	if err != nil {
+		return ""
+	}
+
+	return v
+}
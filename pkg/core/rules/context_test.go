@@ -0,0 +1,114 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRuleContextCommit(t *testing.T) {
+	ctx := GetRuleContext(map[string]string{
+		"NCI_COMMIT_REF_TYPE":                  "branch",
+		"NCI_COMMIT_REF_NAME":                  "main",
+		"NCI_PROJECT_DEFAULT_BRANCH":           "main",
+		"NCI_COMMIT_TITLE":                     "feat: add rules",
+		"NCI_COMMIT_DESCRIPTION":               "details",
+		"NCI_COMMIT_AUTHOR_NAME":               "Jane Doe",
+		"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main",
+		"CI_MERGE_REQUEST_LABELS":              "deploy, docs",
+	})
+
+	assert.Equal(t, true, ctx[IsDefaultBranch])
+	assert.Equal(t, "feat: add rules\n\ndetails", ctx[CommitMessage])
+	assert.Equal(t, "Jane Doe", ctx[CommitAuthor])
+	assert.Equal(t, "main", ctx[MergeRequestTargetBranch])
+	assert.Equal(t, []string{"deploy", "docs"}, ctx[MergeRequestLabels])
+	assert.Equal(t, []string{}, ctx[ChangedFiles])
+	assert.Equal(t, "", ctx[TagSemver])
+}
+
+func TestGetRuleContextTag(t *testing.T) {
+	ctx := GetRuleContext(map[string]string{
+		"NCI_COMMIT_REF_TYPE":        "tag",
+		"NCI_COMMIT_REF_NAME":        "v1.2.3",
+		"NCI_PROJECT_DEFAULT_BRANCH": "main",
+	})
+
+	assert.Equal(t, false, ctx[IsDefaultBranch])
+	assert.Equal(t, "1.2.3", ctx[TagSemver])
+	assert.Equal(t, []string{}, ctx[MergeRequestLabels])
+}
+
+func TestAddChangedFilesContext(t *testing.T) {
+	ctx := AddChangedFilesContext(GetRuleContext(map[string]string{}), []string{"Dockerfile"})
+	assert.True(t, AnyRuleMatches([]catalog.WorkflowRule{{Expression: `glob(CHANGED_FILES, "Dockerfile") && !IS_DEFAULT_BRANCH`}}, ctx))
+
+	ctx = AddChangedFilesContext(ctx, nil)
+	assert.Equal(t, []string{}, ctx[ChangedFiles])
+}
+
+func TestSplitChangedFilesRules(t *testing.T) {
+	otherRules, changedFilesRules := SplitChangedFilesRules([]catalog.WorkflowRule{
+		{Type: catalog.WorkflowExpressionCEL, Expression: `glob(CHANGED_FILES, "**/*.go")`},
+		{Type: catalog.WorkflowExpressionCEL, Expression: `MY_CHANGED_FILES_COUNT > 0`},
+	})
+
+	assert.Equal(t, []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `MY_CHANGED_FILES_COUNT > 0`}}, otherRules)
+	assert.Equal(t, []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `glob(CHANGED_FILES, "**/*.go")`}}, changedFilesRules)
+}
+
+// testMergeRequestRepository creates a repository with a feature branch, main advances after the feature branch has been created
+func testMergeRequestRepository(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")}})
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	commitFile := func(file string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(file), 0644))
+		_, err = wt.Add(file)
+		require.NoError(t, err)
+		_, err = wt.Commit("add "+file, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()}})
+		require.NoError(t, err)
+	}
+
+	commitFile("a.txt")
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	commitFile("b.txt")
+	commitFile("c.txt")
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("main")}))
+	commitFile("d.txt")
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature")}))
+
+	return dir
+}
+
+func TestDetectChangedFiles(t *testing.T) {
+	dir := testMergeRequestRepository(t)
+
+	files, err := DetectChangedFiles(dir, map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.txt"}, files)
+
+	// changes on the target branch are not part of the merge request
+	files, err = DetectChangedFiles(dir, map[string]string{"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b.txt", "c.txt"}, files)
+}
+
+func TestDetectChangedFilesMissingTargetBranch(t *testing.T) {
+	dir := testMergeRequestRepository(t)
+
+	_, err := DetectChangedFiles(dir, map[string]string{"NCI_MERGE_REQUEST_TARGET_BRANCH_NAME": "develop"})
+	assert.ErrorContains(t, err, "failed to find merge base with target branch develop")
+}
@@ -1,13 +1,14 @@
 package rules
 
 import (
+	"fmt"
 	"log/slog"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/cidverse/cid/pkg/core/catalog"
-	"github.com/cidverse/go-rules/pkg/expr"
 	"github.com/cidverse/repoanalyzer/analyzerapi"
 )
 
@@ -22,8 +23,23 @@ const (
 	ModuleDeploymentSpec    = "MODULE_DEPLOYMENT_SPEC"
 	ModuleDeploymentType    = "MODULE_DEPLOYMENT_TYPE"
 	ModuleFiles             = "MODULE_FILES"
+	ModuleDependencies      = "MODULE_DEPENDENCIES"
+	ModuleDependencyVersion = "MODULE_DEPENDENCY_VERSIONS"
 )
 
+const (
+	ChangedFiles             = "CHANGED_FILES"
+	CommitMessage            = "COMMIT_MESSAGE"
+	CommitAuthor             = "COMMIT_AUTHOR"
+	MergeRequestLabels       = "MERGE_REQUEST_LABELS"
+	MergeRequestTargetBranch = "MERGE_REQUEST_TARGET_BRANCH"
+	IsDefaultBranch          = "IS_DEFAULT_BRANCH"
+	TagSemver                = "TAG_SEMVER"
+)
+
+// Matrix holds the matrix values of an action in the rule context, e.g. MATRIX["go"] == "1.22"
+const Matrix = "MATRIX"
+
 var ReservedVariables = []string{
 	ModuleName,
 	ModuleSlug,
@@ -35,6 +51,8 @@ var ReservedVariables = []string{
 	ModuleDeploymentSpec,
 	ModuleDeploymentType,
 	ModuleFiles,
+	ModuleDependencies,
+	ModuleDependencyVersion,
 }
 
 // AnyRuleMatches will return true if at least one rule matches, if no rules are provided this always returns true
@@ -84,6 +102,67 @@ func EvaluateRule(rule catalog.WorkflowRule, evalContext map[string]interface{})
 	return false
 }
 
+// EvaluateRuleStrict will evaluate a WorkflowRule and return the result, invalid expressions are returned as error instead of not matching
+func EvaluateRuleStrict(rule catalog.WorkflowRule, evalContext map[string]interface{}) (bool, error) {
+	if rule.Type == "" || rule.Type == catalog.WorkflowExpressionCEL {
+		return EvalExpression(rule.Expression, evalContext)
+	}
+
+	return false, fmt.Errorf("expression type %s is not supported", rule.Type)
+}
+
+// RuleResult is the result of a single rule, including the context values referenced by the expression
+type RuleResult struct {
+	Expression string                 `json:"expression"`
+	Match      bool                   `json:"match"`
+	Error      string                 `json:"error,omitempty"`
+	Values     map[string]interface{} `json:"values,omitempty"`
+}
+
+// ExplainRules evaluates all rules and returns the result of each rule
+func ExplainRules(rules []catalog.WorkflowRule, evalContext map[string]interface{}) []RuleResult {
+	results := make([]RuleResult, 0, len(rules))
+	for _, rule := range rules {
+		result := RuleResult{
+			Expression: rule.Expression,
+			Values:     referencedValues(rule.Expression, evalContext),
+		}
+
+		match, err := EvaluateRuleStrict(rule, evalContext)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Match = match
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// referencedValues returns the context values used in the expression, maps are limited to the keys used in the expression to avoid exposing the whole environment
+func referencedValues(expression string, evalContext map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, value := range evalContext {
+		if !regexp.MustCompile(`\b` + regexp.QuoteMeta(key) + `\b`).MatchString(expression) {
+			continue
+		}
+
+		if m, ok := value.(map[string]string); ok {
+			subset := make(map[string]string)
+			for k, v := range m {
+				if strings.Contains(expression, strconv.Quote(k)) || strings.Contains(expression, "'"+k+"'") || strings.Contains(expression, key+"."+k) {
+					subset[k] = v
+				}
+			}
+			value = subset
+		}
+		values[key] = value
+	}
+
+	return values
+}
+
+// GetRuleContext returns the rule context of the ci environment, CHANGED_FILES is empty until it is set using AddChangedFilesContext
 func GetRuleContext(env map[string]string) map[string]interface{} {
 	return map[string]interface{}{
 		"NCI_COMMIT_REF_PATH":        env["NCI_COMMIT_REF_PATH"],
@@ -92,6 +171,13 @@ func GetRuleContext(env map[string]string) map[string]interface{} {
 		"NCI_REPOSITORY_HOST_TYPE":   env["NCI_REPOSITORY_HOST_TYPE"],
 		"NCI_REPOSITORY_HOST_SERVER": env["NCI_REPOSITORY_HOST_SERVER"],
 		"ENV":                        env,
+		ChangedFiles:                 []string{},
+		CommitMessage:                commitMessage(env),
+		CommitAuthor:                 env["NCI_COMMIT_AUTHOR_NAME"],
+		MergeRequestLabels:           mergeRequestLabels(env),
+		MergeRequestTargetBranch:     env["NCI_MERGE_REQUEST_TARGET_BRANCH_NAME"],
+		IsDefaultBranch:              env["NCI_COMMIT_REF_TYPE"] == "branch" && env["NCI_COMMIT_REF_NAME"] != "" && env["NCI_COMMIT_REF_NAME"] == env["NCI_PROJECT_DEFAULT_BRANCH"],
+		TagSemver:                    tagSemver(env),
 	}
 }
 
@@ -150,11 +236,20 @@ func GetModuleRuleContext(env map[string]string, module *analyzerapi.ProjectModu
 	}
 	ctx[ModuleFiles] = files
 
+	dependencies := []string{}
+	dependencyVersions := map[string]string{}
+	for _, dep := range module.Dependencies {
+		dependencies = append(dependencies, dep.ID)
+		dependencyVersions[dep.ID] = dep.Version
+	}
+	ctx[ModuleDependencies] = dependencies
+	ctx[ModuleDependencyVersion] = dependencyVersions
+
 	return ctx
 }
 
 func evalRuleCEL(rule catalog.WorkflowRule, context map[string]interface{}) bool {
-	match, err := expr.EvalBooleanExpression(rule.Expression, context)
+	match, err := EvalExpression(rule.Expression, context)
 	if err != nil {
 		slog.With("err", err).With("expression", rule.Expression).Debug("failed to evaluate workflow rule expression")
 		return false
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -292,3 +293,65 @@ func TestCELExpression(t *testing.T) {
 	result := evalRuleCEL(rule, map[string]interface{}{"KEY": "VALUE"})
 	assert.Equal(t, true, result)
 }
+
+func TestAddTestResultContext(t *testing.T) {
+	ctx := AddTestResultContext(map[string]interface{}{}, &testresult.ProjectResult{
+		Summary: testresult.Summary{Total: 5, Passed: 3, Failed: 1, Errors: 1},
+		Modules: map[string]testresult.ModuleResult{
+			"backend":  {Summary: testresult.Summary{Total: 3, Passed: 1, Failed: 1, Errors: 1}},
+			"frontend": {Summary: testresult.Summary{Total: 2, Passed: 2}},
+		},
+	})
+
+	assert.Equal(t, 5, ctx[TestTotal])
+	assert.Equal(t, 2, ctx[TestFailed])
+	assert.Equal(t, []string{"backend"}, ctx[TestFailedModules])
+	assert.True(t, AnyRuleMatches([]catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `TEST_FAILED > 0 && "backend" in TEST_FAILED_MODULES`}}, ctx))
+}
+
+func TestSplitTestResultRules(t *testing.T) {
+	planRules, testResultRules := SplitTestResultRules([]catalog.WorkflowRule{
+		{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_TYPE == "tag"`},
+		{Type: catalog.WorkflowExpressionCEL, Expression: `TEST_FAILED > 0`},
+		{Type: catalog.WorkflowExpressionCEL, Expression: `MY_TEST_FAILED_FLAG == "true"`},
+	})
+
+	assert.Equal(t, []catalog.WorkflowRule{
+		{Type: catalog.WorkflowExpressionCEL, Expression: `NCI_COMMIT_REF_TYPE == "tag"`},
+		{Type: catalog.WorkflowExpressionCEL, Expression: `MY_TEST_FAILED_FLAG == "true"`},
+	}, planRules)
+	assert.Equal(t, []catalog.WorkflowRule{{Type: catalog.WorkflowExpressionCEL, Expression: `TEST_FAILED > 0`}}, testResultRules)
+}
+
+func TestEvaluateRuleStrict(t *testing.T) {
+	match, err := EvaluateRuleStrict(catalog.WorkflowRule{Expression: `NCI_COMMIT_REF_NAME == "main"`}, map[string]interface{}{"NCI_COMMIT_REF_NAME": "main"})
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	_, err = EvaluateRuleStrict(catalog.WorkflowRule{Expression: `NCI_COMIT_REF_NAME == "main"`}, map[string]interface{}{"NCI_COMMIT_REF_NAME": "main"})
+	assert.ErrorContains(t, err, "undeclared reference to 'NCI_COMIT_REF_NAME'")
+
+	_, err = EvaluateRuleStrict(catalog.WorkflowRule{Type: "unknown", Expression: "true"}, map[string]interface{}{})
+	assert.ErrorContains(t, err, "expression type unknown is not supported")
+}
+
+func TestExplainRules(t *testing.T) {
+	ctx := map[string]interface{}{
+		"NCI_COMMIT_REF_NAME": "main",
+		"MODULE_NAME":         "api",
+		"ENV":                 map[string]string{"DEPLOY": "true", "SECRET": "value"},
+	}
+
+	results := ExplainRules([]catalog.WorkflowRule{
+		{Expression: `NCI_COMMIT_REF_NAME == "main" && ENV["DEPLOY"] == "true"`},
+		{Expression: `MODULE_NAM == "api"`},
+	}, ctx)
+
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].Match)
+	assert.Empty(t, results[0].Error)
+	assert.Equal(t, map[string]interface{}{"NCI_COMMIT_REF_NAME": "main", "ENV": map[string]string{"DEPLOY": "true"}}, results[0].Values)
+	assert.False(t, results[1].Match)
+	assert.Contains(t, results[1].Error, "failed to compile expression")
+	assert.Empty(t, results[1].Values)
+}
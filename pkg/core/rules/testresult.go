@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+
+	"github.com/cidverse/cid/internal/state"
+	"github.com/cidverse/cid/pkg/core/catalog"
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+)
+
+const (
+	TestTotal         = "TEST_TOTAL"
+	TestPassed        = "TEST_PASSED"
+	TestFailed        = "TEST_FAILED"
+	TestSkipped       = "TEST_SKIPPED"
+	TestFailedModules = "TEST_FAILED_MODULES"
+)
+
+// testResultVariable matches the rule context values that are only known after the tests ran
+var testResultVariable = regexp.MustCompile(`\bTEST_(TOTAL|PASSED|FAILED|SKIPPED|FAILED_MODULES)\b`)
+
+// SplitTestResultRules splits the rules into rules that can be evaluated during plan generation and rules that reference the test result
+func SplitTestResultRules(ruleList []catalog.WorkflowRule) (planRules []catalog.WorkflowRule, testResultRules []catalog.WorkflowRule) {
+	for _, rule := range ruleList {
+		if testResultVariable.MatchString(rule.Expression) {
+			testResultRules = append(testResultRules, rule)
+		} else {
+			planRules = append(planRules, rule)
+		}
+	}
+
+	return planRules, testResultRules
+}
+
+// TestResultFromState returns the aggregated test result of the report:test-result artifact, or nil if no test result is available
+func TestResultFromState(s state.ActionStateContext) *testresult.ProjectResult {
+	artifacts := s.FindArtifacts("report", "test-result")
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	result, err := testresult.ReadProjectResultFile(artifacts[len(artifacts)-1].Path)
+	if err != nil {
+		slog.With("err", err).Warn("failed to load test result")
+		return nil
+	}
+
+	return result
+}
+
+// AddTestResultContext adds the aggregated test results to the rule context, failed includes tests with errors
+func AddTestResultContext(ctx map[string]interface{}, result *testresult.ProjectResult) map[string]interface{} {
+	ctx[TestTotal] = 0
+	ctx[TestPassed] = 0
+	ctx[TestFailed] = 0
+	ctx[TestSkipped] = 0
+	ctx[TestFailedModules] = []string{}
+	if result == nil {
+		return ctx
+	}
+
+	failedModules := []string{}
+	for slug, m := range result.Modules {
+		if !m.Summary.Successful() {
+			failedModules = append(failedModules, slug)
+		}
+	}
+	slices.Sort(failedModules)
+
+	ctx[TestTotal] = result.Summary.Total
+	ctx[TestPassed] = result.Summary.Passed
+	ctx[TestFailed] = result.Summary.Failed + result.Summary.Errors
+	ctx[TestSkipped] = result.Summary.Skipped
+	ctx[TestFailedModules] = failedModules
+
+	return ctx
+}
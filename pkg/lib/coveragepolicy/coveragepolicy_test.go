@@ -0,0 +1,88 @@
+package coveragepolicy
+
+import (
+	"testing"
+
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleProject() coverage.ProjectReport {
+	report := coverage.NewReport()
+	api := report.File("backend/api.go")
+	api.AddLine(1, 1, 0, 0)
+	api.AddLine(2, 1, 0, 0)
+	api.AddLine(3, 0, 0, 0)
+	api.AddLine(4, 0, 0, 0)
+	web := report.File("frontend/index.js")
+	web.AddLine(1, 1, 0, 0)
+	web.AddLine(2, 1, 0, 0)
+
+	return coverage.ProjectReport{
+		Summary: report.Summary(),
+		Modules: map[string]coverage.Summary{
+			"backend":  report.File("backend/api.go").Summary(),
+			"frontend": report.File("frontend/index.js").Summary(),
+		},
+		Report: report,
+	}
+}
+
+func TestCalculateDiffCoverage(t *testing.T) {
+	project := sampleProject()
+
+	diff := CalculateDiffCoverage(project.Report, map[string][]int{
+		"backend/api.go":    {2, 3, 3, 10}, // 10 is not executable
+		"README.md":         {1},
+		"frontend/index.js": {5},
+	})
+	assert.Equal(t, 2, diff.LinesValid)
+	assert.Equal(t, 1, diff.LinesCovered)
+	assert.Equal(t, 50.0, diff.Percent())
+	assert.Len(t, diff.Files, 1)
+	assert.Equal(t, []int{3}, diff.Files[0].UncoveredLines)
+}
+
+func TestDiffCoverageWithoutExecutableLines(t *testing.T) {
+	diff := CalculateDiffCoverage(sampleProject().Report, map[string][]int{"README.md": {1}})
+	assert.Equal(t, 100.0, diff.Percent())
+}
+
+func TestEvaluate(t *testing.T) {
+	project := sampleProject()
+	diff := CalculateDiffCoverage(project.Report, map[string][]int{"backend/api.go": {2, 3}})
+
+	result := Evaluate(Policy{
+		ProjectMinimum: 60,
+		ModuleMinimum:  80,
+		ModuleMinimums: map[string]float64{"backend": 50},
+		DiffMinimum:    80,
+	}, project, &diff)
+
+	assert.Len(t, result.Checks, 4)
+	assert.False(t, result.Passed())
+	failed := result.Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "diff", failed[0].Name)
+}
+
+func TestEvaluateDisabled(t *testing.T) {
+	result := Evaluate(Policy{}, sampleProject(), nil)
+	assert.Empty(t, result.Checks)
+	assert.True(t, result.Passed())
+}
+
+func TestRenderMarkdownSummary(t *testing.T) {
+	project := sampleProject()
+	diff := CalculateDiffCoverage(project.Report, map[string][]int{"backend/api.go": {2, 3}})
+	result := Evaluate(Policy{DiffMinimum: 80}, project, &diff)
+
+	summary, err := RenderMarkdownSummary(project, &diff, result)
+	assert.NoError(t, err)
+	assert.Contains(t, summary, SummaryMarker)
+	assert.Contains(t, summary, "**66.67%** line coverage (4 of 6 lines).")
+	assert.Contains(t, summary, "**50.00%** of the changed lines are covered (1 of 2 lines).")
+	assert.Contains(t, summary, "| backend | 2 / 4 | 50.00% |")
+	assert.Contains(t, summary, "| diff | 50.00% | 80.00% | failed |")
+	assert.Contains(t, summary, "| `backend/api.go` | 3 |")
+}
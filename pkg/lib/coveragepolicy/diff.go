@@ -0,0 +1,74 @@
+package coveragepolicy
+
+import (
+	"slices"
+
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+)
+
+// DiffFile is the coverage of the changed lines of a single file
+type DiffFile struct {
+	Path           string `json:"path"`
+	LinesValid     int    `json:"lines_valid"`
+	LinesCovered   int    `json:"lines_covered"`
+	UncoveredLines []int  `json:"uncovered_lines,omitempty"`
+}
+
+// DiffCoverage is the coverage of all changed lines, only lines that are executable according to the coverage report are counted
+type DiffCoverage struct {
+	LinesValid   int        `json:"lines_valid"`
+	LinesCovered int        `json:"lines_covered"`
+	Files        []DiffFile `json:"files"`
+}
+
+// Percent returns the diff coverage in percent, a change without executable lines is fully covered
+func (d DiffCoverage) Percent() float64 {
+	if d.LinesValid == 0 {
+		return 100
+	}
+
+	return float64(d.LinesCovered) / float64(d.LinesValid) * 100
+}
+
+// CalculateDiffCoverage calculates the coverage of the changed lines, changedLines contains the changed line numbers per project relative file path
+func CalculateDiffCoverage(report *coverage.Report, changedLines map[string][]int) DiffCoverage {
+	var result DiffCoverage
+
+	paths := make([]string, 0, len(changedLines))
+	for p := range changedLines {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	for _, p := range paths {
+		f, ok := report.Files[p]
+		if !ok {
+			continue
+		}
+
+		df := DiffFile{Path: p}
+		lines := slices.Clone(changedLines[p])
+		slices.Sort(lines)
+		for _, number := range slices.Compact(lines) {
+			l, ok := f.Lines[number]
+			if !ok {
+				continue
+			}
+
+			df.LinesValid++
+			if l.Hits > 0 {
+				df.LinesCovered++
+			} else {
+				df.UncoveredLines = append(df.UncoveredLines, number)
+			}
+		}
+
+		if df.LinesValid > 0 {
+			result.LinesValid += df.LinesValid
+			result.LinesCovered += df.LinesCovered
+			result.Files = append(result.Files, df)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,86 @@
+package coveragepolicy
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+)
+
+// Policy defines the minimum coverage in percent, a value of 0 disables the check
+type Policy struct {
+	ProjectMinimum float64            `json:"project_minimum"`
+	ModuleMinimum  float64            `json:"module_minimum"`
+	ModuleMinimums map[string]float64 `json:"module_minimums"` // ModuleMinimums overrides the module minimum for individual module slugs
+	DiffMinimum    float64            `json:"diff_minimum"`
+}
+
+// Check is the result of a single threshold
+type Check struct {
+	Name    string  `json:"name"`
+	Actual  float64 `json:"actual"`
+	Minimum float64 `json:"minimum"`
+	Passed  bool    `json:"passed"`
+}
+
+// Result contains the results of all checks
+type Result struct {
+	Checks []Check `json:"checks"`
+}
+
+// Failed returns all checks that did not pass
+func (r Result) Failed() []Check {
+	var failed []Check
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failed = append(failed, c)
+		}
+	}
+
+	return failed
+}
+
+// Passed returns true if all checks passed
+func (r Result) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// Evaluate checks the project, module and diff coverage against the policy, diff can be nil if no diff is available
+func Evaluate(policy Policy, project coverage.ProjectReport, diff *DiffCoverage) Result {
+	var result Result
+
+	if policy.ProjectMinimum > 0 {
+		result.Checks = append(result.Checks, newCheck("project", project.Summary.LinePercent(), policy.ProjectMinimum))
+	}
+
+	modules := make([]string, 0, len(project.Modules))
+	for slug := range project.Modules {
+		modules = append(modules, slug)
+	}
+	slices.Sort(modules)
+	for _, slug := range modules {
+		minimum := policy.ModuleMinimum
+		if m, ok := policy.ModuleMinimums[slug]; ok {
+			minimum = m
+		}
+
+		if minimum > 0 {
+			result.Checks = append(result.Checks, newCheck(fmt.Sprintf("module %s", slug), project.Modules[slug].LinePercent(), minimum))
+		}
+	}
+
+	if diff != nil && policy.DiffMinimum > 0 {
+		result.Checks = append(result.Checks, newCheck("diff", diff.Percent(), policy.DiffMinimum))
+	}
+
+	return result
+}
+
+func newCheck(name string, actual float64, minimum float64) Check {
+	return Check{
+		Name:    name,
+		Actual:  actual,
+		Minimum: minimum,
+		Passed:  actual >= minimum,
+	}
+}
@@ -0,0 +1,65 @@
+package coveragepolicy
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/cidverse/cid/pkg/lib/formats/coverage"
+)
+
+// SummaryMarker identifies the summary in merge request comments, to update the existing comment instead of creating a new one
+const SummaryMarker = "<!-- cid:coverage-summary -->"
+
+//go:embed templates/summary.gohtml
+var summaryTemplate string
+
+type SummaryData struct {
+	Marker  string
+	Project coverage.Summary
+	Modules []ModuleSummary
+	Diff    *DiffCoverage
+	Result  Result
+}
+
+type ModuleSummary struct {
+	Slug    string
+	Summary coverage.Summary
+}
+
+// RenderMarkdownSummary renders the project, module and diff coverage as markdown
+func RenderMarkdownSummary(project coverage.ProjectReport, diff *DiffCoverage, result Result) (string, error) {
+	var modules []ModuleSummary
+	for slug, s := range project.Modules {
+		modules = append(modules, ModuleSummary{Slug: slug, Summary: s})
+	}
+	slices.SortFunc(modules, func(a, b ModuleSummary) int {
+		return strings.Compare(a.Slug, b.Slug)
+	})
+
+	tmpl, err := template.New("summary").Funcs(template.FuncMap{
+		"percent": func(v float64) string {
+			return fmt.Sprintf("%.2f%%", v)
+		},
+	}).Parse(summaryTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse summary template: %w", err)
+	}
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, SummaryData{
+		Marker:  SummaryMarker,
+		Project: project.Summary,
+		Modules: modules,
+		Diff:    diff,
+		Result:  result,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render summary template: %w", err)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,55 @@
+package flakytest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryFlaky(t *testing.T) {
+	h := NewHistory()
+	now := time.Now()
+	h.Record("aaa", now, &testresult.Report{Cases: []testresult.Case{
+		{Suite: "pkg", Name: "TestStable", Status: testresult.StatusPassed},
+		{Suite: "pkg", Name: "TestFlaky", Status: testresult.StatusFailed},
+		{Suite: "pkg", Name: "TestBroken", Status: testresult.StatusFailed},
+		{Suite: "pkg", Name: "TestSkipped", Status: testresult.StatusSkipped},
+	}})
+	h.Record("aaa", now, &testresult.Report{Cases: []testresult.Case{
+		{Suite: "pkg", Name: "TestStable", Status: testresult.StatusPassed},
+		{Suite: "pkg", Name: "TestFlaky", Status: testresult.StatusPassed},
+	}})
+	h.Record("bbb", now, &testresult.Report{Cases: []testresult.Case{
+		{Suite: "pkg", Name: "TestBroken", Status: testresult.StatusPassed},
+	}})
+
+	assert.Equal(t, []FlakyTest{{Name: "pkg/TestFlaky", Commits: []string{"aaa"}, Passed: 1, Failed: 1}}, h.Flaky())
+	assert.NotContains(t, h.Tests, "pkg/TestSkipped")
+}
+
+func TestHistoryRecordLimit(t *testing.T) {
+	h := NewHistory()
+	for i := 0; i < MaxOutcomes+5; i++ {
+		h.Record("aaa", time.Now(), &testresult.Report{Cases: []testresult.Case{{Name: "TestA", Status: testresult.StatusPassed}}})
+	}
+
+	assert.Len(t, h.Tests["TestA"], MaxOutcomes)
+}
+
+func TestQuarantine(t *testing.T) {
+	entries, err := ParseQuarantine(strings.NewReader("# flaky network tests\ngithub.com/acme/api/TestDownload*\n\nTestTimeout\n"))
+	assert.NoError(t, err)
+	q := NewQuarantine(entries...)
+	assert.Equal(t, 2, q.Len())
+
+	quarantined, remaining := q.Split([]testresult.Case{
+		{Suite: "github.com/acme/api", Name: "TestDownloadLarge"},
+		{Suite: "github.com/acme/worker", Name: "TestTimeout"},
+		{Suite: "github.com/acme/api", Name: "TestUpload"},
+	})
+	assert.Len(t, quarantined, 2)
+	assert.Equal(t, "TestUpload", remaining[0].Name)
+}
@@ -0,0 +1,137 @@
+package flakytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+)
+
+// MaxOutcomes is the number of outcomes that are kept per test
+const MaxOutcomes = 50
+
+// Outcome is the result of a test in a single run
+type Outcome struct {
+	Commit    string            `json:"commit"`
+	Status    testresult.Status `json:"status"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// History contains the recent outcomes of all tests of a module
+type History struct {
+	Tests map[string][]Outcome `json:"tests"` // Tests contains the outcomes per full test name, oldest first
+}
+
+// FlakyTest is a test that passed and failed on the same commit
+type FlakyTest struct {
+	Name    string   `json:"name"`
+	Commits []string `json:"commits"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+}
+
+// NewHistory creates an empty history
+func NewHistory() *History {
+	return &History{Tests: make(map[string][]Outcome)}
+}
+
+// ObjectName returns the storage object name of the test history of a module
+func ObjectName(hostServer string, projectPath string, module string) string {
+	return fmt.Sprintf("test-history/%s/%s/%s.json", strings.ToLower(hostServer), strings.ToLower(projectPath), strings.ToLower(module))
+}
+
+// Record adds the outcomes of all executed tests, skipped tests are ignored
+func (h *History) Record(commit string, timestamp time.Time, report *testresult.Report) {
+	for _, c := range report.Cases {
+		if c.Status == testresult.StatusSkipped {
+			continue
+		}
+
+		name := c.FullName()
+		outcomes := append(h.Tests[name], Outcome{Commit: commit, Status: c.Status, Timestamp: timestamp})
+		if len(outcomes) > MaxOutcomes {
+			outcomes = outcomes[len(outcomes)-MaxOutcomes:]
+		}
+		h.Tests[name] = outcomes
+	}
+}
+
+// Flaky returns all tests that passed and failed on the same commit, sorted by name
+func (h *History) Flaky() []FlakyTest {
+	var result []FlakyTest
+	for name, outcomes := range h.Tests {
+		passedCommits := make(map[string]bool)
+		failedCommits := make(map[string]bool)
+		flaky := FlakyTest{Name: name}
+		for _, o := range outcomes {
+			if o.Status == testresult.StatusPassed {
+				passedCommits[o.Commit] = true
+				flaky.Passed++
+			} else {
+				failedCommits[o.Commit] = true
+				flaky.Failed++
+			}
+		}
+
+		for commit := range passedCommits {
+			if failedCommits[commit] {
+				flaky.Commits = append(flaky.Commits, commit)
+			}
+		}
+		if len(flaky.Commits) > 0 {
+			slices.Sort(flaky.Commits)
+			result = append(result, flaky)
+		}
+	}
+	slices.SortFunc(result, func(a, b FlakyTest) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result
+}
+
+// StoreHistory uploads the test history
+func StoreHistory(ctx context.Context, api storageapi.API, bucket string, objectName string, history *History) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal test history: %w", err)
+	}
+
+	err = api.PutObject(ctx, bucket, objectName, bytes.NewReader(data), "application/json")
+	if err != nil {
+		return fmt.Errorf("failed to store test history %s: %w", objectName, err)
+	}
+
+	return nil
+}
+
+// LoadHistory downloads a previously stored test history
+func LoadHistory(ctx context.Context, api storageapi.API, bucket string, objectName string) (*History, error) {
+	object, err := api.GetObject(ctx, bucket, objectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve test history %s: %w", objectName, err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test history %s: %w", objectName, err)
+	}
+
+	history := NewHistory()
+	if err = json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse test history %s: %w", objectName, err)
+	}
+	if history.Tests == nil {
+		history.Tests = make(map[string][]Outcome)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,80 @@
+package flakytest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/lib/formats/testresult"
+)
+
+// Quarantine is a list of tests whose failures are reported, but do not fail the build
+type Quarantine struct {
+	patterns []*regexp.Regexp
+}
+
+// NewQuarantine creates a quarantine from test names or patterns, * matches any characters
+func NewQuarantine(entries ...string) *Quarantine {
+	q := &Quarantine{}
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" || strings.HasPrefix(e, "#") {
+			continue
+		}
+
+		expr := strings.ReplaceAll(regexp.QuoteMeta(e), `\*`, ".*")
+		q.patterns = append(q.patterns, regexp.MustCompile("^"+expr+"$"))
+	}
+
+	return q
+}
+
+// ParseQuarantine reads the entries of a quarantine file, one test name or pattern per line, lines starting with # are comments
+func ParseQuarantine(r io.Reader) ([]string, error) {
+	var entries []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quarantine: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Len returns the number of quarantine entries
+func (q *Quarantine) Len() int {
+	return len(q.patterns)
+}
+
+// Contains checks if the test is quarantined, the entries are matched against the full test name and the test name
+func (q *Quarantine) Contains(c testresult.Case) bool {
+	for _, p := range q.patterns {
+		if p.MatchString(c.FullName()) || p.MatchString(c.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Split separates the quarantined test cases from the remaining ones
+func (q *Quarantine) Split(cases []testresult.Case) (quarantined []testresult.Case, remaining []testresult.Case) {
+	for _, c := range cases {
+		if q.Contains(c) {
+			quarantined = append(quarantined, c)
+		} else {
+			remaining = append(remaining, c)
+		}
+	}
+
+	return quarantined, remaining
+}
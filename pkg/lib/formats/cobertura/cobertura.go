@@ -8,12 +8,17 @@ import (
 )
 
 type Coverage struct {
-	XMLName    xml.Name     `xml:"coverage"`
-	LineRate   float64      `xml:"line-rate,attr"`
-	BranchRate float64      `xml:"branch-rate,attr"`
-	Version    string       `xml:"version,attr"`
-	Timestamp  int64        `xml:"timestamp,attr"`
-	Packages   []CobPackage `xml:"packages>package"`
+	XMLName         xml.Name     `xml:"coverage"`
+	LineRate        float64      `xml:"line-rate,attr"`
+	BranchRate      float64      `xml:"branch-rate,attr"`
+	LinesCovered    int          `xml:"lines-covered,attr"`
+	LinesValid      int          `xml:"lines-valid,attr"`
+	BranchesCovered int          `xml:"branches-covered,attr"`
+	BranchesValid   int          `xml:"branches-valid,attr"`
+	Version         string       `xml:"version,attr"`
+	Timestamp       int64        `xml:"timestamp,attr"`
+	Sources         []string     `xml:"sources>source"`
+	Packages        []CobPackage `xml:"packages>package"`
 }
 
 type CobPackage struct {
@@ -0,0 +1,74 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cidverse/cid/pkg/util"
+)
+
+type cloverCoverage struct {
+	XMLName xml.Name      `xml:"coverage"`
+	Project cloverProject `xml:"project"`
+}
+
+type cloverProject struct {
+	Packages []cloverPackage `xml:"package"`
+	Files    []cloverFile    `xml:"file"`
+}
+
+type cloverPackage struct {
+	Name  string       `xml:"name,attr"`
+	Files []cloverFile `xml:"file"`
+}
+
+type cloverFile struct {
+	Name  string       `xml:"name,attr"`
+	Path  string       `xml:"path,attr"`
+	Lines []cloverLine `xml:"line"`
+}
+
+type cloverLine struct {
+	Num        int    `xml:"num,attr"`
+	Type       string `xml:"type,attr"` // stmt, cond or method
+	Count      int    `xml:"count,attr"`
+	TrueCount  int    `xml:"truecount,attr"`
+	FalseCount int    `xml:"falsecount,attr"`
+}
+
+// ParseClover parses a Clover XML report
+func ParseClover(r io.Reader) (*Report, error) {
+	var clover cloverCoverage
+	if err := xml.NewDecoder(r).Decode(&clover); err != nil {
+		return nil, fmt.Errorf("failed to parse Clover XML: %w", err)
+	}
+
+	report := NewReport()
+	files := clover.Project.Files
+	for _, p := range clover.Project.Packages {
+		files = append(files, p.Files...)
+	}
+	for _, cf := range files {
+		f := report.File(util.GetStringOrDefault(cf.Path, cf.Name))
+		for _, l := range cf.Lines {
+			switch l.Type {
+			case "cond":
+				covered := 0
+				if l.TrueCount > 0 {
+					covered++
+				}
+				if l.FalseCount > 0 {
+					covered++
+				}
+				f.AddLine(l.Num, l.Count, 2, covered)
+			case "method":
+				// method entries are not a source line on their own
+			default:
+				f.AddLine(l.Num, l.Count, 0, 0)
+			}
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cidverse/cid/pkg/lib/formats/cobertura"
+)
+
+var conditionCoverageRegex = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+
+// ParseCobertura parses a Cobertura XML report
+func ParseCobertura(r io.Reader) (*Report, error) {
+	var cob cobertura.Coverage
+	if err := xml.NewDecoder(r).Decode(&cob); err != nil {
+		return nil, fmt.Errorf("failed to parse Cobertura XML: %w", err)
+	}
+
+	report := NewReport()
+	for _, p := range cob.Packages {
+		for _, c := range p.Classes {
+			f := report.File(c.Filename)
+			for _, l := range c.Lines {
+				branches, covered := 0, 0
+				if l.Branch == "true" {
+					if m := conditionCoverageRegex.FindStringSubmatch(l.ConditionCoverage); m != nil {
+						covered, _ = strconv.Atoi(m[1])
+						branches, _ = strconv.Atoi(m[2])
+					}
+				}
+				f.AddLine(l.Number, l.Hits, branches, covered)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// WriteCobertura writes the report as Cobertura XML, files are grouped into packages by directory
+func WriteCobertura(w io.Writer, report *Report) error {
+	summary := report.Summary()
+	cob := cobertura.Coverage{
+		LineRate:        summary.LineRate(),
+		BranchRate:      summary.BranchRate(),
+		LinesCovered:    summary.LinesCovered,
+		LinesValid:      summary.LinesValid,
+		BranchesCovered: summary.BranchesCovered,
+		BranchesValid:   summary.BranchesValid,
+		Version:         "cid",
+		Timestamp:       time.Now().UnixMilli(),
+		Sources:         []string{"."},
+	}
+
+	packageIndex := make(map[string]int)
+	packageSummary := make(map[string]Summary)
+	for _, f := range report.SortedFiles() {
+		dir := path.Dir(f.Path)
+		idx, ok := packageIndex[dir]
+		if !ok {
+			idx = len(cob.Packages)
+			packageIndex[dir] = idx
+			cob.Packages = append(cob.Packages, cobertura.CobPackage{Name: strings.ReplaceAll(dir, "/", ".")})
+		}
+
+		fileSummary := f.Summary()
+		packageSummary[dir] = packageSummary[dir].add(fileSummary)
+		class := cobertura.CobClass{
+			Name:     strings.TrimSuffix(path.Base(f.Path), path.Ext(f.Path)),
+			Filename: f.Path,
+		}
+		for _, l := range f.SortedLines() {
+			line := cobertura.CobLine{Number: l.Number, Hits: l.Hits, Branch: "false"}
+			if l.Branches > 0 {
+				line.Branch = "true"
+				line.ConditionCoverage = fmt.Sprintf("%d%% (%d/%d)", l.CoveredBranches*100/l.Branches, l.CoveredBranches, l.Branches)
+			}
+			class.Lines = append(class.Lines, line)
+		}
+		cob.Packages[idx].Classes = append(cob.Packages[idx].Classes, class)
+	}
+	for dir, idx := range packageIndex {
+		cob.Packages[idx].LineRate = packageSummary[dir].LineRate()
+		cob.Packages[idx].BranchRate = packageSummary[dir].BranchRate()
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(cob); err != nil {
+		return fmt.Errorf("failed to write Cobertura XML: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package coverage
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Report is a format independent coverage report with line and branch granularity
+type Report struct {
+	Files map[string]*File `json:"files"`
+}
+
+// File is the coverage of a single source file
+type File struct {
+	Path  string        `json:"path"`
+	Lines map[int]*Line `json:"lines"`
+}
+
+// Line is the coverage of a single source line
+type Line struct {
+	Number          int `json:"number"`
+	Hits            int `json:"hits"`
+	Branches        int `json:"branches,omitempty"`
+	CoveredBranches int `json:"covered_branches,omitempty"`
+}
+
+// Summary contains the aggregated counters of a report or file
+type Summary struct {
+	LinesValid      int `json:"lines_valid"`
+	LinesCovered    int `json:"lines_covered"`
+	BranchesValid   int `json:"branches_valid"`
+	BranchesCovered int `json:"branches_covered"`
+}
+
+// ProjectReport is the merged coverage of all modules of a project
+type ProjectReport struct {
+	Summary Summary            `json:"summary"`
+	Modules map[string]Summary `json:"modules"` // Modules contains the summary per module slug
+	Report  *Report            `json:"report"`
+}
+
+// NewReport creates an empty report
+func NewReport() *Report {
+	return &Report{Files: make(map[string]*File)}
+}
+
+// File returns the file with the given path, the file is created if it does not exist yet
+func (r *Report) File(filePath string) *File {
+	filePath = normalizePath(filePath)
+	f, ok := r.Files[filePath]
+	if !ok {
+		f = &File{Path: filePath, Lines: make(map[int]*Line)}
+		r.Files[filePath] = f
+	}
+
+	return f
+}
+
+// SortedFiles returns all files sorted by path
+func (r *Report) SortedFiles() []*File {
+	files := make([]*File, 0, len(r.Files))
+	for _, f := range r.Files {
+		files = append(files, f)
+	}
+	slices.SortFunc(files, func(a, b *File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return files
+}
+
+// Summary returns the aggregated counters of all files
+func (r *Report) Summary() Summary {
+	var s Summary
+	for _, f := range r.Files {
+		s = s.add(f.Summary())
+	}
+
+	return s
+}
+
+// WithPathPrefix returns a copy of the report with all file paths prefixed, e.g. to make module relative paths project relative
+func (r *Report) WithPathPrefix(prefix string) *Report {
+	return r.MapPaths(func(p string) string {
+		return path.Join(normalizePath(prefix), p)
+	})
+}
+
+// MapPaths returns a copy of the report with all file paths mapped by the given function
+func (r *Report) MapPaths(mapper func(p string) string) *Report {
+	result := NewReport()
+	for _, f := range r.Files {
+		target := result.File(mapper(f.Path))
+		for _, l := range f.Lines {
+			target.AddLine(l.Number, l.Hits, l.Branches, l.CoveredBranches)
+		}
+	}
+
+	return result
+}
+
+// AddLine records the coverage of a line, hits are summed up and the branch counters keep the highest value if the line is already present
+func (f *File) AddLine(number int, hits int, branches int, coveredBranches int) {
+	l, ok := f.Lines[number]
+	if !ok {
+		f.Lines[number] = &Line{Number: number, Hits: hits, Branches: branches, CoveredBranches: min(coveredBranches, branches)}
+		return
+	}
+
+	l.Hits += hits
+	l.Branches = max(l.Branches, branches)
+	l.CoveredBranches = min(max(l.CoveredBranches, coveredBranches), l.Branches)
+}
+
+// SortedLines returns all lines sorted by line number
+func (f *File) SortedLines() []*Line {
+	lines := make([]*Line, 0, len(f.Lines))
+	for _, l := range f.Lines {
+		lines = append(lines, l)
+	}
+	slices.SortFunc(lines, func(a, b *Line) int {
+		return a.Number - b.Number
+	})
+
+	return lines
+}
+
+// Summary returns the aggregated counters of the file
+func (f *File) Summary() Summary {
+	var s Summary
+	for _, l := range f.Lines {
+		s.LinesValid++
+		if l.Hits > 0 {
+			s.LinesCovered++
+		}
+		s.BranchesValid += l.Branches
+		s.BranchesCovered += l.CoveredBranches
+	}
+
+	return s
+}
+
+// LineRate returns the line coverage between 0 and 1
+func (s Summary) LineRate() float64 {
+	return rate(s.LinesCovered, s.LinesValid)
+}
+
+// BranchRate returns the branch coverage between 0 and 1
+func (s Summary) BranchRate() float64 {
+	return rate(s.BranchesCovered, s.BranchesValid)
+}
+
+// LinePercent returns the line coverage in percent
+func (s Summary) LinePercent() float64 {
+	return s.LineRate() * 100
+}
+
+func (s Summary) add(other Summary) Summary {
+	return Summary{
+		LinesValid:      s.LinesValid + other.LinesValid,
+		LinesCovered:    s.LinesCovered + other.LinesCovered,
+		BranchesValid:   s.BranchesValid + other.BranchesValid,
+		BranchesCovered: s.BranchesCovered + other.BranchesCovered,
+	}
+}
+
+// Merge combines multiple reports into one, the coverage of files present in multiple reports is combined
+func Merge(reports ...*Report) *Report {
+	result := NewReport()
+	for _, r := range reports {
+		if r == nil {
+			continue
+		}
+
+		for _, f := range r.Files {
+			target := result.File(f.Path)
+			for _, l := range f.Lines {
+				target.AddLine(l.Number, l.Hits, l.Branches, l.CoveredBranches)
+			}
+		}
+	}
+
+	return result
+}
+
+func rate(covered int, valid int) float64 {
+	if valid == 0 {
+		return 0
+	}
+
+	return float64(covered) / float64(valid)
+}
+
+func normalizePath(p string) string {
+	p = strings.ReplaceAll(p, "\\", "/")
+	if p == "" {
+		return p
+	}
+
+	return strings.TrimPrefix(path.Clean(p), "./")
+}
@@ -0,0 +1,175 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleLCOV = `TN:
+SF:src/index.js
+FN:1,main
+DA:1,1
+DA:2,1
+DA:3,0
+BRDA:2,0,0,1
+BRDA:2,0,1,-
+LF:3
+LH:2
+end_of_record
+`
+
+const sampleGoCoverProfile = `mode: atomic
+github.com/cidverse/example/pkg/math/add.go:3.24,5.2 1 4
+github.com/cidverse/example/pkg/math/add.go:7.24,9.2 1 0
+`
+
+const sampleClover = `<?xml version="1.0" encoding="UTF-8"?>
+<coverage generated="1700000000">
+  <project timestamp="1700000000">
+    <package name="App">
+      <file name="Service.php" path="src/Service.php">
+        <line num="5" type="method" name="run" count="1"/>
+        <line num="6" type="stmt" count="1"/>
+        <line num="7" type="cond" count="1" truecount="1" falsecount="0"/>
+        <line num="8" type="stmt" count="0"/>
+      </file>
+    </package>
+  </project>
+</coverage>`
+
+const sampleOpenCover = `<?xml version="1.0" encoding="utf-8"?>
+<CoverageSession>
+  <Modules>
+    <Module>
+      <Files>
+        <File uid="1" fullPath="src/Calculator.cs" />
+      </Files>
+      <Classes>
+        <Class>
+          <Methods>
+            <Method>
+              <FileRef uid="1" />
+              <SequencePoints>
+                <SequencePoint vc="2" sl="10" />
+                <SequencePoint vc="0" sl="11" />
+              </SequencePoints>
+              <BranchPoints>
+                <BranchPoint vc="1" sl="10" />
+                <BranchPoint vc="0" sl="10" />
+              </BranchPoints>
+            </Method>
+          </Methods>
+        </Class>
+      </Classes>
+    </Module>
+  </Modules>
+</CoverageSession>`
+
+const sampleJacoco = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<report name="example">
+  <package name="com/example">
+    <sourcefile name="Main.java">
+      <line nr="3" mi="0" ci="3" mb="1" cb="1"/>
+      <line nr="4" mi="2" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+  <counter type="LINE" missed="1" covered="1"/>
+</report>`
+
+func TestParseLCOV(t *testing.T) {
+	report, err := ParseLCOV(strings.NewReader(sampleLCOV))
+	assert.NoError(t, err)
+
+	s := report.Summary()
+	assert.Equal(t, Summary{LinesValid: 3, LinesCovered: 2, BranchesValid: 2, BranchesCovered: 1}, s)
+}
+
+func TestParseGoCoverProfile(t *testing.T) {
+	report, err := ParseGoCoverProfile(strings.NewReader(sampleGoCoverProfile), "github.com/cidverse/example")
+	assert.NoError(t, err)
+
+	assert.Contains(t, report.Files, "pkg/math/add.go")
+	s := report.Summary()
+	assert.Equal(t, 6, s.LinesValid)
+	assert.Equal(t, 3, s.LinesCovered)
+	assert.Equal(t, 50.0, s.LinePercent())
+}
+
+func TestParseClover(t *testing.T) {
+	report, err := ParseClover(strings.NewReader(sampleClover))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{LinesValid: 3, LinesCovered: 2, BranchesValid: 2, BranchesCovered: 1}, report.File("src/Service.php").Summary())
+}
+
+func TestParseOpenCover(t *testing.T) {
+	report, err := ParseOpenCover(strings.NewReader(sampleOpenCover))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{LinesValid: 2, LinesCovered: 1, BranchesValid: 2, BranchesCovered: 1}, report.File("src/Calculator.cs").Summary())
+}
+
+func TestParseJacoco(t *testing.T) {
+	report, err := ParseJacoco(strings.NewReader(sampleJacoco))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{LinesValid: 2, LinesCovered: 1, BranchesValid: 2, BranchesCovered: 1}, report.File("com/example/Main.java").Summary())
+}
+
+func TestMerge(t *testing.T) {
+	goReport, err := ParseGoCoverProfile(strings.NewReader(sampleGoCoverProfile), "github.com/cidverse/example")
+	assert.NoError(t, err)
+	jsReport, err := ParseLCOV(strings.NewReader(sampleLCOV))
+	assert.NoError(t, err)
+
+	merged := Merge(goReport.WithPathPrefix("backend"), jsReport.WithPathPrefix("frontend"), jsReport.WithPathPrefix("frontend"))
+	assert.Len(t, merged.Files, 2)
+	assert.Contains(t, merged.Files, "backend/pkg/math/add.go")
+	assert.Equal(t, 2, merged.File("frontend/src/index.js").Lines[1].Hits)
+	assert.Equal(t, Summary{LinesValid: 9, LinesCovered: 5, BranchesValid: 2, BranchesCovered: 1}, merged.Summary())
+}
+
+func TestWriteSonarGeneric(t *testing.T) {
+	report, err := ParseLCOV(strings.NewReader(sampleLCOV))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSonarGeneric(&buf, report))
+	assert.Contains(t, buf.String(), `<file path="src/index.js">`)
+	assert.Contains(t, buf.String(), `<lineToCover lineNumber="2" covered="true" branchesToCover="2" coveredBranches="1"></lineToCover>`)
+	assert.Contains(t, buf.String(), `<lineToCover lineNumber="3" covered="false"></lineToCover>`)
+}
+
+func TestCoberturaRoundTrip(t *testing.T) {
+	report, err := ParseLCOV(strings.NewReader(sampleLCOV))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(FormatCobertura, &buf, report))
+	assert.Contains(t, buf.String(), `filename="src/index.js"`)
+	assert.Contains(t, buf.String(), `condition-coverage="50% (1/2)"`)
+
+	parsed, err := Parse(FormatCobertura, &buf, ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, report.Summary(), parsed.Summary())
+}
+
+func TestLCOVRoundTrip(t *testing.T) {
+	report, err := ParseClover(strings.NewReader(sampleClover))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteLCOV(&buf, report))
+
+	parsed, err := ParseLCOV(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, report.Summary(), parsed.Summary())
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	_, err := Parse("unknown", strings.NewReader(""), ParseOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Format is the artifact format of a coverage report
+type Format string
+
+const (
+	FormatCobertura    Format = "cobertura"
+	FormatJacoco       Format = "jacoco"
+	FormatLCOV         Format = "lcov"
+	FormatGoCoverage   Format = "go-coverage" // go coverage profile, artifact format version "out"
+	FormatClover       Format = "clover"
+	FormatOpenCover    Format = "opencover"
+	FormatSonarGeneric Format = "sonarqube-generic-coverage"
+)
+
+// ParseOptions contains format specific parser options
+type ParseOptions struct {
+	GoModulePath string // GoModulePath is removed from the file paths of go coverage profiles
+}
+
+// Parse parses a coverage report of the given format
+func Parse(format Format, r io.Reader, opts ParseOptions) (*Report, error) {
+	switch format {
+	case FormatCobertura:
+		return ParseCobertura(r)
+	case FormatJacoco:
+		return ParseJacoco(r)
+	case FormatLCOV:
+		return ParseLCOV(r)
+	case FormatGoCoverage:
+		return ParseGoCoverProfile(r, opts.GoModulePath)
+	case FormatClover:
+		return ParseClover(r)
+	case FormatOpenCover:
+		return ParseOpenCover(r)
+	default:
+		return nil, fmt.Errorf("unsupported coverage format: %s", format)
+	}
+}
+
+// ParseFile parses a coverage report file of the given format
+func ParseFile(format Format, file string, opts ParseOptions) (*Report, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	return Parse(format, f, opts)
+}
+
+// Write writes the report in the given format
+func Write(format Format, w io.Writer, report *Report) error {
+	switch format {
+	case FormatCobertura:
+		return WriteCobertura(w, report)
+	case FormatLCOV:
+		return WriteLCOV(w, report)
+	case FormatSonarGeneric:
+		return WriteSonarGeneric(w, report)
+	default:
+		return fmt.Errorf("writing coverage format %s is not supported", format)
+	}
+}
@@ -0,0 +1,69 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseGoCoverProfile parses a go coverage profile (go test -coverprofile), import paths are converted to paths relative to the module root by removing the module path prefix
+func ParseGoCoverProfile(r io.Reader, modulePath string) (*Report, error) {
+	report := NewReport()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// format: name.go:line.column,line.column numberOfStatements count
+		fileName, block, ok := strings.Cut(line, ":")
+		fields := strings.Fields(block)
+		if !ok || len(fields) != 3 {
+			return nil, fmt.Errorf("go coverage profile line %d: invalid block [%s]", lineNo, line)
+		}
+		startPos, endPos, ok := strings.Cut(fields[0], ",")
+		if !ok {
+			return nil, fmt.Errorf("go coverage profile line %d: invalid position [%s]", lineNo, fields[0])
+		}
+		startLine, err := parseGoCoverLine(startPos)
+		if err != nil {
+			return nil, fmt.Errorf("go coverage profile line %d: %w", lineNo, err)
+		}
+		endLine, err := parseGoCoverLine(endPos)
+		if err != nil {
+			return nil, fmt.Errorf("go coverage profile line %d: %w", lineNo, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("go coverage profile line %d: invalid count: %w", lineNo, err)
+		}
+
+		if modulePath != "" {
+			fileName = strings.TrimPrefix(strings.TrimPrefix(fileName, modulePath), "/")
+		}
+		f := report.File(fileName)
+		for number := startLine; number <= endLine; number++ {
+			f.AddLine(number, count, 0, 0)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go coverage profile: %w", err)
+	}
+
+	return report, nil
+}
+
+func parseGoCoverLine(pos string) (int, error) {
+	lineStr, _, _ := strings.Cut(pos, ".")
+	number, err := strconv.Atoi(lineStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid line number [%s]: %w", pos, err)
+	}
+
+	return number, nil
+}
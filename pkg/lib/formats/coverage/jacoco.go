@@ -0,0 +1,34 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/cidverse/cid/pkg/lib/formats/jacoco"
+)
+
+// ParseJacoco parses a JaCoCo XML report, file paths are relative to the source root (e.g. com/example/Main.java)
+func ParseJacoco(r io.Reader) (*Report, error) {
+	var jr jacoco.Report
+	if err := xml.NewDecoder(r).Decode(&jr); err != nil {
+		return nil, fmt.Errorf("failed to parse JaCoCo XML: %w", err)
+	}
+
+	report := NewReport()
+	for _, p := range jr.Packages {
+		for _, sf := range p.SourceFiles {
+			f := report.File(path.Join(p.Name, sf.Name))
+			for _, l := range sf.Lines {
+				hits := 0
+				if l.CoveredInstructions > 0 {
+					hits = 1
+				}
+				f.AddLine(l.Number, hits, l.MissedBranches+l.CoveredBranches, l.CoveredBranches)
+			}
+		}
+	}
+
+	return report, nil
+}
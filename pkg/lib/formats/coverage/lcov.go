@@ -0,0 +1,104 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseLCOV parses a LCOV tracefile, see https://github.com/linux-test-project/lcov/blob/master/man/geninfo.1
+func ParseLCOV(r io.Reader) (*Report, error) {
+	report := NewReport()
+	var current *File
+	branches := make(map[int][2]int) // line -> total, covered
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, _ := strings.Cut(line, ":")
+
+		switch key {
+		case "SF":
+			current = report.File(value)
+			clear(branches)
+		case "DA":
+			if current == nil {
+				return nil, fmt.Errorf("lcov line %d: DA record outside of a source file", lineNo)
+			}
+			parts := strings.Split(value, ",")
+			if len(parts) < 2 {
+				return nil, fmt.Errorf("lcov line %d: invalid DA record", lineNo)
+			}
+			number, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("lcov line %d: invalid line number: %w", lineNo, err)
+			}
+			hits, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("lcov line %d: invalid hit count: %w", lineNo, err)
+			}
+			current.AddLine(number, hits, 0, 0)
+		case "BRDA":
+			if current == nil {
+				return nil, fmt.Errorf("lcov line %d: BRDA record outside of a source file", lineNo)
+			}
+			parts := strings.Split(value, ",")
+			if len(parts) < 4 {
+				return nil, fmt.Errorf("lcov line %d: invalid BRDA record", lineNo)
+			}
+			number, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("lcov line %d: invalid line number: %w", lineNo, err)
+			}
+			b := branches[number]
+			b[0]++
+			if parts[3] != "-" && parts[3] != "0" {
+				b[1]++
+			}
+			branches[number] = b
+		case "end_of_record":
+			if current != nil {
+				for number, b := range branches {
+					current.AddLine(number, 0, b[0], b[1])
+				}
+			}
+			current = nil
+			clear(branches)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lcov report: %w", err)
+	}
+
+	return report, nil
+}
+
+// WriteLCOV writes the report as LCOV tracefile
+func WriteLCOV(w io.Writer, report *Report) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range report.SortedFiles() {
+		s := f.Summary()
+		_, _ = fmt.Fprintf(bw, "SF:%s\n", f.Path)
+		for _, l := range f.SortedLines() {
+			for i := 0; i < l.Branches; i++ {
+				taken := "0"
+				if i < l.CoveredBranches {
+					taken = "1"
+				}
+				_, _ = fmt.Fprintf(bw, "BRDA:%d,0,%d,%s\n", l.Number, i, taken)
+			}
+		}
+		if s.BranchesValid > 0 {
+			_, _ = fmt.Fprintf(bw, "BRF:%d\nBRH:%d\n", s.BranchesValid, s.BranchesCovered)
+		}
+		for _, l := range f.SortedLines() {
+			_, _ = fmt.Fprintf(bw, "DA:%d,%d\n", l.Number, l.Hits)
+		}
+		_, _ = fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", s.LinesValid, s.LinesCovered)
+	}
+
+	return bw.Flush()
+}
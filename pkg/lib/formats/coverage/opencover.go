@@ -0,0 +1,112 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cidverse/cid/pkg/util"
+)
+
+type openCoverSession struct {
+	XMLName xml.Name          `xml:"CoverageSession"`
+	Modules []openCoverModule `xml:"Modules>Module"`
+}
+
+type openCoverModule struct {
+	Files   []openCoverFile  `xml:"Files>File"`
+	Classes []openCoverClass `xml:"Classes>Class"`
+}
+
+type openCoverFile struct {
+	UID      string `xml:"uid,attr"`
+	FullPath string `xml:"fullPath,attr"`
+}
+
+type openCoverClass struct {
+	Methods []openCoverMethod `xml:"Methods>Method"`
+}
+
+type openCoverMethod struct {
+	FileRef        *openCoverFileRef        `xml:"FileRef"`
+	SequencePoints []openCoverSequencePoint `xml:"SequencePoints>SequencePoint"`
+	BranchPoints   []openCoverBranchPoint   `xml:"BranchPoints>BranchPoint"`
+}
+
+type openCoverFileRef struct {
+	UID string `xml:"uid,attr"`
+}
+
+type openCoverSequencePoint struct {
+	VisitCount int    `xml:"vc,attr"`
+	StartLine  int    `xml:"sl,attr"`
+	FileID     string `xml:"fileid,attr"`
+}
+
+type openCoverBranchPoint struct {
+	VisitCount int    `xml:"vc,attr"`
+	StartLine  int    `xml:"sl,attr"`
+	FileID     string `xml:"fileid,attr"`
+}
+
+// ParseOpenCover parses an OpenCover XML report, as produced by dotnet coverlet
+func ParseOpenCover(r io.Reader) (*Report, error) {
+	var session openCoverSession
+	if err := xml.NewDecoder(r).Decode(&session); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenCover XML: %w", err)
+	}
+
+	report := NewReport()
+	for _, m := range session.Modules {
+		paths := make(map[string]string, len(m.Files))
+		for _, f := range m.Files {
+			paths[f.UID] = f.FullPath
+		}
+
+		for _, c := range m.Classes {
+			for _, method := range c.Methods {
+				methodFileID := ""
+				if method.FileRef != nil {
+					methodFileID = method.FileRef.UID
+				}
+
+				for _, sp := range method.SequencePoints {
+					filePath, ok := paths[util.GetStringOrDefault(sp.FileID, methodFileID)]
+					if !ok || sp.StartLine <= 0 {
+						continue
+					}
+					report.File(filePath).AddLine(sp.StartLine, sp.VisitCount, 0, 0)
+				}
+
+				// branch points of the same line are counted individually
+				type branchCounter struct{ total, covered int }
+				branches := make(map[string]map[int]*branchCounter)
+				for _, bp := range method.BranchPoints {
+					filePath, ok := paths[util.GetStringOrDefault(bp.FileID, methodFileID)]
+					if !ok || bp.StartLine <= 0 {
+						continue
+					}
+					if branches[filePath] == nil {
+						branches[filePath] = make(map[int]*branchCounter)
+					}
+					counter := branches[filePath][bp.StartLine]
+					if counter == nil {
+						counter = &branchCounter{}
+						branches[filePath][bp.StartLine] = counter
+					}
+					counter.total++
+					if bp.VisitCount > 0 {
+						counter.covered++
+					}
+				}
+				for filePath, lines := range branches {
+					for number, counter := range lines {
+						report.File(filePath).AddLine(number, 0, counter.total, counter.covered)
+					}
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
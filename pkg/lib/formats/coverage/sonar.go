@@ -0,0 +1,34 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cidverse/cid/pkg/lib/formats/sonargeneric"
+)
+
+// WriteSonarGeneric writes the report in the SonarQube generic coverage format
+func WriteSonarGeneric(w io.Writer, report *Report) error {
+	sonar := sonargeneric.SonarCoverage{Version: "1"}
+	for _, f := range report.SortedFiles() {
+		file := sonargeneric.SonarFile{Path: f.Path}
+		for _, l := range f.SortedLines() {
+			file.LinesToCover = append(file.LinesToCover, sonargeneric.LineToCover{
+				LineNumber:      l.Number,
+				Covered:         l.Hits > 0,
+				BranchesToCover: l.Branches,
+				CoveredBranches: l.CoveredBranches,
+			})
+		}
+		sonar.Files = append(sonar.Files, file)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(sonar); err != nil {
+		return fmt.Errorf("failed to write Sonar generic coverage XML: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package gitlabreport
+
+import (
+	"github.com/cidverse/cid/pkg/lib/sarifpolicy"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+)
+
+// CodeQualityIssue is a single entry of a GitLab Code Quality report, see https://docs.gitlab.com/ci/testing/code_quality/#code-quality-report-format
+type CodeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    CodeQualityLocation `json:"location"`
+}
+
+type CodeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeQualityLines `json:"lines"`
+}
+
+type CodeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+var codeQualitySeverity = map[sarifpolicy.Severity]string{
+	sarifpolicy.SeverityNone:     "info",
+	sarifpolicy.SeverityInfo:     "info",
+	sarifpolicy.SeverityLow:      "minor",
+	sarifpolicy.SeverityMedium:   "major",
+	sarifpolicy.SeverityHigh:     "critical",
+	sarifpolicy.SeverityCritical: "blocker",
+}
+
+// SarifToCodeQuality converts all results of a SARIF report into GitLab Code Quality issues, results suppressed by the tool are skipped
+func SarifToCodeQuality(report *sarif.Report) []CodeQualityIssue {
+	issues := make([]CodeQualityIssue, 0)
+	for _, f := range sarifpolicy.FindingsFromReport(report) {
+		if f.Suppressed {
+			continue
+		}
+
+		issues = append(issues, CodeQualityIssue{
+			Description: f.Message,
+			CheckName:   f.RuleID,
+			Fingerprint: f.Fingerprint,
+			Severity:    codeQualitySeverity[f.Severity],
+			Location: CodeQualityLocation{
+				Path:  f.Path,
+				Lines: CodeQualityLines{Begin: max(f.StartLine, 1)},
+			},
+		})
+	}
+
+	return issues
+}
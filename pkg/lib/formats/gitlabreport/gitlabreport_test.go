@@ -0,0 +1,75 @@
+package gitlabreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSarif = `{
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "semgrep", "version": "1.100.0", "rules": [{"id": "go.sql-injection", "properties": {"security-severity": "9.8"}}]}},
+      "results": [
+        {
+          "ruleId": "go.sql-injection",
+          "message": {"text": "possible sql injection"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "pkg/db/query.go"}, "region": {"startLine": 42}}}],
+          "partialFingerprints": {"primaryLocationLineHash": "abc123"}
+        },
+        {
+          "ruleId": "go.sql-injection",
+          "message": {"text": "suppressed sql injection"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "pkg/db/legacy.go"}, "region": {"startLine": 7}}}],
+          "suppressions": [{"kind": "inSource"}]
+        }
+      ]
+    }
+  ]
+}`
+
+func sampleReport(t *testing.T) *sarif.Report {
+	report, err := sarif.FromBytes([]byte(sampleSarif))
+	assert.NoError(t, err)
+	return report
+}
+
+func TestSarifToCodeQuality(t *testing.T) {
+	issues := SarifToCodeQuality(sampleReport(t))
+
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "go.sql-injection", issues[0].CheckName)
+	assert.Equal(t, "possible sql injection", issues[0].Description)
+	assert.Equal(t, "blocker", issues[0].Severity)
+	assert.Equal(t, "semgrep/primaryLocationLineHash=abc123", issues[0].Fingerprint)
+	assert.Equal(t, "pkg/db/query.go", issues[0].Location.Path)
+	assert.Equal(t, 42, issues[0].Location.Lines.Begin)
+}
+
+func TestSarifToCodeQualityEmpty(t *testing.T) {
+	issues := SarifToCodeQuality(nil)
+	assert.NotNil(t, issues)
+	assert.Len(t, issues, 0)
+}
+
+func TestSarifToSAST(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := SarifToSAST(sampleReport(t), start, start.Add(time.Minute))
+
+	assert.Equal(t, SASTSchemaVersion, result.Version)
+	assert.Equal(t, "semgrep", result.Scan.Scanner.ID)
+	assert.Equal(t, "1.100.0", result.Scan.Scanner.Version)
+	assert.Equal(t, constants.Version, result.Scan.Analyzer.Version)
+	assert.Equal(t, "sast", result.Scan.Type)
+	assert.Equal(t, "2026-01-02T03:04:05", result.Scan.StartTime)
+	assert.Equal(t, "2026-01-02T03:05:05", result.Scan.EndTime)
+	assert.Len(t, result.Vulnerabilities, 1)
+	assert.Equal(t, "Critical", result.Vulnerabilities[0].Severity)
+	assert.Equal(t, "pkg/db/query.go", result.Vulnerabilities[0].Location.File)
+	assert.Equal(t, 42, result.Vulnerabilities[0].Location.StartLine)
+	assert.Equal(t, SarifToSAST(sampleReport(t), start, start).Vulnerabilities[0].ID, result.Vulnerabilities[0].ID, "ids must be stable")
+}
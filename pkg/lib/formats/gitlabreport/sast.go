@@ -0,0 +1,126 @@
+package gitlabreport
+
+import (
+	"time"
+
+	"github.com/cidverse/cid/pkg/constants"
+	"github.com/cidverse/cid/pkg/lib/sarifpolicy"
+	"github.com/cidverse/cid/pkg/util"
+	"github.com/cidverse/go-ptr"
+	"github.com/google/uuid"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+)
+
+// SASTSchemaVersion is the version of the GitLab security report schema, see https://gitlab.com/gitlab-org/security-products/security-report-schemas
+const SASTSchemaVersion = "15.2.1"
+
+const sastTimeFormat = "2006-01-02T15:04:05"
+
+// SASTReport is a GitLab SAST report
+type SASTReport struct {
+	Version         string              `json:"version"`
+	Vulnerabilities []SASTVulnerability `json:"vulnerabilities"`
+	Scan            SASTScan            `json:"scan"`
+}
+
+type SASTVulnerability struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Severity    string           `json:"severity"`
+	Identifiers []SASTIdentifier `json:"identifiers"`
+	Location    SASTLocation     `json:"location"`
+}
+
+type SASTIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type SASTLocation struct {
+	File      string `json:"file,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+}
+
+type SASTScan struct {
+	Analyzer  SASTTool `json:"analyzer"`
+	Scanner   SASTTool `json:"scanner"`
+	Type      string   `json:"type"`
+	StartTime string   `json:"start_time"`
+	EndTime   string   `json:"end_time"`
+	Status    string   `json:"status"`
+}
+
+type SASTTool struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Version string     `json:"version"`
+	Vendor  SASTVendor `json:"vendor"`
+}
+
+type SASTVendor struct {
+	Name string `json:"name"`
+}
+
+var sastSeverity = map[sarifpolicy.Severity]string{
+	sarifpolicy.SeverityNone:     "Unknown",
+	sarifpolicy.SeverityInfo:     "Info",
+	sarifpolicy.SeverityLow:      "Low",
+	sarifpolicy.SeverityMedium:   "Medium",
+	sarifpolicy.SeverityHigh:     "High",
+	sarifpolicy.SeverityCritical: "Critical",
+}
+
+// SarifToSAST converts a SARIF report into a GitLab SAST report, results suppressed by the tool are skipped
+func SarifToSAST(report *sarif.Report, startTime time.Time, endTime time.Time) SASTReport {
+	scanner := SASTTool{ID: "sarif", Name: "sarif", Version: "unknown", Vendor: SASTVendor{Name: "unknown"}}
+	if report != nil && len(report.Runs) > 0 && report.Runs[0].Tool != nil && report.Runs[0].Tool.Driver != nil {
+		driver := report.Runs[0].Tool.Driver
+		name := ptr.Value(driver.Name)
+		scanner = SASTTool{
+			ID:      name,
+			Name:    name,
+			Version: util.GetStringOrDefault(ptr.Value(driver.Version), util.GetStringOrDefault(ptr.Value(driver.SemanticVersion), "unknown")),
+			Vendor:  SASTVendor{Name: util.GetStringOrDefault(ptr.Value(driver.Organization), name)},
+		}
+	}
+
+	vulnerabilities := make([]SASTVulnerability, 0)
+	for _, f := range sarifpolicy.FindingsFromReport(report) {
+		if f.Suppressed {
+			continue
+		}
+
+		vulnerabilities = append(vulnerabilities, SASTVulnerability{
+			ID:          uuid.NewSHA1(uuid.NameSpaceOID, []byte(f.Fingerprint)).String(),
+			Name:        f.RuleID,
+			Description: f.Message,
+			Severity:    sastSeverity[f.Severity],
+			Identifiers: []SASTIdentifier{
+				{
+					Type:  f.Tool + "_rule_id",
+					Name:  f.RuleID,
+					Value: f.RuleID,
+				},
+			},
+			Location: SASTLocation{
+				File:      f.Path,
+				StartLine: f.StartLine,
+			},
+		})
+	}
+
+	return SASTReport{
+		Version:         SASTSchemaVersion,
+		Vulnerabilities: vulnerabilities,
+		Scan: SASTScan{
+			Analyzer:  SASTTool{ID: "cid", Name: "cid", Version: constants.Version, Vendor: SASTVendor{Name: "cidverse"}},
+			Scanner:   scanner,
+			Type:      "sast",
+			StartTime: startTime.UTC().Format(sastTimeFormat),
+			EndTime:   endTime.UTC().Format(sastTimeFormat),
+			Status:    "success",
+		},
+	}
+}
@@ -9,9 +9,29 @@ import (
 
 type Report struct {
 	XMLName  xml.Name  `xml:"report"`
+	Packages []Package `xml:"package"`
 	Counters []Counter `xml:"counter"`
 }
 
+type Package struct {
+	Name        string       `xml:"name,attr"`
+	SourceFiles []SourceFile `xml:"sourcefile"`
+}
+
+type SourceFile struct {
+	Name  string `xml:"name,attr"`
+	Lines []Line `xml:"line"`
+}
+
+// Line is the coverage of a single source line, see https://www.jacoco.org/jacoco/trunk/coverage/report.dtd
+type Line struct {
+	Number              int `xml:"nr,attr"`
+	MissedInstructions  int `xml:"mi,attr"`
+	CoveredInstructions int `xml:"ci,attr"`
+	MissedBranches      int `xml:"mb,attr"`
+	CoveredBranches     int `xml:"cb,attr"`
+}
+
 type Counter struct {
 	Type    string `xml:"type,attr"`
 	Missed  int    `xml:"missed,attr"`
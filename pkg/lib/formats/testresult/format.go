@@ -0,0 +1,32 @@
+package testresult
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format is the artifact format of a test report
+type Format string
+
+const (
+	FormatJUnit      Format = "junit"
+	FormatGoTestJSON Format = "go-test-json"
+	FormatTRX        Format = "trx"
+	FormatTAP        Format = "tap"
+)
+
+// Parse parses a test report of the given format
+func Parse(format Format, r io.Reader) (*Report, error) {
+	switch format {
+	case FormatJUnit:
+		return ParseJUnit(r)
+	case FormatGoTestJSON:
+		return ParseGoTestJSON(r)
+	case FormatTRX:
+		return ParseTRX(r)
+	case FormatTAP:
+		return ParseTAP(r)
+	default:
+		return nil, fmt.Errorf("unsupported test report format: %s", format)
+	}
+}
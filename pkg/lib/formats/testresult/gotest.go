@@ -0,0 +1,96 @@
+package testresult
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// goTestEvent is a single line of the go test -json output, see https://pkg.go.dev/cmd/test2json
+type goTestEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+}
+
+// ParseGoTestJSON parses the output of go test -json
+func ParseGoTestJSON(r io.Reader) (*Report, error) {
+	report := &Report{}
+	output := make(map[string]*strings.Builder)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue // go test -json may contain non-json output, e.g. build errors
+		}
+
+		var event goTestEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("go test json line %d: %w", lineNo, err)
+		}
+		if event.Test == "" {
+			continue // package level events
+		}
+
+		key := event.Package + "/" + event.Test
+		switch event.Action {
+		case "output":
+			if output[key] == nil {
+				output[key] = &strings.Builder{}
+			}
+			output[key].WriteString(event.Output)
+		case "pass", "fail", "skip":
+			status := map[string]Status{"pass": StatusPassed, "fail": StatusFailed, "skip": StatusSkipped}[event.Action]
+			c := Case{
+				Suite:    event.Package,
+				Name:     event.Test,
+				Status:   status,
+				Duration: time.Duration(event.Elapsed * float64(time.Second)),
+			}
+			if status == StatusFailed && output[key] != nil {
+				c.Message = strings.TrimSpace(output[key].String())
+			}
+			report.Cases = append(report.Cases, c)
+			delete(output, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go test json: %w", err)
+	}
+	report.Cases = withoutParentTests(report.Cases)
+
+	return report, nil
+}
+
+// withoutParentTests removes tests that have subtests, as go test reports a result for the parent and each subtest - a failed parent is kept if none of its subtests failed, as the failure is caused by the parent itself
+func withoutParentTests(cases []Case) []Case {
+	hasSubtests := make(map[string]bool)
+	hasFailedSubtests := make(map[string]bool)
+	for _, c := range cases {
+		for i := strings.LastIndex(c.Name, "/"); i > 0; i = strings.LastIndex(c.Name[:i], "/") {
+			parent := c.Suite + "/" + c.Name[:i]
+			hasSubtests[parent] = true
+			if c.Status == StatusFailed {
+				hasFailedSubtests[parent] = true
+			}
+		}
+	}
+
+	result := make([]Case, 0, len(cases))
+	for _, c := range cases {
+		key := c.Suite + "/" + c.Name
+		if hasSubtests[key] && (c.Status != StatusFailed || hasFailedSubtests[key]) {
+			continue
+		}
+		result = append(result, c)
+	}
+
+	return result
+}
@@ -0,0 +1,102 @@
+package testresult
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junitTestSuite is used for both <testsuites> and <testsuite> elements, as both can contain test cases and nested suites
+type junitTestSuite struct {
+	XMLName xml.Name
+	Name    string           `xml:"name,attr"`
+	Cases   []junitTestCase  `xml:"testcase"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ParseJUnit parses a JUnit XML report, the root element can be <testsuites> or a single <testsuite>
+func ParseJUnit(r io.Reader) (*Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JUnit XML: %w", err)
+	}
+
+	var root junitTestSuite
+	if err = xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JUnit XML: %w", err)
+	}
+	if root.XMLName.Local == "testsuites" {
+		root.Name = "" // the name of the <testsuites> element describes the whole run, not a suite
+	}
+
+	report := &Report{}
+	appendJUnitSuite(report, root)
+
+	return report, nil
+}
+
+func appendJUnitSuite(report *Report, suite junitTestSuite) {
+	for _, tc := range suite.Cases {
+		c := Case{
+			Suite:    suite.Name,
+			Name:     tc.Name,
+			Status:   StatusPassed,
+			Duration: parseSeconds(tc.Time),
+		}
+		if c.Suite == "" {
+			c.Suite = tc.Classname
+		}
+
+		switch {
+		case tc.Failure != nil:
+			c.Status = StatusFailed
+			c.Message = junitMessageText(tc.Failure)
+		case tc.Error != nil:
+			c.Status = StatusError
+			c.Message = junitMessageText(tc.Error)
+		case tc.Skipped != nil:
+			c.Status = StatusSkipped
+			c.Message = junitMessageText(tc.Skipped)
+		}
+		report.Cases = append(report.Cases, c)
+	}
+
+	for _, nested := range suite.Suites {
+		appendJUnitSuite(report, nested)
+	}
+}
+
+func junitMessageText(m *junitMessage) string {
+	if m.Message != "" {
+		return m.Message
+	}
+
+	return strings.TrimSpace(m.Text)
+}
+
+// parseSeconds parses a duration in (fractional) seconds, e.g. 0.123
+func parseSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
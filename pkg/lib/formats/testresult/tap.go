@@ -0,0 +1,55 @@
+package testresult
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var tapTestLineRegex = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*)(?:#\s*(\S+)\s*(.*))?$`)
+
+// ParseTAP parses a Test Anything Protocol stream, see https://testanything.org/tap-version-14-specification.html
+func ParseTAP(r io.Reader) (*Report, error) {
+	report := &Report{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "Bail out!") {
+			report.Cases = append(report.Cases, Case{Name: "bail out", Status: StatusError, Message: strings.TrimSpace(strings.TrimPrefix(line, "Bail out!"))})
+			break
+		}
+
+		m := tapTestLineRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		c := Case{
+			Name:   strings.TrimSpace(m[3]),
+			Status: StatusPassed,
+		}
+		if c.Name == "" {
+			c.Name = "test " + m[2]
+		}
+		if m[1] == "not ok" {
+			c.Status = StatusFailed
+		}
+		switch strings.ToUpper(m[4]) {
+		case "SKIP":
+			c.Status = StatusSkipped
+			c.Message = strings.TrimSpace(m[5])
+		case "TODO":
+			c.Status = StatusSkipped // failing todo tests must not fail the run
+			c.Message = strings.TrimSpace(m[5])
+		}
+		report.Cases = append(report.Cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read TAP stream: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,164 @@
+package testresult
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+)
+
+// Case is the result of a single test
+type Case struct {
+	Suite    string        `json:"suite,omitempty"`
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Message  string        `json:"message,omitempty"`
+}
+
+// FullName returns the name of the test including the suite
+func (c Case) FullName() string {
+	if c.Suite == "" {
+		return c.Name
+	}
+
+	return c.Suite + "/" + c.Name
+}
+
+// Report is a format independent test report
+type Report struct {
+	Cases []Case `json:"cases"`
+}
+
+// Summary contains the aggregated counters of a report
+type Summary struct {
+	Total    int           `json:"total"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Errors   int           `json:"errors"`
+	Skipped  int           `json:"skipped"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Successful returns true if there are no failed tests
+func (s Summary) Successful() bool {
+	return s.Failed == 0 && s.Errors == 0
+}
+
+// Add returns the sum of both summaries
+func (s Summary) Add(other Summary) Summary {
+	return Summary{
+		Total:    s.Total + other.Total,
+		Passed:   s.Passed + other.Passed,
+		Failed:   s.Failed + other.Failed,
+		Errors:   s.Errors + other.Errors,
+		Skipped:  s.Skipped + other.Skipped,
+		Duration: s.Duration + other.Duration,
+	}
+}
+
+// Summary returns the aggregated counters of all test cases
+func (r *Report) Summary() Summary {
+	var s Summary
+	for _, c := range r.Cases {
+		s.Total++
+		s.Duration += c.Duration
+		switch c.Status {
+		case StatusPassed:
+			s.Passed++
+		case StatusFailed:
+			s.Failed++
+		case StatusError:
+			s.Errors++
+		case StatusSkipped:
+			s.Skipped++
+		}
+	}
+
+	return s
+}
+
+// Failures returns all failed tests
+func (r *Report) Failures() []Case {
+	var result []Case
+	for _, c := range r.Cases {
+		if c.Status == StatusFailed || c.Status == StatusError {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// Slowest returns the n slowest tests
+func (r *Report) Slowest(n int) []Case {
+	sorted := slices.Clone(r.Cases)
+	slices.SortStableFunc(sorted, func(a, b Case) int {
+		if a.Duration != b.Duration {
+			return int(b.Duration - a.Duration)
+		}
+		return strings.Compare(a.FullName(), b.FullName())
+	})
+
+	return sorted[:min(n, len(sorted))]
+}
+
+// Merge combines the test cases of multiple reports
+func Merge(reports ...*Report) *Report {
+	result := &Report{}
+	for _, r := range reports {
+		if r != nil {
+			result.Cases = append(result.Cases, r.Cases...)
+		}
+	}
+
+	return result
+}
+
+// ModuleResult is the aggregated test result of a module
+type ModuleResult struct {
+	Summary  Summary `json:"summary"`
+	Failures []Case  `json:"failures,omitempty"`
+	Slowest  []Case  `json:"slowest,omitempty"`
+}
+
+// ProjectResult is the aggregated test result of all modules, stored as report:test-result artifact
+type ProjectResult struct {
+	Summary Summary                 `json:"summary"`
+	Modules map[string]ModuleResult `json:"modules"` // Modules contains the result per module slug
+}
+
+// NewModuleResult aggregates the report of a module
+func NewModuleResult(report *Report, slowest int) ModuleResult {
+	return ModuleResult{
+		Summary:  report.Summary(),
+		Failures: report.Failures(),
+		Slowest:  report.Slowest(slowest),
+	}
+}
+
+// ReadProjectResultFile reads a report:test-result artifact
+func ReadProjectResultFile(file string) (*ProjectResult, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test result %s: %w", file, err)
+	}
+
+	var result ProjectResult
+	if err = json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse test result %s: %w", file, err)
+	}
+
+	return &result, nil
+}
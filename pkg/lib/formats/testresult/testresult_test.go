@@ -0,0 +1,133 @@
+package testresult
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJUnit(t *testing.T) {
+	report, err := ParseJUnit(strings.NewReader(`<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="pkg/a">
+    <testcase name="TestOk" classname="pkg/a" time="0.5"/>
+    <testcase name="TestFail" classname="pkg/a" time="1.25"><failure message="expected 1">trace</failure></testcase>
+    <testcase name="TestSkip" classname="pkg/a" time="0"><skipped/></testcase>
+  </testsuite>
+</testsuites>`))
+	assert.NoError(t, err)
+
+	s := report.Summary()
+	assert.Equal(t, Summary{Total: 3, Passed: 1, Failed: 1, Skipped: 1, Duration: 1750 * time.Millisecond}, s)
+	assert.Equal(t, "expected 1", report.Failures()[0].Message)
+	assert.Equal(t, "pkg/a/TestFail", report.Slowest(1)[0].FullName())
+}
+
+func TestParseJUnitSingleSuite(t *testing.T) {
+	report, err := ParseJUnit(strings.NewReader(`<testsuite name="s"><testcase name="a"><error message="boom"/></testcase></testsuite>`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Summary().Errors)
+	assert.False(t, report.Summary().Successful())
+}
+
+func TestParseJUnitNestedSuites(t *testing.T) {
+	report, err := ParseJUnit(strings.NewReader(`<testsuite name="root">
+  <testcase name="top"/>
+  <testsuite name="nested">
+    <testcase name="inner"><failure message="boom"/></testcase>
+  </testsuite>
+</testsuite>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{Total: 2, Passed: 1, Failed: 1}, report.Summary())
+	assert.Equal(t, "root/top", report.Cases[0].FullName())
+	assert.Equal(t, "nested/inner", report.Cases[1].FullName())
+}
+
+func TestParseJUnitTestSuitesWithCases(t *testing.T) {
+	report, err := ParseJUnit(strings.NewReader(`<testsuites name="all">
+  <testcase name="top" classname="pkg"/>
+  <testsuite name="s"><testcase name="a"/></testsuite>
+</testsuites>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, report.Summary().Total)
+	assert.Equal(t, "pkg/top", report.Cases[0].FullName())
+}
+
+func TestParseGoTestJSON(t *testing.T) {
+	report, err := ParseGoTestJSON(strings.NewReader(`{"Action":"run","Package":"example.com/a","Test":"TestA"}
+{"Action":"output","Package":"example.com/a","Test":"TestA","Output":"a_test.go:10: wrong\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestA","Elapsed":0.2}
+{"Action":"pass","Package":"example.com/a","Test":"TestB","Elapsed":0.1}
+{"Action":"skip","Package":"example.com/a","Test":"TestC","Elapsed":0}
+{"Action":"fail","Package":"example.com/a","Elapsed":0.3}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{Total: 3, Passed: 1, Failed: 1, Skipped: 1, Duration: 300 * time.Millisecond}, report.Summary())
+	assert.Equal(t, "a_test.go:10: wrong", report.Failures()[0].Message)
+}
+
+func TestParseGoTestJSONSubtests(t *testing.T) {
+	report, err := ParseGoTestJSON(strings.NewReader(`{"Action":"pass","Package":"example.com/a","Test":"TestA/one","Elapsed":0.1}
+{"Action":"output","Package":"example.com/a","Test":"TestA/two","Output":"a_test.go:20: wrong\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestA/two","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/a","Test":"TestA","Elapsed":0.2}
+{"Action":"pass","Package":"example.com/a","Test":"TestB/nested/deep","Elapsed":0.1}
+{"Action":"pass","Package":"example.com/a","Test":"TestB/nested","Elapsed":0.1}
+{"Action":"pass","Package":"example.com/a","Test":"TestB","Elapsed":0.1}
+{"Action":"pass","Package":"example.com/a","Test":"TestC/ok","Elapsed":0.1}
+{"Action":"output","Package":"example.com/a","Test":"TestC","Output":"a_test.go:40: cleanup failed\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestC","Elapsed":0.1}`))
+	assert.NoError(t, err)
+
+	var names []string
+	for _, c := range report.Cases {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"TestA/one", "TestA/two", "TestB/nested/deep", "TestC/ok", "TestC"}, names)
+	assert.Equal(t, Summary{Total: 5, Passed: 3, Failed: 2, Duration: 500 * time.Millisecond}, report.Summary())
+	assert.Equal(t, "a_test.go:40: cleanup failed", report.Failures()[1].Message)
+}
+
+func TestParseTRX(t *testing.T) {
+	report, err := ParseTRX(strings.NewReader(`<TestRun xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="1" testName="Adds" outcome="Passed" duration="00:00:01.5000000"/>
+    <UnitTestResult testId="2" testName="Divides" outcome="Failed" duration="00:00:00.2500000"><Output><ErrorInfo><Message>division by zero</Message></ErrorInfo></Output></UnitTestResult>
+    <UnitTestResult testId="3" testName="Ignored" outcome="NotExecuted"/>
+  </Results>
+  <TestDefinitions>
+    <UnitTest id="1"><TestMethod className="Calc.Tests"/></UnitTest>
+    <UnitTest id="2"><TestMethod className="Calc.Tests"/></UnitTest>
+  </TestDefinitions>
+</TestRun>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{Total: 3, Passed: 1, Failed: 1, Skipped: 1, Duration: 1750 * time.Millisecond}, report.Summary())
+	assert.Equal(t, "Calc.Tests/Divides", report.Failures()[0].FullName())
+	assert.Equal(t, "division by zero", report.Failures()[0].Message)
+}
+
+func TestParseTAP(t *testing.T) {
+	report, err := ParseTAP(strings.NewReader(`TAP version 14
+1..4
+ok 1 - adds numbers
+not ok 2 - divides numbers
+  ---
+  message: division by zero
+  ...
+ok 3 - network # SKIP offline
+not ok 4 - later # TODO not implemented`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{Total: 4, Passed: 1, Failed: 1, Skipped: 2}, report.Summary())
+	assert.Equal(t, "divides numbers", report.Failures()[0].Name)
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	_, err := Parse("unknown", strings.NewReader(""))
+	assert.Error(t, err)
+}
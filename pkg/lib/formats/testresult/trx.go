@@ -0,0 +1,84 @@
+package testresult
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type trxTestRun struct {
+	XMLName xml.Name        `xml:"TestRun"`
+	Results []trxUnitResult `xml:"Results>UnitTestResult"`
+	Tests   []trxUnitTest   `xml:"TestDefinitions>UnitTest"`
+}
+
+type trxUnitResult struct {
+	TestID   string `xml:"testId,attr"`
+	TestName string `xml:"testName,attr"`
+	Outcome  string `xml:"outcome,attr"`
+	Duration string `xml:"duration,attr"`
+	Message  string `xml:"Output>ErrorInfo>Message"`
+}
+
+type trxUnitTest struct {
+	ID         string `xml:"id,attr"`
+	TestMethod struct {
+		ClassName string `xml:"className,attr"`
+	} `xml:"TestMethod"`
+}
+
+// ParseTRX parses a Visual Studio test results (TRX) file, as produced by dotnet test
+func ParseTRX(r io.Reader) (*Report, error) {
+	var run trxTestRun
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return nil, fmt.Errorf("failed to parse TRX: %w", err)
+	}
+
+	classNames := make(map[string]string, len(run.Tests))
+	for _, t := range run.Tests {
+		classNames[t.ID] = t.TestMethod.ClassName
+	}
+
+	report := &Report{}
+	for _, res := range run.Results {
+		c := Case{
+			Suite:    classNames[res.TestID],
+			Name:     res.TestName,
+			Duration: parseTRXDuration(res.Duration),
+			Message:  strings.TrimSpace(res.Message),
+		}
+		switch strings.ToLower(res.Outcome) {
+		case "passed":
+			c.Status = StatusPassed
+		case "failed":
+			c.Status = StatusFailed
+		case "error", "timeout", "aborted":
+			c.Status = StatusError
+		default:
+			c.Status = StatusSkipped // notexecuted, inconclusive, ...
+		}
+		report.Cases = append(report.Cases, c)
+	}
+
+	return report, nil
+}
+
+// parseTRXDuration parses a duration in the format hh:mm:ss.fffffff
+func parseTRXDuration(value string) time.Duration {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	hours, errH := strconv.Atoi(parts[0])
+	minutes, errM := strconv.Atoi(parts[1])
+	seconds, errS := strconv.ParseFloat(parts[2], 64)
+	if errH != nil || errM != nil || errS != nil {
+		return 0
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second))
+}
@@ -0,0 +1,69 @@
+package gitdiff
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrShallowRepository is returned if a commit can not be found because the history of the repository is incomplete
+var ErrShallowRepository = errors.New("repository is a shallow clone")
+
+// IsShallow returns true if the repository is a shallow clone
+func IsShallow(dir string) (bool, error) {
+	repo, err := openRepository(dir)
+	if err != nil {
+		return false, err
+	}
+
+	return isShallow(repo)
+}
+
+func openRepository(dir string) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	return repo, nil
+}
+
+func isShallow(repo *git.Repository) (bool, error) {
+	shallowCommits, err := repo.Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("failed to read shallow commits: %w", err)
+	}
+
+	return len(shallowCommits) > 0, nil
+}
+
+// resolveCommit resolves the revision to a commit, branches that are not present locally are resolved using the origin remote as ci systems usually only fetch remote branches
+func resolveCommit(repo *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		remoteHash, remoteErr := repo.ResolveRevision(plumbing.Revision("origin/" + revision))
+		if remoteErr != nil {
+			return nil, fmt.Errorf("failed to resolve revision %s: %w", revision, err)
+		}
+		hash = remoteHash
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", revision, err)
+	}
+
+	return commit, nil
+}
+
+// shallowError marks the error as caused by a shallow clone, as the required commits might not be fetched
+func shallowError(err error, shallow bool) error {
+	if shallow {
+		return fmt.Errorf("%w, fetch the full history or the target branch: %w", ErrShallowRepository, err)
+	}
+
+	return err
+}
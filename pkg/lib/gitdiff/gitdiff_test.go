@@ -0,0 +1,111 @@
+package gitdiff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func commitFile(t *testing.T, repo *git.Repository, dir string, file string, content string) plumbing.Hash {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add(file)
+	require.NoError(t, err)
+	hash, err := wt.Commit("update "+file, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()}})
+	require.NoError(t, err)
+
+	return hash
+}
+
+func testRepository(t *testing.T) (dir string, base plumbing.Hash) {
+	t.Helper()
+
+	dir = t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")}})
+	require.NoError(t, err)
+	base = commitFile(t, repo, dir, "a.txt", "a")
+
+	// feature branch
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	commitFile(t, repo, dir, "b.txt", "b")
+
+	// main advances after the feature branch has been created
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("main")}))
+	commitFile(t, repo, dir, "c.txt", "c")
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature")}))
+
+	return dir, base
+}
+
+func TestMergeBase(t *testing.T) {
+	dir, base := testRepository(t)
+
+	hash, err := MergeBase(dir, "HEAD", "main")
+	assert.NoError(t, err)
+	assert.Equal(t, base.String(), hash)
+}
+
+func TestMergeBaseUnknownBranch(t *testing.T) {
+	dir, _ := testRepository(t)
+
+	_, err := MergeBase(dir, "HEAD", "develop")
+	assert.ErrorContains(t, err, "failed to resolve revision develop")
+	assert.NotErrorIs(t, err, ErrShallowRepository)
+}
+
+func TestMergeBaseShallow(t *testing.T) {
+	dir, base := testRepository(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "shallow"), []byte(base.String()+"\n"), 0644))
+
+	shallow, err := IsShallow(dir)
+	assert.NoError(t, err)
+	assert.True(t, shallow)
+
+	_, err = MergeBase(dir, "HEAD", "develop")
+	assert.ErrorIs(t, err, ErrShallowRepository)
+}
+
+func TestHunks(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	base := commitFile(t, repo, dir, "main.go", "package main\n\nfunc a() {}\n\nfunc b() {}\n")
+	commitFile(t, repo, dir, "main.go", "package main\n\nfunc a() {}\n\n\nfunc c() {}\nfunc d() {}\n")
+	commitFile(t, repo, dir, "new.go", "package main\n\nfunc e() {}")
+
+	hunks, err := Hunks(dir, base.String(), "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]Hunk{
+		"main.go": {{FromLine: 5, FromLines: 1, ToLine: 5, ToLines: 3}},
+		"new.go":  {{FromLine: 0, FromLines: 0, ToLine: 1, ToLines: 3}},
+	}, hunks)
+	assert.Equal(t, []int{5, 6, 7}, hunks["main.go"][0].ToLineNumbers())
+}
+
+func TestHunksDeletedLines(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	base := commitFile(t, repo, dir, "main.go", "a\nb\nc\nd\n")
+	commitFile(t, repo, dir, "main.go", "a\nd\ne\n")
+
+	hunks, err := Hunks(dir, base.String(), "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, []Hunk{
+		{FromLine: 2, FromLines: 2, ToLine: 1, ToLines: 0},
+		{FromLine: 4, FromLines: 0, ToLine: 3, ToLines: 1},
+	}, hunks["main.go"])
+	assert.Empty(t, hunks["main.go"][0].ToLineNumbers())
+}
@@ -0,0 +1,128 @@
+package gitdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+// Hunk is a changed region of a file with unified diff semantics, line numbers start at 1 and a side without lines refers to the line before the change
+type Hunk struct {
+	FromLine  int `json:"from_line"`
+	FromLines int `json:"from_lines"`
+	ToLine    int `json:"to_line"`
+	ToLines   int `json:"to_lines"`
+}
+
+// ToLineNumbers returns the line numbers of the new file version that have been added or modified by the hunk
+func (h Hunk) ToLineNumbers() []int {
+	lines := make([]int, 0, h.ToLines)
+	for i := 0; i < h.ToLines; i++ {
+		lines = append(lines, h.ToLine+i)
+	}
+
+	return lines
+}
+
+// Hunks returns the changed regions between both revisions per file, keyed by the path of the new file version or the old path for deleted files - binary files are skipped
+func Hunks(dir string, from string, to string) (map[string][]Hunk, error) {
+	repo, err := openRepository(dir)
+	if err != nil {
+		return nil, err
+	}
+	shallow, err := isShallow(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCommit, err := resolveCommit(repo, from)
+	if err != nil {
+		return nil, shallowError(err, shallow)
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return nil, shallowError(err, shallow)
+	}
+
+	patch, err := fromCommit.Patch(toCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s and %s: %w", from, to, err)
+	}
+
+	result := make(map[string][]Hunk)
+	for _, filePatch := range patch.FilePatches() {
+		if filePatch.IsBinary() {
+			continue
+		}
+
+		fileFrom, fileTo := filePatch.Files()
+		var path string
+		if fileTo != nil {
+			path = fileTo.Path()
+		} else if fileFrom != nil {
+			path = fileFrom.Path()
+		}
+		if hunks := chunksToHunks(filePatch.Chunks()); len(hunks) > 0 {
+			result[path] = hunks
+		}
+	}
+
+	return result, nil
+}
+
+// chunksToHunks groups consecutive added and deleted chunks into hunks
+func chunksToHunks(chunks []diff.Chunk) []Hunk {
+	var hunks []Hunk
+	var current *Hunk
+	fromLine, toLine := 0, 0
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if current.FromLines == 0 {
+			current.FromLine--
+		}
+		if current.ToLines == 0 {
+			current.ToLine--
+		}
+		hunks = append(hunks, *current)
+		current = nil
+	}
+
+	for _, chunk := range chunks {
+		count := lineCount(chunk.Content())
+		if chunk.Type() == diff.Equal {
+			flush()
+			fromLine += count
+			toLine += count
+			continue
+		}
+
+		if current == nil {
+			current = &Hunk{FromLine: fromLine + 1, ToLine: toLine + 1}
+		}
+		switch chunk.Type() {
+		case diff.Delete:
+			current.FromLines += count
+			fromLine += count
+		case diff.Add:
+			current.ToLines += count
+			toLine += count
+		}
+	}
+	flush()
+
+	return hunks
+}
+
+// lineCount returns the number of lines of the chunk, the last line of a file may not end with a newline
+func lineCount(content string) int {
+	count := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,35 @@
+package gitdiff
+
+import (
+	"fmt"
+)
+
+// MergeBase returns the hash of the best common ancestor of both revisions, like git merge-base
+func MergeBase(dir string, from string, to string) (string, error) {
+	repo, err := openRepository(dir)
+	if err != nil {
+		return "", err
+	}
+	shallow, err := isShallow(repo)
+	if err != nil {
+		return "", err
+	}
+
+	fromCommit, err := resolveCommit(repo, from)
+	if err != nil {
+		return "", shallowError(err, shallow)
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return "", shallowError(err, shallow)
+	}
+
+	bases, err := fromCommit.MergeBase(toCommit)
+	if err != nil {
+		return "", shallowError(fmt.Errorf("failed to find merge base of %s and %s: %w", from, to, err), shallow)
+	} else if len(bases) == 0 {
+		return "", shallowError(fmt.Errorf("%s and %s have no common ancestor", from, to), shallow)
+	}
+
+	return bases[0].Hash.String(), nil
+}
@@ -0,0 +1,196 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the JSON Schema dialect of the generated schemas
+const Version = "http://json-schema.org/draft-07/schema#"
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+)
+
+// Schema is a subset of JSON Schema draft-07, sufficient to describe the cid configuration files
+type Schema struct {
+	Version              string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+
+	// noAdditionalProperties renders additionalProperties: false, unknown keys are rejected
+	noAdditionalProperties bool
+}
+
+// False returns a schema that rejects every value, used for additionalProperties: false
+func False() *Schema {
+	return &Schema{noAdditionalProperties: true}
+}
+
+// IsFalse checks if the schema rejects every value
+func (s *Schema) IsFalse() bool {
+	return s != nil && s.noAdditionalProperties
+}
+
+// MarshalJSON renders the false schema as boolean
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.noAdditionalProperties {
+		return []byte("false"), nil
+	}
+
+	type plain Schema
+	return json.Marshal((*plain)(s))
+}
+
+// Reflector generates schemas from go types, the field names are taken from the TagName struct tag (yaml or json)
+type Reflector struct {
+	TagName string
+}
+
+// Reflect generates the schema of a go type, nested structs are inlined and reject unknown properties
+func (r Reflector) Reflect(t reflect.Type) *Schema {
+	s := r.reflect(t, map[reflect.Type]bool{})
+	s.Version = Version
+	return s
+}
+
+func (r Reflector) reflect(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// types with a custom decoder accept any value
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(yamlUnmarshalerType) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.reflect(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflect(t.Elem(), visiting)}
+	case reflect.Struct:
+		// recursive types are not expanded
+		if visiting[t] {
+			return &Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: False()}
+		r.addFields(s, t, visiting)
+		return s
+	default:
+		// interface{} accepts any value
+		return &Schema{}
+	}
+}
+
+func (r Reflector) addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, inline, skip := r.fieldName(field)
+		if skip {
+			continue
+		}
+		if inline {
+			ft := field.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(s, ft, visiting)
+				continue
+			}
+		}
+
+		fieldSchema := r.reflect(field.Type, visiting)
+		if def, ok := field.Tag.Lookup("default"); ok {
+			fieldSchema.Default = defaultValue(fieldSchema.Type, def)
+		}
+		// validate:"required" is not used, it is checked after defaults and environment variables are applied
+		if field.Tag.Get("required") == "true" {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fieldSchema
+	}
+}
+
+// fieldName returns the property name of a field, following the naming rules of the yaml and json decoders
+func (r Reflector) fieldName(field reflect.StructField) (name string, inline bool, skip bool) {
+	tag := field.Tag.Get(r.TagName)
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "inline" {
+			return "", true, false
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], false, false
+	}
+
+	// the json decoder inlines embedded structs, yaml requires the inline option
+	if field.Anonymous && r.TagName == "json" {
+		return "", true, false
+	}
+	if r.TagName == "yaml" {
+		return strings.Map(unicode.ToLower, field.Name), false, false
+	}
+	return field.Name, false, false
+}
+
+func defaultValue(schemaType string, value string) any {
+	switch schemaType {
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStage struct {
+	Name    string   `yaml:"name" required:"true"`
+	Actions []string `yaml:"actions,omitempty"`
+}
+
+type testConfig struct {
+	Workflow string            `yaml:"workflow,omitempty"`
+	Parallel int               `yaml:"parallel" default:"2"`
+	Stages   []testStage       `yaml:"stages"`
+	Env      map[string]string // untagged fields are lowercased by the yaml decoder
+	Config   interface{}       `yaml:"config"`
+	Internal string            `yaml:"-"`
+}
+
+func TestReflect(t *testing.T) {
+	s := Reflector{TagName: "yaml"}.Reflect(reflect.TypeOf(testConfig{}))
+
+	assert.Equal(t, Version, s.Version)
+	assert.Equal(t, "object", s.Type)
+	assert.True(t, s.AdditionalProperties.IsFalse())
+	assert.Equal(t, "integer", s.Properties["parallel"].Type)
+	assert.Equal(t, int64(2), s.Properties["parallel"].Default)
+	assert.Equal(t, "object", s.Properties["env"].Type)
+	assert.Equal(t, "string", s.Properties["env"].AdditionalProperties.Type)
+	assert.Equal(t, []string{"name"}, s.Properties["stages"].Items.Required)
+	assert.NotContains(t, s.Properties, "Internal")
+	assert.NotContains(t, s.Properties, "internal")
+
+	out, err := json.Marshal(s.Properties["stages"].Items)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{"name":{"type":"string"},"actions":{"type":"array","items":{"type":"string"}}},"additionalProperties":false,"required":["name"]}`, string(out))
+}
+
+func TestValidateYAML(t *testing.T) {
+	s := Reflector{TagName: "yaml"}.Reflect(reflect.TypeOf(testConfig{}))
+
+	errs, err := ValidateYAML(s, []byte(`workflow: main
+parallel: many
+stages:
+  - name: build
+    action: [go-build]
+  - actions: [go-test]
+variables:
+  FOO: bar
+config:
+  anything: goes
+`))
+	require.NoError(t, err)
+	require.Len(t, errs, 4)
+	assert.Equal(t, "2:11: parallel: expected integer, got string", errs[0].Error())
+	assert.Equal(t, "5:5: stages[0].action: unknown property action, did you mean actions?", errs[1].Error())
+	assert.Equal(t, "6:5: stages[1]: missing required property name", errs[2].Error())
+	assert.Equal(t, "7:1: variables: unknown property variables", errs[3].Error())
+}
+
+func TestValidateYAMLConditional(t *testing.T) {
+	s := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"id": {Type: "string"}, "config": {}},
+		AllOf: []*Schema{{
+			If:   &Schema{Properties: map[string]*Schema{"id": {Const: "go-build"}}},
+			Then: &Schema{Properties: map[string]*Schema{"config": {Type: "object", Properties: map[string]*Schema{"platform": {Type: "string"}}, AdditionalProperties: False()}}},
+		}},
+	}
+
+	errs, err := ValidateYAML(s, []byte("id: go-build\nconfig:\n  platfrom: linux\n"))
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "3:3: config.platfrom: unknown property platfrom, did you mean platform?", errs[0].Error())
+
+	errs, err = ValidateYAML(s, []byte("id: go-test\nconfig:\n  platfrom: linux\n"))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,206 @@
+package jsonschema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a schema violation at a position of the validated file
+type ValidationError struct {
+	Path    string // Path of the value, e.g. workflows[0].stages[1].name
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// ValidateYAML validates a yaml or json document against the schema, all violations are returned
+func ValidateYAML(schema *Schema, content []byte) ([]ValidationError, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	return ValidateNode(schema, doc.Content[0], ""), nil
+}
+
+// ValidateNode validates a yaml node against the schema, the path is used as prefix for the reported paths
+func ValidateNode(schema *Schema, node *yaml.Node, path string) []ValidationError {
+	if schema == nil || node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if schema.IsFalse() {
+		return []ValidationError{newError(node, path, "value is not allowed")}
+	}
+
+	// null is decoded into the zero value
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		return nil
+	}
+
+	var errs []ValidationError
+	if schema.Type != "" && !matchesType(schema.Type, node) {
+		return append(errs, newError(node, path, fmt.Sprintf("expected %s, got %s", schema.Type, nodeType(node))))
+	}
+	if schema.Const != nil && (node.Kind != yaml.ScalarNode || node.Value != fmt.Sprint(schema.Const)) {
+		errs = append(errs, newError(node, path, fmt.Sprintf("value must be %v", schema.Const)))
+	}
+	if len(schema.Enum) > 0 && (node.Kind != yaml.ScalarNode || !slices.ContainsFunc(schema.Enum, func(e any) bool { return fmt.Sprint(e) == node.Value })) {
+		errs = append(errs, newError(node, path, fmt.Sprintf("value must be one of %v", schema.Enum)))
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		errs = append(errs, validateMapping(schema, node, path)...)
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			errs = append(errs, ValidateNode(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	for _, sub := range schema.AllOf {
+		errs = append(errs, ValidateNode(sub, node, path)...)
+	}
+	if schema.If != nil && len(ValidateNode(schema.If, node, path)) == 0 {
+		errs = append(errs, ValidateNode(schema.Then, node, path)...)
+	}
+
+	return errs
+}
+
+func validateMapping(schema *Schema, node *yaml.Node, path string) []ValidationError {
+	var errs []ValidationError
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "<<" {
+			continue
+		}
+		keys = append(keys, key.Value)
+
+		propertyPath := joinPath(path, key.Value)
+		if propertySchema, ok := schema.Properties[key.Value]; ok {
+			errs = append(errs, ValidateNode(propertySchema, value, propertyPath)...)
+		} else if schema.AdditionalProperties.IsFalse() {
+			errs = append(errs, newError(key, propertyPath, unknownKeyMessage(key.Value, schema.Properties)))
+		} else if schema.AdditionalProperties != nil {
+			errs = append(errs, ValidateNode(schema.AdditionalProperties, value, propertyPath)...)
+		}
+	}
+
+	for _, required := range schema.Required {
+		if !slices.Contains(keys, required) {
+			errs = append(errs, newError(node, path, fmt.Sprintf("missing required property %s", required)))
+		}
+	}
+
+	return errs
+}
+
+// unknownKeyMessage reports an unknown key and suggests the known key with the most similar name
+func unknownKeyMessage(key string, properties map[string]*Schema) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+	}
+
+	suggestion, best := "", 3 // only suggest names with at most 2 edits
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		if d := editDistance(normalize(name), normalize(key)); d < best {
+			suggestion, best = name, d
+		}
+	}
+	if suggestion != "" {
+		return fmt.Sprintf("unknown property %s, did you mean %s?", key, suggestion)
+	}
+
+	return fmt.Sprintf("unknown property %s", key)
+}
+
+// editDistance returns the levenshtein distance of two strings
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(rb)]
+}
+
+func matchesType(schemaType string, node *yaml.Node) bool {
+	switch schemaType {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		return node.Kind == yaml.ScalarNode
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!bool"
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!int"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.ShortTag() == "!!int" || node.ShortTag() == "!!float")
+	default:
+		return true
+	}
+}
+
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.ShortTag() {
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func newError(node *yaml.Node, path string, message string) ValidationError {
+	return ValidationError{Path: path, Line: node.Line, Column: node.Column, Message: message}
+}
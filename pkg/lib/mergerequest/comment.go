@@ -0,0 +1,128 @@
+package mergerequest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// UpsertComment creates a merge request comment or updates the existing comment containing the marker
+//
+// The mergeRequestID is the pull request number on GitHub or the merge request iid on GitLab, the marker is a hidden string within the body.
+func UpsertComment(repo Repository, mergeRequestID string, marker string, body string) error {
+	if mergeRequestID == "" {
+		return fmt.Errorf("merge request id is required")
+	}
+	if repo.Token == "" {
+		return fmt.Errorf("no token available to comment on merge requests")
+	}
+
+	switch repo.HostType {
+	case "github":
+		number, err := strconv.Atoi(mergeRequestID)
+		if err != nil {
+			return fmt.Errorf("failed to parse pull request number: %w", err)
+		}
+
+		return upsertGitHubComment(repo, number, marker, body)
+	case "gitlab":
+		mergeRequestIID, err := strconv.ParseInt(mergeRequestID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse merge request iid: %w", err)
+		}
+
+		return upsertGitLabNote(repo, mergeRequestIID, marker, body)
+	default:
+		return unsupportedHostError(repo.HostType)
+	}
+}
+
+// upsertGitHubComment creates a pull request comment or updates the existing comment containing the marker
+func upsertGitHubComment(repo Repository, pullRequest int, marker string, body string) error {
+	client, owner, name, err := repo.githubClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	// find existing comment
+	listOpts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, name, pullRequest, listOpts)
+		if err != nil {
+			return fmt.Errorf("failed to list pull request comments: %w", err)
+		}
+
+		for _, c := range comments {
+			if strings.Contains(c.GetBody(), marker) {
+				slog.With("repository", repo.ProjectPath).With("pull_request", pullRequest).With("comment_id", c.GetID()).Info("updating pull request comment")
+				_, _, err = client.Issues.EditComment(ctx, owner, name, c.GetID(), &github.IssueComment{Body: github.Ptr(body)})
+				if err != nil {
+					return fmt.Errorf("failed to update pull request comment: %w", err)
+				}
+				return nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	// create new comment
+	slog.With("repository", repo.ProjectPath).With("pull_request", pullRequest).Info("creating pull request comment")
+	_, _, err = client.Issues.CreateComment(ctx, owner, name, pullRequest, &github.IssueComment{Body: github.Ptr(body)})
+	if err != nil {
+		return fmt.Errorf("failed to create pull request comment: %w", err)
+	}
+
+	return nil
+}
+
+// upsertGitLabNote creates a merge request note or updates the existing note containing the marker
+func upsertGitLabNote(repo Repository, mergeRequestIID int64, marker string, body string) error {
+	glab, projectID, err := repo.gitlabClient()
+	if err != nil {
+		return err
+	}
+
+	// find existing note
+	listOpts := &gitlab.ListMergeRequestNotesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		notes, resp, err := glab.Notes.ListMergeRequestNotes(projectID, mergeRequestIID, listOpts)
+		if err != nil {
+			return fmt.Errorf("failed to list merge request notes: %w", err)
+		}
+
+		for _, n := range notes {
+			if strings.Contains(n.Body, marker) {
+				slog.With("project_id", projectID).With("merge_request", mergeRequestIID).With("note_id", n.ID).Info("updating merge request note")
+				_, _, err = glab.Notes.UpdateMergeRequestNote(projectID, mergeRequestIID, n.ID, &gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
+				if err != nil {
+					return fmt.Errorf("failed to update merge request note: %w", err)
+				}
+				return nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	// create new note
+	slog.With("project_id", projectID).With("merge_request", mergeRequestIID).Info("creating merge request note")
+	_, _, err = glab.Notes.CreateMergeRequestNote(projectID, mergeRequestIID, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
+	if err != nil {
+		return fmt.Errorf("failed to create merge request note: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package mergerequest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedRequest is a request received by the fake api
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// fakeAPI serves the given responses by method and path and records all requests
+func fakeAPI(t *testing.T, responses map[string]string) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+
+		response, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"not found"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestRepositoryFromEnv(t *testing.T) {
+	env := map[string]string{"NCI_REPOSITORY_HOST_TYPE": "gitlab", "NCI_REPOSITORY_HOST_SERVER": "gitlab.com", "NCI_PROJECT_ID": "12", "NCI_PROJECT_PATH": "group/project"}
+
+	repo := RepositoryFromEnv(env, "gh-token", "gl-token")
+	assert.Equal(t, "gl-token", repo.Token)
+	assert.True(t, repo.Supported())
+
+	env["NCI_REPOSITORY_HOST_TYPE"] = "gitea"
+	assert.False(t, RepositoryFromEnv(env, "gh-token", "gl-token").Supported())
+}
+
+func TestUpsertCommentGitHubCreate(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /repos/owner/repo/issues/7/comments":  `[{"id": 1, "body": "unrelated"}]`,
+		"POST /repos/owner/repo/issues/7/comments": `{"id": 2}`,
+	})
+	repo := Repository{HostType: "github", ProjectPath: "owner/repo", Token: "token", APIURL: server.URL}
+
+	require.NoError(t, UpsertComment(repo, "7", "<!-- marker -->", "<!-- marker -->\nsummary"))
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "POST", (*requests)[1].Method)
+	assert.JSONEq(t, `{"body":"<!-- marker -->\nsummary"}`, (*requests)[1].Body)
+}
+
+func TestUpsertCommentGitHubUpdate(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /repos/owner/repo/issues/7/comments":   `[{"id": 1, "body": "<!-- marker -->\nold"}]`,
+		"PATCH /repos/owner/repo/issues/comments/1": `{"id": 1}`,
+	})
+	repo := Repository{HostType: "github", ProjectPath: "owner/repo", Token: "token", APIURL: server.URL}
+
+	require.NoError(t, UpsertComment(repo, "7", "<!-- marker -->", "<!-- marker -->\nnew"))
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "PATCH", (*requests)[1].Method)
+}
+
+func TestUpsertCommentGitLabUpdate(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /api/v4/projects/12/merge_requests/3/notes":   `[{"id": 5, "body": "<!-- marker -->\nold"}]`,
+		"PUT /api/v4/projects/12/merge_requests/3/notes/5": `{"id": 5}`,
+	})
+	repo := Repository{HostType: "gitlab", ProjectID: "12", Token: "token", APIURL: server.URL}
+
+	require.NoError(t, UpsertComment(repo, "3", "<!-- marker -->", "<!-- marker -->\nnew"))
+	require.Len(t, *requests, 2)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal([]byte((*requests)[1].Body), &body))
+	assert.Equal(t, "<!-- marker -->\nnew", body["body"])
+}
+
+func TestUpsertCommentErrors(t *testing.T) {
+	assert.EqualError(t, UpsertComment(Repository{HostType: "github", Token: "token"}, "", "m", "b"), "merge request id is required")
+	assert.EqualError(t, UpsertComment(Repository{HostType: "github"}, "1", "m", "b"), "no token available to comment on merge requests")
+	assert.EqualError(t, UpsertComment(Repository{HostType: "gitea", Token: "token"}, "1", "m", "b"), "merge requests are not supported for host type [gitea]")
+}
@@ -0,0 +1,176 @@
+package mergerequest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/go-github/v89/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// MergeRequest is the pull request / merge request a commit was merged with
+type MergeRequest struct {
+	ID     string // ID is the pull request number on GitHub (#12) or the merge request reference on GitLab (!12)
+	Title  string
+	Author string
+	URL    string
+}
+
+// Lookup finds the merge requests of commits.
+//
+// The api client is created once and the results are cached, all commits of a found merge request are resolved without further requests.
+type Lookup struct {
+	repo  Repository
+	cache map[string]*MergeRequest
+
+	githubClient *github.Client
+	owner        string
+	name         string
+
+	gitlabClient *gitlab.Client
+	projectID    int64
+}
+
+// NewLookup returns a merge request lookup for the repository
+func NewLookup(repo Repository) (*Lookup, error) {
+	if repo.Token == "" {
+		return nil, fmt.Errorf("no token available to query merge requests")
+	}
+
+	l := &Lookup{repo: repo, cache: map[string]*MergeRequest{}}
+	var err error
+	switch repo.HostType {
+	case "github":
+		l.githubClient, l.owner, l.name, err = repo.githubClient()
+	case "gitlab":
+		l.gitlabClient, l.projectID, err = repo.gitlabClient()
+	default:
+		err = unsupportedHostError(repo.HostType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// FindByCommit returns the merge request the commit was merged with, nil if the commit was pushed directly
+func (l *Lookup) FindByCommit(hash string) (*MergeRequest, error) {
+	if mr, ok := l.cache[hash]; ok {
+		return mr, nil
+	}
+
+	var mr *MergeRequest
+	var commits []string
+	var err error
+	switch l.repo.HostType {
+	case "github":
+		mr, commits, err = l.findGitHubPullRequest(hash)
+	case "gitlab":
+		mr, commits, err = l.findGitLabMergeRequest(hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l.cache[hash] = mr
+	for _, c := range commits {
+		l.cache[c] = mr
+	}
+
+	return mr, nil
+}
+
+// findGitHubPullRequest returns the first merged pull request containing the commit and the commits of the pull request
+func (l *Lookup) findGitHubPullRequest(hash string) (*MergeRequest, []string, error) {
+	ctx := context.Background()
+	pulls, _, err := l.githubClient.PullRequests.ListPullRequestsWithCommit(ctx, l.owner, l.name, hash, &github.ListOptions{PerPage: 10})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pull requests of commit %s: %w", hash, err)
+	}
+
+	for _, pr := range pulls {
+		if pr.MergedAt == nil {
+			continue
+		}
+
+		mr := &MergeRequest{
+			ID:     fmt.Sprintf("#%d", pr.GetNumber()),
+			Title:  pr.GetTitle(),
+			Author: pr.GetUser().GetLogin(),
+			URL:    pr.GetHTMLURL(),
+		}
+
+		// the commits of the pull request are merged with the same pull request
+		var commits []string
+		listOpts := &github.ListOptions{PerPage: 100}
+		for {
+			prCommits, resp, err := l.githubClient.PullRequests.ListCommits(ctx, l.owner, l.name, pr.GetNumber(), listOpts)
+			if err != nil {
+				slog.With("err", err).With("pull_request", pr.GetNumber()).Debug("failed to list pull request commits")
+				break
+			}
+			for _, c := range prCommits {
+				commits = append(commits, c.GetSHA())
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			listOpts.Page = resp.NextPage
+		}
+
+		return mr, commits, nil
+	}
+
+	return nil, nil, nil
+}
+
+// findGitLabMergeRequest returns the first merged merge request containing the commit and the commits of the merge request
+func (l *Lookup) findGitLabMergeRequest(hash string) (*MergeRequest, []string, error) {
+	mergeRequests, _, err := l.gitlabClient.Commits.ListMergeRequestsByCommit(l.projectID, hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list merge requests of commit %s: %w", hash, err)
+	}
+
+	for _, m := range mergeRequests {
+		if m.State != "merged" {
+			continue
+		}
+
+		author := ""
+		if m.Author != nil {
+			author = m.Author.Username
+		}
+		mr := &MergeRequest{
+			ID:     fmt.Sprintf("!%d", m.IID),
+			Title:  m.Title,
+			Author: author,
+			URL:    m.WebURL,
+		}
+
+		// the commits of the merge request are merged with the same merge request
+		var commits []string
+		listOpts := &gitlab.GetMergeRequestCommitsOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+		for {
+			mrCommits, resp, err := l.gitlabClient.MergeRequests.GetMergeRequestCommits(l.projectID, m.IID, listOpts)
+			if err != nil {
+				slog.With("err", err).With("merge_request", m.IID).Debug("failed to list merge request commits")
+				break
+			}
+			for _, c := range mrCommits {
+				commits = append(commits, c.ID)
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			listOpts.Page = resp.NextPage
+		}
+
+		return mr, commits, nil
+	}
+
+	return nil, nil, nil
+}
@@ -0,0 +1,65 @@
+package mergerequest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupGitHubCachesPullRequestCommits(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /repos/owner/repo/commits/aaa/pulls": `[{"number": 3, "title": "open", "merged_at": null}, {"number": 7, "title": "feat: add feature", "merged_at": "2026-01-01T00:00:00Z", "user": {"login": "octocat"}, "html_url": "https://github.com/owner/repo/pull/7"}]`,
+		"GET /repos/owner/repo/pulls/7/commits":   `[{"sha": "aaa"}, {"sha": "bbb"}]`,
+		"GET /repos/owner/repo/commits/ccc/pulls": `[]`,
+	})
+	lookup, err := NewLookup(Repository{HostType: "github", ProjectPath: "owner/repo", Token: "token", APIURL: server.URL})
+	require.NoError(t, err)
+
+	mr, err := lookup.FindByCommit("aaa")
+	require.NoError(t, err)
+	assert.Equal(t, &MergeRequest{ID: "#7", Title: "feat: add feature", Author: "octocat", URL: "https://github.com/owner/repo/pull/7"}, mr)
+
+	// commits of a known pull request are resolved from the cache
+	mr, err = lookup.FindByCommit("bbb")
+	require.NoError(t, err)
+	assert.Equal(t, "#7", mr.ID)
+
+	mr, err = lookup.FindByCommit("ccc")
+	require.NoError(t, err)
+	assert.Nil(t, mr)
+	mr, err = lookup.FindByCommit("ccc")
+	require.NoError(t, err)
+	assert.Nil(t, mr)
+
+	assert.Len(t, *requests, 3)
+}
+
+func TestLookupGitLab(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /api/v4/projects/12/repository/commits/aaa/merge_requests": `[{"iid": 4, "title": "fix: bug", "state": "merged", "author": {"username": "dev"}, "web_url": "https://gitlab.com/group/project/-/merge_requests/4"}]`,
+		"GET /api/v4/projects/12/merge_requests/4/commits":              `[{"id": "aaa"}, {"id": "bbb"}]`,
+	})
+	lookup, err := NewLookup(Repository{HostType: "gitlab", ProjectID: "12", Token: "token", APIURL: server.URL})
+	require.NoError(t, err)
+
+	mr, err := lookup.FindByCommit("bbb")
+	require.Error(t, err)
+	assert.Nil(t, mr)
+
+	mr, err = lookup.FindByCommit("aaa")
+	require.NoError(t, err)
+	assert.Equal(t, &MergeRequest{ID: "!4", Title: "fix: bug", Author: "dev", URL: "https://gitlab.com/group/project/-/merge_requests/4"}, mr)
+	mr, err = lookup.FindByCommit("bbb")
+	require.NoError(t, err)
+	assert.Equal(t, "!4", mr.ID)
+
+	assert.Len(t, *requests, 3)
+}
+
+func TestNewLookupErrors(t *testing.T) {
+	_, err := NewLookup(Repository{HostType: "github"})
+	assert.EqualError(t, err, "no token available to query merge requests")
+	_, err = NewLookup(Repository{HostType: "gitea", Token: "token"})
+	assert.EqualError(t, err, "merge requests are not supported for host type [gitea]")
+}
@@ -0,0 +1,89 @@
+package mergerequest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// Repository is the repository hosting the merge requests, based on the normalized NCI_* environment
+type Repository struct {
+	HostType    string // HostType is the repository host type, e.g. github or gitlab
+	HostServer  string // HostServer is the repository host, e.g. github.com
+	ProjectID   string // ProjectID is required for GitLab
+	ProjectPath string // ProjectPath is required for GitHub
+	Token       string
+	APIURL      string // APIURL overrides the api endpoint derived from the host server, e.g. for tests
+}
+
+// RepositoryFromEnv returns the repository of the NCI_* environment, the token matching the host type is used
+func RepositoryFromEnv(env map[string]string, githubToken string, gitlabToken string) Repository {
+	repo := Repository{
+		HostType:    env["NCI_REPOSITORY_HOST_TYPE"],
+		HostServer:  env["NCI_REPOSITORY_HOST_SERVER"],
+		ProjectID:   env["NCI_PROJECT_ID"],
+		ProjectPath: env["NCI_PROJECT_PATH"],
+	}
+	switch repo.HostType {
+	case "github":
+		repo.Token = githubToken
+	case "gitlab":
+		repo.Token = gitlabToken
+	}
+
+	return repo
+}
+
+// Supported checks if merge requests can be queried for the repository host
+func (r Repository) Supported() bool {
+	return r.Token != "" && (r.HostType == "github" || r.HostType == "gitlab")
+}
+
+// githubClient returns the GitHub client and the owner and name of the repository
+func (r Repository) githubClient() (*github.Client, string, string, error) {
+	parts := strings.Split(r.ProjectPath, "/")
+	if len(parts) != 2 {
+		return nil, "", "", fmt.Errorf("invalid repository path: %s", r.ProjectPath)
+	}
+
+	opts := []github.ClientOptionsFunc{github.WithAuthToken(r.Token)}
+	if r.APIURL != "" {
+		opts = append(opts, github.WithURLs(github.Ptr(strings.TrimSuffix(r.APIURL, "/")+"/"), nil))
+	} else if r.HostServer != "" && r.HostServer != "github.com" {
+		opts = append(opts, github.WithEnterpriseURLs("https://"+r.HostServer, "https://"+r.HostServer))
+	}
+	client, err := github.NewClient(opts...)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return client, parts[0], parts[1], nil
+}
+
+// gitlabClient returns the GitLab client and the project id
+func (r Repository) gitlabClient() (*gitlab.Client, int64, error) {
+	projectID, err := strconv.ParseInt(r.ProjectID, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse project ID: %w", err)
+	}
+
+	baseURL := r.APIURL
+	if baseURL == "" {
+		baseURL = "https://" + r.HostServer
+	}
+	client, err := gitlab.NewClient(r.Token, gitlab.WithBaseURL(baseURL), gitlab.WithHTTPClient(&http.Client{Transport: http.DefaultTransport}))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return client, projectID, nil
+}
+
+// unsupportedHostError returns the error for repository hosts without merge request support
+func unsupportedHostError(hostType string) error {
+	return fmt.Errorf("merge requests are not supported for host type [%s]", hostType)
+}
@@ -0,0 +1,216 @@
+package mergerequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/google/go-github/v89/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// FileUpdate returns the new content of a file based on its current content on the base branch, the content is empty for new files
+type FileUpdate func(content string) string
+
+// SubmitRequest describes file changes that are committed to a branch and proposed with a merge request
+type SubmitRequest struct {
+	BaseBranch    string                // BaseBranch is the target branch of the merge request
+	Branch        string                // Branch is the source branch, it is reset to the BaseBranch on every submit
+	CommitMessage string                // CommitMessage is used for all file changes
+	Title         string                // Title of the merge request
+	Description   string                // Description of the merge request
+	Files         map[string]FileUpdate // Files maps the repository-relative file path to the update of the file content
+}
+
+// Submit applies the file updates to the current content of the base branch, commits them to the branch and creates a merge request, if no open merge request exists for the branch.
+// It returns the merge request url, or an empty string if the base branch already contains the changes.
+func Submit(repo Repository, req SubmitRequest) (string, error) {
+	if repo.Token == "" {
+		return "", fmt.Errorf("no token available to create merge requests")
+	}
+	if req.BaseBranch == "" || req.Branch == "" {
+		return "", fmt.Errorf("base branch and branch are required")
+	}
+
+	switch repo.HostType {
+	case "github":
+		return submitGitHub(repo, req)
+	case "gitlab":
+		return submitGitLab(repo, req)
+	default:
+		return "", unsupportedHostError(repo.HostType)
+	}
+}
+
+// submitGitHub resets the branch to the base branch, commits the files using the contents api and opens a pull request
+func submitGitHub(repo Repository, req SubmitRequest) (string, error) {
+	client, owner, name, err := repo.githubClient()
+	if err != nil {
+		return "", err
+	}
+	ctx := context.Background()
+
+	// update the files of the base branch
+	base, _, err := client.Git.GetRef(ctx, owner, name, "heads/"+req.BaseBranch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get base branch %s: %w", req.BaseBranch, err)
+	}
+	baseSHA := base.GetObject().GetSHA()
+	files := map[string]string{}
+	fileSHA := map[string]*string{}
+	for _, file := range slices.Sorted(maps.Keys(req.Files)) {
+		current := ""
+		existing, _, resp, err := client.Repositories.GetContents(ctx, owner, name, file, &github.RepositoryContentGetOptions{Ref: baseSHA})
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return "", fmt.Errorf("failed to get file %s: %w", file, err)
+		}
+		if existing != nil {
+			current, err = existing.GetContent()
+			if err != nil {
+				return "", fmt.Errorf("failed to decode file %s: %w", file, err)
+			}
+			fileSHA[file] = existing.SHA
+		}
+
+		if updated := req.Files[file](current); updated != current {
+			files[file] = updated
+		}
+	}
+	if len(files) == 0 {
+		slog.With("repository", repo.ProjectPath).With("branch", req.BaseBranch).Info("base branch already contains the changes, skipping pull request")
+		return "", nil
+	}
+
+	// create or reset branch
+	_, resp, err := client.Git.GetRef(ctx, owner, name, "heads/"+req.Branch)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		_, _, err = client.Git.CreateRef(ctx, owner, name, github.CreateRef{Ref: "refs/heads/" + req.Branch, SHA: baseSHA})
+	} else if err == nil {
+		_, _, err = client.Git.UpdateRef(ctx, owner, name, "heads/"+req.Branch, github.UpdateRef{SHA: baseSHA, Force: github.Ptr(true)})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare branch %s: %w", req.Branch, err)
+	}
+
+	// commit files
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		fileOpts := &github.RepositoryContentFileOptions{
+			Message: github.Ptr(req.CommitMessage),
+			Content: []byte(files[file]),
+			Branch:  github.Ptr(req.Branch),
+			SHA:     fileSHA[file],
+		}
+
+		if fileOpts.SHA != nil {
+			_, _, err = client.Repositories.UpdateFile(ctx, owner, name, file, fileOpts)
+		} else {
+			_, _, err = client.Repositories.CreateFile(ctx, owner, name, file, fileOpts)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to commit file %s: %w", file, err)
+		}
+	}
+
+	// find existing pull request
+	pulls, _, err := client.PullRequests.List(ctx, owner, name, &github.PullRequestListOptions{State: "open", Head: owner + ":" + req.Branch, Base: req.BaseBranch})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	if len(pulls) > 0 {
+		slog.With("repository", repo.ProjectPath).With("pull_request", pulls[0].GetNumber()).Info("updated existing pull request")
+		return pulls[0].GetHTMLURL(), nil
+	}
+
+	// create pull request
+	pr, _, err := client.PullRequests.Create(ctx, owner, name, &github.NewPullRequest{
+		Title: github.Ptr(req.Title),
+		Body:  github.Ptr(req.Description),
+		Head:  github.Ptr(req.Branch),
+		Base:  github.Ptr(req.BaseBranch),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+	slog.With("repository", repo.ProjectPath).With("pull_request", pr.GetNumber()).Info("created pull request")
+
+	return pr.GetHTMLURL(), nil
+}
+
+// submitGitLab commits the files onto the base branch, force-updating the branch, and opens a merge request
+func submitGitLab(repo Repository, req SubmitRequest) (string, error) {
+	glab, projectID, err := repo.gitlabClient()
+	if err != nil {
+		return "", err
+	}
+
+	// update the files of the base branch
+	var actions []*gitlab.CommitActionOptions
+	for _, file := range slices.Sorted(maps.Keys(req.Files)) {
+		action := gitlab.FileUpdate
+		current, _, err := glab.RepositoryFiles.GetRawFile(projectID, file, &gitlab.GetRawFileOptions{Ref: gitlab.Ptr(req.BaseBranch)})
+		if errors.Is(err, gitlab.ErrNotFound) {
+			action = gitlab.FileCreate
+		} else if err != nil {
+			return "", fmt.Errorf("failed to get file %s: %w", file, err)
+		}
+
+		updated := req.Files[file](string(current))
+		if updated == string(current) {
+			continue
+		}
+		actions = append(actions, &gitlab.CommitActionOptions{
+			Action:   gitlab.Ptr(action),
+			FilePath: gitlab.Ptr(file),
+			Content:  gitlab.Ptr(updated),
+		})
+	}
+	if len(actions) == 0 {
+		slog.With("project_id", projectID).With("branch", req.BaseBranch).Info("base branch already contains the changes, skipping merge request")
+		return "", nil
+	}
+
+	// commit files
+	_, _, err = glab.Commits.CreateCommit(projectID, &gitlab.CreateCommitOptions{
+		Branch:        gitlab.Ptr(req.Branch),
+		StartBranch:   gitlab.Ptr(req.BaseBranch),
+		CommitMessage: gitlab.Ptr(req.CommitMessage),
+		Actions:       actions,
+		Force:         gitlab.Ptr(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit files to branch %s: %w", req.Branch, err)
+	}
+
+	// find existing merge request
+	mergeRequests, _, err := glab.MergeRequests.ListProjectMergeRequests(projectID, &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.Ptr("opened"),
+		SourceBranch: gitlab.Ptr(req.Branch),
+		TargetBranch: gitlab.Ptr(req.BaseBranch),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list merge requests: %w", err)
+	}
+	if len(mergeRequests) > 0 {
+		slog.With("project_id", projectID).With("merge_request", mergeRequests[0].IID).Info("updated existing merge request")
+		return mergeRequests[0].WebURL, nil
+	}
+
+	// create merge request
+	mr, _, err := glab.MergeRequests.CreateMergeRequest(projectID, &gitlab.CreateMergeRequestOptions{
+		Title:              gitlab.Ptr(req.Title),
+		Description:        gitlab.Ptr(req.Description),
+		SourceBranch:       gitlab.Ptr(req.Branch),
+		TargetBranch:       gitlab.Ptr(req.BaseBranch),
+		RemoveSourceBranch: gitlab.Ptr(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create merge request: %w", err)
+	}
+	slog.With("project_id", projectID).With("merge_request", mr.IID).Info("created merge request")
+
+	return mr.WebURL, nil
+}
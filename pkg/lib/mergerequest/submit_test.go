@@ -0,0 +1,94 @@
+package mergerequest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendLine(line string) FileUpdate {
+	return func(content string) string {
+		return content + line + "\n"
+	}
+}
+
+func TestSubmitGitHubUsesBaseBranchContent(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /repos/owner/repo/git/ref/heads/main":    `{"ref": "refs/heads/main", "object": {"sha": "base"}}`,
+		"GET /repos/owner/repo/contents/CHANGELOG.md": `{"type": "file", "encoding": "base64", "sha": "file-sha", "content": "` + base64.StdEncoding.EncodeToString([]byte("# Changelog\nunreleased entry\n")) + `"}`,
+		"POST /repos/owner/repo/git/refs":             `{"ref": "refs/heads/cid/changelog"}`,
+		"PUT /repos/owner/repo/contents/CHANGELOG.md": `{}`,
+		"GET /repos/owner/repo/pulls":                 `[]`,
+		"POST /repos/owner/repo/pulls":                `{"number": 9, "html_url": "https://github.com/owner/repo/pull/9"}`,
+	})
+	repo := Repository{HostType: "github", ProjectPath: "owner/repo", Token: "token", APIURL: server.URL}
+
+	url, err := Submit(repo, SubmitRequest{BaseBranch: "main", Branch: "cid/changelog", CommitMessage: "docs: update changelog", Title: "docs: update changelog", Files: map[string]FileUpdate{"CHANGELOG.md": appendLine("release 1.2.0")}})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/owner/repo/pull/9", url)
+
+	var update map[string]string
+	for _, r := range *requests {
+		if r.Method == "PUT" {
+			require.NoError(t, json.Unmarshal([]byte(r.Body), &update))
+		}
+	}
+	content, err := base64.StdEncoding.DecodeString(update["content"])
+	require.NoError(t, err)
+	assert.Equal(t, "# Changelog\nunreleased entry\nrelease 1.2.0\n", string(content))
+	assert.Equal(t, "file-sha", update["sha"])
+	assert.Equal(t, "cid/changelog", update["branch"])
+}
+
+func TestSubmitGitHubSkipsUnchangedFiles(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /repos/owner/repo/git/ref/heads/main":    `{"ref": "refs/heads/main", "object": {"sha": "base"}}`,
+		"GET /repos/owner/repo/contents/CHANGELOG.md": `{"type": "file", "encoding": "base64", "sha": "file-sha", "content": "` + base64.StdEncoding.EncodeToString([]byte("up to date\n")) + `"}`,
+	})
+	repo := Repository{HostType: "github", ProjectPath: "owner/repo", Token: "token", APIURL: server.URL}
+
+	url, err := Submit(repo, SubmitRequest{BaseBranch: "main", Branch: "cid/changelog", Files: map[string]FileUpdate{"CHANGELOG.md": func(content string) string { return content }}})
+	require.NoError(t, err)
+	assert.Empty(t, url)
+	assert.Len(t, *requests, 2)
+}
+
+func TestSubmitGitLabUsesBaseBranchContent(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]string{
+		"GET /api/v4/projects/12/repository/files/CHANGELOG.md/raw": `# Changelog`,
+		"POST /api/v4/projects/12/repository/commits":               `{"id": "abc"}`,
+		"GET /api/v4/projects/12/merge_requests":                    `[]`,
+		"POST /api/v4/projects/12/merge_requests":                   `{"iid": 4, "web_url": "https://gitlab.com/group/project/-/merge_requests/4"}`,
+	})
+	repo := Repository{HostType: "gitlab", ProjectID: "12", Token: "token", APIURL: server.URL}
+
+	url, err := Submit(repo, SubmitRequest{BaseBranch: "main", Branch: "cid/changelog", CommitMessage: "docs: update changelog", Files: map[string]FileUpdate{"CHANGELOG.md": appendLine("\nrelease 1.2.0")}})
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.com/group/project/-/merge_requests/4", url)
+
+	var commit struct {
+		Branch      string `json:"branch"`
+		StartBranch string `json:"start_branch"`
+		Actions     []struct {
+			Action  string `json:"action"`
+			Content string `json:"content"`
+		} `json:"actions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte((*requests)[1].Body), &commit))
+	assert.Equal(t, "main", commit.StartBranch)
+	require.Len(t, commit.Actions, 1)
+	assert.Equal(t, "update", commit.Actions[0].Action)
+	assert.Equal(t, "# Changelog\nrelease 1.2.0\n", commit.Actions[0].Content)
+}
+
+func TestSubmitErrors(t *testing.T) {
+	_, err := Submit(Repository{HostType: "github"}, SubmitRequest{BaseBranch: "main", Branch: "b"})
+	assert.EqualError(t, err, "no token available to create merge requests")
+	_, err = Submit(Repository{HostType: "github", Token: "token"}, SubmitRequest{Branch: "b"})
+	assert.EqualError(t, err, "base branch and branch are required")
+	_, err = Submit(Repository{HostType: "gitea", Token: "token"}, SubmitRequest{BaseBranch: "main", Branch: "b"})
+	assert.EqualError(t, err, "merge requests are not supported for host type [gitea]")
+}
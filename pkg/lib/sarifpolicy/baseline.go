@@ -0,0 +1,93 @@
+package sarifpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+)
+
+// BaselineObjectName returns the storage object name of the SARIF baseline for a project
+func BaselineObjectName(hostServer string, projectPath string) string {
+	return fmt.Sprintf("sarif-baseline/%s/%s/baseline.sarif.json", strings.ToLower(hostServer), strings.ToLower(projectPath))
+}
+
+// MergeReports combines the runs of all reports into a single report
+func MergeReports(reports []*sarif.Report) *sarif.Report {
+	merged := sarif.NewReport()
+	for _, r := range reports {
+		if r == nil {
+			continue
+		}
+
+		for _, run := range r.Runs {
+			merged.AddRun(run)
+		}
+	}
+
+	return merged
+}
+
+// NewFindings returns all findings whose fingerprint is not part of the baseline
+func NewFindings(findings []Finding, baseline []Finding) []Finding {
+	known := fingerprintSet(baseline)
+
+	var result []Finding
+	for _, f := range findings {
+		if !known[f.Fingerprint] {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+func fingerprintSet(findings []Finding) map[string]bool {
+	set := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		set[f.Fingerprint] = true
+	}
+
+	return set
+}
+
+// StoreBaseline uploads the report as the baseline for future comparisons
+func StoreBaseline(ctx context.Context, api storageapi.API, bucket string, objectName string, report *sarif.Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sarif baseline: %w", err)
+	}
+
+	err = api.PutObject(ctx, bucket, objectName, bytes.NewReader(data), "application/sarif+json")
+	if err != nil {
+		return fmt.Errorf("failed to store sarif baseline %s: %w", objectName, err)
+	}
+
+	return nil
+}
+
+// LoadBaseline downloads a previously stored baseline
+func LoadBaseline(ctx context.Context, api storageapi.API, bucket string, objectName string) (*sarif.Report, error) {
+	object, err := api.GetObject(ctx, bucket, objectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sarif baseline %s: %w", objectName, err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sarif baseline %s: %w", objectName, err)
+	}
+
+	report, err := sarif.FromBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sarif baseline %s: %w", objectName, err)
+	}
+
+	return report, nil
+}
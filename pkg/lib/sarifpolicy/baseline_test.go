@@ -0,0 +1,41 @@
+package sarifpolicy
+
+import (
+	"testing"
+
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeReports(t *testing.T) {
+	report, err := sarif.FromBytes([]byte(sampleSarif))
+	assert.NoError(t, err)
+
+	merged := MergeReports([]*sarif.Report{report, nil, report})
+	assert.Len(t, merged.Runs, 2)
+	assert.Len(t, FindingsFromReport(merged), 4)
+}
+
+func TestNewFindings(t *testing.T) {
+	findings := sampleFindings(t)
+
+	newFindings := NewFindings(findings, findings[1:])
+	assert.Len(t, newFindings, 1)
+	assert.Equal(t, "go.sql-injection", newFindings[0].RuleID)
+}
+
+func TestRenderMarkdownSummary(t *testing.T) {
+	findings := sampleFindings(t)
+
+	summary, err := RenderMarkdownSummary(findings[1:], len(findings), true)
+	assert.NoError(t, err)
+	assert.Contains(t, summary, SummaryMarker)
+	assert.Contains(t, summary, "**1** new finding(s) introduced by this change (2 total).")
+	assert.Contains(t, summary, "| low | semgrep | `go.weak-hash` | `pkg/hash/md5.go:3` | md5 is a weak hash |")
+	assert.NotContains(t, summary, "No baseline")
+
+	summary, err = RenderMarkdownSummary(nil, 0, false)
+	assert.NoError(t, err)
+	assert.Contains(t, summary, "No baseline from the default branch is available yet")
+	assert.Contains(t, summary, "No new findings introduced by this change (0 total).")
+}
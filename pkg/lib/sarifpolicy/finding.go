@@ -0,0 +1,208 @@
+package sarifpolicy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cidverse/go-ptr"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+)
+
+type Severity string
+
+const (
+	SeverityNone     Severity = "none"
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+var severityRank = map[Severity]int{
+	SeverityNone:     0,
+	SeverityInfo:     1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// AtLeast returns true if the severity is equal to or higher than the given severity
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRank[s] >= severityRank[other]
+}
+
+// ParseSeverity parses a severity name, returns an error for unknown values
+func ParseSeverity(value string) (Severity, error) {
+	s := Severity(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := severityRank[s]; !ok {
+		return SeverityNone, fmt.Errorf("unknown severity: %s", value)
+	}
+
+	return s, nil
+}
+
+// Finding is a normalized view on a single SARIF result
+type Finding struct {
+	Tool        string   `json:"tool"`
+	RuleID      string   `json:"rule_id"`
+	Level       string   `json:"level"`
+	Severity    Severity `json:"severity"`
+	Message     string   `json:"message"`
+	Path        string   `json:"path,omitempty"`
+	StartLine   int      `json:"start_line,omitempty"`
+	Fingerprint string   `json:"fingerprint"`
+	Suppressed  bool     `json:"suppressed,omitempty"` // Suppressed is true if the tool itself reported the result as suppressed
+}
+
+// FindingsFromReport extracts all results of all runs within the report
+func FindingsFromReport(report *sarif.Report) []Finding {
+	var findings []Finding
+	if report == nil {
+		return findings
+	}
+
+	for _, run := range report.Runs {
+		toolName := ""
+		var rules []*sarif.ReportingDescriptor
+		if run.Tool != nil && run.Tool.Driver != nil {
+			toolName = ptr.Value(run.Tool.Driver.Name)
+			rules = run.Tool.Driver.Rules
+		}
+
+		for _, result := range run.Results {
+			ruleID := ptr.Value(result.RuleID)
+			rule := findRule(rules, ruleID, result.RuleIndex)
+			if ruleID == "" && rule != nil {
+				ruleID = ptr.Value(rule.ID)
+			}
+
+			level := result.Level
+			if level == "" && rule != nil && rule.DefaultConfiguration != nil {
+				level = rule.DefaultConfiguration.Level
+			}
+			if level == "" {
+				level = sarif.LevelWarning
+			}
+
+			f := Finding{
+				Tool:       toolName,
+				RuleID:     ruleID,
+				Level:      level,
+				Severity:   resultSeverity(level, rule),
+				Suppressed: isSuppressed(result),
+			}
+			if result.Message != nil {
+				f.Message = ptr.Value(result.Message.Text)
+			}
+			if len(result.Locations) > 0 && result.Locations[0].PhysicalLocation != nil {
+				pl := result.Locations[0].PhysicalLocation
+				if pl.ArtifactLocation != nil {
+					f.Path = strings.TrimPrefix(ptr.Value(pl.ArtifactLocation.URI), "file://")
+				}
+				if pl.Region != nil && pl.Region.StartLine != nil {
+					f.StartLine = *pl.Region.StartLine
+				}
+			}
+			f.Fingerprint = fingerprint(f, result)
+
+			findings = append(findings, f)
+		}
+	}
+
+	return findings
+}
+
+// findRule looks up the rule descriptor by id, falling back to the rule index
+//
+// The rule index defaults to 0 if the result does not set it, for results with a rule id it is only used if explicitly set to another rule.
+func findRule(rules []*sarif.ReportingDescriptor, ruleID string, ruleIndex int) *sarif.ReportingDescriptor {
+	for _, r := range rules {
+		if ruleID != "" && ptr.Value(r.ID) == ruleID {
+			return r
+		}
+	}
+	if ruleID != "" && ruleIndex <= 0 {
+		return nil
+	}
+	if ruleIndex >= 0 && ruleIndex < len(rules) {
+		return rules[ruleIndex]
+	}
+
+	return nil
+}
+
+// resultSeverity prefers the numeric security-severity property (used by GitHub code scanning) and falls back to the result level
+func resultSeverity(level string, rule *sarif.ReportingDescriptor) Severity {
+	if rule != nil && rule.Properties != nil {
+		if raw, ok := rule.Properties.Properties["security-severity"]; ok {
+			if score, err := strconv.ParseFloat(fmt.Sprint(raw), 64); err == nil {
+				switch {
+				case score >= 9.0:
+					return SeverityCritical
+				case score >= 7.0:
+					return SeverityHigh
+				case score >= 4.0:
+					return SeverityMedium
+				case score > 0:
+					return SeverityLow
+				default:
+					return SeverityInfo
+				}
+			}
+		}
+	}
+
+	switch level {
+	case sarif.LevelError:
+		return SeverityHigh
+	case sarif.LevelWarning:
+		return SeverityMedium
+	case sarif.LevelNote:
+		return SeverityLow
+	default:
+		return SeverityInfo
+	}
+}
+
+// isSuppressed checks for in-source or external suppressions that were not rejected
+func isSuppressed(result *sarif.Result) bool {
+	for _, s := range result.Suppressions {
+		status := ptr.Value(s.Status)
+		if status == "" || status == "accepted" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fingerprint returns a stable identity for the result, preferring tool-provided fingerprints.
+// The computed fallback intentionally excludes line numbers, so that unrelated changes moving code around do not produce new findings.
+func fingerprint(f Finding, result *sarif.Result) string {
+	prints := result.Fingerprints
+	if len(prints) == 0 {
+		prints = result.PartialFingerprints
+	}
+	if len(prints) > 0 {
+		keys := make([]string, 0, len(prints))
+		for k := range prints {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		var parts []string
+		for _, k := range keys {
+			parts = append(parts, k+"="+prints[k])
+		}
+		return f.Tool + "/" + strings.Join(parts, ";")
+	}
+
+	h := sha256.Sum256([]byte(strings.Join([]string{f.Tool, f.RuleID, f.Path, f.Message}, "|")))
+	return f.Tool + "/" + hex.EncodeToString(h[:])
+}
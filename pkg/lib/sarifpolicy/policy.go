@@ -0,0 +1,177 @@
+package sarifpolicy
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+const suppressionDateLayout = "2006-01-02"
+
+// Policy decides which findings fail the workflow
+type Policy struct {
+	SeverityThreshold Severity      `json:"severity_threshold" yaml:"severity_threshold"` // SeverityThreshold is the minimum severity that fails the policy, SeverityNone disables severity-based failures
+	FailOnRules       []string      `json:"fail_on_rules" yaml:"fail_on_rules"`           // FailOnRules always fail, regardless of their severity
+	IgnoreRules       []string      `json:"ignore_rules" yaml:"ignore_rules"`             // IgnoreRules never fail
+	NewFindingsOnly   bool          `json:"new_findings_only" yaml:"new_findings_only"`   // NewFindingsOnly only fails on findings that are not part of the baseline
+	Suppressions      []Suppression `json:"suppressions" yaml:"suppressions"`
+}
+
+// Suppression silences matching findings until it expires
+type Suppression struct {
+	RuleID      string `json:"rule_id" yaml:"rule_id"`         // RuleID supports glob patterns, empty matches all rules
+	Path        string `json:"path" yaml:"path"`               // Path supports glob patterns, empty matches all paths
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"` // Fingerprint matches a single finding
+	Reason      string `json:"reason" yaml:"reason"`
+	Expires     string `json:"expires" yaml:"expires"` // Expires is a date in the format YYYY-MM-DD, the suppression is active until the end of that day
+}
+
+// Matches checks if the suppression applies to the finding
+func (s Suppression) Matches(f Finding) bool {
+	if s.RuleID == "" && s.Path == "" && s.Fingerprint == "" {
+		return false
+	}
+	if s.Fingerprint != "" && s.Fingerprint != f.Fingerprint {
+		return false
+	}
+	if s.RuleID != "" && !matchPattern(s.RuleID, f.RuleID) {
+		return false
+	}
+	if s.Path != "" && !matchPattern(s.Path, f.Path) {
+		return false
+	}
+
+	return true
+}
+
+// Expired checks if the suppression is expired at the given time
+func (s Suppression) Expired(now time.Time) (bool, error) {
+	if s.Expires == "" {
+		return false, nil
+	}
+
+	expires, err := time.ParseInLocation(suppressionDateLayout, s.Expires, now.Location())
+	if err != nil {
+		return false, fmt.Errorf("invalid expiry date [%s] for suppression of rule [%s]: %w", s.Expires, s.RuleID, err)
+	}
+
+	return !now.Before(expires.AddDate(0, 0, 1)), nil
+}
+
+// EvaluatedFinding is a finding together with the policy decision
+type EvaluatedFinding struct {
+	Finding
+	New         bool         `json:"new"`
+	Violation   bool         `json:"violation"`
+	Reason      string       `json:"reason"`
+	Suppression *Suppression `json:"suppression,omitempty"`
+}
+
+// Result holds the outcome of a policy evaluation
+type Result struct {
+	Findings            []EvaluatedFinding `json:"findings"`
+	ExpiredSuppressions []Suppression      `json:"expired_suppressions,omitempty"`
+}
+
+// Violations returns all findings that fail the policy
+func (r Result) Violations() []EvaluatedFinding {
+	var violations []EvaluatedFinding
+	for _, f := range r.Findings {
+		if f.Violation {
+			violations = append(violations, f)
+		}
+	}
+
+	return violations
+}
+
+// Passed returns true if no finding violates the policy
+func (r Result) Passed() bool {
+	return len(r.Violations()) == 0
+}
+
+// Evaluate applies the policy to the findings, baseline may be nil if no baseline is available in which case all findings are considered new
+func Evaluate(policy Policy, findings []Finding, baseline []Finding, now time.Time) (Result, error) {
+	result := Result{}
+
+	threshold := policy.SeverityThreshold
+	if threshold == "" {
+		threshold = SeverityHigh
+	}
+
+	// active suppressions
+	var suppressions []Suppression
+	for _, s := range policy.Suppressions {
+		expired, err := s.Expired(now)
+		if err != nil {
+			return result, err
+		}
+
+		if expired {
+			result.ExpiredSuppressions = append(result.ExpiredSuppressions, s)
+		} else {
+			suppressions = append(suppressions, s)
+		}
+	}
+
+	// baseline lookup
+	known := fingerprintSet(baseline)
+
+	for _, f := range findings {
+		ef := EvaluatedFinding{Finding: f, New: !known[f.Fingerprint]}
+		suppression := findSuppression(suppressions, f)
+
+		switch {
+		case matchAny(policy.IgnoreRules, f.RuleID):
+			ef.Reason = "rule is ignored"
+		case f.Suppressed:
+			ef.Reason = "suppressed by tool"
+		case suppression != nil:
+			ef.Suppression = suppression
+			ef.Reason = "suppressed by policy"
+		case policy.NewFindingsOnly && !ef.New:
+			ef.Reason = "present in baseline"
+		case matchAny(policy.FailOnRules, f.RuleID):
+			ef.Violation = true
+			ef.Reason = "rule is configured to fail"
+		case threshold != SeverityNone && f.Severity.AtLeast(threshold):
+			ef.Violation = true
+			ef.Reason = fmt.Sprintf("severity %s is at or above threshold %s", f.Severity, threshold)
+		default:
+			ef.Reason = fmt.Sprintf("severity %s is below threshold %s", f.Severity, threshold)
+		}
+
+		result.Findings = append(result.Findings, ef)
+	}
+
+	return result, nil
+}
+
+func findSuppression(suppressions []Suppression, f Finding) *Suppression {
+	for i := range suppressions {
+		if suppressions[i].Matches(f) {
+			return &suppressions[i]
+		}
+	}
+
+	return nil
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if matchPattern(p, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchPattern(pattern string, value string) bool {
+	if pattern == value {
+		return true
+	}
+
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
@@ -0,0 +1,129 @@
+package sarifpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cidverse/go-ptr"
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSarif = `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "semgrep",
+          "rules": [
+            {"id": "go.sql-injection", "properties": {"security-severity": "9.8"}},
+            {"id": "go.weak-hash", "defaultConfiguration": {"level": "note"}}
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "go.sql-injection",
+          "level": "error",
+          "message": {"text": "possible sql injection"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "pkg/db/query.go"}, "region": {"startLine": 12}}}]
+        },
+        {
+          "ruleId": "go.weak-hash",
+          "message": {"text": "md5 is a weak hash"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "pkg/hash/md5.go"}, "region": {"startLine": 3}}}]
+        }
+      ]
+    }
+  ]
+}`
+
+func sampleFindings(t *testing.T) []Finding {
+	report, err := sarif.FromBytes([]byte(sampleSarif))
+	assert.NoError(t, err)
+
+	return FindingsFromReport(report)
+}
+
+func TestFindingsFromReport(t *testing.T) {
+	findings := sampleFindings(t)
+
+	assert.Len(t, findings, 2)
+	assert.Equal(t, "semgrep", findings[0].Tool)
+	assert.Equal(t, "go.sql-injection", findings[0].RuleID)
+	assert.Equal(t, SeverityCritical, findings[0].Severity)
+	assert.Equal(t, "pkg/db/query.go", findings[0].Path)
+	assert.Equal(t, 12, findings[0].StartLine)
+	assert.Equal(t, "note", findings[1].Level)
+	assert.Equal(t, SeverityLow, findings[1].Severity)
+	assert.NotEqual(t, findings[0].Fingerprint, findings[1].Fingerprint)
+}
+
+func TestFindRule(t *testing.T) {
+	rules := []*sarif.ReportingDescriptor{
+		{ID: ptr.Ptr("go.sql-injection")},
+		{ID: ptr.Ptr("CA2101")},
+	}
+
+	assert.Equal(t, rules[1], findRule(rules, "CA2101", 0))
+	assert.Equal(t, rules[1], findRule(rules, "", 1))
+	assert.Equal(t, rules[0], findRule(rules, "", 0))
+	assert.Equal(t, rules[1], findRule(rules, "CA2101/1", 1), "explicit rule index for hierarchical rule ids")
+	assert.Nil(t, findRule(rules, "go.unknown", 0), "unset rule index must not fall back to the first rule")
+	assert.Nil(t, findRule(rules, "go.unknown", 5))
+}
+
+func TestEvaluateSeverityThreshold(t *testing.T) {
+	result, err := Evaluate(Policy{SeverityThreshold: SeverityHigh}, sampleFindings(t), nil, time.Now())
+	assert.NoError(t, err)
+
+	assert.False(t, result.Passed())
+	assert.Len(t, result.Violations(), 1)
+	assert.Equal(t, "go.sql-injection", result.Violations()[0].RuleID)
+}
+
+func TestEvaluateFailOnRules(t *testing.T) {
+	result, err := Evaluate(Policy{SeverityThreshold: SeverityNone, FailOnRules: []string{"go.weak-*"}}, sampleFindings(t), nil, time.Now())
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Violations(), 1)
+	assert.Equal(t, "go.weak-hash", result.Violations()[0].RuleID)
+}
+
+func TestEvaluateBaseline(t *testing.T) {
+	findings := sampleFindings(t)
+
+	result, err := Evaluate(Policy{SeverityThreshold: SeverityLow, NewFindingsOnly: true}, findings, findings[:1], time.Now())
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Violations(), 1)
+	assert.Equal(t, "go.weak-hash", result.Violations()[0].RuleID)
+	assert.True(t, result.Violations()[0].New)
+}
+
+func TestEvaluateSuppressionExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	policy := Policy{
+		SeverityThreshold: SeverityHigh,
+		Suppressions: []Suppression{
+			{RuleID: "go.sql-injection", Path: "pkg/db/*.go", Reason: "false positive", Expires: "2025-06-15"},
+		},
+	}
+
+	result, err := Evaluate(policy, sampleFindings(t), nil, now)
+	assert.NoError(t, err)
+	assert.True(t, result.Passed())
+	assert.Empty(t, result.ExpiredSuppressions)
+
+	result, err = Evaluate(policy, sampleFindings(t), nil, now.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.False(t, result.Passed())
+	assert.Len(t, result.ExpiredSuppressions, 1)
+}
+
+func TestEvaluateInvalidSuppressionDate(t *testing.T) {
+	_, err := Evaluate(Policy{Suppressions: []Suppression{{RuleID: "x", Expires: "next week"}}}, nil, nil, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package sarifpolicy
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// SummaryMarker identifies the summary in merge request comments, to update the existing comment instead of creating a new one
+const SummaryMarker = "<!-- cid:sarif-summary -->"
+
+//go:embed templates/summary.gohtml
+var summaryTemplate string
+
+type SummaryData struct {
+	Marker            string
+	NewFindings       []Finding
+	TotalFindings     int
+	BaselineAvailable bool
+}
+
+// RenderMarkdownSummary renders the new findings as markdown, ordered by severity
+func RenderMarkdownSummary(newFindings []Finding, totalFindings int, baselineAvailable bool) (string, error) {
+	sorted := slices.Clone(newFindings)
+	slices.SortStableFunc(sorted, func(a, b Finding) int {
+		return severityRank[b.Severity] - severityRank[a.Severity]
+	})
+
+	tmpl, err := template.New("summary").Funcs(template.FuncMap{
+		"escape": func(s string) string {
+			s = strings.ReplaceAll(s, "|", "\\|")
+			return strings.Join(strings.Fields(s), " ")
+		},
+	}).Parse(summaryTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse summary template: %w", err)
+	}
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, SummaryData{
+		Marker:            SummaryMarker,
+		NewFindings:       sorted,
+		TotalFindings:     totalFindings,
+		BaselineAvailable: baselineAvailable,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render summary template: %w", err)
+	}
+
+	return out.String(), nil
+}
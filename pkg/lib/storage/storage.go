@@ -7,10 +7,22 @@ import (
 	"github.com/cidverse/cid/pkg/lib/storage/storages3"
 )
 
+// DefaultBucket is the bucket used if no bucket is configured
+const DefaultBucket = "cidverse-cid"
+
 func GetStorageApi() (storageapi.API, error) {
-	s3Endpoint := os.Getenv("CID_STORAGE_S3_ENDPOINT")
-	s3AccessKey := os.Getenv("CID_STORAGE_S3_ACCESS_KEY")
-	s3SecretKey := os.Getenv("CID_STORAGE_S3_SECRET_KEY")
+	return GetStorageApiFromEnv(map[string]string{
+		"CID_STORAGE_S3_ENDPOINT":   os.Getenv("CID_STORAGE_S3_ENDPOINT"),
+		"CID_STORAGE_S3_ACCESS_KEY": os.Getenv("CID_STORAGE_S3_ACCESS_KEY"),
+		"CID_STORAGE_S3_SECRET_KEY": os.Getenv("CID_STORAGE_S3_SECRET_KEY"),
+	})
+}
+
+// GetStorageApiFromEnv returns the storage api configured by the given environment, returns nil if no storage is configured
+func GetStorageApiFromEnv(env map[string]string) (storageapi.API, error) {
+	s3Endpoint := env["CID_STORAGE_S3_ENDPOINT"]
+	s3AccessKey := env["CID_STORAGE_S3_ACCESS_KEY"]
+	s3SecretKey := env["CID_STORAGE_S3_SECRET_KEY"]
 
 	if s3Endpoint != "" && s3AccessKey != "" && s3SecretKey != "" {
 		client, err := storages3.NewS3Client(s3Endpoint, s3AccessKey, s3SecretKey, false)
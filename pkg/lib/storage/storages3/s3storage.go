@@ -39,6 +39,10 @@ func (s3 S3Client) PutObjectFile(ctx context.Context, bucketName string, objectN
 	return nil
 }
 
+func (s3 S3Client) RemoveObject(ctx context.Context, bucketName string, objectName string) error {
+	return s3.minioClient.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
+}
+
 func NewS3Client(endpoint, accessKeyID, secretAccessKey string, useSSL bool) (*S3Client, error) {
 	minioClient, err := minio.New(endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(accessKeyID, secretAccessKey, ""),
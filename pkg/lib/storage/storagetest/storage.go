@@ -0,0 +1,106 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cidverse/cid/pkg/lib/storage/storageapi"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Storage is an in-memory storage for tests, objects are keyed by bucket/object
+type Storage struct {
+	mu      sync.Mutex
+	Objects map[string][]byte
+	client  *minio.Client
+}
+
+var _ storageapi.API = (*Storage)(nil)
+
+// NewStorage returns an in-memory storage, objects are served to GetObject using a local S3 compatible endpoint
+func NewStorage(t *testing.T) *Storage {
+	s := &Storage{Objects: map[string][]byte{}}
+
+	server := httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(server.Close)
+
+	client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create storage client: %v", err)
+	}
+	s.client = client
+
+	return s
+}
+
+func (s *Storage) GetObject(ctx context.Context, bucketName, objectName string) (*minio.Object, error) {
+	return s.client.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
+}
+
+func (s *Storage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, contentType string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Objects[bucketName+"/"+objectName] = data
+	return nil
+}
+
+func (s *Storage) PutObjectFile(ctx context.Context, bucketName, objectName, filePath, contentType string) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (s *Storage) RemoveObject(ctx context.Context, bucketName, objectName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Objects, bucketName+"/"+objectName)
+	return nil
+}
+
+// Object returns the content of an object, nil if it does not exist
+func (s *Storage) Object(bucketName, objectName string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Objects[bucketName+"/"+objectName]
+}
+
+// serveHTTP implements the object download of the S3 api
+func (s *Storage) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	s.mu.Lock()
+	data := s.Objects[strings.TrimPrefix(r.URL.Path, "/")]
+	s.mu.Unlock()
+	if data == nil {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("ETag", `"`+strconv.Itoa(len(data))+`"`)
+	w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
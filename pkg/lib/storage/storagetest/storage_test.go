@@ -0,0 +1,27 @@
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage(t *testing.T) {
+	s := NewStorage(t)
+	require.NoError(t, s.PutObject(context.Background(), "bucket", "dir/file.json", bytes.NewReader([]byte(`{"a":1}`)), "application/json"))
+
+	object, err := s.GetObject(context.Background(), "bucket", "dir/file.json")
+	require.NoError(t, err)
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+
+	object, err = s.GetObject(context.Background(), "bucket", "missing.json")
+	require.NoError(t, err)
+	_, err = io.ReadAll(object)
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	before := testutil.ToFloat64(PipelineCache.WithLabelValues("hit"))
+	PipelineCache.WithLabelValues("hit").Inc()
+	StepDuration.WithLabelValues("go-build", "root", "success").Observe(5)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(PipelineCache.WithLabelValues("hit")))
+	assert.Equal(t, 1, testutil.CollectAndCount(StepDuration, "cid_step_duration_seconds"))
+}
+
+func TestHandler(t *testing.T) {
+	PipelineGenerations.WithLabelValues("github", Result(nil)).Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, string(body), `cid_pipeline_generations_total{platform="github",result="success"}`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,41 @@
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metrics recorded by cid, served by the metrics endpoint of the app server and the api
+var (
+	PipelineGenerations = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "cid_pipeline_generations_total",
+		Help: "Number of generated pipelines by platform and result.",
+	}, []string{"platform", "result"})
+	PipelineCache = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "cid_pipeline_cache_requests_total",
+		Help: "Number of pipeline cache lookups by result (hit, miss).",
+	}, []string{"result"})
+	StepDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cid_step_duration_seconds",
+		Help:    "Duration of workflow steps by action, module and result.",
+		Buckets: DefaultBuckets,
+	}, []string{"action", "module", "result"})
+	ExecutableResolve = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cid_executable_resolution_duration_seconds",
+		Help:    "Time spent selecting an executable candidate by executable.",
+		Buckets: DefaultBuckets,
+	}, []string{"executable"})
+	ContainerPull = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cid_container_pull_duration_seconds",
+		Help:    "Duration of container image pulls by image and result.",
+		Buckets: DefaultBuckets,
+	}, []string{"image", "result"})
+)
+
+// Result returns the result label value for an error
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,158 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	defaultServiceName  = "cid"
+	instrumentationName = "github.com/cidverse/cid"
+)
+
+var (
+	provider  *sdktrace.TracerProvider
+	openSpans *openSpanProcessor
+	setupOnce sync.Once
+)
+
+// setup configures the otlp/http exporter using the standard OpenTelemetry environment variables, tracing is disabled if no endpoint is configured
+func setup() {
+	if disabled, _ := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED")); disabled {
+		return
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return
+	}
+
+	exporter, err := otlptracehttp.New(context.Background())
+	if err != nil {
+		slog.With("err", err).Warn("failed to create trace exporter, tracing is disabled")
+		return
+	}
+	configure(sdktrace.WithBatcher(exporter))
+}
+
+// configure creates the tracer provider, the service name can be overwritten by OTEL_SERVICE_NAME
+func configure(opts ...sdktrace.TracerProviderOption) {
+	res, err := resource.Merge(resource.NewSchemaless(attribute.String("service.name", defaultServiceName)), resource.Environment())
+	if err != nil {
+		slog.With("err", err).Warn("failed to read the trace resource from the environment")
+	}
+
+	openSpans = &openSpanProcessor{spans: make(map[trace.SpanID]sdktrace.ReadWriteSpan)}
+	provider = sdktrace.NewTracerProvider(append(opts, sdktrace.WithResource(res), sdktrace.WithSpanProcessor(openSpans))...)
+}
+
+// Enabled returns true if spans are exported
+func Enabled() bool {
+	setupOnce.Do(setup)
+	return provider != nil
+}
+
+func tracer() trace.Tracer {
+	if !Enabled() {
+		return noop.NewTracerProvider().Tracer(instrumentationName)
+	}
+	return provider.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span as child of the span in the context, root spans join the trace passed in the TRACEPARENT environment variable
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(parentContext(ctx), name, trace.WithAttributes(attrs...))
+}
+
+// RecordSpan records a completed span that started at start, nothing is recorded if tracing is disabled
+func RecordSpan(ctx context.Context, name string, start time.Time, err error, attrs ...attribute.KeyValue) {
+	if !Enabled() {
+		return
+	}
+
+	_, span := tracer().Start(parentContext(ctx), name, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError records the error and marks the span as failed
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Shutdown ends all open spans, marking them as failed with the cause if set, and exports the remaining spans
+func Shutdown(ctx context.Context, cause error) error {
+	if !Enabled() {
+		return nil
+	}
+
+	openSpans.endAll(cause)
+	return provider.Shutdown(ctx)
+}
+
+// parentContext returns the context holding the parent span, using the TRACEPARENT environment variable if the context has no span
+func parentContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": os.Getenv("TRACEPARENT")})
+}
+
+// openSpanProcessor tracks started spans, to end them if the workflow is aborted
+type openSpanProcessor struct {
+	mu    sync.Mutex
+	spans map[trace.SpanID]sdktrace.ReadWriteSpan
+}
+
+func (p *openSpanProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.spans[s.SpanContext().SpanID()] = s
+}
+
+func (p *openSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.spans, s.SpanContext().SpanID())
+}
+
+func (p *openSpanProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *openSpanProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p *openSpanProcessor) endAll(cause error) {
+	p.mu.Lock()
+	var open []sdktrace.ReadWriteSpan
+	for _, s := range p.spans {
+		open = append(open, s)
+	}
+	p.mu.Unlock()
+
+	for _, s := range open {
+		RecordError(s, cause)
+		s.End()
+	}
+}
@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// testRecorder configures tracing with a recorder of the ended spans
+func testRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	setupOnce.Do(func() {})
+	recorder := tracetest.NewSpanRecorder()
+	configure(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider, openSpans = nil, nil })
+
+	return recorder
+}
+
+func TestStartSpan(t *testing.T) {
+	recorder := testRecorder(t)
+
+	ctx, plan := StartSpan(context.Background(), "workflow main")
+	_, step := StartSpan(ctx, "step go-build", attribute.String("cid.module", "root"))
+	RecordError(step, errors.New("exit status 1"))
+	step.End()
+	plan.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "step go-build", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "exit status 1", spans[0].Status().Description)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("cid.module", "root")}, spans[0].Attributes())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	serviceName, _ := spans[0].Resource().Set().Value("service.name")
+	assert.Equal(t, "cid", serviceName.AsString())
+}
+
+func TestShutdownEndsOpenSpans(t *testing.T) {
+	recorder := testRecorder(t)
+
+	_, _ = StartSpan(context.Background(), "workflow main")
+	require.NoError(t, Shutdown(context.Background(), errors.New("aborted")))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "aborted", spans[0].Status().Description)
+}
+
+func TestRecordSpan(t *testing.T) {
+	recorder := testRecorder(t)
+
+	start := time.Now().Add(-time.Second)
+	RecordSpan(context.Background(), "resolve go", start, nil, attribute.String("cid.executable", "go"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.True(t, start.Equal(spans[0].StartTime()))
+	assert.True(t, spans[0].EndTime().After(start))
+}
+
+func TestStartSpanTraceParent(t *testing.T) {
+	recorder := testRecorder(t)
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	_, span := StartSpan(context.Background(), "workflow main")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+func TestDisabled(t *testing.T) {
+	setupOnce.Do(func() {})
+
+	assert.False(t, Enabled())
+	_, span := StartSpan(context.Background(), "workflow main")
+	assert.False(t, span.IsRecording())
+	assert.NoError(t, Shutdown(context.Background(), nil))
+}
@@ -13,3 +13,7 @@ func CIDConfigDir() string {
 func CIDStateDir() string {
 	return filepath.Join(xdg.StateHome, "cid")
 }
+
+func CIDCacheDir() string {
+	return filepath.Join(xdg.CacheHome, "cid")
+}